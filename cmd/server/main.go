@@ -2,15 +2,19 @@ package main
 
 import (
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/hafizd-kurniawan/pos-baru/internal/config"
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
 	"github.com/hafizd-kurniawan/pos-baru/internal/handler"
 	"github.com/hafizd-kurniawan/pos-baru/internal/middleware"
 	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
 	"github.com/hafizd-kurniawan/pos-baru/internal/service"
 	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/notifier"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/storage"
 	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
 )
 
@@ -38,23 +42,55 @@ func main() {
 	sparePartRepo := repository.NewSparePartRepository(db)
 	sparePartCategoryRepo := repository.NewSparePartCategoryRepository(db.DB.DB)
 	repairRepo := repository.NewRepairRepository(db)
+	customerVehicleRepo := repository.NewCustomerVehicleRepository(db)
+	vehicleBrandRepo := repository.NewVehicleBrandRepository(db.DB)
+	vehicleModelRepo := repository.NewVehicleModelRepository(db)
+	vehicleAttributeRepo := repository.NewVehicleAttributeRepository(db)
+	vehicleTimelineRepo := repository.NewVehicleTimelineRepository(db)
+	serviceInvoiceRepo := repository.NewServiceInvoiceRepository(db)
+	serviceCatalogRepo := repository.NewServiceCatalogRepository(db)
+	checklistTemplateRepo := repository.NewChecklistTemplateRepository(db)
+	skillRepo := repository.NewSkillRepository(db)
+	workshopRepo := repository.NewWorkshopRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
 	dashboardRepo := repository.NewDashboardRepository(db.DB)
 	supplierRepo := repository.NewSupplierRepository(db.DB)
+	warrantyRepo := repository.NewWarrantyRepository(db)
+	vehicleDocumentRepo := repository.NewVehicleDocumentRepository(db)
+	appointmentRepo := repository.NewAppointmentRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	vehicleExpenseRepo := repository.NewVehicleExpenseRepository(db)
 
 	// Initialize JWT middleware
 	jwtMiddleware := middleware.NewJWTMiddleware(cfg)
 
 	// Initialize services
 	authService := service.NewAuthService(userRepo, jwtMiddleware)
-	vehicleService := service.NewVehicleService(vehicleRepo)
 	vehicleTypeService := service.NewVehicleTypeService(vehicleTypeRepo)
-	customerService := service.NewCustomerService(customerRepo)
+	vehicleBrandService := service.NewVehicleBrandService(vehicleBrandRepo)
+	vehicleModelService := service.NewVehicleModelService(vehicleModelRepo, vehicleBrandRepo)
+	vehicleAttributeService := service.NewVehicleAttributeService(vehicleAttributeRepo, vehicleTypeRepo)
+	customerService := service.NewCustomerService(customerRepo, customerVehicleRepo, vehicleBrandRepo)
 	transactionService := service.NewTransactionService(transactionRepo, vehicleRepo, customerRepo)
 	salesService := service.NewSalesService(salesRepo, vehicleRepo, customerRepo)
 	sparePartService := service.NewSparePartService(sparePartRepo)
 	sparePartCategoryService := service.NewSparePartCategoryService(sparePartCategoryRepo)
-	repairService := service.NewRepairService(repairRepo, vehicleRepo, userRepo, sparePartRepo)
-	dashboardService := service.NewDashboardService(dashboardRepo)
+	serviceCatalogService := service.NewServiceCatalogService(serviceCatalogRepo, vehicleTypeRepo)
+	checklistTemplateService := service.NewChecklistTemplateService(checklistTemplateRepo, vehicleTypeRepo)
+	skillService := service.NewSkillService(skillRepo, userRepo, vehicleTypeRepo)
+	notificationService := service.NewNotificationService(notificationRepo)
+	workshopService := service.NewWorkshopService(workshopRepo)
+	fileStore := newFileStore(cfg)
+	fileUploader := service.NewFileUploader(fileStore, cfg.Storage.MaxUploadSizeMB, time.Duration(cfg.Storage.URLExpiryMinutes)*time.Minute)
+	vehicleService := service.NewVehicleService(vehicleRepo, vehicleModelRepo, vehicleAttributeRepo, vehicleTimelineRepo, fileUploader)
+	repairService := service.NewRepairService(repairRepo, vehicleRepo, userRepo, sparePartRepo, customerVehicleRepo, serviceCatalogRepo, checklistTemplateRepo, skillRepo, workshopRepo, notificationService, fileUploader, models.SkillCheckMode(cfg.Workshop.SkillCheckMode), time.Duration(cfg.Workshop.EstimateLinkHours)*time.Hour, cfg.Workshop.RepairSLADays, cfg.Workshop.SLAAtRiskDays)
+	serviceInvoiceService := service.NewServiceInvoiceService(serviceInvoiceRepo, repairRepo)
+	vehicleDocumentService := service.NewVehicleDocumentService(vehicleDocumentRepo, vehicleRepo, transactionRepo, fileUploader)
+	vehicleExpenseService := service.NewVehicleExpenseService(vehicleExpenseRepo, vehicleRepo, userRepo, notificationService, cfg.Inventory.RegistrationDueDays)
+	warrantyService := service.NewWarrantyService(warrantyRepo, transactionRepo, customerVehicleRepo, repairService)
+	appointmentService := service.NewAppointmentService(appointmentRepo, customerVehicleRepo, serviceCatalogRepo, repairService, time.Duration(cfg.Workshop.AppointmentSlotMinutes)*time.Minute, time.Duration(cfg.Workshop.NoShowGraceMinutes)*time.Minute)
+	reminderService := service.NewReminderService(reminderRepo, serviceCatalogRepo, appointmentRepo, reminderChannel(cfg))
+	dashboardService := service.NewDashboardService(dashboardRepo, cfg.Workshop.SLAAtRiskDays)
 	supplierService := service.NewSupplierService(supplierRepo)
 	userService := service.NewUserService(userRepo)
 
@@ -62,18 +98,51 @@ func main() {
 	authHandler := handler.NewAuthHandler(authService)
 	vehicleHandler := handler.NewVehicleHandler(vehicleService)
 	vehicleTypeHandler := handler.NewVehicleTypeHandler(vehicleTypeService)
+	vehicleBrandHandler := handler.NewVehicleBrandHandler(vehicleBrandService)
+	vehicleModelHandler := handler.NewVehicleModelHandler(vehicleModelService)
+	vehicleAttributeHandler := handler.NewVehicleAttributeHandler(vehicleAttributeService)
 	customerHandler := handler.NewCustomerHandler(customerService)
 	transactionHandler := handler.NewTransactionHandler(transactionService)
 	salesHandler := handler.NewSalesHandler(salesService)
+	warrantyHandler := handler.NewWarrantyHandler(warrantyService)
+	vehicleDocumentHandler := handler.NewVehicleDocumentHandler(vehicleDocumentService)
+	vehicleExpenseHandler := handler.NewVehicleExpenseHandler(vehicleExpenseService)
 	sparePartHandler := handler.NewSparePartHandler(sparePartService)
 	sparePartCategoryHandler := handler.NewSparePartCategoryHandler(sparePartCategoryService)
+	serviceCatalogHandler := handler.NewServiceCatalogHandler(serviceCatalogService)
+	checklistTemplateHandler := handler.NewChecklistTemplateHandler(checklistTemplateService)
+	skillHandler := handler.NewSkillHandler(skillService)
+	workshopHandler := handler.NewWorkshopHandler(workshopService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
 	repairHandler := handler.NewRepairHandler(repairService)
+	serviceOrderHandler := handler.NewServiceOrderHandler(repairService, serviceInvoiceService)
+	appointmentHandler := handler.NewAppointmentHandler(appointmentService)
+	reminderHandler := handler.NewReminderHandler(reminderService)
 	dashboardHandler := handler.NewDashboardHandler(dashboardService)
 	supplierHandler := handler.NewSupplierHandler(supplierService)
 	userHandler := handler.NewUserHandler(userService)
+	fileHandler := handler.NewFileHandler(fileStore)
 
 	// Setup router
-	router := setupRouter(cfg, jwtMiddleware, authHandler, vehicleHandler, vehicleTypeHandler, customerHandler, transactionHandler, salesHandler, sparePartHandler, sparePartCategoryHandler, repairHandler, dashboardHandler, supplierHandler, userHandler)
+	router := setupRouter(cfg, jwtMiddleware, authHandler, vehicleHandler, vehicleTypeHandler, vehicleBrandHandler, vehicleModelHandler, vehicleAttributeHandler, customerHandler, transactionHandler, salesHandler, warrantyHandler, vehicleDocumentHandler, vehicleExpenseHandler, sparePartHandler, sparePartCategoryHandler, serviceCatalogHandler, checklistTemplateHandler, skillHandler, workshopHandler, notificationHandler, repairHandler, serviceOrderHandler, appointmentHandler, reminderHandler, dashboardHandler, supplierHandler, userHandler, fileHandler)
+
+	// Background jobs
+	go runEvery(time.Hour, "overdue repair alerts", func() error {
+		_, err := repairService.AlertOverdueRepairs()
+		return err
+	})
+	go runEvery(15*time.Minute, "appointment no-shows", func() error {
+		_, err := appointmentService.MarkNoShows()
+		return err
+	})
+	go runEvery(24*time.Hour, "service reminders", func() error {
+		_, err := reminderService.RunDueReminders()
+		return err
+	})
+	go runEvery(24*time.Hour, "registration due alerts", func() error {
+		_, err := vehicleExpenseService.AlertRegistrationDue()
+		return err
+	})
 
 	// Start server
 	log.Printf("Starting server on port %s", cfg.Server.Port)
@@ -82,7 +151,46 @@ func main() {
 	}
 }
 
-func setupRouter(cfg *config.Config, jwtMiddleware *middleware.JWTMiddleware, authHandler *handler.AuthHandler, vehicleHandler *handler.VehicleHandler, vehicleTypeHandler *handler.VehicleTypeHandler, customerHandler *handler.CustomerHandler, transactionHandler *handler.TransactionHandler, salesHandler *handler.SalesHandler, sparePartHandler *handler.SparePartHandler, sparePartCategoryHandler *handler.SparePartCategoryHandler, repairHandler *handler.RepairHandler, dashboardHandler *handler.DashboardHandler, supplierHandler *handler.SupplierHandler, userHandler *handler.UserHandler) *gin.Engine {
+// reminderChannel picks the channel customer reminders are delivered through
+func reminderChannel(cfg *config.Config) notifier.Channel {
+	if cfg.Reminder.Channel == "webhook" && cfg.Reminder.WebhookURL != "" {
+		return notifier.NewWebhook(cfg.Reminder.WebhookURL)
+	}
+	return notifier.NewLog()
+}
+
+// newFileStore picks the storage driver for uploaded photos and documents
+func newFileStore(cfg *config.Config) storage.Store {
+	if cfg.Storage.Driver == "s3" {
+		return storage.NewS3(storage.S3Config{
+			Endpoint:  cfg.Storage.S3Endpoint,
+			Region:    cfg.Storage.S3Region,
+			Bucket:    cfg.Storage.S3Bucket,
+			AccessKey: cfg.Storage.S3AccessKey,
+			SecretKey: cfg.Storage.S3SecretKey,
+			UseSSL:    cfg.Storage.S3UseSSL,
+			PathStyle: cfg.Storage.S3PathStyle,
+		})
+	}
+
+	signingKey := cfg.Storage.SigningKey
+	if signingKey == "" {
+		signingKey = cfg.JWT.Secret
+	}
+	return storage.NewLocal(cfg.Storage.UploadDir, "/uploads", signingKey)
+}
+
+// runEvery runs job now and then at every interval, logging failures
+func runEvery(interval time.Duration, name string, job func() error) {
+	for {
+		if err := job(); err != nil {
+			log.Printf("Background job %s failed: %v", name, err)
+		}
+		time.Sleep(interval)
+	}
+}
+
+func setupRouter(cfg *config.Config, jwtMiddleware *middleware.JWTMiddleware, authHandler *handler.AuthHandler, vehicleHandler *handler.VehicleHandler, vehicleTypeHandler *handler.VehicleTypeHandler, vehicleBrandHandler *handler.VehicleBrandHandler, vehicleModelHandler *handler.VehicleModelHandler, vehicleAttributeHandler *handler.VehicleAttributeHandler, customerHandler *handler.CustomerHandler, transactionHandler *handler.TransactionHandler, salesHandler *handler.SalesHandler, warrantyHandler *handler.WarrantyHandler, vehicleDocumentHandler *handler.VehicleDocumentHandler, vehicleExpenseHandler *handler.VehicleExpenseHandler, sparePartHandler *handler.SparePartHandler, sparePartCategoryHandler *handler.SparePartCategoryHandler, serviceCatalogHandler *handler.ServiceCatalogHandler, checklistTemplateHandler *handler.ChecklistTemplateHandler, skillHandler *handler.SkillHandler, workshopHandler *handler.WorkshopHandler, notificationHandler *handler.NotificationHandler, repairHandler *handler.RepairHandler, serviceOrderHandler *handler.ServiceOrderHandler, appointmentHandler *handler.AppointmentHandler, reminderHandler *handler.ReminderHandler, dashboardHandler *handler.DashboardHandler, supplierHandler *handler.SupplierHandler, userHandler *handler.UserHandler, fileHandler *handler.FileHandler) *gin.Engine {
 	// Set gin mode
 	if cfg.App.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -91,11 +199,19 @@ func setupRouter(cfg *config.Config, jwtMiddleware *middleware.JWTMiddleware, au
 	// Create router
 	router := gin.New()
 
+	// Client IPs, used by the rate limits, come from X-Forwarded-For only behind a trusted proxy
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxies:", err)
+	}
+
 	// Global middleware
 	router.Use(middleware.LoggerMiddleware())
 	router.Use(middleware.ErrorHandler())
 	router.Use(middleware.CORS())
 
+	// Uploaded photos and documents, through signed links only
+	router.GET("/uploads/*filepath", fileHandler.ServeFile)
+
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
 		utils.SendSuccess(c, "Service is healthy", gin.H{
@@ -114,6 +230,17 @@ func setupRouter(cfg *config.Config, jwtMiddleware *middleware.JWTMiddleware, au
 			auth.POST("/login", authHandler.Login)
 		}
 
+		// Customer-facing routes (public, rate-limited per client IP)
+		public := api.Group("/public")
+		public.Use(middleware.RateLimit(30, time.Minute))
+		{
+			// Estimate links, the token is the credential
+			public.GET("/estimates/:token", serviceOrderHandler.GetPublicEstimate)
+			public.POST("/estimates/:token/decision", serviceOrderHandler.DecidePublicEstimate)
+			// Repair tracking, verified by the last digits of the customer's phone number
+			public.GET("/repairs/:code", repairHandler.TrackRepair)
+		}
+
 		// Protected routes
 		protected := api.Group("/")
 		protected.Use(jwtMiddleware.AuthMiddleware())
@@ -132,11 +259,34 @@ func setupRouter(cfg *config.Config, jwtMiddleware *middleware.JWTMiddleware, au
 				vehicles.GET("/search", vehicleHandler.SearchVehicles)
 				vehicles.GET("/brands", vehicleHandler.GetVehicleBrands)
 				vehicles.GET("/available", vehicleHandler.GetAvailableVehicles)
+				vehicles.GET("/registration-due", vehicleExpenseHandler.ListRegistrationDue)
 				vehicles.GET("/:id", vehicleHandler.GetVehicle)
+				vehicles.GET("/:id/timeline", vehicleHandler.GetVehicleTimeline)
 				vehicles.POST("", jwtMiddleware.RequireCashierOrAdmin(), vehicleHandler.CreateVehicle)
 				vehicles.PUT("/:id", jwtMiddleware.RequireCashierOrAdmin(), vehicleHandler.UpdateVehicle)
 				vehicles.DELETE("/:id", jwtMiddleware.RequireAdmin(), vehicleHandler.DeleteVehicle)
 				vehicles.PATCH("/:id/selling-price", jwtMiddleware.RequireAdmin(), vehicleHandler.SetSellingPrice)
+				vehicles.GET("/:id/photos", vehicleHandler.GetVehiclePhotos)
+				vehicles.POST("/:id/photos", jwtMiddleware.RequireCashierOrAdmin(), vehicleHandler.UploadVehiclePhoto)
+				vehicles.PUT("/:id/photos/order", jwtMiddleware.RequireCashierOrAdmin(), vehicleHandler.ReorderVehiclePhotos)
+				vehicles.PUT("/:id/photos/:photo_id/primary", jwtMiddleware.RequireCashierOrAdmin(), vehicleHandler.SetPrimaryVehiclePhoto)
+				vehicles.DELETE("/:id/photos/:photo_id", jwtMiddleware.RequireCashierOrAdmin(), vehicleHandler.DeleteVehiclePhoto)
+				vehicles.GET("/:id/documents", vehicleDocumentHandler.ListVehicleDocuments)
+				vehicles.POST("/:id/documents", jwtMiddleware.RequireCashierOrAdmin(), vehicleDocumentHandler.CreateVehicleDocument)
+				vehicles.GET("/:id/expenses", vehicleExpenseHandler.ListVehicleExpenses)
+				vehicles.POST("/:id/expenses", jwtMiddleware.RequireCashierOrAdmin(), vehicleExpenseHandler.AddVehicleExpense)
+				vehicles.DELETE("/:id/expenses/:expense_id", jwtMiddleware.RequireAdmin(), vehicleExpenseHandler.DeleteVehicleExpense)
+			}
+
+			// Vehicle document register (BPKB, STNK, receipts, keys)
+			vehicleDocuments := protected.Group("/vehicle-documents")
+			{
+				vehicleDocuments.GET("/pending-handover", vehicleDocumentHandler.ListPendingHandovers)
+				vehicleDocuments.GET("/:id", vehicleDocumentHandler.GetVehicleDocument)
+				vehicleDocuments.PUT("/:id", jwtMiddleware.RequireCashierOrAdmin(), vehicleDocumentHandler.UpdateVehicleDocument)
+				vehicleDocuments.DELETE("/:id", jwtMiddleware.RequireAdmin(), vehicleDocumentHandler.DeleteVehicleDocument)
+				vehicleDocuments.POST("/:id/attachments", jwtMiddleware.RequireCashierOrAdmin(), vehicleDocumentHandler.UploadVehicleDocumentAttachment)
+				vehicleDocuments.DELETE("/:id/attachments/:attachment_id", jwtMiddleware.RequireCashierOrAdmin(), vehicleDocumentHandler.DeleteVehicleDocumentAttachment)
 			}
 
 			// Vehicle Type routes
@@ -147,6 +297,46 @@ func setupRouter(cfg *config.Config, jwtMiddleware *middleware.JWTMiddleware, au
 				vehicleTypes.POST("", jwtMiddleware.RequireAdmin(), vehicleTypeHandler.CreateVehicleType)
 				vehicleTypes.PUT("/:id", jwtMiddleware.RequireAdmin(), vehicleTypeHandler.UpdateVehicleType)
 				vehicleTypes.DELETE("/:id", jwtMiddleware.RequireAdmin(), vehicleTypeHandler.DeleteVehicleType)
+				vehicleTypes.GET("/:id/attributes", vehicleAttributeHandler.ListAttributes)
+				vehicleTypes.POST("/:id/attributes", jwtMiddleware.RequireAdmin(), vehicleAttributeHandler.CreateAttribute)
+			}
+
+			vehicleAttributes := protected.Group("/vehicle-attributes")
+			{
+				vehicleAttributes.GET("/:id", vehicleAttributeHandler.GetAttribute)
+				vehicleAttributes.PUT("/:id", jwtMiddleware.RequireAdmin(), vehicleAttributeHandler.UpdateAttribute)
+				vehicleAttributes.DELETE("/:id", jwtMiddleware.RequireAdmin(), vehicleAttributeHandler.DeleteAttribute)
+			}
+
+			// Vehicle brand, model and variant catalog routes
+			vehicleBrands := protected.Group("/vehicle-brands")
+			{
+				vehicleBrands.GET("", vehicleBrandHandler.ListVehicleBrands)
+				vehicleBrands.GET("/:id", vehicleBrandHandler.GetVehicleBrand)
+				vehicleBrands.POST("", jwtMiddleware.RequireAdmin(), vehicleBrandHandler.CreateVehicleBrand)
+				vehicleBrands.PUT("/:id", jwtMiddleware.RequireAdmin(), vehicleBrandHandler.UpdateVehicleBrand)
+				vehicleBrands.DELETE("/:id", jwtMiddleware.RequireAdmin(), vehicleBrandHandler.DeleteVehicleBrand)
+				vehicleBrands.GET("/:id/models", vehicleModelHandler.ListBrandModels)
+				vehicleBrands.POST("/:id/models", jwtMiddleware.RequireAdmin(), vehicleModelHandler.CreateModel)
+			}
+
+			vehicleModels := protected.Group("/vehicle-models")
+			{
+				vehicleModels.GET("/unmapped", jwtMiddleware.RequireAdmin(), vehicleModelHandler.ListUnmapped)
+				vehicleModels.POST("/map", jwtMiddleware.RequireAdmin(), vehicleModelHandler.MapFreeText)
+				vehicleModels.POST("/auto-map", jwtMiddleware.RequireAdmin(), vehicleModelHandler.AutoMap)
+				vehicleModels.GET("/:id", vehicleModelHandler.GetModel)
+				vehicleModels.PUT("/:id", jwtMiddleware.RequireAdmin(), vehicleModelHandler.UpdateModel)
+				vehicleModels.DELETE("/:id", jwtMiddleware.RequireAdmin(), vehicleModelHandler.DeleteModel)
+				vehicleModels.GET("/:id/variants", vehicleModelHandler.ListVariants)
+				vehicleModels.POST("/:id/variants", jwtMiddleware.RequireAdmin(), vehicleModelHandler.CreateVariant)
+			}
+
+			vehicleVariants := protected.Group("/vehicle-variants")
+			{
+				vehicleVariants.GET("/:id", vehicleModelHandler.GetVariant)
+				vehicleVariants.PUT("/:id", jwtMiddleware.RequireAdmin(), vehicleModelHandler.UpdateVariant)
+				vehicleVariants.DELETE("/:id", jwtMiddleware.RequireAdmin(), vehicleModelHandler.DeleteVariant)
 			}
 
 			// Customer routes
@@ -159,6 +349,15 @@ func setupRouter(cfg *config.Config, jwtMiddleware *middleware.JWTMiddleware, au
 				customers.POST("", jwtMiddleware.RequireCashierOrAdmin(), customerHandler.CreateCustomer)
 				customers.PUT("/:id", jwtMiddleware.RequireCashierOrAdmin(), customerHandler.UpdateCustomer)
 				customers.DELETE("/:id", jwtMiddleware.RequireAdmin(), customerHandler.DeleteCustomer)
+				customers.GET("/:id/vehicles", customerHandler.ListCustomerVehicles)
+				customers.POST("/:id/vehicles", jwtMiddleware.RequireCashierOrAdmin(), customerHandler.CreateCustomerVehicle)
+			}
+
+			// Customer-owned vehicle routes
+			customerVehicles := protected.Group("/customer-vehicles")
+			{
+				customerVehicles.GET("/:id", customerHandler.GetCustomerVehicle)
+				customerVehicles.PUT("/:id", jwtMiddleware.RequireCashierOrAdmin(), customerHandler.UpdateCustomerVehicle)
 			}
 
 			// Transaction routes
@@ -192,6 +391,12 @@ func setupRouter(cfg *config.Config, jwtMiddleware *middleware.JWTMiddleware, au
 				sales.PUT("/transactions/:id", jwtMiddleware.RequireCashierOrAdmin(), salesHandler.UpdateSalesTransaction)
 				sales.DELETE("/transactions/:id", jwtMiddleware.RequireAdmin(), salesHandler.DeleteSalesTransaction)
 				sales.GET("/vehicles/available", salesHandler.GetAvailableVehicles)
+				sales.GET("/warranty-report", warrantyHandler.GetWarrantyCostReport)
+				sales.GET("/transactions/:id/warranty", warrantyHandler.GetWarranty)
+				sales.PUT("/transactions/:id/warranty", jwtMiddleware.RequireCashierOrAdmin(), warrantyHandler.SetWarranty)
+				sales.POST("/transactions/:id/warranty/claims", jwtMiddleware.RequireCashierOrAdmin(), warrantyHandler.CreateWarrantyClaim)
+				sales.GET("/transactions/:id/document-handovers", vehicleDocumentHandler.GetDocumentHandovers)
+				sales.POST("/transactions/:id/document-handovers", jwtMiddleware.RequireCashierOrAdmin(), vehicleDocumentHandler.HandOverDocuments)
 			}
 
 			// Spare Parts routes
@@ -203,6 +408,7 @@ func setupRouter(cfg *config.Config, jwtMiddleware *middleware.JWTMiddleware, au
 				spareParts.GET("/:id", sparePartHandler.GetSparePart)
 				spareParts.GET("/code/:code", sparePartHandler.GetSparePartByCode)
 				spareParts.GET("/:id/stock-check", sparePartHandler.CheckStockAvailability)
+				spareParts.GET("/:id/movements", sparePartHandler.GetStockMovements)
 				spareParts.POST("", jwtMiddleware.RequireCashierOrAdmin(), sparePartHandler.CreateSparePart)
 				spareParts.PUT("/:id", jwtMiddleware.RequireCashierOrAdmin(), sparePartHandler.UpdateSparePart)
 				spareParts.DELETE("/:id", jwtMiddleware.RequireAdmin(), sparePartHandler.DeleteSparePart)
@@ -221,22 +427,142 @@ func setupRouter(cfg *config.Config, jwtMiddleware *middleware.JWTMiddleware, au
 				sparePartCategories.DELETE("/:id", jwtMiddleware.RequireAdmin(), sparePartCategoryHandler.Delete)
 			}
 
+			// Service catalog routes
+			serviceCatalog := protected.Group("/service-catalog")
+			{
+				serviceCatalog.GET("", serviceCatalogHandler.ListServices)
+				serviceCatalog.GET("/:id", serviceCatalogHandler.GetService)
+				serviceCatalog.POST("", jwtMiddleware.RequireAdmin(), serviceCatalogHandler.CreateService)
+				serviceCatalog.PUT("/:id", jwtMiddleware.RequireAdmin(), serviceCatalogHandler.UpdateService)
+				serviceCatalog.DELETE("/:id", jwtMiddleware.RequireAdmin(), serviceCatalogHandler.DeleteService)
+			}
+
+			// Inspection checklist templates
+			checklistTemplates := protected.Group("/checklist-templates")
+			{
+				checklistTemplates.GET("", checklistTemplateHandler.ListTemplates)
+				checklistTemplates.GET("/:id", checklistTemplateHandler.GetTemplate)
+				checklistTemplates.POST("", jwtMiddleware.RequireAdmin(), checklistTemplateHandler.CreateTemplate)
+				checklistTemplates.PUT("/:id", jwtMiddleware.RequireAdmin(), checklistTemplateHandler.UpdateTemplate)
+				checklistTemplates.DELETE("/:id", jwtMiddleware.RequireAdmin(), checklistTemplateHandler.DeleteTemplate)
+			}
+
+			// Mechanic skill routes
+			skills := protected.Group("/skills")
+			{
+				skills.GET("", skillHandler.ListSkills)
+				skills.POST("", jwtMiddleware.RequireAdmin(), skillHandler.CreateSkill)
+				skills.DELETE("/:id", jwtMiddleware.RequireAdmin(), skillHandler.DeleteSkill)
+			}
+
+			// Workshop routes
+			workshop := protected.Group("/workshop")
+			{
+				workshop.GET("/calendar", workshopHandler.GetCalendar)
+				workshop.GET("/bays", workshopHandler.ListBays)
+				workshop.GET("/bays/:id", workshopHandler.GetBay)
+				workshop.POST("/bays", jwtMiddleware.RequireAdmin(), workshopHandler.CreateBay)
+				workshop.PUT("/bays/:id", jwtMiddleware.RequireAdmin(), workshopHandler.UpdateBay)
+				workshop.DELETE("/bays/:id", jwtMiddleware.RequireAdmin(), workshopHandler.DeleteBay)
+			}
+
+			// Notification routes (current user)
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", notificationHandler.ListNotifications)
+				notifications.PATCH("/read-all", notificationHandler.MarkAllNotificationsRead)
+				notifications.PATCH("/:id/read", notificationHandler.MarkNotificationRead)
+			}
+
 			// Repair routes
 			repairs := protected.Group("/repairs")
 			{
 				repairs.GET("", repairHandler.ListRepairOrders)
 				repairs.GET("/stats", repairHandler.GetRepairStats)
 				repairs.GET("/mechanic-workload", repairHandler.GetMechanicWorkload)
-				// repairs.GET("/vehicles-needing-orders", repairHandler.GetVehiclesNeedingRepairOrders) // Method not implemented
+				repairs.GET("/mechanic-utilization", repairHandler.GetMechanicUtilization)
+				repairs.GET("/turnaround", repairHandler.GetRepairTurnaround)
+				repairs.GET("/status-transitions", repairHandler.GetStatusTransitions)
+				repairs.PUT("/status-transitions", jwtMiddleware.RequireAdmin(), repairHandler.UpdateStatusTransitions)
+				repairs.GET("/vehicles-needing-orders", repairHandler.GetVehiclesNeedingRepairOrders)
 				repairs.GET("/:id", repairHandler.GetRepairOrder)
 				repairs.GET("/code/:code", repairHandler.GetRepairOrderByCode)
 				repairs.GET("/:id/spare-parts", repairHandler.GetRepairSpareParts)
+				repairs.GET("/:id/history", repairHandler.GetRepairStatusHistory)
 				repairs.POST("", jwtMiddleware.RequireCashierOrAdmin(), repairHandler.CreateRepairOrder)
 				repairs.PUT("/:id", jwtMiddleware.RequireCashierOrAdmin(), repairHandler.UpdateRepairOrder)
 				repairs.DELETE("/:id", jwtMiddleware.RequireAdmin(), repairHandler.DeleteRepairOrder)
+				repairs.POST("/:id/cancel", jwtMiddleware.RequireCashierOrAdmin(), repairHandler.CancelRepairOrder)
 				repairs.PATCH("/:id/progress", repairHandler.UpdateRepairProgress)   // Mechanics can update their own repairs
 				repairs.POST("/:id/spare-parts", repairHandler.AddSparePartToRepair) // Mechanics can add spare parts
 				repairs.DELETE("/:id/spare-parts/:spare_part_id", jwtMiddleware.RequireCashierOrAdmin(), repairHandler.RemoveSparePartFromRepair)
+				repairs.PUT("/:id/schedule", jwtMiddleware.RequireCashierOrAdmin(), repairHandler.ScheduleRepair)
+				repairs.DELETE("/:id/schedule", jwtMiddleware.RequireCashierOrAdmin(), repairHandler.UnscheduleRepair)
+				repairs.GET("/:id/time", repairHandler.GetRepairTimeSummary)
+				repairs.POST("/:id/timer/start", jwtMiddleware.RequireMechanicOrAdmin(), repairHandler.StartRepairTimer)
+				repairs.POST("/:id/timer/pause", jwtMiddleware.RequireMechanicOrAdmin(), repairHandler.PauseRepairTimer)
+				repairs.POST("/:id/timer/stop", jwtMiddleware.RequireMechanicOrAdmin(), repairHandler.StopRepairTimer)
+				repairs.GET("/:id/labor", repairHandler.GetRepairLaborLines)
+				repairs.POST("/:id/labor", repairHandler.AddLaborLine) // Mechanics can record labor
+				repairs.DELETE("/:id/labor/:line_id", jwtMiddleware.RequireCashierOrAdmin(), repairHandler.RemoveLaborLine)
+				repairs.GET("/:id/checklist", repairHandler.GetRepairChecklist)
+				repairs.POST("/:id/checklist", repairHandler.AttachChecklist)
+				repairs.PATCH("/:id/checklist/:item_id", repairHandler.UpdateChecklistItem) // Mechanics check items
+				repairs.GET("/:id/attachments", repairHandler.GetRepairAttachments)
+				repairs.POST("/:id/attachments", repairHandler.UploadRepairAttachment) // Mechanics upload photos
+				repairs.DELETE("/:id/attachments/:attachment_id", jwtMiddleware.RequireCashierOrAdmin(), repairHandler.DeleteRepairAttachment)
+				repairs.GET("/:id/reservations", repairHandler.GetRepairReservations)
+				repairs.POST("/:id/reservations", repairHandler.ReserveSparePart) // Mechanics can reserve parts for their jobs
+				repairs.DELETE("/:id/reservations/:reservation_id", repairHandler.ReleaseReservation)
+			}
+
+			// Customer service order routes (repairs on customer-owned vehicles)
+			serviceOrders := protected.Group("/service-orders")
+			{
+				serviceOrders.GET("", serviceOrderHandler.ListServiceOrders)
+				serviceOrders.GET("/report", jwtMiddleware.RequireCashierOrAdmin(), serviceOrderHandler.GetWorkshopServiceReport)
+				serviceOrders.POST("", jwtMiddleware.RequireCashierOrAdmin(), serviceOrderHandler.CreateServiceOrder)
+				serviceOrders.GET("/:id/estimate", serviceOrderHandler.GetServiceEstimate)
+				serviceOrders.POST("/:id/estimate/decision", jwtMiddleware.RequireCashierOrAdmin(), serviceOrderHandler.DecideEstimate)
+				serviceOrders.POST("/:id/estimate/link", jwtMiddleware.RequireCashierOrAdmin(), serviceOrderHandler.CreateEstimateLink)
+				serviceOrders.GET("/:id/invoice", serviceOrderHandler.GetServiceOrderInvoice)
+				serviceOrders.POST("/:id/invoice", jwtMiddleware.RequireCashierOrAdmin(), serviceOrderHandler.CreateServiceInvoice)
+			}
+
+			// Service invoice routes
+			serviceInvoices := protected.Group("/service-invoices")
+			{
+				serviceInvoices.GET("", jwtMiddleware.RequireCashierOrAdmin(), serviceOrderHandler.ListServiceInvoices)
+				serviceInvoices.GET("/:id", jwtMiddleware.RequireCashierOrAdmin(), serviceOrderHandler.GetServiceInvoice)
+				serviceInvoices.POST("/:id/payments", jwtMiddleware.RequireCashierOrAdmin(), serviceOrderHandler.AddServicePayment)
+			}
+
+			// Service reminder routes
+			reminders := protected.Group("/reminders")
+			{
+				reminders.GET("", reminderHandler.ListReminders)
+				reminders.GET("/rules", reminderHandler.ListReminderRules)
+				reminders.POST("/rules", jwtMiddleware.RequireAdmin(), reminderHandler.CreateReminderRule)
+				reminders.GET("/rules/:id", reminderHandler.GetReminderRule)
+				reminders.PUT("/rules/:id", jwtMiddleware.RequireAdmin(), reminderHandler.UpdateReminderRule)
+				reminders.DELETE("/rules/:id", jwtMiddleware.RequireAdmin(), reminderHandler.DeleteReminderRule)
+				reminders.POST("/run", jwtMiddleware.RequireAdmin(), reminderHandler.RunReminders)
+				reminders.GET("/:id", reminderHandler.GetReminder)
+				reminders.POST("/:id/outcome", jwtMiddleware.RequireCashierOrAdmin(), reminderHandler.RecordReminderOutcome)
+			}
+
+			// Service appointment routes
+			appointments := protected.Group("/appointments")
+			{
+				appointments.GET("", appointmentHandler.ListAppointments)
+				appointments.GET("/capacity", appointmentHandler.GetCapacity)
+				appointments.GET("/stats", jwtMiddleware.RequireCashierOrAdmin(), appointmentHandler.GetAppointmentStats)
+				appointments.GET("/:id", appointmentHandler.GetAppointment)
+				appointments.POST("", jwtMiddleware.RequireCashierOrAdmin(), appointmentHandler.BookAppointment)
+				appointments.PUT("/:id/reschedule", jwtMiddleware.RequireCashierOrAdmin(), appointmentHandler.RescheduleAppointment)
+				appointments.POST("/:id/arrive", jwtMiddleware.RequireCashierOrAdmin(), appointmentHandler.ArriveAppointment)
+				appointments.POST("/:id/cancel", jwtMiddleware.RequireCashierOrAdmin(), appointmentHandler.CancelAppointment)
+				appointments.POST("/:id/no-show", jwtMiddleware.RequireCashierOrAdmin(), appointmentHandler.MarkNoShow)
 			}
 
 			// Dashboard routes
@@ -280,6 +606,9 @@ func setupRouter(cfg *config.Config, jwtMiddleware *middleware.JWTMiddleware, au
 				users.POST("/change-password", userHandler.ChangePassword)                                 // Users can change their own password
 				users.POST("/:id/reset-password", jwtMiddleware.RequireAdmin(), userHandler.ResetPassword) // Admin can reset any password
 				users.PATCH("/:id/toggle-status", jwtMiddleware.RequireAdmin(), userHandler.ToggleUserStatus)
+				users.GET("/:id/skills", jwtMiddleware.RequireCashierOrAdmin(), skillHandler.GetUserSkills)
+				users.POST("/:id/skills", jwtMiddleware.RequireAdmin(), skillHandler.AssignUserSkill)
+				users.DELETE("/:id/skills/:skill_id", jwtMiddleware.RequireAdmin(), skillHandler.RemoveUserSkill)
 			}
 		}
 	}
@@ -3,15 +3,21 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	JWT      JWTConfig
-	Server   ServerConfig
-	App      AppConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Server    ServerConfig
+	App       AppConfig
+	Workshop  WorkshopConfig
+	Storage   StorageConfig
+	Reminder  ReminderConfig
+	Inventory InventoryConfig
 }
 
 type DatabaseConfig struct {
@@ -28,13 +34,47 @@ type JWTConfig struct {
 }
 
 type ServerConfig struct {
-	Port string
+	Port           string
+	TrustedProxies []string // proxies allowed to set the client IP through X-Forwarded-For, none by default
 }
 
 type AppConfig struct {
 	Environment string
 }
 
+type WorkshopConfig struct {
+	SkillCheckMode         string // off, warn or enforce
+	EstimateLinkHours      int    // lifetime of public estimate links
+	RepairSLADays          int    // target turnaround when neither catalog nor vehicle type sets one
+	SLAAtRiskDays          int    // open repairs due within this many days are flagged at risk
+	AppointmentSlotMinutes int    // slot length when the booked operation has no standard hours
+	NoShowGraceMinutes     int    // booked appointments are marked no-show this long after their slot ends
+}
+
+type StorageConfig struct {
+	Driver           string // local or s3
+	UploadDir        string
+	MaxUploadSizeMB  int
+	URLExpiryMinutes int    // lifetime of signed download links
+	SigningKey       string // signs local download links, falls back to the JWT secret
+	S3Endpoint       string
+	S3Region         string
+	S3Bucket         string
+	S3AccessKey      string
+	S3SecretKey      string
+	S3UseSSL         bool
+	S3PathStyle      bool // required by MinIO
+}
+
+type InventoryConfig struct {
+	RegistrationDueDays int // STNK tax and plate renewal dates within this many days are alerted
+}
+
+type ReminderConfig struct {
+	Channel    string // log or webhook
+	WebhookURL string // gateway receiving customer messages when Channel is webhook
+}
+
 func Load() (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
@@ -52,11 +92,41 @@ func Load() (*Config, error) {
 			Secret: getEnv("JWT_SECRET", "your_super_secret_jwt_key_here_change_in_production"),
 		},
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
+			Port:           getEnv("SERVER_PORT", "8080"),
+			TrustedProxies: getEnvList("TRUSTED_PROXIES"),
 		},
 		App: AppConfig{
 			Environment: getEnv("APP_ENV", "development"),
 		},
+		Workshop: WorkshopConfig{
+			SkillCheckMode:         getEnv("SKILL_CHECK_MODE", "warn"),
+			EstimateLinkHours:      getEnvInt("ESTIMATE_LINK_HOURS", 72),
+			RepairSLADays:          getEnvInt("REPAIR_SLA_DAYS", 3),
+			SLAAtRiskDays:          getEnvInt("SLA_AT_RISK_DAYS", 1),
+			AppointmentSlotMinutes: getEnvInt("APPOINTMENT_SLOT_MINUTES", 60),
+			NoShowGraceMinutes:     getEnvInt("APPOINTMENT_NO_SHOW_MINUTES", 30),
+		},
+		Storage: StorageConfig{
+			Driver:           getEnv("STORAGE_DRIVER", "local"),
+			UploadDir:        getEnv("UPLOAD_DIR", "./uploads"),
+			MaxUploadSizeMB:  getEnvInt("UPLOAD_MAX_SIZE_MB", 10),
+			URLExpiryMinutes: getEnvInt("STORAGE_URL_EXPIRY_MINUTES", 60),
+			SigningKey:       getEnv("STORAGE_SIGNING_KEY", ""),
+			S3Endpoint:       getEnv("S3_ENDPOINT", "localhost:9000"),
+			S3Region:         getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:         getEnv("S3_BUCKET", "pos-uploads"),
+			S3AccessKey:      getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:      getEnv("S3_SECRET_KEY", ""),
+			S3UseSSL:         getEnvBool("S3_USE_SSL", false),
+			S3PathStyle:      getEnvBool("S3_PATH_STYLE", true),
+		},
+		Reminder: ReminderConfig{
+			Channel:    getEnv("REMINDER_CHANNEL", "log"),
+			WebhookURL: getEnv("REMINDER_WEBHOOK_URL", ""),
+		},
+		Inventory: InventoryConfig{
+			RegistrationDueDays: getEnvInt("REGISTRATION_DUE_DAYS", 30),
+		},
 	}
 
 	return config, nil
@@ -78,4 +148,29 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvList splits a comma separated value, returning nil when it is empty
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
 }
\ No newline at end of file
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"time"
+)
+
+// AppointmentStatus enum
+type AppointmentStatus string
+
+const (
+	AppointmentStatusBooked    AppointmentStatus = "booked"
+	AppointmentStatusArrived   AppointmentStatus = "arrived" // converted into a repair order
+	AppointmentStatusNoShow    AppointmentStatus = "no_show"
+	AppointmentStatusCancelled AppointmentStatus = "cancelled"
+)
+
+// AppointmentSource tells who booked an appointment
+type AppointmentSource string
+
+const (
+	AppointmentSourceFrontDesk AppointmentSource = "front_desk"
+	AppointmentSourcePublic    AppointmentSource = "public"
+)
+
+// ServiceAppointment represents the service_appointments table
+type ServiceAppointment struct {
+	ID                int               `json:"id" db:"id"`
+	Code              string            `json:"code" db:"code"`
+	CustomerID        int               `json:"customer_id" db:"customer_id"`
+	CustomerVehicleID int               `json:"customer_vehicle_id" db:"customer_vehicle_id"`
+	ServiceID         *int              `json:"service_id" db:"service_id"`
+	RequestedService  string            `json:"requested_service" db:"requested_service"`
+	ScheduledStart    time.Time         `json:"scheduled_start" db:"scheduled_start"`
+	ScheduledEnd      time.Time         `json:"scheduled_end" db:"scheduled_end"`
+	Status            AppointmentStatus `json:"status" db:"status"`
+	Source            AppointmentSource `json:"source" db:"source"`
+	RepairOrderID     *int              `json:"repair_order_id" db:"repair_order_id"`
+	Notes             *string           `json:"notes" db:"notes"`
+	CancelReason      *string           `json:"cancel_reason" db:"cancel_reason"`
+	ArrivedAt         *time.Time        `json:"arrived_at" db:"arrived_at"`
+	CancelledAt       *time.Time        `json:"cancelled_at" db:"cancelled_at"`
+	NoShowAt          *time.Time        `json:"no_show_at" db:"no_show_at"`
+	BookedBy          *int              `json:"booked_by" db:"booked_by"`
+	CreatedAt         time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at" db:"updated_at"`
+	// Additional fields for joined queries
+	CustomerName  string  `json:"customer_name" db:"customer_name"`
+	CustomerPhone *string `json:"customer_phone" db:"customer_phone"`
+	LicensePlate  string  `json:"license_plate" db:"license_plate"`
+	VehicleModel  string  `json:"vehicle_model" db:"vehicle_model"`
+	ServiceName   *string `json:"service_name" db:"service_name"`
+	RepairCode    *string `json:"repair_code" db:"repair_code"`
+}
+
+// ServiceAppointmentCreateRequest books a service slot for a customer vehicle.
+// The slot length comes from the standard hours of the catalog operation when no end is given.
+type ServiceAppointmentCreateRequest struct {
+	CustomerVehicleID int        `json:"customer_vehicle_id" validate:"required"`
+	ServiceID         *int       `json:"service_id"`
+	RequestedService  string     `json:"requested_service" validate:"omitempty,max=500"`
+	ScheduledStart    time.Time  `json:"scheduled_start" validate:"required"`
+	ScheduledEnd      *time.Time `json:"scheduled_end"`
+	Notes             *string    `json:"notes"`
+}
+
+// ServiceAppointmentRescheduleRequest moves a booked appointment
+type ServiceAppointmentRescheduleRequest struct {
+	ScheduledStart time.Time  `json:"scheduled_start" validate:"required"`
+	ScheduledEnd   *time.Time `json:"scheduled_end"`
+}
+
+// ServiceAppointmentArrivalRequest converts an appointment into a service order on arrival
+type ServiceAppointmentArrivalRequest struct {
+	MechanicID    int     `json:"mechanic_id"` // omit to auto-assign the least-loaded mechanic
+	EstimatedCost float64 `json:"estimated_cost" validate:"min=0"`
+	Odometer      *int    `json:"odometer" validate:"omitempty,min=0"`
+	Notes         *string `json:"notes"`
+}
+
+// ServiceAppointmentCancelRequest cancels a booked appointment
+type ServiceAppointmentCancelRequest struct {
+	Reason *string `json:"reason"`
+}
+
+// ServiceAppointmentFilter for filtering appointments
+type ServiceAppointmentFilter struct {
+	Status     AppointmentStatus `form:"status"`
+	CustomerID int               `form:"customer_id"`
+	DateFrom   string            `form:"date_from"` // YYYY-MM-DD, on the scheduled start
+	DateTo     string            `form:"date_to"`
+}
+
+// AppointmentCapacity is the workshop load of a time slot
+type AppointmentCapacity struct {
+	SlotStart  time.Time `json:"slot_start"`
+	SlotEnd    time.Time `json:"slot_end"`
+	Bays       int       `json:"bays"`
+	Mechanics  int       `json:"mechanics"`
+	Capacity   int       `json:"capacity"` // the scarcer of bays and mechanics
+	Booked     int       `json:"booked"`   // appointments plus scheduled repairs overlapping the slot
+	Available  int       `json:"available"`
+	IsBookable bool      `json:"is_bookable"`
+}
+
+// AppointmentStats counts appointment outcomes for a period
+type AppointmentStats struct {
+	DateFrom   string  `json:"date_from"`
+	DateTo     string  `json:"date_to"`
+	Total      int     `json:"total" db:"total"`
+	Booked     int     `json:"booked" db:"booked"`
+	Arrived    int     `json:"arrived" db:"arrived"`
+	NoShow     int     `json:"no_show" db:"no_show"`
+	Cancelled  int     `json:"cancelled" db:"cancelled"`
+	NoShowRate float64 `json:"no_show_rate"` // percent of appointments that were due
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+)
+
+// ChecklistResult enum
+type ChecklistResult string
+
+const (
+	ChecklistResultPass           ChecklistResult = "pass"
+	ChecklistResultFail           ChecklistResult = "fail"
+	ChecklistResultNeedsAttention ChecklistResult = "needs_attention"
+)
+
+// ChecklistTemplate represents the checklist_templates table
+type ChecklistTemplate struct {
+	ID            int                     `json:"id" db:"id"`
+	Name          string                  `json:"name" db:"name" validate:"required,max=150"`
+	VehicleTypeID *int                    `json:"vehicle_type_id" db:"vehicle_type_id"`
+	Description   *string                 `json:"description" db:"description"`
+	IsActive      bool                    `json:"is_active" db:"is_active"`
+	CreatedAt     time.Time               `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at" db:"updated_at"`
+	Items         []ChecklistTemplateItem `json:"items,omitempty"`
+}
+
+// ChecklistTemplateItem represents the checklist_template_items table
+type ChecklistTemplateItem struct {
+	ID         int     `json:"id" db:"id"`
+	TemplateID int     `json:"template_id" db:"template_id"`
+	Label      string  `json:"label" db:"label" validate:"required,max=150"`
+	Category   *string `json:"category" db:"category" validate:"omitempty,max=50"`
+	IsRequired bool    `json:"is_required" db:"is_required"`
+	SortOrder  int     `json:"sort_order" db:"sort_order"`
+}
+
+// ChecklistTemplateItemRequest for defining a template item
+type ChecklistTemplateItemRequest struct {
+	Label      string  `json:"label" validate:"required,max=150"`
+	Category   *string `json:"category" validate:"omitempty,max=50"`
+	IsRequired bool    `json:"is_required"`
+}
+
+// ChecklistTemplateCreateRequest for creating new template
+type ChecklistTemplateCreateRequest struct {
+	Name          string                         `json:"name" validate:"required,max=150"`
+	VehicleTypeID *int                           `json:"vehicle_type_id"`
+	Description   *string                        `json:"description"`
+	Items         []ChecklistTemplateItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// ChecklistTemplateUpdateRequest for updating template; Items replaces all items when given
+type ChecklistTemplateUpdateRequest struct {
+	Name        *string                        `json:"name" validate:"omitempty,max=150"`
+	Description *string                        `json:"description"`
+	IsActive    *bool                          `json:"is_active"`
+	Items       []ChecklistTemplateItemRequest `json:"items" validate:"omitempty,dive"`
+}
+
+// RepairChecklistItem represents the repair_checklist_items table
+type RepairChecklistItem struct {
+	ID            int              `json:"id" db:"id"`
+	RepairOrderID int              `json:"repair_order_id" db:"repair_order_id"`
+	TemplateID    *int             `json:"template_id" db:"template_id"`
+	Label         string           `json:"label" db:"label"`
+	Category      *string          `json:"category" db:"category"`
+	IsRequired    bool             `json:"is_required" db:"is_required"`
+	SortOrder     int              `json:"sort_order" db:"sort_order"`
+	Result        *ChecklistResult `json:"result" db:"result"`
+	Note          *string          `json:"note" db:"note"`
+	PhotoPath     *string          `json:"photo_path" db:"photo_path"`
+	CheckedBy     *int             `json:"checked_by" db:"checked_by"`
+	CheckedAt     *time.Time       `json:"checked_at" db:"checked_at"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+}
+
+// RepairChecklistAttachRequest for attaching a template to a repair order.
+// Without TemplateID the active template of the vehicle's type is used.
+type RepairChecklistAttachRequest struct {
+	TemplateID *int `json:"template_id"`
+}
+
+// RepairChecklistItemUpdateRequest for recording the result of a checklist item
+type RepairChecklistItemUpdateRequest struct {
+	Result    ChecklistResult `json:"result" validate:"required,oneof=pass fail needs_attention"`
+	Note      *string         `json:"note"`
+	PhotoPath *string         `json:"photo_path" validate:"omitempty,max=255"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// CustomerVehicle represents the customer_vehicles table
+type CustomerVehicle struct {
+	ID            int       `json:"id" db:"id"`
+	CustomerID    int       `json:"customer_id" db:"customer_id"`
+	BrandID       int       `json:"brand_id" db:"brand_id"`
+	Model         string    `json:"model" db:"model"`
+	Year          *int      `json:"year" db:"year"`
+	Color         *string   `json:"color" db:"color"`
+	LicensePlate  string    `json:"license_plate" db:"license_plate"`
+	ChassisNumber *string   `json:"chassis_number" db:"chassis_number"`
+	EngineNumber  *string   `json:"engine_number" db:"engine_number"`
+	Odometer      int       `json:"odometer" db:"odometer"`
+	Notes         *string   `json:"notes" db:"notes"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	// Additional fields for joined queries
+	BrandName     string `json:"brand_name" db:"brand_name"`
+	VehicleTypeID int    `json:"vehicle_type_id" db:"vehicle_type_id"`
+	CustomerName  string `json:"customer_name" db:"customer_name"`
+}
+
+// CustomerVehicleCreateRequest for registering a customer-owned vehicle
+type CustomerVehicleCreateRequest struct {
+	BrandID       int     `json:"brand_id" validate:"required"`
+	Model         string  `json:"model" validate:"required,max=100"`
+	Year          *int    `json:"year" validate:"omitempty,min=1900"`
+	Color         *string `json:"color" validate:"omitempty,max=50"`
+	LicensePlate  string  `json:"license_plate" validate:"required,max=20"`
+	ChassisNumber *string `json:"chassis_number" validate:"omitempty,max=100"`
+	EngineNumber  *string `json:"engine_number" validate:"omitempty,max=100"`
+	Odometer      int     `json:"odometer" validate:"min=0"`
+	Notes         *string `json:"notes"`
+}
+
+// CustomerVehicleUpdateRequest for updating a customer-owned vehicle
+type CustomerVehicleUpdateRequest struct {
+	BrandID       *int    `json:"brand_id"`
+	Model         *string `json:"model" validate:"omitempty,max=100"`
+	Year          *int    `json:"year" validate:"omitempty,min=1900"`
+	Color         *string `json:"color" validate:"omitempty,max=50"`
+	LicensePlate  *string `json:"license_plate" validate:"omitempty,max=20"`
+	ChassisNumber *string `json:"chassis_number" validate:"omitempty,max=100"`
+	EngineNumber  *string `json:"engine_number" validate:"omitempty,max=100"`
+	Odometer      *int    `json:"odometer" validate:"omitempty,min=0"`
+	Notes         *string `json:"notes"`
+}
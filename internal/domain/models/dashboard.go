@@ -69,6 +69,8 @@ type DashboardResponse struct {
 	Overview          DashboardMetric      `json:"overview"`
 	RecentTransactions []interface{}       `json:"recent_transactions"`
 	PendingRepairs    []RepairOrder       `json:"pending_repairs"`
+	OverdueRepairs    int                 `json:"overdue_repairs"`
+	AtRiskRepairs     int                 `json:"at_risk_repairs"`
 	LowStockItems     []SparePart         `json:"low_stock_items"`
 	AvailableVehicles []Vehicle           `json:"available_vehicles"`
 }
@@ -90,6 +92,8 @@ type CashierDashboardResponse struct {
 // MechanicDashboardResponse for mechanic specific dashboard
 type MechanicDashboardResponse struct {
 	AssignedRepairs  []RepairOrder `json:"assigned_repairs"`
+	OverdueRepairs   int           `json:"overdue_repairs"`
+	AtRiskRepairs    int           `json:"at_risk_repairs"`
 	CompletedToday   []RepairOrder `json:"completed_today"`
 	RequiredParts    []SparePart   `json:"required_parts"`
 }
\ No newline at end of file
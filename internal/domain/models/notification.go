@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// Notification types
+const (
+	NotificationRepairScheduled   = "repair_scheduled"
+	NotificationRepairRescheduled = "repair_rescheduled"
+	NotificationRepairUnscheduled = "repair_unscheduled"
+	NotificationEstimateDecided   = "estimate_decided"
+	NotificationRepairOverdue     = "repair_overdue"
+	NotificationRegistrationDue   = "registration_due"
+)
+
+// Notification represents the notifications table
+type Notification struct {
+	ID            int        `json:"id" db:"id"`
+	UserID        int        `json:"user_id" db:"user_id"`
+	Type          string     `json:"type" db:"type"`
+	Title         string     `json:"title" db:"title"`
+	Message       string     `json:"message" db:"message"`
+	ReferenceType *string    `json:"reference_type" db:"reference_type"`
+	ReferenceID   *int       `json:"reference_id" db:"reference_id"`
+	IsRead        bool       `json:"is_read" db:"is_read"`
+	ReadAt        *time.Time `json:"read_at" db:"read_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
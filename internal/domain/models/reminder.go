@@ -0,0 +1,134 @@
+package models
+
+import (
+	"time"
+)
+
+// ReminderTrigger enum
+type ReminderTrigger string
+
+const (
+	ReminderTriggerTime           ReminderTrigger = "time"     // months since the last service
+	ReminderTriggerOdometer       ReminderTrigger = "odometer" // estimated km since the last service
+	ReminderTriggerWarrantyExpiry ReminderTrigger = "warranty_expiry"
+)
+
+// ReminderStatus enum
+type ReminderStatus string
+
+const (
+	ReminderStatusPending ReminderStatus = "pending"
+	ReminderStatusSent    ReminderStatus = "sent"
+	ReminderStatusFailed  ReminderStatus = "failed"
+)
+
+// ReminderOutcome enum
+type ReminderOutcome string
+
+const (
+	ReminderOutcomeBooked      ReminderOutcome = "booked"
+	ReminderOutcomeDeclined    ReminderOutcome = "declined"
+	ReminderOutcomeUnreachable ReminderOutcome = "unreachable"
+)
+
+// ServiceReminderRule represents the service_reminder_rules table
+type ServiceReminderRule struct {
+	ID             int             `json:"id" db:"id"`
+	Name           string          `json:"name" db:"name"`
+	TriggerType    ReminderTrigger `json:"trigger_type" db:"trigger_type"`
+	IntervalMonths *int            `json:"interval_months" db:"interval_months"`
+	IntervalKm     *int            `json:"interval_km" db:"interval_km"`
+	AvgKmPerMonth  int             `json:"avg_km_per_month" db:"avg_km_per_month"`
+	DaysBefore     int             `json:"days_before" db:"days_before"`
+	ServiceID      *int            `json:"service_id" db:"service_id"`
+	Message        string          `json:"message" db:"message"`
+	IsActive       bool            `json:"is_active" db:"is_active"`
+	CreatedBy      int             `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+	// Additional fields for joined queries
+	ServiceName *string `json:"service_name" db:"service_name"`
+}
+
+// ServiceReminderRuleCreateRequest for creating a reminder rule.
+// Message placeholders: {customer}, {vehicle}, {plate}, {due_date}, {due_odometer}.
+type ServiceReminderRuleCreateRequest struct {
+	Name           string          `json:"name" validate:"required,max=100"`
+	TriggerType    ReminderTrigger `json:"trigger_type" validate:"required,oneof=time odometer warranty_expiry"`
+	IntervalMonths *int            `json:"interval_months" validate:"omitempty,min=1"`
+	IntervalKm     *int            `json:"interval_km" validate:"omitempty,min=1"`
+	AvgKmPerMonth  int             `json:"avg_km_per_month" validate:"omitempty,min=1"`
+	DaysBefore     int             `json:"days_before" validate:"min=0"`
+	ServiceID      *int            `json:"service_id"`
+	Message        string          `json:"message" validate:"required"`
+}
+
+// ServiceReminderRuleUpdateRequest for updating a reminder rule
+type ServiceReminderRuleUpdateRequest struct {
+	Name           *string `json:"name" validate:"omitempty,max=100"`
+	IntervalMonths *int    `json:"interval_months" validate:"omitempty,min=1"`
+	IntervalKm     *int    `json:"interval_km" validate:"omitempty,min=1"`
+	AvgKmPerMonth  *int    `json:"avg_km_per_month" validate:"omitempty,min=1"`
+	DaysBefore     *int    `json:"days_before" validate:"omitempty,min=0"`
+	ServiceID      *int    `json:"service_id"` // 0 clears the suggested operation
+	Message        *string `json:"message"`
+	IsActive       *bool   `json:"is_active"`
+}
+
+// ServiceReminder represents the service_reminders table
+type ServiceReminder struct {
+	ID                int              `json:"id" db:"id"`
+	RuleID            int              `json:"rule_id" db:"rule_id"`
+	CustomerID        int              `json:"customer_id" db:"customer_id"`
+	CustomerVehicleID *int             `json:"customer_vehicle_id" db:"customer_vehicle_id"`
+	SalesWarrantyID   *int             `json:"sales_warranty_id" db:"sales_warranty_id"`
+	DueDate           time.Time        `json:"due_date" db:"due_date"`
+	DueOdometer       *int             `json:"due_odometer" db:"due_odometer"`
+	EstimatedOdometer *int             `json:"estimated_odometer" db:"estimated_odometer"`
+	Message           string           `json:"message" db:"message"`
+	Status            ReminderStatus   `json:"status" db:"status"`
+	Channel           *string          `json:"channel" db:"channel"`
+	Attempts          int              `json:"attempts" db:"attempts"`
+	LastError         *string          `json:"last_error" db:"last_error"`
+	SentAt            *time.Time       `json:"sent_at" db:"sent_at"`
+	Outcome           *ReminderOutcome `json:"outcome" db:"outcome"`
+	OutcomeNotes      *string          `json:"outcome_notes" db:"outcome_notes"`
+	AppointmentID     *int             `json:"appointment_id" db:"appointment_id"`
+	ContactedBy       *int             `json:"contacted_by" db:"contacted_by"`
+	ContactedAt       *time.Time       `json:"contacted_at" db:"contacted_at"`
+	CreatedAt         time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at" db:"updated_at"`
+	// Additional fields for joined queries
+	RuleName      string          `json:"rule_name" db:"rule_name"`
+	TriggerType   ReminderTrigger `json:"trigger_type" db:"trigger_type"`
+	CustomerName  string          `json:"customer_name" db:"customer_name"`
+	CustomerPhone *string         `json:"customer_phone" db:"customer_phone"`
+	CustomerEmail *string         `json:"customer_email" db:"customer_email"`
+	LicensePlate  *string         `json:"license_plate" db:"license_plate"`
+	VehicleModel  string          `json:"vehicle_model" db:"vehicle_model"`
+}
+
+// ServiceReminderOutcomeRequest records the result of contacting the customer
+type ServiceReminderOutcomeRequest struct {
+	Outcome       ReminderOutcome `json:"outcome" validate:"required,oneof=booked declined unreachable"`
+	AppointmentID *int            `json:"appointment_id"` // booking made from the reminder
+	Notes         *string         `json:"notes"`
+}
+
+// ServiceReminderFilter for filtering reminders
+type ServiceReminderFilter struct {
+	Status      ReminderStatus  `form:"status"`
+	Outcome     ReminderOutcome `form:"outcome"`
+	NoOutcome   bool            `form:"no_outcome"` // reminders still to follow up
+	RuleID      int             `form:"rule_id"`
+	CustomerID  int             `form:"customer_id"`
+	DueDateFrom string          `form:"due_date_from"` // YYYY-MM-DD
+	DueDateTo   string          `form:"due_date_to"`
+}
+
+// ServiceReminderRunResult summarises a reminder job run
+type ServiceReminderRunResult struct {
+	Generated int `json:"generated"`
+	Sent      int `json:"sent"`
+	Failed    int `json:"failed"`
+}
@@ -8,69 +8,214 @@ import (
 type RepairStatus string
 
 const (
-	RepairStatusPending    RepairStatus = "pending"
-	RepairStatusInProgress RepairStatus = "in_progress"
-	RepairStatusCompleted  RepairStatus = "completed"
-	RepairStatusCancelled  RepairStatus = "cancelled"
+	RepairStatusPending      RepairStatus = "pending"
+	RepairStatusApproved     RepairStatus = "approved" // customer approved the estimate, work not started
+	RepairStatusInProgress   RepairStatus = "in_progress"
+	RepairStatusWaitingParts RepairStatus = "waiting_parts"
+	RepairStatusQualityCheck RepairStatus = "quality_check"
+	RepairStatusOnHold       RepairStatus = "on_hold"
+	RepairStatusCompleted    RepairStatus = "completed"
+	RepairStatusCancelled    RepairStatus = "cancelled"
+)
+
+// RepairOrderType enum
+type RepairOrderType string
+
+const (
+	RepairOrderTypeReconditioning  RepairOrderType = "reconditioning"   // showroom inventory
+	RepairOrderTypeCustomerService RepairOrderType = "customer_service" // customer-owned vehicle
+)
+
+// EstimateStatus enum
+type EstimateStatus string
+
+const (
+	EstimateStatusPending  EstimateStatus = "pending"
+	EstimateStatusApproved EstimateStatus = "approved"
+	EstimateStatusRejected EstimateStatus = "rejected"
+)
+
+// RepairSLAStatus flags an open repair order against its target completion date
+type RepairSLAStatus string
+
+const (
+	RepairSLAOnTrack RepairSLAStatus = "on_track"
+	RepairSLAAtRisk  RepairSLAStatus = "at_risk"
+	RepairSLAOverdue RepairSLAStatus = "overdue"
+)
+
+// PartDisposition enum, what happens to a used part when its repair is cancelled
+type PartDisposition string
+
+const (
+	PartDispositionReturnToStock PartDisposition = "return_to_stock"
+	PartDispositionScrap         PartDisposition = "scrap"
+	PartDispositionKeepAsCost    PartDisposition = "keep_as_cost"
 )
 
 // RepairOrder represents the repair_orders table
 type RepairOrder struct {
-	ID            int          `json:"id" db:"id"`
-	Code          string       `json:"code" db:"code" validate:"required,max=50"`
-	VehicleID     int          `json:"vehicle_id" db:"vehicle_id" validate:"required"`
-	MechanicID    int          `json:"mechanic_id" db:"mechanic_id" validate:"required"`
-	AssignedBy    int          `json:"assigned_by" db:"assigned_by" validate:"required"`
-	Description   *string      `json:"description" db:"description"`
-	EstimatedCost float64      `json:"estimated_cost" db:"estimated_cost" validate:"min=0"`
-	ActualCost    float64      `json:"actual_cost" db:"actual_cost" validate:"min=0"`
-	Status        RepairStatus `json:"status" db:"status"`
-	StartedAt     *time.Time   `json:"started_at" db:"started_at"`
-	CompletedAt   *time.Time   `json:"completed_at" db:"completed_at"`
-	Notes         *string      `json:"notes" db:"notes"`
-	CreatedAt     time.Time    `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time    `json:"updated_at" db:"updated_at"`
+	ID                   int             `json:"id" db:"id"`
+	Code                 string          `json:"code" db:"code" validate:"required,max=50"`
+	OrderType            RepairOrderType `json:"order_type" db:"order_type"`
+	VehicleID            *int            `json:"vehicle_id" db:"vehicle_id"`
+	CustomerID           *int            `json:"customer_id" db:"customer_id"`
+	CustomerVehicleID    *int            `json:"customer_vehicle_id" db:"customer_vehicle_id"`
+	MechanicID           int             `json:"mechanic_id" db:"mechanic_id" validate:"required"`
+	AssignedBy           int             `json:"assigned_by" db:"assigned_by" validate:"required"`
+	Description          *string         `json:"description" db:"description"`
+	EstimatedCost        float64         `json:"estimated_cost" db:"estimated_cost" validate:"min=0"`
+	ActualCost           float64         `json:"actual_cost" db:"actual_cost" validate:"min=0"`
+	ActualHours          float64         `json:"actual_hours" db:"actual_hours"`
+	Status               RepairStatus    `json:"status" db:"status"`
+	StartedAt            *time.Time      `json:"started_at" db:"started_at"`
+	CompletedAt          *time.Time      `json:"completed_at" db:"completed_at"`
+	Notes                *string         `json:"notes" db:"notes"`
+	EstimateStatus       *EstimateStatus `json:"estimate_status" db:"estimate_status"`
+	EstimateDecidedAt    *time.Time      `json:"estimate_decided_at" db:"estimate_decided_at"`
+	EstimateDecidedBy    *string         `json:"estimate_decided_by" db:"estimate_decided_by"`
+	TargetCompletionDate *time.Time      `json:"target_completion_date" db:"target_completion_date"`
+	SLAAlertedAt         *time.Time      `json:"-" db:"sla_alerted_at"`
+	IsWarranty           bool            `json:"is_warranty" db:"is_warranty"` // after-sales warranty work, not invoiced
+	CreatedAt            time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time       `json:"updated_at" db:"updated_at"`
 	// Additional fields for joined queries
 	Brand        *string `json:"brand,omitempty" db:"brand"`
 	TypeName     *string `json:"type_name,omitempty" db:"type_name"`
 	LicensePlate *string `json:"license_plate,omitempty" db:"license_plate"`
 	MechanicName *string `json:"mechanic_name,omitempty" db:"mechanic_name"`
+	// Computed against the target completion date, empty once the order is closed
+	SLAStatus RepairSLAStatus `json:"sla_status,omitempty" db:"-"`
 	// Relationships
-	Vehicle    *Vehicle          `json:"vehicle,omitempty"`
-	Mechanic   *User             `json:"mechanic,omitempty"`
-	Assigner   *User             `json:"assigner,omitempty"`
-	SpareParts []RepairSparePart `json:"spare_parts,omitempty"`
+	Vehicle         *Vehicle               `json:"vehicle,omitempty"`
+	Customer        *Customer              `json:"customer,omitempty"`
+	CustomerVehicle *CustomerVehicle       `json:"customer_vehicle,omitempty"`
+	Mechanic        *User                  `json:"mechanic,omitempty"`
+	Assigner        *User                  `json:"assigner,omitempty"`
+	SpareParts      []RepairSparePart      `json:"spare_parts,omitempty"`
+	LaborLines      []RepairLaborLine      `json:"labor_lines,omitempty"`
+	Checklist       []RepairChecklistItem  `json:"checklist,omitempty"`
+	Attachments     []RepairAttachment     `json:"attachments,omitempty"`
+	Reservations    []SparePartReservation `json:"reservations,omitempty"`
+	Schedule        *RepairSchedule        `json:"schedule,omitempty"`
+	StatusHistory   []RepairStatusHistory  `json:"status_history,omitempty"`
+	// Assignment warnings, e.g. missing mechanic skills
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // RepairSparePart represents the repair_spare_parts table
 type RepairSparePart struct {
-	ID            int        `json:"id" db:"id"`
-	RepairOrderID int        `json:"repair_order_id" db:"repair_order_id" validate:"required"`
-	SparePartID   int        `json:"spare_part_id" db:"spare_part_id" validate:"required"`
-	QuantityUsed  int        `json:"quantity_used" db:"quantity_used" validate:"required,min=1"`
-	UnitPrice     float64    `json:"unit_price" db:"unit_price" validate:"required,min=0"`
-	TotalPrice    float64    `json:"total_price" db:"total_price" validate:"required,min=0"`
-	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
-	SparePart     *SparePart `json:"spare_part,omitempty"`
+	ID            int              `json:"id" db:"id"`
+	RepairOrderID int              `json:"repair_order_id" db:"repair_order_id" validate:"required"`
+	SparePartID   int              `json:"spare_part_id" db:"spare_part_id" validate:"required"`
+	QuantityUsed  int              `json:"quantity_used" db:"quantity_used" validate:"required,min=1"`
+	UnitPrice     float64          `json:"unit_price" db:"unit_price" validate:"required,min=0"`
+	TotalPrice    float64          `json:"total_price" db:"total_price" validate:"required,min=0"`
+	Disposition   *PartDisposition `json:"disposition" db:"disposition"`
+	DisposedAt    *time.Time       `json:"disposed_at" db:"disposed_at"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+	SparePart     *SparePart       `json:"spare_part,omitempty"`
+}
+
+// RepairLaborLine represents the repair_labor_lines table
+type RepairLaborLine struct {
+	ID            int             `json:"id" db:"id"`
+	RepairOrderID int             `json:"repair_order_id" db:"repair_order_id" validate:"required"`
+	ServiceID     *int            `json:"service_id" db:"service_id"`
+	Description   string          `json:"description" db:"description" validate:"required,max=255"`
+	Hours         float64         `json:"hours" db:"hours" validate:"required,gt=0"`
+	LaborRate     float64         `json:"labor_rate" db:"labor_rate" validate:"min=0"`
+	TotalPrice    float64         `json:"total_price" db:"total_price" validate:"min=0"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	Service       *ServiceCatalog `json:"service,omitempty"`
 }
 
 // RepairOrderCreateRequest for creating new repair order
 type RepairOrderCreateRequest struct {
 	Code          string  `json:"code" validate:"required,max=50"`
 	VehicleID     int     `json:"vehicle_id" validate:"required"`
-	MechanicID    int     `json:"mechanic_id" validate:"required"`
+	MechanicID    int     `json:"mechanic_id"` // omit to auto-assign the least-loaded mechanic
 	Description   *string `json:"description"`
 	EstimatedCost float64 `json:"estimated_cost" validate:"min=0"`
 	Notes         *string `json:"notes"`
+	// Service catalog categories the job needs, used for the mechanic skill check
+	ServiceCategories []string `json:"service_categories"`
+	// YYYY-MM-DD; defaults from the service catalog or vehicle type
+	TargetCompletionDate *string `json:"target_completion_date"`
+}
+
+// ServiceOrderCreateRequest for creating a repair order on a customer-owned vehicle
+type ServiceOrderCreateRequest struct {
+	CustomerVehicleID int     `json:"customer_vehicle_id" validate:"required"`
+	MechanicID        int     `json:"mechanic_id"` // omit to auto-assign the least-loaded mechanic
+	Description       *string `json:"description"`
+	EstimatedCost     float64 `json:"estimated_cost" validate:"min=0"`
+	Notes             *string `json:"notes"`
+	// Service catalog categories the job needs, used for the mechanic skill check
+	ServiceCategories []string `json:"service_categories"`
+	// YYYY-MM-DD; defaults from the service catalog or vehicle type
+	TargetCompletionDate *string `json:"target_completion_date"`
+}
+
+// EstimateDecisionRequest records the customer's answer to a service estimate
+type EstimateDecisionRequest struct {
+	Approved  bool   `json:"approved"`
+	DecidedBy string `json:"decided_by" validate:"required,max=150"`
+}
+
+// RepairStatusHistory represents the repair_status_history table
+type RepairStatusHistory struct {
+	ID            int           `json:"id" db:"id"`
+	RepairOrderID int           `json:"repair_order_id" db:"repair_order_id"`
+	FromStatus    *RepairStatus `json:"from_status" db:"from_status"`
+	ToStatus      RepairStatus  `json:"to_status" db:"to_status"`
+	ChangedBy     *int          `json:"changed_by" db:"changed_by"`
+	ActorName     *string       `json:"actor_name" db:"actor_name"` // set when the actor is not a user, e.g. the customer
+	Note          *string       `json:"note" db:"note"`
+	ChangedAt     time.Time     `json:"changed_at" db:"changed_at"`
+	// Additional fields for joined queries
+	ChangedByName *string `json:"changed_by_name,omitempty" db:"changed_by_name"`
+}
+
+// RepairStatusChange describes who changes a repair status and why
+type RepairStatusChange struct {
+	ChangedBy *int
+	ActorName *string
+	Note      *string
+}
+
+// RepairStatusTransition represents the repair_status_transitions table
+type RepairStatusTransition struct {
+	FromStatus RepairStatus `json:"from_status" db:"from_status" validate:"required"`
+	ToStatus   RepairStatus `json:"to_status" db:"to_status" validate:"required"`
+}
+
+// RepairStatusTransitionsUpdateRequest replaces the whole transition map
+type RepairStatusTransitionsUpdateRequest struct {
+	Transitions []RepairStatusTransition `json:"transitions" validate:"required,min=1,dive"`
+}
+
+// RepairEstimateLink represents the repair_estimate_links table
+type RepairEstimateLink struct {
+	ID            int       `json:"id" db:"id"`
+	RepairOrderID int       `json:"repair_order_id" db:"repair_order_id"`
+	Token         string    `json:"token" db:"token"`
+	ExpiresAt     time.Time `json:"expires_at" db:"expires_at"`
+	CreatedBy     int       `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	// Public path of the read-only estimate
+	URL string `json:"url" db:"-"`
 }
 
 // RepairOrderUpdateRequest for updating repair order
 type RepairOrderUpdateRequest struct {
-	Description   *string       `json:"description"`
-	EstimatedCost *float64      `json:"estimated_cost" validate:"omitempty,min=0"`
-	ActualCost    *float64      `json:"actual_cost" validate:"omitempty,min=0"`
-	Status        *RepairStatus `json:"status"`
-	Notes         *string       `json:"notes"`
+	Description          *string       `json:"description"`
+	EstimatedCost        *float64      `json:"estimated_cost" validate:"omitempty,min=0"`
+	ActualCost           *float64      `json:"actual_cost" validate:"omitempty,min=0"`
+	Status               *RepairStatus `json:"status"`
+	Notes                *string       `json:"notes"`
+	Reason               *string       `json:"reason"`                 // recorded in the status history
+	TargetCompletionDate *string       `json:"target_completion_date"` // YYYY-MM-DD
 }
 
 // RepairSparePartCreateRequest for adding spare part to repair order
@@ -79,19 +224,102 @@ type RepairSparePartCreateRequest struct {
 	QuantityUsed int `json:"quantity_used" validate:"required,min=1"`
 }
 
+// RepairLaborLineCreateRequest for adding labor to repair order.
+// When ServiceID is set, missing fields default to the catalog entry;
+// otherwise description, hours and labor rate must be entered manually.
+type RepairLaborLineCreateRequest struct {
+	ServiceID   *int     `json:"service_id"`
+	Description *string  `json:"description" validate:"omitempty,max=255"`
+	Hours       *float64 `json:"hours" validate:"omitempty,gt=0"`
+	LaborRate   *float64 `json:"labor_rate" validate:"omitempty,min=0"`
+}
+
 // RepairProgressUpdateRequest for updating repair progress
 type RepairProgressUpdateRequest struct {
 	Status     RepairStatus                   `json:"status" validate:"required"`
 	ActualCost *float64                       `json:"actual_cost" validate:"omitempty,min=0"`
 	Notes      *string                        `json:"notes"`
+	Reason     *string                        `json:"reason"` // recorded in the status history, e.g. the part being waited for
 	SpareParts []RepairSparePartCreateRequest `json:"spare_parts,omitempty"`
 }
 
+// RepairCancelRequest cancels a repair order; part lines that are not listed are returned to stock
+type RepairCancelRequest struct {
+	Reason *string                        `json:"reason"`
+	Parts  []RepairPartDispositionRequest `json:"parts" validate:"omitempty,dive"`
+}
+
+// RepairPartDispositionRequest chooses what happens to one used part line
+type RepairPartDispositionRequest struct {
+	RepairSparePartID int             `json:"repair_spare_part_id" validate:"required"`
+	Disposition       PartDisposition `json:"disposition" validate:"required,oneof=return_to_stock scrap keep_as_cost"`
+}
+
+// MechanicWorkload holds the open work currently assigned to a mechanic
+type MechanicWorkload struct {
+	MechanicID              int     `json:"mechanic_id" db:"mechanic_id"`
+	MechanicName            string  `json:"mechanic_name" db:"mechanic_name"`
+	OpenJobs                int     `json:"open_jobs" db:"open_jobs"`
+	PendingJobs             int     `json:"pending_jobs" db:"pending_jobs"`
+	InProgressJobs          int     `json:"in_progress_jobs" db:"in_progress_jobs"`
+	EstimatedHoursRemaining float64 `json:"estimated_hours_remaining" db:"estimated_hours_remaining"`
+	OverdueJobs             int     `json:"overdue_jobs" db:"overdue_jobs"`
+}
+
+// RepairTurnaround summarises the completed repairs of one mechanic or vehicle type
+type RepairTurnaround struct {
+	GroupID           int     `json:"group_id" db:"group_id"`
+	GroupName         string  `json:"group_name" db:"group_name"`
+	CompletedJobs     int     `json:"completed_jobs" db:"completed_jobs"`
+	AvgTurnaroundDays float64 `json:"avg_turnaround_days" db:"avg_turnaround_days"`
+	OnTimeJobs        int     `json:"on_time_jobs" db:"on_time_jobs"`
+	LateJobs          int     `json:"late_jobs" db:"late_jobs"`
+	OnTimePercent     float64 `json:"on_time_percent" db:"on_time_percent"`
+}
+
+// RepairTurnaroundReport holds the average turnaround per mechanic and per vehicle type
+type RepairTurnaroundReport struct {
+	DateFrom      string             `json:"date_from"`
+	DateTo        string             `json:"date_to"`
+	ByMechanic    []RepairTurnaround `json:"by_mechanic"`
+	ByVehicleType []RepairTurnaround `json:"by_vehicle_type"`
+}
+
 // RepairOrderFilter for filtering repair orders
 type RepairOrderFilter struct {
-	Status     RepairStatus `form:"status"`
-	MechanicID int          `form:"mechanic_id"`
-	VehicleID  int          `form:"vehicle_id"`
-	DateFrom   string       `form:"date_from"`
-	DateTo     string       `form:"date_to"`
+	Status     RepairStatus    `form:"status"`
+	OrderType  RepairOrderType `form:"order_type"`
+	CustomerID int             `form:"customer_id"`
+	MechanicID int             `form:"mechanic_id"`
+	VehicleID  int             `form:"vehicle_id"`
+	Overdue    bool            `form:"overdue"`  // open orders past their target completion date
+	Warranty   bool            `form:"warranty"` // only after-sales warranty orders
+	DateFrom   string          `form:"date_from"`
+	DateTo     string          `form:"date_to"`
+}
+
+// VehicleRepairNeedFilter narrows the vehicles that still need a repair order
+type VehicleRepairNeedFilter struct {
+	BrandID int `form:"brand_id"`
+	TypeID  int `form:"type_id"`
+}
+
+// RepairTrackingStep is one stage of the public repair timeline
+type RepairTrackingStep struct {
+	Stage     string     `json:"stage"` // received, approved, in_progress, waiting_parts, quality_check, done or cancelled
+	Label     string     `json:"label"`
+	Reached   bool       `json:"reached"`
+	ReachedAt *time.Time `json:"reached_at"`
+}
+
+// RepairTracking is the customer-facing status of a repair order; it never carries costs
+type RepairTracking struct {
+	Code               string               `json:"code"`
+	Vehicle            string               `json:"vehicle"`
+	LicensePlate       string               `json:"license_plate"`
+	Status             string               `json:"status"`
+	StatusLabel        string               `json:"status_label"`
+	ExpectedCompletion *time.Time           `json:"expected_completion,omitempty"`
+	Timeline           []RepairTrackingStep `json:"timeline"`
+	UpdatedAt          time.Time            `json:"updated_at"`
 }
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// RepairAttachmentStage enum
+type RepairAttachmentStage string
+
+const (
+	RepairAttachmentStageBefore   RepairAttachmentStage = "before"
+	RepairAttachmentStageDuring   RepairAttachmentStage = "during"
+	RepairAttachmentStageAfter    RepairAttachmentStage = "after"
+	RepairAttachmentStageDocument RepairAttachmentStage = "document"
+)
+
+// RepairAttachment represents the repair_attachments table
+type RepairAttachment struct {
+	ID            int                   `json:"id" db:"id"`
+	RepairOrderID int                   `json:"repair_order_id" db:"repair_order_id"`
+	Stage         RepairAttachmentStage `json:"stage" db:"stage"`
+	FilePath      string                `json:"file_path" db:"file_path"`
+	ThumbnailPath *string               `json:"thumbnail_path" db:"thumbnail_path"`
+	Caption       *string               `json:"caption" db:"caption"`
+	FileName      string                `json:"file_name" db:"file_name"`
+	ContentType   string                `json:"content_type" db:"content_type"`
+	FileSize      int64                 `json:"file_size" db:"file_size"`
+	UploadedBy    int                   `json:"uploaded_by" db:"uploaded_by"`
+	CreatedAt     time.Time             `json:"created_at" db:"created_at"`
+	// Download links, filled by the service
+	URL          string  `json:"url" db:"-"`
+	ThumbnailURL *string `json:"thumbnail_url,omitempty" db:"-"`
+}
+
+// RepairAttachmentUploadRequest holds the form fields sent with an uploaded file
+type RepairAttachmentUploadRequest struct {
+	Stage   RepairAttachmentStage `form:"stage" validate:"required,oneof=before during after document"`
+	Caption *string               `form:"caption" validate:"omitempty,max=255"`
+}
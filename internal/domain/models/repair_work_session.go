@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+)
+
+// WorkSessionEndReason enum
+type WorkSessionEndReason string
+
+const (
+	WorkSessionPaused  WorkSessionEndReason = "paused"
+	WorkSessionStopped WorkSessionEndReason = "stopped"
+	WorkSessionClosed  WorkSessionEndReason = "closed" // ended automatically when the repair was completed or cancelled
+)
+
+// TimerStatus enum
+type TimerStatus string
+
+const (
+	TimerStatusIdle    TimerStatus = "idle"
+	TimerStatusRunning TimerStatus = "running"
+	TimerStatusPaused  TimerStatus = "paused"
+	TimerStatusStopped TimerStatus = "stopped"
+)
+
+// RepairWorkSession represents the repair_work_sessions table
+type RepairWorkSession struct {
+	ID            int                   `json:"id" db:"id"`
+	RepairOrderID int                   `json:"repair_order_id" db:"repair_order_id"`
+	MechanicID    int                   `json:"mechanic_id" db:"mechanic_id"`
+	StartedAt     time.Time             `json:"started_at" db:"started_at"`
+	EndedAt       *time.Time            `json:"ended_at" db:"ended_at"`
+	EndReason     *WorkSessionEndReason `json:"end_reason" db:"end_reason"`
+	Notes         *string               `json:"notes" db:"notes"`
+	CreatedAt     time.Time             `json:"created_at" db:"created_at"`
+	// Calculated fields
+	DurationHours float64 `json:"duration_hours" db:"duration_hours"`
+	MechanicName  *string `json:"mechanic_name,omitempty" db:"mechanic_name"`
+}
+
+// RepairTimerRequest for starting, pausing or stopping a repair timer
+type RepairTimerRequest struct {
+	Notes *string `json:"notes"`
+}
+
+// RepairTimeSummary compares time spent on a repair against catalog standard hours
+type RepairTimeSummary struct {
+	RepairOrderID     int                 `json:"repair_order_id"`
+	TimerStatus       TimerStatus         `json:"timer_status"`
+	ActualHours       float64             `json:"actual_hours"`
+	StandardHours     float64             `json:"standard_hours"`
+	VarianceHours     float64             `json:"variance_hours"`
+	EfficiencyPercent float64             `json:"efficiency_percent"`
+	Sessions          []RepairWorkSession `json:"sessions"`
+}
+
+// MechanicUtilization holds time tracking figures for one mechanic over a period
+type MechanicUtilization struct {
+	MechanicID         int     `json:"mechanic_id" db:"mechanic_id"`
+	MechanicName       string  `json:"mechanic_name" db:"mechanic_name"`
+	LoggedHours        float64 `json:"logged_hours" db:"logged_hours"`
+	AvailableHours     float64 `json:"available_hours" db:"available_hours"`
+	UtilizationPercent float64 `json:"utilization_percent" db:"utilization_percent"`
+	CompletedJobs      int     `json:"completed_jobs" db:"completed_jobs"`
+	StandardHours      float64 `json:"standard_hours" db:"standard_hours"`
+	ActualHours        float64 `json:"actual_hours" db:"actual_hours"`
+	EfficiencyPercent  float64 `json:"efficiency_percent" db:"efficiency_percent"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+)
+
+// ServiceCatalog represents the service_catalog table
+type ServiceCatalog struct {
+	ID            int       `json:"id" db:"id"`
+	Code          string    `json:"code" db:"code" validate:"required,max=50"`
+	Name          string    `json:"name" db:"name" validate:"required,max=150"`
+	Description   *string   `json:"description" db:"description"`
+	Category      string    `json:"category" db:"category" validate:"required,max=50"`
+	VehicleTypeID *int      `json:"vehicle_type_id" db:"vehicle_type_id"`
+	StandardHours float64   `json:"standard_hours" db:"standard_hours" validate:"min=0"`
+	LaborRate     float64   `json:"labor_rate" db:"labor_rate" validate:"min=0"`
+	SLADays       *int      `json:"sla_days" db:"sla_days"` // target turnaround of jobs needing this operation
+	IsActive      bool      `json:"is_active" db:"is_active"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	// Additional fields for joined queries
+	VehicleTypeName *string `json:"vehicle_type_name,omitempty" db:"vehicle_type_name"`
+}
+
+// ServiceCatalogCreateRequest for creating new catalog entry
+type ServiceCatalogCreateRequest struct {
+	Code          string  `json:"code" validate:"required,max=50"`
+	Name          string  `json:"name" validate:"required,max=150"`
+	Description   *string `json:"description"`
+	Category      string  `json:"category" validate:"required,max=50"`
+	VehicleTypeID *int    `json:"vehicle_type_id"`
+	StandardHours float64 `json:"standard_hours" validate:"min=0"`
+	LaborRate     float64 `json:"labor_rate" validate:"min=0"`
+	SLADays       *int    `json:"sla_days" validate:"omitempty,min=1"`
+}
+
+// ServiceCatalogUpdateRequest for updating catalog entry
+type ServiceCatalogUpdateRequest struct {
+	Name          *string  `json:"name" validate:"omitempty,max=150"`
+	Description   *string  `json:"description"`
+	Category      *string  `json:"category" validate:"omitempty,max=50"`
+	VehicleTypeID *int     `json:"vehicle_type_id"`
+	StandardHours *float64 `json:"standard_hours" validate:"omitempty,min=0"`
+	LaborRate     *float64 `json:"labor_rate" validate:"omitempty,min=0"`
+	SLADays       *int     `json:"sla_days" validate:"omitempty,min=0"` // 0 clears the target
+	IsActive      *bool    `json:"is_active"`
+}
+
+// ServiceCatalogFilter for filtering catalog entries
+type ServiceCatalogFilter struct {
+	Search        string `form:"search"`
+	Category      string `form:"category"`
+	VehicleTypeID int    `form:"vehicle_type_id"`
+	IsActive      *bool  `form:"is_active"`
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"time"
+)
+
+// ServiceInvoice represents the service_invoices table
+type ServiceInvoice struct {
+	ID            int           `json:"id" db:"id"`
+	InvoiceNumber string        `json:"invoice_number" db:"invoice_number"`
+	RepairOrderID int           `json:"repair_order_id" db:"repair_order_id"`
+	CustomerID    int           `json:"customer_id" db:"customer_id"`
+	InvoiceDate   time.Time     `json:"invoice_date" db:"invoice_date"`
+	PartsTotal    float64       `json:"parts_total" db:"parts_total"`
+	LaborTotal    float64       `json:"labor_total" db:"labor_total"`
+	Discount      float64       `json:"discount" db:"discount"`
+	TotalAmount   float64       `json:"total_amount" db:"total_amount"`
+	PaidAmount    float64       `json:"paid_amount" db:"paid_amount"`
+	PaymentStatus PaymentStatus `json:"payment_status" db:"payment_status"`
+	Notes         *string       `json:"notes" db:"notes"`
+	ProcessedBy   int           `json:"processed_by" db:"processed_by"`
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at" db:"updated_at"`
+	// Additional fields for joined queries
+	RepairCode   string `json:"repair_code" db:"repair_code"`
+	CustomerName string `json:"customer_name" db:"customer_name"`
+	LicensePlate string `json:"license_plate" db:"license_plate"`
+	// Relationships
+	Items    []ServiceInvoiceItem `json:"items,omitempty"`
+	Payments []ServicePayment     `json:"payments,omitempty"`
+}
+
+// ServiceInvoiceItem represents the service_invoice_items table
+type ServiceInvoiceItem struct {
+	ID          int     `json:"id" db:"id"`
+	InvoiceID   int     `json:"invoice_id" db:"invoice_id"`
+	ItemType    string  `json:"item_type" db:"item_type"` // part or labor
+	Description string  `json:"description" db:"description"`
+	Quantity    float64 `json:"quantity" db:"quantity"`
+	UnitPrice   float64 `json:"unit_price" db:"unit_price"`
+	TotalPrice  float64 `json:"total_price" db:"total_price"`
+}
+
+// ServicePayment represents the service_payments table
+type ServicePayment struct {
+	ID            int       `json:"id" db:"id"`
+	InvoiceID     int       `json:"invoice_id" db:"invoice_id"`
+	Amount        float64   `json:"amount" db:"amount"`
+	PaymentMethod string    `json:"payment_method" db:"payment_method"`
+	Notes         *string   `json:"notes" db:"notes"`
+	ReceivedBy    int       `json:"received_by" db:"received_by"`
+	PaidAt        time.Time `json:"paid_at" db:"paid_at"`
+}
+
+// ServiceInvoiceCreateRequest for billing a completed service order
+type ServiceInvoiceCreateRequest struct {
+	Discount float64 `json:"discount" validate:"min=0"`
+	Notes    *string `json:"notes"`
+}
+
+// ServicePaymentCreateRequest for recording a payment on a service invoice
+type ServicePaymentCreateRequest struct {
+	Amount        float64 `json:"amount" validate:"required,gt=0"`
+	PaymentMethod string  `json:"payment_method" validate:"required,oneof=cash transfer card"`
+	Notes         *string `json:"notes"`
+}
+
+// ServiceInvoiceFilter for filtering service invoices
+type ServiceInvoiceFilter struct {
+	PaymentStatus PaymentStatus `form:"payment_status"`
+	CustomerID    int           `form:"customer_id"`
+	DateFrom      string        `form:"date_from"`
+	DateTo        string        `form:"date_to"`
+}
+
+// WorkshopServiceReport summarizes customer service work, kept apart from inventory reconditioning
+type WorkshopServiceReport struct {
+	DateFrom           string  `json:"date_from"`
+	DateTo             string  `json:"date_to"`
+	ServiceOrders      int     `json:"service_orders" db:"service_orders"`
+	CompletedOrders    int     `json:"completed_orders" db:"completed_orders"`
+	InvoiceCount       int     `json:"invoice_count" db:"invoice_count"`
+	PartsRevenue       float64 `json:"parts_revenue" db:"parts_revenue"`
+	LaborRevenue       float64 `json:"labor_revenue" db:"labor_revenue"`
+	Discounts          float64 `json:"discounts" db:"discounts"`
+	TotalInvoiced      float64 `json:"total_invoiced" db:"total_invoiced"`
+	TotalPaid          float64 `json:"total_paid" db:"total_paid"`
+	Outstanding        float64 `json:"outstanding" db:"outstanding"`
+	ReconditioningJobs int     `json:"reconditioning_jobs" db:"reconditioning_jobs"`
+	ReconditioningCost float64 `json:"reconditioning_cost" db:"reconditioning_cost"`
+	WarrantyJobs       int     `json:"warranty_jobs" db:"warranty_jobs"`
+	WarrantyCost       float64 `json:"warranty_cost" db:"warranty_cost"`
+}
+
+// ServiceEstimateLine is a part or labor line of a service estimate
+type ServiceEstimateLine struct {
+	ItemType    string  `json:"item_type"` // part or labor
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	TotalPrice  float64 `json:"total_price"`
+}
+
+// ServiceEstimate is the read-only estimate of a service order shown to the customer
+type ServiceEstimate struct {
+	RepairOrderID     int                   `json:"repair_order_id"`
+	RepairCode        string                `json:"repair_code"`
+	CustomerName      string                `json:"customer_name"`
+	VehicleModel      string                `json:"vehicle_model"`
+	LicensePlate      string                `json:"license_plate"`
+	Description       *string               `json:"description"`
+	Lines             []ServiceEstimateLine `json:"lines"`
+	PartsTotal        float64               `json:"parts_total"`
+	LaborTotal        float64               `json:"labor_total"`
+	Total             float64               `json:"total"`
+	EstimateStatus    *EstimateStatus       `json:"estimate_status"`
+	EstimateDecidedAt *time.Time            `json:"estimate_decided_at"`
+	EstimateDecidedBy *string               `json:"estimate_decided_by"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// SkillCheckMode controls what happens when a mechanic lacks a required skill
+type SkillCheckMode string
+
+const (
+	SkillCheckOff     SkillCheckMode = "off"
+	SkillCheckWarn    SkillCheckMode = "warn"
+	SkillCheckEnforce SkillCheckMode = "enforce"
+)
+
+// Skill represents the skills table
+type Skill struct {
+	ID              int       `json:"id" db:"id"`
+	Name            string    `json:"name" db:"name" validate:"required,max=100"`
+	Description     *string   `json:"description" db:"description"`
+	VehicleTypeID   *int      `json:"vehicle_type_id" db:"vehicle_type_id"`
+	ServiceCategory *string   `json:"service_category" db:"service_category" validate:"omitempty,max=50"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	// Additional fields for joined queries
+	VehicleTypeName *string `json:"vehicle_type_name,omitempty" db:"vehicle_type_name"`
+}
+
+// SkillCreateRequest for creating new skill
+type SkillCreateRequest struct {
+	Name            string  `json:"name" validate:"required,max=100"`
+	Description     *string `json:"description"`
+	VehicleTypeID   *int    `json:"vehicle_type_id"`
+	ServiceCategory *string `json:"service_category" validate:"omitempty,max=50"`
+}
+
+// UserSkill represents the user_skills table
+type UserSkill struct {
+	ID                  int        `json:"id" db:"id"`
+	UserID              int        `json:"user_id" db:"user_id"`
+	SkillID             int        `json:"skill_id" db:"skill_id"`
+	CertificationNumber *string    `json:"certification_number" db:"certification_number"`
+	CertifiedAt         *time.Time `json:"certified_at" db:"certified_at"`
+	ExpiresAt           *time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	// Additional fields for joined queries
+	SkillName       string  `json:"skill_name" db:"skill_name"`
+	VehicleTypeID   *int    `json:"vehicle_type_id" db:"vehicle_type_id"`
+	ServiceCategory *string `json:"service_category" db:"service_category"`
+}
+
+// UserSkillAssignRequest for granting a skill or certification to a user
+type UserSkillAssignRequest struct {
+	SkillID             int     `json:"skill_id" validate:"required"`
+	CertificationNumber *string `json:"certification_number" validate:"omitempty,max=100"`
+	CertifiedAt         *string `json:"certified_at"` // YYYY-MM-DD
+	ExpiresAt           *string `json:"expires_at"`   // YYYY-MM-DD
+}
@@ -19,6 +19,9 @@ type SparePart struct {
 	IsActive      bool      `json:"is_active" db:"is_active"`
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	// Calculated fields: stock held by active repair reservations and what is left to promise
+	ReservedQuantity  int `json:"reserved_quantity" db:"reserved_quantity"`
+	AvailableQuantity int `json:"available_quantity" db:"available_quantity"`
 }
 
 // SparePartCreateRequest for creating new spare part
@@ -53,3 +56,27 @@ type SparePartStockUpdate struct {
 	Operation string `json:"operation" validate:"required,oneof=add subtract"` // "add" or "subtract"
 	Notes     string `json:"notes" validate:"omitempty,max=255"`
 }
+
+// StockMovementType enum
+type StockMovementType string
+
+const (
+	StockMovementRepairIssue  StockMovementType = "repair_issue"
+	StockMovementRepairReturn StockMovementType = "repair_return"
+	StockMovementRepairScrap  StockMovementType = "repair_scrap"
+)
+
+// SparePartMovement represents the spare_part_movements table
+type SparePartMovement struct {
+	ID            int               `json:"id" db:"id"`
+	SparePartID   int               `json:"spare_part_id" db:"spare_part_id"`
+	MovementType  StockMovementType `json:"movement_type" db:"movement_type"`
+	Quantity      int               `json:"quantity" db:"quantity"` // change to stock_quantity
+	Units         int               `json:"units" db:"units"`
+	RepairOrderID *int              `json:"repair_order_id" db:"repair_order_id"`
+	RepairCode    *string           `json:"repair_code,omitempty" db:"repair_code"`
+	Notes         *string           `json:"notes" db:"notes"`
+	CreatedBy     *int              `json:"created_by" db:"created_by"`
+	CreatedByName *string           `json:"created_by_name,omitempty" db:"created_by_name"`
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+)
+
+// ReservationStatus enum
+type ReservationStatus string
+
+const (
+	ReservationStatusActive   ReservationStatus = "active"
+	ReservationStatusConsumed ReservationStatus = "consumed"
+	ReservationStatusReleased ReservationStatus = "released"
+)
+
+// SparePartReservation represents the spare_part_reservations table
+type SparePartReservation struct {
+	ID               int               `json:"id" db:"id"`
+	RepairOrderID    int               `json:"repair_order_id" db:"repair_order_id" validate:"required"`
+	SparePartID      int               `json:"spare_part_id" db:"spare_part_id" validate:"required"`
+	Quantity         int               `json:"quantity" db:"quantity" validate:"required,min=1"`
+	ConsumedQuantity int               `json:"consumed_quantity" db:"consumed_quantity"`
+	Status           ReservationStatus `json:"status" db:"status"`
+	ReservedBy       int               `json:"reserved_by" db:"reserved_by" validate:"required"`
+	ReleasedAt       *time.Time        `json:"released_at" db:"released_at"`
+	CreatedAt        time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at" db:"updated_at"`
+	SparePart        *SparePart        `json:"spare_part,omitempty"`
+}
+
+// SparePartReservationCreateRequest for reserving spare parts on a repair order
+type SparePartReservationCreateRequest struct {
+	SparePartID int `json:"spare_part_id" validate:"required"`
+	Quantity    int `json:"quantity" validate:"required,min=1"`
+}
@@ -6,22 +6,25 @@ import (
 
 // VehicleType represents the vehicle_types table
 type VehicleType struct {
-	ID          int       `json:"id" db:"id"`
-	Name        string    `json:"name" db:"name" validate:"required,max=100"`
-	Description *string   `json:"description" db:"description"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ID            int       `json:"id" db:"id"`
+	Name          string    `json:"name" db:"name" validate:"required,max=100"`
+	Description   *string   `json:"description" db:"description"`
+	RepairSLADays *int      `json:"repair_sla_days" db:"repair_sla_days"` // target reconditioning turnaround
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 }
 
 // VehicleTypeCreateRequest for creating new vehicle type
 type VehicleTypeCreateRequest struct {
-	Name        string  `json:"name" validate:"required,max=100"`
-	Description *string `json:"description"`
+	Name          string  `json:"name" validate:"required,max=100"`
+	Description   *string `json:"description"`
+	RepairSLADays *int    `json:"repair_sla_days" validate:"omitempty,min=1"`
 }
 
 // VehicleTypeUpdateRequest for updating vehicle type
 type VehicleTypeUpdateRequest struct {
-	Name        *string `json:"name" validate:"omitempty,max=100"`
-	Description *string `json:"description"`
+	Name          *string `json:"name" validate:"omitempty,max=100"`
+	Description   *string `json:"description"`
+	RepairSLADays *int    `json:"repair_sla_days" validate:"omitempty,min=0"` // 0 clears the target
 }
 
 // VehicleBrand represents the vehicle_brands table
@@ -80,6 +83,8 @@ type Vehicle struct {
 	Code             string          `json:"code" db:"code" validate:"required,max=50"`
 	BrandID          int             `json:"brand_id" db:"brand_id" validate:"required"`
 	Model            string          `json:"model" db:"model" validate:"required,max=100"`
+	ModelID          *int            `json:"model_id" db:"model_id"` // NULL while the model is free text
+	VariantID        *int            `json:"variant_id" db:"variant_id"`
 	Year             int             `json:"year" db:"year" validate:"required,min=1980"`
 	Color            *string         `json:"color" db:"color" validate:"omitempty,max=50"`
 	EngineCapacity   *string         `json:"engine_capacity" db:"engine_capacity" validate:"omitempty,max=20"`
@@ -95,10 +100,13 @@ type Vehicle struct {
 	ConditionStatus  ConditionStatus `json:"condition_status" db:"condition_status" validate:"required"`
 	Status           VehicleStatus   `json:"status" db:"status"`
 	RepairCost       *float64        `json:"repair_cost" db:"repair_cost"`
+	ExpenseCost      *float64        `json:"expense_cost" db:"expense_cost"` // taxes and fees, see VehicleExpense
 	HPPPrice         *float64        `json:"hpp_price" db:"hpp_price"`
 	SellingPrice     *float64        `json:"selling_price" db:"selling_price"`
 	SoldPrice        *float64        `json:"sold_price" db:"sold_price"`
 	SoldDate         *time.Time      `json:"sold_date" db:"sold_date"`
+	TaxDueDate       *time.Time      `json:"tax_due_date" db:"tax_due_date"`                     // STNK annual tax
+	PlateRenewalDue  *time.Time      `json:"plate_renewal_due_date" db:"plate_renewal_due_date"` // five-yearly STNK and plate renewal
 	Notes            *string         `json:"notes" db:"notes"`
 	CreatedBy        int             `json:"created_by" db:"created_by" validate:"required"`
 	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
@@ -106,23 +114,46 @@ type Vehicle struct {
 	Brand            *VehicleBrand   `json:"brand,omitempty"`
 	Creator          *User           `json:"creator,omitempty"`
 	Photos           []VehiclePhoto  `json:"photos,omitempty"`
+	// Custom attributes of the vehicle type
+	Attributes []VehicleAttributeValue `json:"attributes,omitempty"`
 }
 
 // VehiclePhoto represents the vehicle_photos table
 type VehiclePhoto struct {
-	ID        int       `json:"id" db:"id"`
-	VehicleID int       `json:"vehicle_id" db:"vehicle_id" validate:"required"`
-	PhotoPath string    `json:"photo_path" db:"photo_path" validate:"required,max=255"`
-	IsPrimary bool      `json:"is_primary" db:"is_primary"`
-	Caption   *string   `json:"caption" db:"caption" validate:"omitempty,max=255"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID            int       `json:"id" db:"id"`
+	VehicleID     int       `json:"vehicle_id" db:"vehicle_id" validate:"required"`
+	PhotoPath     string    `json:"photo_path" db:"photo_path" validate:"required,max=255"` // storage key, served through signed links
+	ThumbnailPath *string   `json:"thumbnail_path" db:"thumbnail_path"`
+	IsPrimary     bool      `json:"is_primary" db:"is_primary"`
+	SortOrder     int       `json:"sort_order" db:"sort_order"`
+	Caption       *string   `json:"caption" db:"caption" validate:"omitempty,max=255"`
+	ContentType   *string   `json:"content_type" db:"content_type"`
+	FileSize      int64     `json:"file_size" db:"file_size"`
+	UploadedBy    *int      `json:"uploaded_by" db:"uploaded_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	// Download links, filled by the service
+	URL          string  `json:"url" db:"-"`
+	ThumbnailURL *string `json:"thumbnail_url,omitempty" db:"-"`
+}
+
+// VehiclePhotoUploadRequest holds the form fields sent with an uploaded photo
+type VehiclePhotoUploadRequest struct {
+	Caption   *string `form:"caption" validate:"omitempty,max=255"`
+	IsPrimary bool    `form:"is_primary"` // the first photo of a vehicle is always primary
+}
+
+// VehiclePhotoReorderRequest lists every photo of a vehicle in the new display order
+type VehiclePhotoReorderRequest struct {
+	PhotoIDs []int `json:"photo_ids" validate:"required,min=1"`
 }
 
 // VehicleCreateRequest for creating new vehicle
 type VehicleCreateRequest struct {
 	Code             string          `json:"code" validate:"required,max=50"`
 	BrandID          int             `json:"brand_id" validate:"required"`
-	Model            string          `json:"model" validate:"required,max=100"`
+	Model            string          `json:"model" validate:"omitempty,max=100"` // taken from the catalog when model_id is given
+	ModelID          *int            `json:"model_id"`
+	VariantID        *int            `json:"variant_id"` // fills engine capacity, fuel and transmission left empty
 	Year             int             `json:"year" validate:"required,min=1980"`
 	Color            *string         `json:"color" validate:"omitempty,max=50"`
 	EngineCapacity   *string         `json:"engine_capacity" validate:"omitempty,max=20"`
@@ -136,12 +167,18 @@ type VehicleCreateRequest struct {
 	SourceID         *int            `json:"source_id"`
 	PurchasePrice    float64         `json:"purchase_price" validate:"required,min=0"`
 	ConditionStatus  ConditionStatus `json:"condition_status" validate:"required"`
+	TaxDueDate       *string         `json:"tax_due_date"`           // YYYY-MM-DD
+	PlateRenewalDue  *string         `json:"plate_renewal_due_date"` // YYYY-MM-DD
 	Notes            *string         `json:"notes"`
+	// Custom attribute values by code, e.g. {"seats": 7, "abs": true}
+	Attributes map[string]interface{} `json:"attributes"`
 }
 
 // VehicleUpdateRequest for updating vehicle
 type VehicleUpdateRequest struct {
 	Model            *string          `json:"model" validate:"omitempty,max=100"`
+	ModelID          *int             `json:"model_id"`
+	VariantID        *int             `json:"variant_id"` // replaced whenever the model changes
 	Year             *int             `json:"year" validate:"omitempty,min=1980"`
 	Color            *string          `json:"color" validate:"omitempty,max=50"`
 	EngineCapacity   *string          `json:"engine_capacity" validate:"omitempty,max=20"`
@@ -154,13 +191,19 @@ type VehicleUpdateRequest struct {
 	ConditionStatus  *ConditionStatus `json:"condition_status"`
 	Status           *VehicleStatus   `json:"status"`
 	SellingPrice     *float64         `json:"selling_price" validate:"omitempty,min=0"`
+	TaxDueDate       *string          `json:"tax_due_date"`           // YYYY-MM-DD, empty clears it
+	PlateRenewalDue  *string          `json:"plate_renewal_due_date"` // YYYY-MM-DD, empty clears it
 	Notes            *string          `json:"notes"`
+	// Custom attribute values by code; attributes left out are kept, null clears one
+	Attributes map[string]interface{} `json:"attributes"`
 }
 
 // VehicleSearchFilters represents the search filters for vehicles
 type VehicleSearchFilters struct {
 	BrandID     *int     `form:"brand_id" json:"brand_id,omitempty"`
 	Model       string   `form:"model" json:"model,omitempty"`
+	ModelID     *int     `form:"model_id" json:"model_id,omitempty"`
+	VariantID   *int     `form:"variant_id" json:"variant_id,omitempty"`
 	YearMin     *int     `form:"year_min" json:"year_min,omitempty"`
 	YearMax     *int     `form:"year_max" json:"year_max,omitempty"`
 	Color       string   `form:"color" json:"color,omitempty"`
@@ -169,4 +212,8 @@ type VehicleSearchFilters struct {
 	PriceMin    *float64 `form:"price_min" json:"price_min,omitempty"`
 	PriceMax    *float64 `form:"price_max" json:"price_max,omitempty"`
 	Status      string   `form:"status" json:"status,omitempty"`
+	// Custom attributes by code: exact value, and bounds for number attributes
+	Attributes   map[string]string `form:"attr" json:"attributes,omitempty"`
+	AttributeMin map[string]string `form:"attr_min" json:"attribute_min,omitempty"`
+	AttributeMax map[string]string `form:"attr_max" json:"attribute_max,omitempty"`
 }
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+// VehicleAttributeDataType enum
+type VehicleAttributeDataType string
+
+const (
+	VehicleAttributeText    VehicleAttributeDataType = "text"
+	VehicleAttributeNumber  VehicleAttributeDataType = "number"
+	VehicleAttributeBoolean VehicleAttributeDataType = "boolean"
+	VehicleAttributeEnum    VehicleAttributeDataType = "enum" // one of Options
+)
+
+// VehicleAttributeDefinition represents the vehicle_attribute_definitions table, a custom field
+// offered for the vehicles of one type
+type VehicleAttributeDefinition struct {
+	ID         int                      `json:"id" db:"id"`
+	TypeID     int                      `json:"type_id" db:"type_id"`
+	Code       string                   `json:"code" db:"code"`
+	Name       string                   `json:"name" db:"name"`
+	DataType   VehicleAttributeDataType `json:"data_type" db:"data_type"`
+	Options    []string                 `json:"options" db:"-"`
+	IsRequired bool                     `json:"is_required" db:"is_required"`
+	SortOrder  int                      `json:"sort_order" db:"sort_order"`
+	IsActive   bool                     `json:"is_active" db:"is_active"`
+	CreatedAt  time.Time                `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time                `json:"updated_at" db:"updated_at"`
+}
+
+// VehicleAttributeDefinitionCreateRequest for adding an attribute to a vehicle type
+type VehicleAttributeDefinitionCreateRequest struct {
+	Code       string                   `json:"code" validate:"required,max=50"` // lowercase key, e.g. seats
+	Name       string                   `json:"name" validate:"required,max=100"`
+	DataType   VehicleAttributeDataType `json:"data_type" validate:"required,oneof=text number boolean enum"`
+	Options    []string                 `json:"options" validate:"omitempty,dive,required,max=100"` // enum only
+	IsRequired bool                     `json:"is_required"`
+	SortOrder  int                      `json:"sort_order"`
+}
+
+// VehicleAttributeDefinitionUpdateRequest for updating an attribute; code and data type are fixed
+// once created
+type VehicleAttributeDefinitionUpdateRequest struct {
+	Name       *string  `json:"name" validate:"omitempty,min=1,max=100"`
+	Options    []string `json:"options" validate:"omitempty,dive,required,max=100"` // replaces the enum options
+	IsRequired *bool    `json:"is_required"`
+	SortOrder  *int     `json:"sort_order"`
+	IsActive   *bool    `json:"is_active"` // inactive attributes keep their values but are no longer accepted
+}
+
+// VehicleAttributeValue is the value of a custom attribute on a vehicle
+type VehicleAttributeValue struct {
+	VehicleID    int                      `json:"-" db:"vehicle_id"`
+	DefinitionID int                      `json:"definition_id" db:"definition_id"`
+	Code         string                   `json:"code" db:"code"`
+	Name         string                   `json:"name" db:"name"`
+	DataType     VehicleAttributeDataType `json:"data_type" db:"data_type"`
+	Value        string                   `json:"value" db:"value"`
+}
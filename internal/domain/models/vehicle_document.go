@@ -0,0 +1,130 @@
+package models
+
+import (
+	"time"
+)
+
+// VehicleDocumentType enum
+type VehicleDocumentType string
+
+const (
+	VehicleDocumentTypeBPKB            VehicleDocumentType = "bpkb"
+	VehicleDocumentTypeSTNK            VehicleDocumentType = "stnk"
+	VehicleDocumentTypePurchaseInvoice VehicleDocumentType = "purchase_invoice"
+	VehicleDocumentTypeSpareKey        VehicleDocumentType = "spare_key"
+	VehicleDocumentTypeOther           VehicleDocumentType = "other"
+)
+
+// DocumentCustodyStatus enum
+type DocumentCustodyStatus string
+
+const (
+	DocumentCustodyInCustody        DocumentCustodyStatus = "in_custody"
+	DocumentCustodyOutForProcessing DocumentCustodyStatus = "out_for_processing" // e.g. at SAMSAT for renewal
+	DocumentCustodyHandedOver       DocumentCustodyStatus = "handed_over"
+	DocumentCustodyMissing          DocumentCustodyStatus = "missing"
+)
+
+// VehicleDocument represents the vehicle_documents table
+type VehicleDocument struct {
+	ID              int                   `json:"id" db:"id"`
+	VehicleID       int                   `json:"vehicle_id" db:"vehicle_id"`
+	DocumentType    VehicleDocumentType   `json:"document_type" db:"document_type"`
+	DocumentNumber  *string               `json:"document_number" db:"document_number"`
+	ExpiryDate      *time.Time            `json:"expiry_date" db:"expiry_date"`
+	StorageLocation *string               `json:"storage_location" db:"storage_location"`
+	CustodyStatus   DocumentCustodyStatus `json:"custody_status" db:"custody_status"`
+	HandoverID      *int                  `json:"handover_id" db:"handover_id"`
+	Notes           *string               `json:"notes" db:"notes"`
+	CreatedBy       int                   `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at" db:"updated_at"`
+	// Relationships
+	Attachments []VehicleDocumentAttachment `json:"attachments,omitempty"`
+}
+
+// VehicleDocumentAttachment represents the vehicle_document_attachments table
+type VehicleDocumentAttachment struct {
+	ID            int       `json:"id" db:"id"`
+	DocumentID    int       `json:"document_id" db:"document_id"`
+	FilePath      string    `json:"file_path" db:"file_path"`
+	ThumbnailPath *string   `json:"thumbnail_path" db:"thumbnail_path"`
+	Caption       *string   `json:"caption" db:"caption"`
+	FileName      string    `json:"file_name" db:"file_name"`
+	ContentType   string    `json:"content_type" db:"content_type"`
+	FileSize      int64     `json:"file_size" db:"file_size"`
+	UploadedBy    int       `json:"uploaded_by" db:"uploaded_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	// Download links, filled by the service
+	URL          string  `json:"url" db:"-"`
+	ThumbnailURL *string `json:"thumbnail_url,omitempty" db:"-"`
+}
+
+// VehicleDocumentCreateRequest registers a document held for a vehicle
+type VehicleDocumentCreateRequest struct {
+	DocumentType    VehicleDocumentType `json:"document_type" validate:"required,oneof=bpkb stnk purchase_invoice spare_key other"`
+	DocumentNumber  *string             `json:"document_number" validate:"omitempty,max=100"`
+	ExpiryDate      *string             `json:"expiry_date"` // YYYY-MM-DD
+	StorageLocation *string             `json:"storage_location" validate:"omitempty,max=100"`
+	Notes           *string             `json:"notes"`
+}
+
+// VehicleDocumentUpdateRequest updates a document; handing over goes through a document handover
+type VehicleDocumentUpdateRequest struct {
+	DocumentNumber  *string                `json:"document_number" validate:"omitempty,max=100"`
+	ExpiryDate      *string                `json:"expiry_date"` // YYYY-MM-DD, empty clears it
+	StorageLocation *string                `json:"storage_location" validate:"omitempty,max=100"`
+	CustodyStatus   *DocumentCustodyStatus `json:"custody_status" validate:"omitempty,oneof=in_custody out_for_processing missing"`
+	Notes           *string                `json:"notes"`
+}
+
+// VehicleDocumentAttachmentUploadRequest holds the form fields sent with a scanned document
+type VehicleDocumentAttachmentUploadRequest struct {
+	Caption *string `form:"caption" validate:"omitempty,max=255"`
+}
+
+// DocumentHandover represents the document_handovers table
+type DocumentHandover struct {
+	ID                 int       `json:"id" db:"id"`
+	SalesTransactionID int       `json:"sales_transaction_id" db:"sales_transaction_id"`
+	RecipientName      string    `json:"recipient_name" db:"recipient_name"`
+	RecipientPhone     *string   `json:"recipient_phone" db:"recipient_phone"`
+	HandedOverAt       time.Time `json:"handed_over_at" db:"handed_over_at"`
+	Notes              *string   `json:"notes" db:"notes"`
+	HandedOverBy       int       `json:"handed_over_by" db:"handed_over_by"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+	// Additional fields for joined queries
+	InvoiceNumber    string `json:"invoice_number" db:"invoice_number"`
+	VehicleID        int    `json:"vehicle_id" db:"vehicle_id"`
+	HandedOverByName string `json:"handed_over_by_name" db:"handed_over_by_name"`
+	// Relationships
+	Documents []VehicleDocument `json:"documents,omitempty"`
+}
+
+// DocumentHandoverRequest hands documents of the sold vehicle over to the buyer
+type DocumentHandoverRequest struct {
+	DocumentIDs    []int      `json:"document_ids" validate:"required,min=1"`
+	RecipientName  string     `json:"recipient_name" validate:"required,max=100"`
+	RecipientPhone *string    `json:"recipient_phone" validate:"omitempty,max=20"`
+	HandedOverAt   *time.Time `json:"handed_over_at"` // defaults to now
+	Notes          *string    `json:"notes"`
+}
+
+// PendingDocumentHandover is a sold vehicle with documents not yet handed over to the buyer
+type PendingDocumentHandover struct {
+	SalesTransactionID int       `json:"sales_transaction_id" db:"sales_transaction_id"`
+	InvoiceNumber      string    `json:"invoice_number" db:"invoice_number"`
+	TransactionDate    time.Time `json:"transaction_date" db:"transaction_date"`
+	DaysSinceSale      int       `json:"days_since_sale" db:"days_since_sale"`
+	CustomerID         int       `json:"customer_id" db:"customer_id"`
+	CustomerName       string    `json:"customer_name" db:"customer_name"`
+	CustomerPhone      *string   `json:"customer_phone" db:"customer_phone"`
+	VehicleID          int       `json:"vehicle_id" db:"vehicle_id"`
+	VehicleCode        string    `json:"vehicle_code" db:"vehicle_code"`
+	LicensePlate       *string   `json:"license_plate" db:"license_plate"`
+	BrandName          string    `json:"brand_name" db:"brand_name"`
+	Model              string    `json:"model" db:"model"`
+	PendingDocuments   int       `json:"pending_documents" db:"pending_documents"`
+	PendingTypes       string    `json:"pending_types" db:"pending_types"` // e.g. "bpkb, spare_key"
+	MissingDocuments   int       `json:"missing_documents" db:"missing_documents"`
+}
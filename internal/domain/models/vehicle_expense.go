@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+)
+
+// VehicleExpenseType enum
+type VehicleExpenseType string
+
+const (
+	VehicleExpenseStnkTax      VehicleExpenseType = "stnk_tax"      // annual tax, moves the tax due date a year
+	VehicleExpensePlateRenewal VehicleExpenseType = "plate_renewal" // five-yearly renewal, includes that year's tax
+	VehicleExpenseOther        VehicleExpenseType = "other"
+)
+
+// VehicleExpense represents the vehicle_expenses table. Expenses are added to the vehicle HPP.
+type VehicleExpense struct {
+	ID              int                `json:"id" db:"id"`
+	VehicleID       int                `json:"vehicle_id" db:"vehicle_id"`
+	ExpenseType     VehicleExpenseType `json:"expense_type" db:"expense_type"`
+	Amount          float64            `json:"amount" db:"amount"`
+	ExpenseDate     time.Time          `json:"expense_date" db:"expense_date"`
+	Description     *string            `json:"description" db:"description"`
+	ReferenceNumber *string            `json:"reference_number" db:"reference_number"`
+	CreatedBy       int                `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time          `json:"created_at" db:"created_at"`
+	// Additional fields for joined queries
+	CreatedByName string `json:"created_by_name" db:"created_by_name"`
+}
+
+// VehicleExpenseCreateRequest records a cost paid for a vehicle in stock
+type VehicleExpenseCreateRequest struct {
+	ExpenseType     VehicleExpenseType `json:"expense_type" validate:"required,oneof=stnk_tax plate_renewal other"`
+	Amount          float64            `json:"amount" validate:"required,gt=0"`
+	ExpenseDate     *string            `json:"expense_date"` // YYYY-MM-DD, defaults to today
+	Description     *string            `json:"description"`
+	ReferenceNumber *string            `json:"reference_number" validate:"omitempty,max=100"`
+	// YYYY-MM-DD; for tax and renewal payments, defaults to the current due date moved one or five years
+	NextDueDate *string `json:"next_due_date"`
+}
+
+// VehicleRegistrationDue is a tax or plate renewal date of a vehicle in stock that is due soon or overdue
+type VehicleRegistrationDue struct {
+	VehicleID    int                `json:"vehicle_id" db:"vehicle_id"`
+	VehicleCode  string             `json:"vehicle_code" db:"vehicle_code"`
+	LicensePlate *string            `json:"license_plate" db:"license_plate"`
+	BrandName    string             `json:"brand_name" db:"brand_name"`
+	Model        string             `json:"model" db:"model"`
+	Status       VehicleStatus      `json:"status" db:"status"`
+	DueType      VehicleExpenseType `json:"due_type" db:"due_type"` // stnk_tax or plate_renewal
+	DueDate      time.Time          `json:"due_date" db:"due_date"`
+	DaysLeft     int                `json:"days_left" db:"days_left"` // negative when overdue
+	IsOverdue    bool               `json:"is_overdue" db:"is_overdue"`
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+)
+
+// VehicleModel represents the vehicle_models table, a catalog model under a brand such as Honda Beat
+type VehicleModel struct {
+	ID        int       `json:"id" db:"id"`
+	BrandID   int       `json:"brand_id" db:"brand_id"`
+	Name      string    `json:"name" db:"name"`
+	IsActive  bool      `json:"is_active" db:"is_active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// Additional fields for joined queries
+	BrandName string           `json:"brand_name" db:"brand_name"`
+	Variants  []VehicleVariant `json:"variants,omitempty" db:"-"`
+}
+
+// VehicleModelCreateRequest for adding a model to a brand
+type VehicleModelCreateRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	IsActive *bool  `json:"is_active"` // defaults to true
+}
+
+// VehicleModelUpdateRequest for updating a catalog model
+type VehicleModelUpdateRequest struct {
+	Name     *string `json:"name" validate:"omitempty,min=1,max=100"`
+	IsActive *bool   `json:"is_active"` // inactive models are kept for existing vehicles but not offered
+}
+
+// VehicleVariant represents the vehicle_variants table. Its specs prefill vehicles created from it.
+type VehicleVariant struct {
+	ID               int       `json:"id" db:"id"`
+	ModelID          int       `json:"model_id" db:"model_id"`
+	Name             string    `json:"name" db:"name"`
+	EngineCapacity   *string   `json:"engine_capacity" db:"engine_capacity"`
+	FuelType         *string   `json:"fuel_type" db:"fuel_type"`
+	TransmissionType *string   `json:"transmission_type" db:"transmission_type"`
+	YearFrom         *int      `json:"year_from" db:"year_from"`
+	YearTo           *int      `json:"year_to" db:"year_to"`
+	IsActive         bool      `json:"is_active" db:"is_active"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// VehicleVariantCreateRequest for adding a variant to a model
+type VehicleVariantCreateRequest struct {
+	Name             string  `json:"name" validate:"required,max=100"`
+	EngineCapacity   *string `json:"engine_capacity" validate:"omitempty,max=20"`
+	FuelType         *string `json:"fuel_type" validate:"omitempty,max=20"`
+	TransmissionType *string `json:"transmission_type" validate:"omitempty,max=20"`
+	YearFrom         *int    `json:"year_from" validate:"omitempty,min=1980"`
+	YearTo           *int    `json:"year_to" validate:"omitempty,min=1980"`
+	IsActive         *bool   `json:"is_active"` // defaults to true
+}
+
+// VehicleVariantUpdateRequest for updating a variant
+type VehicleVariantUpdateRequest struct {
+	Name             *string `json:"name" validate:"omitempty,min=1,max=100"`
+	EngineCapacity   *string `json:"engine_capacity" validate:"omitempty,max=20"`
+	FuelType         *string `json:"fuel_type" validate:"omitempty,max=20"`
+	TransmissionType *string `json:"transmission_type" validate:"omitempty,max=20"`
+	YearFrom         *int    `json:"year_from" validate:"omitempty,min=1980"`
+	YearTo           *int    `json:"year_to" validate:"omitempty,min=1980"`
+	IsActive         *bool   `json:"is_active"`
+}
+
+// UnmappedVehicleModel is a free-text model still used by vehicles outside the catalog,
+// with the catalog entry whose name matches it if there is one
+type UnmappedVehicleModel struct {
+	BrandID            int    `json:"brand_id" db:"brand_id"`
+	BrandName          string `json:"brand_name" db:"brand_name"`
+	Model              string `json:"model" db:"model"`
+	VehicleCount       int    `json:"vehicle_count" db:"vehicle_count"`
+	SuggestedModelID   *int   `json:"suggested_model_id" db:"suggested_model_id"`
+	SuggestedVariantID *int   `json:"suggested_variant_id" db:"suggested_variant_id"`
+}
+
+// VehicleModelMappingRequest maps every vehicle of a brand with the given free-text model onto
+// a catalog model and optionally a variant
+type VehicleModelMappingRequest struct {
+	BrandID   int    `json:"brand_id" validate:"required"`
+	Model     string `json:"model" validate:"required"` // the free text exactly as stored on the vehicles
+	ModelID   int    `json:"model_id" validate:"required"`
+	VariantID *int   `json:"variant_id"`
+}
+
+// VehicleModelMappingResult reports how many vehicles were moved onto the catalog
+type VehicleModelMappingResult struct {
+	VehiclesMapped int64 `json:"vehicles_mapped"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// VehicleTimelineEventType enum
+type VehicleTimelineEventType string
+
+const (
+	VehicleEventIntake              VehicleTimelineEventType = "intake"
+	VehicleEventPurchase            VehicleTimelineEventType = "purchase"
+	VehicleEventInspection          VehicleTimelineEventType = "inspection"
+	VehicleEventRepairOpened        VehicleTimelineEventType = "repair_opened"
+	VehicleEventRepairPart          VehicleTimelineEventType = "repair_part"
+	VehicleEventRepairCompleted     VehicleTimelineEventType = "repair_completed"
+	VehicleEventExpense             VehicleTimelineEventType = "expense"
+	VehicleEventPriceChange         VehicleTimelineEventType = "price_change"
+	VehicleEventReservation         VehicleTimelineEventType = "reservation"
+	VehicleEventReservationReleased VehicleTimelineEventType = "reservation_released"
+	VehicleEventStatusChange        VehicleTimelineEventType = "status_change"
+	VehicleEventSale                VehicleTimelineEventType = "sale"
+	VehicleEventPayment             VehicleTimelineEventType = "payment"
+	VehicleEventDocumentHandover    VehicleTimelineEventType = "document_handover"
+)
+
+// VehicleTimelineEvent is one entry in the life of a vehicle, from intake to document handover
+type VehicleTimelineEvent struct {
+	EventType   VehicleTimelineEventType `json:"event_type" db:"event_type"`
+	OccurredAt  time.Time                `json:"occurred_at" db:"occurred_at"`
+	Description *string                  `json:"description" db:"description"`
+	Amount      *float64                 `json:"amount" db:"amount"`
+	ActorID     *int                     `json:"actor_id" db:"actor_id"` // NULL when the record does not keep who did it
+	ActorName   *string                  `json:"actor_name" db:"actor_name"`
+	// Record the event comes from, e.g. repair_order or sales_transaction
+	ReferenceType string  `json:"reference_type" db:"reference_type"`
+	ReferenceID   int     `json:"reference_id" db:"reference_id"`
+	ReferenceCode *string `json:"reference_code" db:"reference_code"` // invoice number, repair code, etc
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+)
+
+// SalesWarranty represents the sales_warranties table
+type SalesWarranty struct {
+	ID                 int       `json:"id" db:"id"`
+	SalesTransactionID int       `json:"sales_transaction_id" db:"sales_transaction_id"`
+	Coverage           string    `json:"coverage" db:"coverage"`
+	DurationMonths     int       `json:"duration_months" db:"duration_months"`
+	OdometerLimit      *int      `json:"odometer_limit" db:"odometer_limit"` // km covered after the sale
+	OdometerAtSale     int       `json:"odometer_at_sale" db:"odometer_at_sale"`
+	StartDate          time.Time `json:"start_date" db:"start_date"`
+	EndDate            time.Time `json:"end_date" db:"end_date"`
+	Notes              *string   `json:"notes" db:"notes"`
+	CreatedBy          int       `json:"created_by" db:"created_by"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
+	// Additional fields for joined queries
+	InvoiceNumber string `json:"invoice_number" db:"invoice_number"`
+	CustomerID    int    `json:"customer_id" db:"customer_id"`
+	VehicleID     int    `json:"vehicle_id" db:"vehicle_id"`
+	// Cost of completed warranty repairs, booked against the sale instead of the vehicle HPP
+	WarrantyExpense float64 `json:"warranty_expense" db:"warranty_expense"`
+	// Computed from the end date, odometer limits are checked per claim
+	IsActive bool            `json:"is_active" db:"-"`
+	Claims   []WarrantyClaim `json:"claims,omitempty"`
+}
+
+// WarrantyClaim represents the warranty_claims table
+type WarrantyClaim struct {
+	ID            int       `json:"id" db:"id"`
+	WarrantyID    int       `json:"warranty_id" db:"warranty_id"`
+	RepairOrderID int       `json:"repair_order_id" db:"repair_order_id"`
+	Complaint     string    `json:"complaint" db:"complaint"`
+	Odometer      int       `json:"odometer" db:"odometer"`
+	ClaimedBy     int       `json:"claimed_by" db:"claimed_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	// Additional fields for joined queries
+	RepairCode   string       `json:"repair_code" db:"repair_code"`
+	RepairStatus RepairStatus `json:"repair_status" db:"repair_status"`
+	Cost         float64      `json:"cost" db:"cost"`
+	// Relationships
+	RepairOrder *RepairOrder `json:"repair_order,omitempty"`
+}
+
+// SalesWarrantyRequest sets the warranty terms of a sale
+type SalesWarrantyRequest struct {
+	Coverage       string  `json:"coverage" validate:"required,max=255"`
+	DurationMonths int     `json:"duration_months" validate:"required,min=1,max=120"`
+	OdometerLimit  *int    `json:"odometer_limit" validate:"omitempty,min=1"`
+	OdometerAtSale *int    `json:"odometer_at_sale" validate:"omitempty,min=0"` // defaults to the vehicle odometer
+	Notes          *string `json:"notes"`
+}
+
+// WarrantyClaimCreateRequest opens a warranty repair order for a sold vehicle
+type WarrantyClaimCreateRequest struct {
+	Complaint  string `json:"complaint" validate:"required"`
+	Odometer   int    `json:"odometer" validate:"min=0"`
+	MechanicID int    `json:"mechanic_id"` // omit to auto-assign the least-loaded mechanic
+	// Service catalog categories the job needs, used for the mechanic skill check
+	ServiceCategories []string `json:"service_categories"`
+	// YYYY-MM-DD; defaults from the service catalog or vehicle type
+	TargetCompletionDate *string `json:"target_completion_date"`
+}
+
+// WarrantyCostLine is the warranty cost of one brand and model
+type WarrantyCostLine struct {
+	BrandID         int     `json:"brand_id" db:"brand_id"`
+	BrandName       string  `json:"brand_name" db:"brand_name"`
+	Model           string  `json:"model" db:"model"`
+	Claims          int     `json:"claims" db:"claims"`
+	TotalCost       float64 `json:"total_cost" db:"total_cost"`
+	AverageCost     float64 `json:"average_cost" db:"average_cost"`
+	VehiclesClaimed int     `json:"vehicles_claimed" db:"vehicles_claimed"`
+}
+
+// WarrantyCostReport sums the warranty repairs completed in a period per brand and model
+type WarrantyCostReport struct {
+	DateFrom   string             `json:"date_from"`
+	DateTo     string             `json:"date_to"`
+	Claims     int                `json:"claims"`
+	TotalCost  float64            `json:"total_cost"`
+	OpenClaims int                `json:"open_claims"`
+	Lines      []WarrantyCostLine `json:"lines"`
+}
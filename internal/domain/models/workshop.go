@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+)
+
+// WorkshopBay represents the workshop_bays table
+type WorkshopBay struct {
+	ID        int       `json:"id" db:"id"`
+	Code      string    `json:"code" db:"code" validate:"required,max=20"`
+	Name      string    `json:"name" db:"name" validate:"required,max=100"`
+	BayType   string    `json:"bay_type" db:"bay_type" validate:"required,max=30"`
+	Notes     *string   `json:"notes" db:"notes"`
+	IsActive  bool      `json:"is_active" db:"is_active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// WorkshopBayCreateRequest for creating new bay
+type WorkshopBayCreateRequest struct {
+	Code    string  `json:"code" validate:"required,max=20"`
+	Name    string  `json:"name" validate:"required,max=100"`
+	BayType string  `json:"bay_type" validate:"omitempty,max=30"`
+	Notes   *string `json:"notes"`
+}
+
+// WorkshopBayUpdateRequest for updating bay
+type WorkshopBayUpdateRequest struct {
+	Name     *string `json:"name" validate:"omitempty,max=100"`
+	BayType  *string `json:"bay_type" validate:"omitempty,max=30"`
+	Notes    *string `json:"notes"`
+	IsActive *bool   `json:"is_active"`
+}
+
+// RepairSchedule represents the repair_schedules table
+type RepairSchedule struct {
+	ID             int       `json:"id" db:"id"`
+	RepairOrderID  int       `json:"repair_order_id" db:"repair_order_id"`
+	BayID          int       `json:"bay_id" db:"bay_id"`
+	MechanicID     int       `json:"mechanic_id" db:"mechanic_id"`
+	ScheduledStart time.Time `json:"scheduled_start" db:"scheduled_start"`
+	ScheduledEnd   time.Time `json:"scheduled_end" db:"scheduled_end"`
+	ScheduledBy    int       `json:"scheduled_by" db:"scheduled_by"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+	// Additional fields for joined queries
+	RepairCode   string       `json:"repair_code" db:"repair_code"`
+	RepairStatus RepairStatus `json:"repair_status" db:"repair_status"`
+	BayCode      string       `json:"bay_code" db:"bay_code"`
+	BayName      string       `json:"bay_name" db:"bay_name"`
+	MechanicName string       `json:"mechanic_name" db:"mechanic_name"`
+	LicensePlate *string      `json:"license_plate" db:"license_plate"`
+	VehicleModel *string      `json:"vehicle_model" db:"vehicle_model"`
+	// Assignment warnings, e.g. missing mechanic skills
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// RepairScheduleRequest for scheduling or rescheduling a repair order.
+// MechanicID defaults to the mechanic assigned on the repair order.
+type RepairScheduleRequest struct {
+	BayID          int       `json:"bay_id" validate:"required"`
+	MechanicID     int       `json:"mechanic_id"`
+	ScheduledStart time.Time `json:"scheduled_start" validate:"required"`
+	ScheduledEnd   time.Time `json:"scheduled_end" validate:"required"`
+}
+
+// WorkshopCalendar is the schedule of bays for a day or week
+type WorkshopCalendar struct {
+	View      string           `json:"view"`
+	StartDate time.Time        `json:"start_date"`
+	EndDate   time.Time        `json:"end_date"`
+	Bays      []WorkshopBay    `json:"bays"`
+	Entries   []RepairSchedule `json:"entries"`
+}
+
+// WorkshopCalendarFilter for filtering the calendar
+type WorkshopCalendarFilter struct {
+	View       string `form:"view"` // day or week
+	Date       string `form:"date"` // YYYY-MM-DD, defaults to today
+	BayID      int    `form:"bay_id"`
+	MechanicID int    `form:"mechanic_id"`
+}
@@ -0,0 +1,376 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/service"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+// AppointmentHandler handles service appointment booking and check-in
+type AppointmentHandler struct {
+	appointmentService service.AppointmentService
+}
+
+func NewAppointmentHandler(appointmentService service.AppointmentService) *AppointmentHandler {
+	return &AppointmentHandler{
+		appointmentService: appointmentService,
+	}
+}
+
+// BookAppointment books a service slot at the front desk
+// @Summary Book service appointment
+// @Description Book a service slot for a customer vehicle; the slot must fit within the free bays and mechanics
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param request body models.ServiceAppointmentCreateRequest true "Appointment data"
+// @Success 200 {object} utils.Response{data=models.ServiceAppointment}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /appointments [post]
+func (h *AppointmentHandler) BookAppointment(c *gin.Context) {
+	var req models.ServiceAppointmentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+	bookedBy := userID.(int)
+
+	appointment, err := h.appointmentService.BookAppointment(&req, models.AppointmentSourceFrontDesk, &bookedBy)
+	if err != nil {
+		sendAppointmentError(c, "Failed to book appointment", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Appointment booked successfully", appointment)
+}
+
+// ListAppointments lists service appointments
+// @Summary List service appointments
+// @Description Get paginated list of service appointments ordered by scheduled start
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status (booked, arrived, no_show, cancelled)"
+// @Param customer_id query int false "Filter by customer ID"
+// @Param date_from query string false "Scheduled from (YYYY-MM-DD)"
+// @Param date_to query string false "Scheduled to (YYYY-MM-DD)"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /appointments [get]
+func (h *AppointmentHandler) ListAppointments(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	var filter models.ServiceAppointmentFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	appointments, total, err := h.appointmentService.ListAppointments(filter, page, limit)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to get appointments", err.Error())
+		return
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	totalPages := (total + limit - 1) / limit
+	utils.SendSuccess(c, "Appointments retrieved successfully", gin.H{
+		"data": appointments,
+		"pagination": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total":        total,
+			"total_pages":  totalPages,
+		},
+	})
+}
+
+// GetAppointment gets a service appointment by ID
+// @Summary Get service appointment
+// @Description Get service appointment details by ID
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Success 200 {object} utils.Response{data=models.ServiceAppointment}
+// @Failure 404 {object} utils.Response
+// @Router /appointments/{id} [get]
+func (h *AppointmentHandler) GetAppointment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid appointment ID", err.Error())
+		return
+	}
+
+	appointment, err := h.appointmentService.GetAppointment(id)
+	if err != nil {
+		sendAppointmentError(c, "Failed to get appointment", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Appointment retrieved successfully", appointment)
+}
+
+// RescheduleAppointment moves a booked appointment to another slot
+// @Summary Reschedule service appointment
+// @Description Move a booked appointment; the new slot is checked against workshop capacity
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Param request body models.ServiceAppointmentRescheduleRequest true "New slot"
+// @Success 200 {object} utils.Response{data=models.ServiceAppointment}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /appointments/{id}/reschedule [put]
+func (h *AppointmentHandler) RescheduleAppointment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid appointment ID", err.Error())
+		return
+	}
+
+	var req models.ServiceAppointmentRescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	appointment, err := h.appointmentService.RescheduleAppointment(id, &req)
+	if err != nil {
+		sendAppointmentError(c, "Failed to reschedule appointment", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Appointment rescheduled successfully", appointment)
+}
+
+// ArriveAppointment checks in a customer and opens the service order
+// @Summary Check in service appointment
+// @Description Mark the customer as arrived and convert the appointment into a service order
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Param request body models.ServiceAppointmentArrivalRequest true "Arrival data"
+// @Success 200 {object} utils.Response{data=models.ServiceAppointment}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /appointments/{id}/arrive [post]
+func (h *AppointmentHandler) ArriveAppointment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid appointment ID", err.Error())
+		return
+	}
+
+	var req models.ServiceAppointmentArrivalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	appointment, err := h.appointmentService.ArriveAppointment(id, &req, userID.(int))
+	if err != nil {
+		sendAppointmentError(c, "Failed to check in appointment", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Appointment checked in successfully", appointment)
+}
+
+// CancelAppointment cancels a booked appointment
+// @Summary Cancel service appointment
+// @Description Cancel a booked appointment and free its slot
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Param request body models.ServiceAppointmentCancelRequest false "Cancel reason"
+// @Success 200 {object} utils.Response{data=models.ServiceAppointment}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /appointments/{id}/cancel [post]
+func (h *AppointmentHandler) CancelAppointment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid appointment ID", err.Error())
+		return
+	}
+
+	var req models.ServiceAppointmentCancelRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+			return
+		}
+	}
+
+	appointment, err := h.appointmentService.CancelAppointment(id, &req)
+	if err != nil {
+		sendAppointmentError(c, "Failed to cancel appointment", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Appointment cancelled successfully", appointment)
+}
+
+// MarkNoShow marks a booked appointment as no-show
+// @Summary Mark service appointment no-show
+// @Description Record that the customer did not turn up; booked appointments are also marked automatically after the grace period
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Success 200 {object} utils.Response{data=models.ServiceAppointment}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /appointments/{id}/no-show [post]
+func (h *AppointmentHandler) MarkNoShow(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid appointment ID", err.Error())
+		return
+	}
+
+	appointment, err := h.appointmentService.MarkNoShow(id)
+	if err != nil {
+		sendAppointmentError(c, "Failed to mark appointment no-show", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Appointment marked as no-show", appointment)
+}
+
+// GetCapacity reports the workshop load of a time slot
+// @Summary Get appointment capacity
+// @Description Get free bays and mechanics for a slot before booking it
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param start query string true "Slot start (RFC3339)"
+// @Param end query string false "Slot end (RFC3339), defaults from the service or the slot length"
+// @Param service_id query int false "Service catalog ID"
+// @Success 200 {object} utils.Response{data=models.AppointmentCapacity}
+// @Failure 400 {object} utils.Response
+// @Router /appointments/capacity [get]
+func (h *AppointmentHandler) GetCapacity(c *gin.Context) {
+	start, err := time.Parse(time.RFC3339, c.Query("start"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid slot start, use RFC3339", err.Error())
+		return
+	}
+
+	var end *time.Time
+	if c.Query("end") != "" {
+		parsed, err := time.Parse(time.RFC3339, c.Query("end"))
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Invalid slot end, use RFC3339", err.Error())
+			return
+		}
+		end = &parsed
+	}
+
+	var serviceID *int
+	if c.Query("service_id") != "" {
+		id, err := strconv.Atoi(c.Query("service_id"))
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Invalid service ID", err.Error())
+			return
+		}
+		serviceID = &id
+	}
+
+	capacity, err := h.appointmentService.GetCapacity(start, end, serviceID)
+	if err != nil {
+		sendAppointmentError(c, "Failed to get capacity", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Capacity retrieved successfully", capacity)
+}
+
+// GetAppointmentStats reports appointment outcomes
+// @Summary Get appointment stats
+// @Description Count booked, arrived, no-show and cancelled appointments scheduled in the period
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param date_from query string false "Date from (YYYY-MM-DD), defaults to start of month"
+// @Param date_to query string false "Date to (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} utils.Response{data=models.AppointmentStats}
+// @Failure 400 {object} utils.Response
+// @Router /appointments/stats [get]
+func (h *AppointmentHandler) GetAppointmentStats(c *gin.Context) {
+	stats, err := h.appointmentService.GetStats(c.Query("date_from"), c.Query("date_to"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Failed to get appointment stats", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Appointment stats retrieved successfully", stats)
+}
+
+// sendAppointmentError maps appointment service errors to HTTP statuses
+func sendAppointmentError(c *gin.Context, message string, err error) {
+	msg := err.Error()
+	switch {
+	case strings.HasSuffix(msg, "not found"):
+		utils.SendError(c, http.StatusNotFound, message, msg)
+	case strings.HasPrefix(msg, "no capacity"):
+		utils.SendError(c, http.StatusConflict, message, msg)
+	case strings.HasPrefix(msg, "cannot ") || strings.HasPrefix(msg, "appointment ") || strings.HasPrefix(msg, "scheduled end") ||
+		strings.HasPrefix(msg, "requested service") || strings.HasSuffix(msg, "is not active") || strings.HasSuffix(msg, "no longer booked"):
+		utils.SendError(c, http.StatusBadRequest, message, msg)
+	default:
+		utils.SendError(c, http.StatusInternalServerError, message, msg)
+	}
+}
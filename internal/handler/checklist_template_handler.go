@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/service"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+type ChecklistTemplateHandler struct {
+	checklistService service.ChecklistTemplateService
+}
+
+func NewChecklistTemplateHandler(checklistService service.ChecklistTemplateService) *ChecklistTemplateHandler {
+	return &ChecklistTemplateHandler{
+		checklistService: checklistService,
+	}
+}
+
+// CreateTemplate godoc
+// @Summary Create checklist template
+// @Description Create an inspection checklist template, optionally for a single vehicle type
+// @Tags checklist-templates
+// @Accept json
+// @Produce json
+// @Param request body models.ChecklistTemplateCreateRequest true "Template data"
+// @Success 201 {object} utils.APIResponse{data=models.ChecklistTemplate}
+// @Failure 400 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/checklist-templates [post]
+func (h *ChecklistTemplateHandler) CreateTemplate(c *gin.Context) {
+	var req models.ChecklistTemplateCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	template, err := h.checklistService.Create(&req)
+	if err != nil {
+		if err.Error() == "vehicle type not found" {
+			utils.SendBadRequest(c, "Invalid vehicle type", err.Error())
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to create checklist template", err.Error())
+		return
+	}
+
+	utils.SendCreated(c, "Checklist template created successfully", template)
+}
+
+// GetTemplate godoc
+// @Summary Get checklist template by ID
+// @Description Get checklist template with its items
+// @Tags checklist-templates
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} utils.APIResponse{data=models.ChecklistTemplate}
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/checklist-templates/{id} [get]
+func (h *ChecklistTemplateHandler) GetTemplate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid template ID", err.Error())
+		return
+	}
+
+	template, err := h.checklistService.GetByID(id)
+	if err != nil {
+		utils.SendNotFound(c, "Checklist template not found")
+		return
+	}
+
+	utils.SendSuccess(c, "Checklist template retrieved successfully", template)
+}
+
+// UpdateTemplate godoc
+// @Summary Update checklist template
+// @Description Update checklist template; items, when given, replace the existing items
+// @Tags checklist-templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param request body models.ChecklistTemplateUpdateRequest true "Template update data"
+// @Success 200 {object} utils.APIResponse{data=models.ChecklistTemplate}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/checklist-templates/{id} [put]
+func (h *ChecklistTemplateHandler) UpdateTemplate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid template ID", err.Error())
+		return
+	}
+
+	var req models.ChecklistTemplateUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	template, err := h.checklistService.Update(id, &req)
+	if err != nil {
+		switch err.Error() {
+		case "checklist template not found":
+			utils.SendNotFound(c, "Checklist template not found")
+		case "no fields to update", "checklist template needs at least one item":
+			utils.SendBadRequest(c, "Invalid checklist template", err.Error())
+		default:
+			utils.SendInternalServerError(c, "Failed to update checklist template", err.Error())
+		}
+		return
+	}
+
+	utils.SendSuccess(c, "Checklist template updated successfully", template)
+}
+
+// DeleteTemplate godoc
+// @Summary Delete checklist template
+// @Description Delete a checklist template, or deactivate it when already used on repair orders
+// @Tags checklist-templates
+// @Param id path int true "Template ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/checklist-templates/{id} [delete]
+func (h *ChecklistTemplateHandler) DeleteTemplate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid template ID", err.Error())
+		return
+	}
+
+	err = h.checklistService.Delete(id)
+	if err != nil {
+		if err.Error() == "checklist template not found" {
+			utils.SendNotFound(c, "Checklist template not found")
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to delete checklist template", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Checklist template deleted successfully", nil)
+}
+
+// ListTemplates godoc
+// @Summary List checklist templates
+// @Description Get checklist templates, optionally only those usable for a vehicle type
+// @Tags checklist-templates
+// @Produce json
+// @Param vehicle_type_id query int false "Vehicle type ID"
+// @Param active_only query bool false "Only active templates"
+// @Success 200 {object} utils.APIResponse{data=[]models.ChecklistTemplate}
+// @Security BearerAuth
+// @Router /api/checklist-templates [get]
+func (h *ChecklistTemplateHandler) ListTemplates(c *gin.Context) {
+	vehicleTypeID, _ := strconv.Atoi(c.Query("vehicle_type_id"))
+	activeOnly := c.Query("active_only") == "true"
+
+	templates, err := h.checklistService.List(vehicleTypeID, activeOnly)
+	if err != nil {
+		utils.SendInternalServerError(c, "Failed to retrieve checklist templates", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Checklist templates retrieved successfully", templates)
+}
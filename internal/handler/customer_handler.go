@@ -231,3 +231,125 @@ func (h *CustomerHandler) SearchCustomerByPhone(c *gin.Context) {
 		"data": customer,
 	})
 }
+
+// ListCustomerVehicles handles GET /api/customers/:id/vehicles
+func (h *CustomerHandler) ListCustomerVehicles(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid customer ID", "Customer ID must be a number")
+		return
+	}
+
+	vehicles, err := h.customerService.ListVehicles(id)
+	if err != nil {
+		if err.Error() == "customer not found" {
+			utils.SendError(c, http.StatusNotFound, "Customer not found", "Customer with this ID does not exist")
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to get customer vehicles", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Customer vehicles retrieved successfully", gin.H{
+		"vehicles": vehicles,
+	})
+}
+
+// CreateCustomerVehicle handles POST /api/customers/:id/vehicles
+func (h *CustomerHandler) CreateCustomerVehicle(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid customer ID", "Customer ID must be a number")
+		return
+	}
+
+	var req models.CustomerVehicleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	vehicle, err := h.customerService.AddVehicle(id, &req)
+	if err != nil {
+		switch err.Error() {
+		case "customer not found":
+			utils.SendError(c, http.StatusNotFound, "Customer not found", "Customer with this ID does not exist")
+		case "vehicle brand not found":
+			utils.SendError(c, http.StatusBadRequest, "Invalid vehicle brand", err.Error())
+		default:
+			utils.SendError(c, http.StatusInternalServerError, "Failed to add customer vehicle", err.Error())
+		}
+		return
+	}
+
+	utils.SendSuccess(c, "Customer vehicle added successfully", gin.H{
+		"vehicle": vehicle,
+	})
+}
+
+// GetCustomerVehicle handles GET /api/customer-vehicles/:id
+func (h *CustomerHandler) GetCustomerVehicle(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid vehicle ID", "Vehicle ID must be a number")
+		return
+	}
+
+	vehicle, err := h.customerService.GetVehicle(id)
+	if err != nil {
+		if err.Error() == "customer vehicle not found" {
+			utils.SendError(c, http.StatusNotFound, "Customer vehicle not found", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to get customer vehicle", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Customer vehicle retrieved successfully", gin.H{
+		"vehicle": vehicle,
+	})
+}
+
+// UpdateCustomerVehicle handles PUT /api/customer-vehicles/:id
+func (h *CustomerHandler) UpdateCustomerVehicle(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid vehicle ID", "Vehicle ID must be a number")
+		return
+	}
+
+	var req models.CustomerVehicleUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	vehicle, err := h.customerService.UpdateVehicle(id, &req)
+	if err != nil {
+		switch err.Error() {
+		case "customer vehicle not found":
+			utils.SendError(c, http.StatusNotFound, "Customer vehicle not found", err.Error())
+		case "vehicle brand not found", "no fields to update":
+			utils.SendError(c, http.StatusBadRequest, "Invalid vehicle data", err.Error())
+		default:
+			utils.SendError(c, http.StatusInternalServerError, "Failed to update customer vehicle", err.Error())
+		}
+		return
+	}
+
+	utils.SendSuccess(c, "Customer vehicle updated successfully", gin.H{
+		"vehicle": vehicle,
+	})
+}
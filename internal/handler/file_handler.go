@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/storage"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+type FileHandler struct {
+	store storage.Store
+}
+
+func NewFileHandler(store storage.Store) *FileHandler {
+	return &FileHandler{
+		store: store,
+	}
+}
+
+// ServeFile godoc
+// @Summary Download an uploaded file
+// @Description Serve a file kept by the local storage driver through a signed, expiring link
+// @Tags files
+// @Produce octet-stream
+// @Param filepath path string true "Storage key"
+// @Param expires query int true "Link expiry as a unix timestamp"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file
+// @Failure 403 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /uploads/{filepath} [get]
+func (h *FileHandler) ServeFile(c *gin.Context) {
+	// Files in an object store are downloaded from the store itself
+	local, ok := h.store.(*storage.Local)
+	if !ok {
+		utils.SendNotFound(c, "File not found")
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("filepath"), "/")
+	if err := local.Verify(key, c.Query("expires"), c.Query("signature")); err != nil {
+		utils.SendForbidden(c, err.Error())
+		return
+	}
+
+	fullPath, err := local.FilePath(key)
+	if err != nil {
+		utils.SendNotFound(c, "File not found")
+		return
+	}
+	if info, err := os.Stat(fullPath); err != nil || info.IsDir() {
+		utils.SendNotFound(c, "File not found")
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.File(fullPath)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/pos-baru/internal/service"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// ListNotifications godoc
+// @Summary List my notifications
+// @Description Get notifications of the current user, newest first
+// @Tags notifications
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param unread query bool false "Only unread notifications"
+// @Success 200 {object} utils.APIResponse{data=[]models.Notification}
+// @Security BearerAuth
+// @Router /api/notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+
+	notifications, total, err := h.notificationService.ListForUser(userID.(int), unreadOnly, page, limit)
+	if err != nil {
+		utils.SendInternalServerError(c, "Failed to retrieve notifications", err.Error())
+		return
+	}
+
+	utils.SendSuccessWithMeta(c, "Notifications retrieved successfully", notifications, utils.CalculatePaginationMeta(page, limit, int64(total)))
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark notification as read
+// @Description Mark one of the current user's notifications as read
+// @Tags notifications
+// @Param id path int true "Notification ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/notifications/{id}/read [patch]
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid notification ID", err.Error())
+		return
+	}
+
+	if err := h.notificationService.MarkAsRead(userID.(int), id); err != nil {
+		if err.Error() == "notification not found" {
+			utils.SendNotFound(c, "Notification not found")
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to update notification", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Notification marked as read", nil)
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Mark all notifications as read
+// @Description Mark every unread notification of the current user as read
+// @Tags notifications
+// @Success 200 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/notifications/read-all [patch]
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.notificationService.MarkAllAsRead(userID.(int)); err != nil {
+		utils.SendInternalServerError(c, "Failed to update notifications", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Notifications marked as read", nil)
+}
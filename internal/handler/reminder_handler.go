@@ -0,0 +1,347 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/service"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+// ReminderHandler handles periodic service reminder rules and customer follow-ups
+type ReminderHandler struct {
+	reminderService service.ReminderService
+}
+
+func NewReminderHandler(reminderService service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{
+		reminderService: reminderService,
+	}
+}
+
+// CreateReminderRule creates a service reminder rule
+// @Summary Create reminder rule
+// @Description Create a rule reminding customers by time since the last service, estimated odometer or warranty expiry
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Param request body models.ServiceReminderRuleCreateRequest true "Rule data"
+// @Success 200 {object} utils.Response{data=models.ServiceReminderRule}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /reminders/rules [post]
+func (h *ReminderHandler) CreateReminderRule(c *gin.Context) {
+	var req models.ServiceReminderRuleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	rule, err := h.reminderService.CreateRule(&req, userID.(int))
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "not found") || strings.HasPrefix(err.Error(), "interval ") {
+			utils.SendError(c, http.StatusBadRequest, "Invalid reminder rule", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to create reminder rule", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Reminder rule created successfully", rule)
+}
+
+// ListReminderRules lists service reminder rules
+// @Summary List reminder rules
+// @Description Get all service reminder rules
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Param active query bool false "Only active rules"
+// @Success 200 {object} utils.Response{data=[]models.ServiceReminderRule}
+// @Failure 500 {object} utils.Response
+// @Router /reminders/rules [get]
+func (h *ReminderHandler) ListReminderRules(c *gin.Context) {
+	activeOnly, _ := strconv.ParseBool(c.DefaultQuery("active", "false"))
+
+	rules, err := h.reminderService.ListRules(activeOnly)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to get reminder rules", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Reminder rules retrieved successfully", rules)
+}
+
+// GetReminderRule gets a service reminder rule by ID
+// @Summary Get reminder rule
+// @Description Get service reminder rule details by ID
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} utils.Response{data=models.ServiceReminderRule}
+// @Failure 404 {object} utils.Response
+// @Router /reminders/rules/{id} [get]
+func (h *ReminderHandler) GetReminderRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid rule ID", err.Error())
+		return
+	}
+
+	rule, err := h.reminderService.GetRule(id)
+	if err != nil {
+		if err.Error() == "reminder rule not found" {
+			utils.SendError(c, http.StatusNotFound, "Reminder rule not found", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to get reminder rule", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Reminder rule retrieved successfully", rule)
+}
+
+// UpdateReminderRule updates a service reminder rule
+// @Summary Update reminder rule
+// @Description Update a service reminder rule; reminders already produced keep their message
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Param request body models.ServiceReminderRuleUpdateRequest true "Rule data"
+// @Success 200 {object} utils.Response{data=models.ServiceReminderRule}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /reminders/rules/{id} [put]
+func (h *ReminderHandler) UpdateReminderRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid rule ID", err.Error())
+		return
+	}
+
+	var req models.ServiceReminderRuleUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	rule, err := h.reminderService.UpdateRule(id, &req)
+	if err != nil {
+		switch {
+		case err.Error() == "reminder rule not found":
+			utils.SendError(c, http.StatusNotFound, "Reminder rule not found", err.Error())
+		case err.Error() == "no fields to update" || strings.HasSuffix(err.Error(), "not found"):
+			utils.SendError(c, http.StatusBadRequest, "Invalid reminder rule", err.Error())
+		default:
+			utils.SendError(c, http.StatusInternalServerError, "Failed to update reminder rule", err.Error())
+		}
+		return
+	}
+
+	utils.SendSuccess(c, "Reminder rule updated successfully", rule)
+}
+
+// DeleteReminderRule deletes a service reminder rule
+// @Summary Delete reminder rule
+// @Description Delete a service reminder rule together with the reminders it produced; deactivate it to keep the history
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /reminders/rules/{id} [delete]
+func (h *ReminderHandler) DeleteReminderRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid rule ID", err.Error())
+		return
+	}
+
+	if err := h.reminderService.DeleteRule(id); err != nil {
+		if err.Error() == "reminder rule not found" {
+			utils.SendError(c, http.StatusNotFound, "Reminder rule not found", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to delete reminder rule", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Reminder rule deleted successfully", nil)
+}
+
+// RunReminders produces and sends the reminders due today
+// @Summary Run service reminders
+// @Description Produce the reminders due under the active rules and send the undelivered ones; the same job runs daily
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.Response{data=models.ServiceReminderRunResult}
+// @Failure 500 {object} utils.Response
+// @Router /reminders/run [post]
+func (h *ReminderHandler) RunReminders(c *gin.Context) {
+	result, err := h.reminderService.RunDueReminders()
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to run reminders", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Reminders processed successfully", result)
+}
+
+// ListReminders lists service reminders
+// @Summary List service reminders
+// @Description Get paginated list of produced reminders with delivery status and contact outcome
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by delivery status (pending, sent, failed)"
+// @Param outcome query string false "Filter by outcome (booked, declined, unreachable)"
+// @Param no_outcome query bool false "Only reminders not followed up yet"
+// @Param rule_id query int false "Filter by rule ID"
+// @Param customer_id query int false "Filter by customer ID"
+// @Param due_date_from query string false "Due from (YYYY-MM-DD)"
+// @Param due_date_to query string false "Due to (YYYY-MM-DD)"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /reminders [get]
+func (h *ReminderHandler) ListReminders(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	var filter models.ServiceReminderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	reminders, total, err := h.reminderService.ListReminders(filter, page, limit)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to get reminders", err.Error())
+		return
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	totalPages := (total + limit - 1) / limit
+	utils.SendSuccess(c, "Reminders retrieved successfully", gin.H{
+		"data": reminders,
+		"pagination": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total":        total,
+			"total_pages":  totalPages,
+		},
+	})
+}
+
+// GetReminder gets a service reminder by ID
+// @Summary Get service reminder
+// @Description Get service reminder details by ID
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Param id path int true "Reminder ID"
+// @Success 200 {object} utils.Response{data=models.ServiceReminder}
+// @Failure 404 {object} utils.Response
+// @Router /reminders/{id} [get]
+func (h *ReminderHandler) GetReminder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid reminder ID", err.Error())
+		return
+	}
+
+	reminder, err := h.reminderService.GetReminder(id)
+	if err != nil {
+		if err.Error() == "service reminder not found" {
+			utils.SendError(c, http.StatusNotFound, "Reminder not found", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to get reminder", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Reminder retrieved successfully", reminder)
+}
+
+// RecordReminderOutcome records the result of contacting the customer
+// @Summary Record reminder outcome
+// @Description Record whether the customer booked, declined or could not be reached
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Param id path int true "Reminder ID"
+// @Param request body models.ServiceReminderOutcomeRequest true "Outcome data"
+// @Success 200 {object} utils.Response{data=models.ServiceReminder}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /reminders/{id}/outcome [post]
+func (h *ReminderHandler) RecordReminderOutcome(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid reminder ID", err.Error())
+		return
+	}
+
+	var req models.ServiceReminderOutcomeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	reminder, err := h.reminderService.RecordOutcome(id, &req, userID.(int))
+	if err != nil {
+		switch {
+		case err.Error() == "service reminder not found":
+			utils.SendError(c, http.StatusNotFound, "Reminder not found", err.Error())
+		case strings.HasPrefix(err.Error(), "appointment ") || err.Error() == "service appointment not found":
+			utils.SendError(c, http.StatusBadRequest, "Invalid appointment", err.Error())
+		default:
+			utils.SendError(c, http.StatusInternalServerError, "Failed to record reminder outcome", err.Error())
+		}
+		return
+	}
+
+	utils.SendSuccess(c, "Reminder outcome recorded successfully", reminder)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -24,7 +25,7 @@ func NewRepairHandler(repairService service.RepairService) *RepairHandler {
 
 // CreateRepairOrder creates a new repair order
 // @Summary Create repair order
-// @Description Create a new repair order for vehicle maintenance
+// @Description Create a new repair order for vehicle maintenance; omit mechanic_id to auto-assign the least-loaded mechanic
 // @Tags repairs
 // @Accept json
 // @Produce json
@@ -110,6 +111,42 @@ func (h *RepairHandler) GetRepairOrderByCode(c *gin.Context) {
 	utils.SendSuccess(c, "Repair order retrieved successfully", repair)
 }
 
+// TrackRepair shows customers the progress of their repair
+// @Summary Track repair
+// @Description Public status timeline of a repair order, verified by the last digits of the customer's phone number; contains no costs
+// @Tags public
+// @Produce json
+// @Param code path string true "Repair Order Code"
+// @Param phone query string true "Last 4 or more digits of the customer's phone number"
+// @Success 200 {object} utils.Response{data=models.RepairTracking}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /public/repairs/{code} [get]
+func (h *RepairHandler) TrackRepair(c *gin.Context) {
+	phone := c.Query("phone")
+	if phone == "" {
+		utils.SendError(c, http.StatusBadRequest, "Phone verification is required", nil)
+		return
+	}
+
+	tracking, err := h.repairService.TrackRepair(c.Param("code"), phone)
+	if err != nil {
+		if err.Error() == "repair order not found" {
+			utils.SendError(c, http.StatusNotFound, "Repair order not found", err.Error())
+			return
+		}
+		if strings.HasPrefix(err.Error(), "too many attempts") {
+			utils.SendError(c, http.StatusTooManyRequests, "Too many attempts", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusBadRequest, "Failed to track repair order", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Repair status retrieved successfully", tracking)
+}
+
 // ListRepairOrders lists repair orders with filtering
 // @Summary List repair orders
 // @Description Get paginated list of repair orders with optional filtering
@@ -121,6 +158,8 @@ func (h *RepairHandler) GetRepairOrderByCode(c *gin.Context) {
 // @Param status query string false "Filter by status"
 // @Param mechanic_id query int false "Filter by mechanic ID"
 // @Param vehicle_id query int false "Filter by vehicle ID"
+// @Param overdue query bool false "Only open orders past their target completion date"
+// @Param warranty query bool false "Only after-sales warranty orders"
 // @Param date_from query string false "Filter from date (YYYY-MM-DD)"
 // @Param date_to query string false "Filter to date (YYYY-MM-DD)"
 // @Success 200 {object} utils.Response{data=utils.PaginatedResponse{items=[]models.RepairOrder}}
@@ -184,7 +223,13 @@ func (h *RepairHandler) UpdateRepairOrder(c *gin.Context) {
 		return
 	}
 
-	err = h.repairService.UpdateRepairOrder(id, &req)
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	err = h.repairService.UpdateRepairOrder(id, &req, userID.(int))
 	if err != nil {
 		utils.SendError(c, http.StatusInternalServerError, "Failed to update repair order", err.Error())
 		return
@@ -219,7 +264,13 @@ func (h *RepairHandler) UpdateRepairProgress(c *gin.Context) {
 		return
 	}
 
-	err = h.repairService.UpdateRepairProgress(id, &req)
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	err = h.repairService.UpdateRepairProgress(id, &req, userID.(int))
 	if err != nil {
 		utils.SendError(c, http.StatusInternalServerError, "Failed to update repair progress", err.Error())
 		return
@@ -228,9 +279,133 @@ func (h *RepairHandler) UpdateRepairProgress(c *gin.Context) {
 	utils.SendSuccess(c, "Repair progress updated successfully", nil)
 }
 
+// GetRepairStatusHistory gets the status changes of a repair order
+// @Summary Get repair status history
+// @Description Get every status change of a repair order with actor, timestamp and note
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Success 200 {object} utils.Response{data=[]models.RepairStatusHistory}
+// @Failure 404 {object} utils.Response
+// @Router /repairs/{id}/history [get]
+func (h *RepairHandler) GetRepairStatusHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	history, err := h.repairService.GetRepairStatusHistory(id)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "Repair order not found", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Repair status history retrieved successfully", history)
+}
+
+// GetStatusTransitions lists the allowed repair status transitions
+// @Summary Get repair status transitions
+// @Description Get the configured map of allowed repair status changes
+// @Tags repairs
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]models.RepairStatusTransition}
+// @Failure 500 {object} utils.Response
+// @Router /repairs/status-transitions [get]
+func (h *RepairHandler) GetStatusTransitions(c *gin.Context) {
+	transitions, err := h.repairService.GetStatusTransitions()
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to retrieve status transitions", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Status transitions retrieved successfully", transitions)
+}
+
+// UpdateStatusTransitions replaces the allowed repair status transitions
+// @Summary Update repair status transitions
+// @Description Replace the whole map of allowed repair status changes
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param request body models.RepairStatusTransitionsUpdateRequest true "Allowed transitions"
+// @Success 200 {object} utils.Response{data=[]models.RepairStatusTransition}
+// @Failure 400 {object} utils.Response
+// @Router /repairs/status-transitions [put]
+func (h *RepairHandler) UpdateStatusTransitions(c *gin.Context) {
+	var req models.RepairStatusTransitionsUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	transitions, err := h.repairService.UpdateStatusTransitions(&req)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Failed to update status transitions", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Status transitions updated successfully", transitions)
+}
+
+// CancelRepairOrder cancels a repair order and settles its used parts
+// @Summary Cancel repair order
+// @Description Cancel a repair order choosing per part line to return it to stock, scrap it or keep it as vehicle cost. Lines not listed are returned to stock
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param request body models.RepairCancelRequest true "Cancellation data"
+// @Success 200 {object} utils.Response{data=models.RepairOrder}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /repairs/{id}/cancel [post]
+func (h *RepairHandler) CancelRepairOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	var req models.RepairCancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	repair, err := h.repairService.CancelRepairOrder(id, &req, userID.(int))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "repair order not found") {
+			utils.SendError(c, http.StatusNotFound, "Repair order not found", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusBadRequest, "Failed to cancel repair order", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Repair order cancelled successfully", repair)
+}
+
 // DeleteRepairOrder deletes a repair order
 // @Summary Delete repair order
-// @Description Delete a repair order (only if pending or cancelled)
+// @Description Delete a repair order (only if pending, approved or cancelled). Parts still in use are returned to stock
 // @Tags repairs
 // @Accept json
 // @Produce json
@@ -363,10 +538,17 @@ func (h *RepairHandler) GetRepairSpareParts(c *gin.Context) {
 // @Param mechanic_id query int false "Filter by mechanic ID"
 // @Param date_from query string false "Filter from date (YYYY-MM-DD)"
 // @Param date_to query string false "Filter to date (YYYY-MM-DD)"
+// @Param order_type query string false "reconditioning (showroom stock), customer_service or all" default(reconditioning)
 // @Success 200 {object} utils.Response{data=map[string]interface{}}
+// @Failure 400 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /repairs/stats [get]
 func (h *RepairHandler) GetRepairStats(c *gin.Context) {
+	orderType, ok := reportOrderType(c)
+	if !ok {
+		return
+	}
+
 	var mechanicID *int
 	if mechanicIDStr := c.Query("mechanic_id"); mechanicIDStr != "" {
 		if id, err := strconv.Atoi(mechanicIDStr); err == nil {
@@ -387,7 +569,7 @@ func (h *RepairHandler) GetRepairStats(c *gin.Context) {
 		}
 	}
 
-	stats, err := h.repairService.GetRepairStats(mechanicID, dateFrom, dateTo)
+	stats, err := h.repairService.GetRepairStats(mechanicID, dateFrom, dateTo, orderType)
 	if err != nil {
 		utils.SendError(c, http.StatusInternalServerError, "Failed to get repair statistics", err.Error())
 		return
@@ -398,11 +580,11 @@ func (h *RepairHandler) GetRepairStats(c *gin.Context) {
 
 // GetMechanicWorkload gets mechanic workload information
 // @Summary Get mechanic workload
-// @Description Get current workload information for mechanics
+// @Description Get open jobs, estimated hours remaining and overdue jobs per mechanic
 // @Tags repairs
 // @Accept json
 // @Produce json
-// @Success 200 {object} utils.Response{data=[]map[string]interface{}}
+// @Success 200 {object} utils.Response{data=[]models.MechanicWorkload}
 // @Failure 500 {object} utils.Response
 // @Router /repairs/mechanic-workload [get]
 func (h *RepairHandler) GetMechanicWorkload(c *gin.Context) {
@@ -413,4 +595,802 @@ func (h *RepairHandler) GetMechanicWorkload(c *gin.Context) {
 	}
 
 	utils.SendSuccess(c, "Mechanic workload retrieved successfully", workload)
-}
\ No newline at end of file
+}
+
+// ReserveSparePart reserves spare parts for a repair order
+// @Summary Reserve spare part for repair
+// @Description Hold spare part stock for a pending or in-progress repair order
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param request body models.SparePartReservationCreateRequest true "Reservation data"
+// @Success 200 {object} utils.Response{data=models.SparePartReservation}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /repairs/{id}/reservations [post]
+func (h *RepairHandler) ReserveSparePart(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	var req models.SparePartReservationCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	reservedBy, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	reservation, err := h.repairService.ReserveSparePart(id, &req, reservedBy.(int))
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to reserve spare part", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Spare part reserved successfully", reservation)
+}
+
+// ReleaseReservation releases a spare part reservation
+// @Summary Release spare part reservation
+// @Description Release an active spare part reservation on a repair order
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param reservation_id path int true "Reservation ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /repairs/{id}/reservations/{reservation_id} [delete]
+func (h *RepairHandler) ReleaseReservation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	reservationID, err := strconv.Atoi(c.Param("reservation_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid reservation ID", err.Error())
+		return
+	}
+
+	err = h.repairService.ReleaseReservation(id, reservationID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "repair order not found") || strings.HasPrefix(err.Error(), "active reservation not found") {
+			utils.SendError(c, http.StatusNotFound, "Failed to release reservation", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to release reservation", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Reservation released successfully", nil)
+}
+
+// GetRepairReservations gets active spare part reservations of a repair
+// @Summary Get repair reservations
+// @Description Get active spare part reservations held by a repair order
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Success 200 {object} utils.Response{data=[]models.SparePartReservation}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /repairs/{id}/reservations [get]
+func (h *RepairHandler) GetRepairReservations(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	reservations, err := h.repairService.GetRepairReservations(id)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "repair order not found") {
+			utils.SendError(c, http.StatusNotFound, "Repair order not found", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to get repair reservations", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Repair reservations retrieved successfully", reservations)
+}
+
+// AddLaborLine adds a labor line to a repair order
+// @Summary Add labor to repair
+// @Description Add a labor line taken from the service catalog or entered freely
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param request body models.RepairLaborLineCreateRequest true "Labor line data"
+// @Success 201 {object} utils.Response{data=models.RepairLaborLine}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /repairs/{id}/labor [post]
+func (h *RepairHandler) AddLaborLine(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	var req models.RepairLaborLineCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	line, err := h.repairService.AddLaborLine(id, &req)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to add labor line", err.Error())
+		return
+	}
+
+	utils.SendCreated(c, "Labor line added successfully", line)
+}
+
+// RemoveLaborLine removes a labor line from a repair order
+// @Summary Remove labor from repair
+// @Description Remove a labor line from an open repair order
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param line_id path int true "Labor Line ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /repairs/{id}/labor/{line_id} [delete]
+func (h *RepairHandler) RemoveLaborLine(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	lineID, err := strconv.Atoi(c.Param("line_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid labor line ID", err.Error())
+		return
+	}
+
+	err = h.repairService.RemoveLaborLine(id, lineID)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "Failed to remove labor line", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Labor line removed successfully", nil)
+}
+
+// GetRepairLaborLines gets labor lines of a repair order
+// @Summary Get repair labor lines
+// @Description Get all labor lines recorded on a repair order
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Success 200 {object} utils.Response{data=[]models.RepairLaborLine}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /repairs/{id}/labor [get]
+func (h *RepairHandler) GetRepairLaborLines(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	laborLines, err := h.repairService.GetRepairLaborLines(id)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "Failed to get repair labor lines", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Repair labor lines retrieved successfully", laborLines)
+}
+
+// AttachChecklist attaches an inspection checklist to a repair order
+// @Summary Attach inspection checklist
+// @Description Copy the items of a checklist template onto a repair order; without template_id the template of the vehicle type is used
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param request body models.RepairChecklistAttachRequest false "Template selection"
+// @Success 201 {object} utils.Response{data=[]models.RepairChecklistItem}
+// @Failure 400 {object} utils.Response
+// @Router /repairs/{id}/checklist [post]
+func (h *RepairHandler) AttachChecklist(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	var req models.RepairChecklistAttachRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+			return
+		}
+	}
+
+	items, err := h.repairService.AttachChecklist(id, &req)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Failed to attach checklist", err.Error())
+		return
+	}
+
+	utils.SendCreated(c, "Checklist attached successfully", items)
+}
+
+// GetRepairChecklist gets the inspection checklist of a repair order
+// @Summary Get repair checklist
+// @Description Get inspection checklist items with their results
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Success 200 {object} utils.Response{data=[]models.RepairChecklistItem}
+// @Failure 404 {object} utils.Response
+// @Router /repairs/{id}/checklist [get]
+func (h *RepairHandler) GetRepairChecklist(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	items, err := h.repairService.GetRepairChecklist(id)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "Failed to get repair checklist", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Repair checklist retrieved successfully", items)
+}
+
+// UpdateChecklistItem records the result of a checklist item
+// @Summary Check checklist item
+// @Description Record pass, fail or needs_attention with an optional note and photo
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param item_id path int true "Checklist Item ID"
+// @Param request body models.RepairChecklistItemUpdateRequest true "Item result"
+// @Success 200 {object} utils.Response{data=models.RepairChecklistItem}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /repairs/{id}/checklist/{item_id} [patch]
+func (h *RepairHandler) UpdateChecklistItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	itemID, err := strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid checklist item ID", err.Error())
+		return
+	}
+
+	var req models.RepairChecklistItemUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	checkedBy, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	item, err := h.repairService.UpdateChecklistItem(id, itemID, &req, checkedBy.(int))
+	if err != nil {
+		if err.Error() == "checklist item not found" {
+			utils.SendError(c, http.StatusNotFound, "Checklist item not found", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusBadRequest, "Failed to update checklist item", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Checklist item updated successfully", item)
+}
+
+// UploadRepairAttachment uploads a photo or document to a repair order
+// @Summary Upload repair attachment
+// @Description Upload a before, during or after photo (JPEG/PNG) or a document (JPEG/PNG/PDF); thumbnails are generated for images
+// @Tags repairs
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param file formData file true "Photo or document"
+// @Param stage formData string true "before, during, after or document"
+// @Param caption formData string false "Caption"
+// @Success 201 {object} utils.Response{data=models.RepairAttachment}
+// @Failure 400 {object} utils.Response
+// @Router /repairs/{id}/attachments [post]
+func (h *RepairHandler) UploadRepairAttachment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	var req models.RepairAttachmentUploadRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "File is required", err.Error())
+		return
+	}
+
+	uploadedBy, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	attachment, err := h.repairService.UploadRepairAttachment(id, &req, file, uploadedBy.(int))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Failed to upload attachment", err.Error())
+		return
+	}
+
+	utils.SendCreated(c, "Attachment uploaded successfully", attachment)
+}
+
+// GetRepairAttachments gets photos and documents of a repair order
+// @Summary Get repair attachments
+// @Description Get photos and documents with download and thumbnail links
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Success 200 {object} utils.Response{data=[]models.RepairAttachment}
+// @Failure 404 {object} utils.Response
+// @Router /repairs/{id}/attachments [get]
+func (h *RepairHandler) GetRepairAttachments(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	attachments, err := h.repairService.GetRepairAttachments(id)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "Failed to get repair attachments", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Repair attachments retrieved successfully", attachments)
+}
+
+// DeleteRepairAttachment deletes a photo or document from a repair order
+// @Summary Delete repair attachment
+// @Description Delete an attachment and its stored files
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /repairs/{id}/attachments/{attachment_id} [delete]
+func (h *RepairHandler) DeleteRepairAttachment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	attachmentID, err := strconv.Atoi(c.Param("attachment_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid attachment ID", err.Error())
+		return
+	}
+
+	err = h.repairService.DeleteRepairAttachment(id, attachmentID)
+	if err != nil {
+		if err.Error() == "attachment not found" {
+			utils.SendError(c, http.StatusNotFound, "Attachment not found", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to delete attachment", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Attachment deleted successfully", nil)
+}
+
+// StartRepairTimer starts a work session on a repair order
+// @Summary Start repair timer
+// @Description Start a timer for the current mechanic; a pending repair is moved to in progress
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param request body models.RepairTimerRequest false "Timer notes"
+// @Success 200 {object} utils.Response{data=models.RepairWorkSession}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /repairs/{id}/timer/start [post]
+func (h *RepairHandler) StartRepairTimer(c *gin.Context) {
+	h.handleRepairTimer(c, h.repairService.StartRepairTimer, "Failed to start timer", "Timer started successfully")
+}
+
+// PauseRepairTimer pauses the running work session on a repair order
+// @Summary Pause repair timer
+// @Description Pause the current mechanic's running timer on a repair order
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param request body models.RepairTimerRequest false "Timer notes"
+// @Success 200 {object} utils.Response{data=models.RepairWorkSession}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /repairs/{id}/timer/pause [post]
+func (h *RepairHandler) PauseRepairTimer(c *gin.Context) {
+	h.handleRepairTimer(c, h.repairService.PauseRepairTimer, "Failed to pause timer", "Timer paused successfully")
+}
+
+// StopRepairTimer stops the running work session on a repair order
+// @Summary Stop repair timer
+// @Description Stop the current mechanic's running timer on a repair order
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param request body models.RepairTimerRequest false "Timer notes"
+// @Success 200 {object} utils.Response{data=models.RepairWorkSession}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /repairs/{id}/timer/stop [post]
+func (h *RepairHandler) StopRepairTimer(c *gin.Context) {
+	h.handleRepairTimer(c, h.repairService.StopRepairTimer, "Failed to stop timer", "Timer stopped successfully")
+}
+
+func (h *RepairHandler) handleRepairTimer(c *gin.Context, action func(int, int, *models.RepairTimerRequest) (*models.RepairWorkSession, error), failureMessage, successMessage string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	// Notes are optional, so an empty body is allowed
+	var req models.RepairTimerRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+			return
+		}
+	}
+
+	mechanicID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	session, err := action(id, mechanicID.(int), &req)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, failureMessage, err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, successMessage, session)
+}
+
+// GetRepairTimeSummary gets time tracking of a repair order
+// @Summary Get repair time tracking
+// @Description Get work sessions, actual hours and catalog standard hours of a repair order
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Success 200 {object} utils.Response{data=models.RepairTimeSummary}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /repairs/{id}/time [get]
+func (h *RepairHandler) GetRepairTimeSummary(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	summary, err := h.repairService.GetRepairTimeSummary(id)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "Failed to get repair time tracking", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Repair time tracking retrieved successfully", summary)
+}
+
+// GetMechanicUtilization gets utilization and efficiency per mechanic
+// @Summary Get mechanic utilization
+// @Description Get logged hours vs available hours (utilization) and standard vs actual hours (efficiency) per mechanic
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param date_from query string false "Period start (YYYY-MM-DD), defaults to first day of current month"
+// @Param date_to query string false "Period end (YYYY-MM-DD), defaults to today"
+// @Param hours_per_day query number false "Available working hours per day" default(8)
+// @Param order_type query string false "reconditioning (showroom stock), customer_service or all" default(reconditioning)
+// @Success 200 {object} utils.Response{data=[]models.MechanicUtilization}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /repairs/mechanic-utilization [get]
+func (h *RepairHandler) GetMechanicUtilization(c *gin.Context) {
+	orderType, ok := reportOrderType(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	dateFrom := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	dateTo := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).Add(24 * time.Hour)
+
+	if dateFromStr := c.Query("date_from"); dateFromStr != "" {
+		date, err := time.ParseInLocation("2006-01-02", dateFromStr, now.Location())
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Invalid date_from", err.Error())
+			return
+		}
+		dateFrom = date
+	}
+	if dateToStr := c.Query("date_to"); dateToStr != "" {
+		date, err := time.ParseInLocation("2006-01-02", dateToStr, now.Location())
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Invalid date_to", err.Error())
+			return
+		}
+		dateTo = date.Add(24 * time.Hour) // Include the whole end day
+	}
+
+	hoursPerDay := 8.0
+	if hoursStr := c.Query("hours_per_day"); hoursStr != "" {
+		if hours, err := strconv.ParseFloat(hoursStr, 64); err == nil {
+			hoursPerDay = hours
+		}
+	}
+
+	utilization, err := h.repairService.GetMechanicUtilization(dateFrom, dateTo, hoursPerDay, orderType)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to get mechanic utilization", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Mechanic utilization retrieved successfully", utilization)
+}
+
+// GetRepairTurnaround gets the average turnaround per mechanic and per vehicle type
+// @Summary Get repair turnaround
+// @Description Get average created-to-completed days and on-time rate against the target completion date, per mechanic and per vehicle type
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param date_from query string false "Period start (YYYY-MM-DD), defaults to first day of current month"
+// @Param date_to query string false "Period end (YYYY-MM-DD), defaults to today"
+// @Param order_type query string false "reconditioning (showroom stock), customer_service or all" default(reconditioning)
+// @Success 200 {object} utils.Response{data=models.RepairTurnaroundReport}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /repairs/turnaround [get]
+func (h *RepairHandler) GetRepairTurnaround(c *gin.Context) {
+	orderType, ok := reportOrderType(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	dateFrom := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	dateTo := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).Add(24 * time.Hour)
+
+	if dateFromStr := c.Query("date_from"); dateFromStr != "" {
+		date, err := time.ParseInLocation("2006-01-02", dateFromStr, now.Location())
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Invalid date_from", err.Error())
+			return
+		}
+		dateFrom = date
+	}
+	if dateToStr := c.Query("date_to"); dateToStr != "" {
+		date, err := time.ParseInLocation("2006-01-02", dateToStr, now.Location())
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Invalid date_to", err.Error())
+			return
+		}
+		dateTo = date.Add(24 * time.Hour) // Include the whole end day
+	}
+
+	report, err := h.repairService.GetTurnaroundReport(dateFrom, dateTo, orderType)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to get repair turnaround", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Repair turnaround retrieved successfully", report)
+}
+
+// reportOrderType reads the order_type of a report. Reports cover showroom reconditioning unless
+// customer_service or all is asked for, so customer and warranty work stays out of stock costs.
+func reportOrderType(c *gin.Context) (models.RepairOrderType, bool) {
+	switch orderType := c.DefaultQuery("order_type", string(models.RepairOrderTypeReconditioning)); orderType {
+	case "all":
+		return "", true
+	case string(models.RepairOrderTypeReconditioning), string(models.RepairOrderTypeCustomerService):
+		return models.RepairOrderType(orderType), true
+	default:
+		utils.SendError(c, http.StatusBadRequest, "Invalid order_type", "use reconditioning, customer_service or all")
+		return "", false
+	}
+}
+
+// GetVehiclesNeedingRepairOrders lists stock vehicles that still need a repair order
+// @Summary Get vehicles needing repair orders
+// @Description Get paginated vehicles that are in repair or in needs_repair/poor condition without an open repair order
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param brand_id query int false "Filter by brand ID"
+// @Param type_id query int false "Filter by vehicle type ID"
+// @Success 200 {object} utils.Response{data=utils.PaginatedResponse{items=[]models.Vehicle}}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /repairs/vehicles-needing-orders [get]
+func (h *RepairHandler) GetVehiclesNeedingRepairOrders(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	var filter models.VehicleRepairNeedFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	vehicles, total, err := h.repairService.GetVehiclesNeedingRepairOrders(filter, page, limit)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to get vehicles needing repair orders", err.Error())
+		return
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	utils.SendSuccess(c, "Vehicles needing repair orders retrieved successfully", gin.H{
+		"vehicles": vehicles,
+		"pagination": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total":        total,
+			"total_pages":  totalPages,
+		},
+	})
+}
+
+// ScheduleRepair schedules or reschedules a repair order on a bay
+// @Summary Schedule repair
+// @Description Book a time slot on a workshop bay and mechanic; overlapping bookings are refused and the mechanic is notified
+// @Tags repairs
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param request body models.RepairScheduleRequest true "Schedule data"
+// @Success 200 {object} utils.Response{data=models.RepairSchedule}
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /repairs/{id}/schedule [put]
+func (h *RepairHandler) ScheduleRepair(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	var req models.RepairScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	scheduledBy, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	schedule, err := h.repairService.ScheduleRepair(id, &req, scheduledBy.(int))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "schedule conflict") {
+			utils.SendError(c, http.StatusConflict, "Schedule conflict", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusBadRequest, "Failed to schedule repair", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Repair scheduled successfully", schedule)
+}
+
+// UnscheduleRepair removes a repair order from the workshop calendar
+// @Summary Unschedule repair
+// @Description Remove the bay booking of a repair order and notify the mechanic
+// @Tags repairs
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /repairs/{id}/schedule [delete]
+func (h *RepairHandler) UnscheduleRepair(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	err = h.repairService.UnscheduleRepair(id)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "Failed to unschedule repair", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Repair unscheduled successfully", nil)
+}
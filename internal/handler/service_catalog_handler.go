@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/service"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+type ServiceCatalogHandler struct {
+	serviceCatalogService service.ServiceCatalogService
+}
+
+func NewServiceCatalogHandler(serviceCatalogService service.ServiceCatalogService) *ServiceCatalogHandler {
+	return &ServiceCatalogHandler{
+		serviceCatalogService: serviceCatalogService,
+	}
+}
+
+// CreateService godoc
+// @Summary Create service catalog entry
+// @Description Create a standard workshop operation with its standard hours and labor rate
+// @Tags service-catalog
+// @Accept json
+// @Produce json
+// @Param request body models.ServiceCatalogCreateRequest true "Service data"
+// @Success 201 {object} utils.APIResponse{data=models.ServiceCatalog}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/service-catalog [post]
+func (h *ServiceCatalogHandler) CreateService(c *gin.Context) {
+	var req models.ServiceCatalogCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	service, err := h.serviceCatalogService.Create(&req)
+	if err != nil {
+		switch err.Error() {
+		case "service code already exists":
+			utils.SendConflict(c, "Service code already exists", err.Error())
+		case "vehicle type not found":
+			utils.SendBadRequest(c, "Invalid vehicle type", err.Error())
+		default:
+			utils.SendInternalServerError(c, "Failed to create service", err.Error())
+		}
+		return
+	}
+
+	utils.SendCreated(c, "Service created successfully", service)
+}
+
+// GetService godoc
+// @Summary Get service catalog entry by ID
+// @Description Get service catalog entry details by ID
+// @Tags service-catalog
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {object} utils.APIResponse{data=models.ServiceCatalog}
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/service-catalog/{id} [get]
+func (h *ServiceCatalogHandler) GetService(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid service ID", err.Error())
+		return
+	}
+
+	service, err := h.serviceCatalogService.GetByID(id)
+	if err != nil {
+		utils.SendNotFound(c, "Service not found")
+		return
+	}
+
+	utils.SendSuccess(c, "Service retrieved successfully", service)
+}
+
+// UpdateService godoc
+// @Summary Update service catalog entry
+// @Description Update service catalog entry information
+// @Tags service-catalog
+// @Accept json
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param request body models.ServiceCatalogUpdateRequest true "Service update data"
+// @Success 200 {object} utils.APIResponse{data=models.ServiceCatalog}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/service-catalog/{id} [put]
+func (h *ServiceCatalogHandler) UpdateService(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid service ID", err.Error())
+		return
+	}
+
+	var req models.ServiceCatalogUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	service, err := h.serviceCatalogService.Update(id, &req)
+	if err != nil {
+		switch err.Error() {
+		case "service catalog entry not found":
+			utils.SendNotFound(c, "Service not found")
+		case "vehicle type not found":
+			utils.SendBadRequest(c, "Invalid vehicle type", err.Error())
+		default:
+			utils.SendInternalServerError(c, "Failed to update service", err.Error())
+		}
+		return
+	}
+
+	utils.SendSuccess(c, "Service updated successfully", service)
+}
+
+// DeleteService godoc
+// @Summary Delete service catalog entry
+// @Description Delete a service catalog entry, or deactivate it when already used on repair orders
+// @Tags service-catalog
+// @Param id path int true "Service ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/service-catalog/{id} [delete]
+func (h *ServiceCatalogHandler) DeleteService(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid service ID", err.Error())
+		return
+	}
+
+	err = h.serviceCatalogService.Delete(id)
+	if err != nil {
+		if err.Error() == "service catalog entry not found" {
+			utils.SendNotFound(c, "Service not found")
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to delete service", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Service deleted successfully", nil)
+}
+
+// ListServices godoc
+// @Summary List service catalog entries
+// @Description Get paginated service catalog with optional search, category, vehicle type and active filters
+// @Tags service-catalog
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param search query string false "Search by code or name"
+// @Param category query string false "Category"
+// @Param vehicle_type_id query int false "Vehicle type ID"
+// @Param is_active query bool false "Active status"
+// @Success 200 {object} utils.APIResponse{data=[]models.ServiceCatalog}
+// @Security BearerAuth
+// @Router /api/service-catalog [get]
+func (h *ServiceCatalogHandler) ListServices(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	var filter models.ServiceCatalogFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.SendBadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+
+	services, total, err := h.serviceCatalogService.List(filter, page, limit)
+	if err != nil {
+		utils.SendInternalServerError(c, "Failed to retrieve services", err.Error())
+		return
+	}
+
+	utils.SendSuccessWithMeta(c, "Services retrieved successfully", services, utils.CalculatePaginationMeta(page, limit, int64(total)))
+}
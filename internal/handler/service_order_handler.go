@@ -0,0 +1,488 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/service"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+// ServiceOrderHandler handles workshop jobs on customer-owned vehicles
+type ServiceOrderHandler struct {
+	repairService  service.RepairService
+	invoiceService service.ServiceInvoiceService
+}
+
+func NewServiceOrderHandler(repairService service.RepairService, invoiceService service.ServiceInvoiceService) *ServiceOrderHandler {
+	return &ServiceOrderHandler{
+		repairService:  repairService,
+		invoiceService: invoiceService,
+	}
+}
+
+// CreateServiceOrder creates a repair order for a customer-owned vehicle
+// @Summary Create service order
+// @Description Create a repair order for a customer-owned vehicle; work starts once the customer approves the estimate
+// @Tags service-orders
+// @Accept json
+// @Produce json
+// @Param request body models.ServiceOrderCreateRequest true "Service order data"
+// @Success 201 {object} utils.Response{data=models.RepairOrder}
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /service-orders [post]
+func (h *ServiceOrderHandler) CreateServiceOrder(c *gin.Context) {
+	var req models.ServiceOrderCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	assignedBy, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	repair, err := h.repairService.CreateServiceOrder(&req, assignedBy.(int))
+	if err != nil {
+		if err.Error() == "customer vehicle not found" {
+			utils.SendError(c, http.StatusNotFound, "Customer vehicle not found", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to create service order", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Service order created successfully", repair)
+}
+
+// ListServiceOrders lists repair orders on customer-owned vehicles
+// @Summary List service orders
+// @Description Get paginated list of customer service orders with optional filters
+// @Tags service-orders
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status"
+// @Param customer_id query int false "Filter by customer ID"
+// @Param mechanic_id query int false "Filter by mechanic ID"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /service-orders [get]
+func (h *ServiceOrderHandler) ListServiceOrders(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	var filter models.RepairOrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid filter parameters", err.Error())
+		return
+	}
+	filter.OrderType = models.RepairOrderTypeCustomerService
+
+	repairs, total, err := h.repairService.ListRepairOrders(filter, page, limit)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to retrieve service orders", err.Error())
+		return
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	utils.SendSuccess(c, "Service orders retrieved successfully", gin.H{
+		"repairs": repairs,
+		"pagination": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total":        total,
+			"total_pages":  totalPages,
+		},
+	})
+}
+
+// GetServiceEstimate returns the itemized estimate of a service order
+// @Summary Get service estimate
+// @Description Get the parts and labor estimate of a service order for customer approval
+// @Tags service-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Success 200 {object} utils.Response{data=models.ServiceEstimate}
+// @Failure 400 {object} utils.Response
+// @Router /service-orders/{id}/estimate [get]
+func (h *ServiceOrderHandler) GetServiceEstimate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	estimate, err := h.repairService.GetServiceEstimate(id)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Failed to get service estimate", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Service estimate retrieved successfully", estimate)
+}
+
+// DecideEstimate records the customer's approval or rejection of an estimate
+// @Summary Decide service estimate
+// @Description Record the customer's approval or rejection; a rejected estimate cancels the order
+// @Tags service-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param request body models.EstimateDecisionRequest true "Decision"
+// @Success 200 {object} utils.Response{data=models.RepairOrder}
+// @Failure 400 {object} utils.Response
+// @Router /service-orders/{id}/estimate/decision [post]
+func (h *ServiceOrderHandler) DecideEstimate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	var req models.EstimateDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	repair, err := h.repairService.DecideEstimate(id, &req)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Failed to record estimate decision", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Estimate decision recorded successfully", repair)
+}
+
+// CreateServiceInvoice bills a completed service order
+// @Summary Create service invoice
+// @Description Bill the parts and labor of a completed service order
+// @Tags service-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Param request body models.ServiceInvoiceCreateRequest true "Invoice data"
+// @Success 201 {object} utils.Response{data=models.ServiceInvoice}
+// @Failure 400 {object} utils.Response
+// @Router /service-orders/{id}/invoice [post]
+func (h *ServiceOrderHandler) CreateServiceInvoice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	var req models.ServiceInvoiceCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	processedBy, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	invoice, err := h.invoiceService.CreateInvoice(id, &req, processedBy.(int))
+	if err != nil {
+		if err.Error() == "repair order not found" {
+			utils.SendError(c, http.StatusNotFound, "Repair order not found", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusBadRequest, "Failed to create service invoice", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Service invoice created successfully", invoice)
+}
+
+// GetServiceOrderInvoice gets the invoice of a service order
+// @Summary Get service order invoice
+// @Description Get the invoice of a service order with items and payments
+// @Tags service-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Success 200 {object} utils.Response{data=models.ServiceInvoice}
+// @Failure 404 {object} utils.Response
+// @Router /service-orders/{id}/invoice [get]
+func (h *ServiceOrderHandler) GetServiceOrderInvoice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	invoice, err := h.invoiceService.GetInvoiceByRepairOrder(id)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "Service invoice not found", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Service invoice retrieved successfully", invoice)
+}
+
+// ListServiceInvoices lists service invoices
+// @Summary List service invoices
+// @Description Get paginated list of service invoices with optional filters
+// @Tags service-orders
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param payment_status query string false "Filter by payment status"
+// @Param customer_id query int false "Filter by customer ID"
+// @Param date_from query string false "Invoice date from (YYYY-MM-DD)"
+// @Param date_to query string false "Invoice date to (YYYY-MM-DD)"
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /service-invoices [get]
+func (h *ServiceOrderHandler) ListServiceInvoices(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	var filter models.ServiceInvoiceFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid filter parameters", err.Error())
+		return
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	invoices, total, err := h.invoiceService.ListInvoices(filter, page, limit)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to retrieve service invoices", err.Error())
+		return
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	utils.SendSuccess(c, "Service invoices retrieved successfully", gin.H{
+		"invoices": invoices,
+		"pagination": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total":        total,
+			"total_pages":  totalPages,
+		},
+	})
+}
+
+// GetServiceInvoice gets a service invoice by ID
+// @Summary Get service invoice
+// @Description Get service invoice details with items and payments
+// @Tags service-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Service Invoice ID"
+// @Success 200 {object} utils.Response{data=models.ServiceInvoice}
+// @Failure 404 {object} utils.Response
+// @Router /service-invoices/{id} [get]
+func (h *ServiceOrderHandler) GetServiceInvoice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid service invoice ID", err.Error())
+		return
+	}
+
+	invoice, err := h.invoiceService.GetInvoice(id)
+	if err != nil {
+		utils.SendError(c, http.StatusNotFound, "Service invoice not found", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Service invoice retrieved successfully", invoice)
+}
+
+// AddServicePayment records a payment against a service invoice
+// @Summary Add service payment
+// @Description Record a (partial) payment against a service invoice
+// @Tags service-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Service Invoice ID"
+// @Param request body models.ServicePaymentCreateRequest true "Payment data"
+// @Success 200 {object} utils.Response{data=models.ServiceInvoice}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /service-invoices/{id}/payments [post]
+func (h *ServiceOrderHandler) AddServicePayment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid service invoice ID", err.Error())
+		return
+	}
+
+	var req models.ServicePaymentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	receivedBy, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	invoice, err := h.invoiceService.AddPayment(id, &req, receivedBy.(int))
+	if err != nil {
+		if err.Error() == "service invoice not found" {
+			utils.SendError(c, http.StatusNotFound, "Service invoice not found", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusBadRequest, "Failed to record payment", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Payment recorded successfully", invoice)
+}
+
+// GetWorkshopServiceReport summarizes customer service revenue
+// @Summary Workshop service report
+// @Description Customer service orders and revenue, reported separately from inventory reconditioning cost
+// @Tags service-orders
+// @Accept json
+// @Produce json
+// @Param date_from query string false "Date from (YYYY-MM-DD), defaults to start of month"
+// @Param date_to query string false "Date to (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} utils.Response{data=models.WorkshopServiceReport}
+// @Failure 400 {object} utils.Response
+// @Router /service-orders/report [get]
+func (h *ServiceOrderHandler) GetWorkshopServiceReport(c *gin.Context) {
+	report, err := h.invoiceService.GetReport(c.Query("date_from"), c.Query("date_to"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Failed to get workshop service report", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Workshop service report retrieved successfully", report)
+}
+
+// CreateEstimateLink creates a shareable link to the estimate of a service order
+// @Summary Create estimate link
+// @Description Create a tokenized, expiring public link where the customer can review and approve or reject the estimate
+// @Tags service-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Repair Order ID"
+// @Success 201 {object} utils.Response{data=models.RepairEstimateLink}
+// @Failure 400 {object} utils.Response
+// @Router /service-orders/{id}/estimate/link [post]
+func (h *ServiceOrderHandler) CreateEstimateLink(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid repair order ID", err.Error())
+		return
+	}
+
+	createdBy, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	link, err := h.repairService.CreateEstimateLink(id, createdBy.(int))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Failed to create estimate link", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Estimate link created successfully", link)
+}
+
+// GetPublicEstimate shows a read-only estimate to the customer
+// @Summary Get public estimate
+// @Description Read-only estimate behind a shared link; no authentication required
+// @Tags public
+// @Produce json
+// @Param token path string true "Estimate link token"
+// @Success 200 {object} utils.Response{data=models.ServiceEstimate}
+// @Failure 404 {object} utils.Response
+// @Failure 410 {object} utils.Response
+// @Router /public/estimates/{token} [get]
+func (h *ServiceOrderHandler) GetPublicEstimate(c *gin.Context) {
+	estimate, err := h.repairService.GetPublicEstimate(c.Param("token"))
+	if err != nil {
+		sendEstimateLinkError(c, "Failed to get estimate", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Estimate retrieved successfully", estimate)
+}
+
+// DecidePublicEstimate lets the customer approve or reject an estimate through a shared link
+// @Summary Decide public estimate
+// @Description Customer approval or rejection with their name; no authentication required
+// @Tags public
+// @Accept json
+// @Produce json
+// @Param token path string true "Estimate link token"
+// @Param request body models.EstimateDecisionRequest true "Decision and customer name"
+// @Success 200 {object} utils.Response{data=models.ServiceEstimate}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 410 {object} utils.Response
+// @Router /public/estimates/{token}/decision [post]
+func (h *ServiceOrderHandler) DecidePublicEstimate(c *gin.Context) {
+	var req models.EstimateDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	estimate, err := h.repairService.DecidePublicEstimate(c.Param("token"), &req)
+	if err != nil {
+		sendEstimateLinkError(c, "Failed to record estimate decision", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Estimate decision recorded successfully", estimate)
+}
+
+func sendEstimateLinkError(c *gin.Context, message string, err error) {
+	switch err.Error() {
+	case "estimate link not found":
+		utils.SendError(c, http.StatusNotFound, "Estimate not found", err.Error())
+	case "estimate link has expired":
+		utils.SendError(c, http.StatusGone, "Estimate link has expired", err.Error())
+	default:
+		utils.SendError(c, http.StatusBadRequest, message, err.Error())
+	}
+}
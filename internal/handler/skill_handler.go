@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/service"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+type SkillHandler struct {
+	skillService service.SkillService
+}
+
+func NewSkillHandler(skillService service.SkillService) *SkillHandler {
+	return &SkillHandler{
+		skillService: skillService,
+	}
+}
+
+// CreateSkill godoc
+// @Summary Create skill
+// @Description Create a mechanic skill linked to a vehicle type and/or service category
+// @Tags skills
+// @Accept json
+// @Produce json
+// @Param request body models.SkillCreateRequest true "Skill data"
+// @Success 201 {object} utils.APIResponse{data=models.Skill}
+// @Failure 400 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/skills [post]
+func (h *SkillHandler) CreateSkill(c *gin.Context) {
+	var req models.SkillCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	skill, err := h.skillService.Create(&req)
+	if err != nil {
+		if err.Error() == "vehicle type not found" {
+			utils.SendBadRequest(c, "Invalid vehicle type", err.Error())
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to create skill", err.Error())
+		return
+	}
+
+	utils.SendCreated(c, "Skill created successfully", skill)
+}
+
+// DeleteSkill godoc
+// @Summary Delete skill
+// @Description Delete a skill and remove it from every user
+// @Tags skills
+// @Param id path int true "Skill ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/skills/{id} [delete]
+func (h *SkillHandler) DeleteSkill(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid skill ID", err.Error())
+		return
+	}
+
+	if err := h.skillService.Delete(id); err != nil {
+		if err.Error() == "skill not found" {
+			utils.SendNotFound(c, "Skill not found")
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to delete skill", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Skill deleted successfully", nil)
+}
+
+// ListSkills godoc
+// @Summary List skills
+// @Description Get list of all mechanic skills
+// @Tags skills
+// @Produce json
+// @Success 200 {object} utils.APIResponse{data=[]models.Skill}
+// @Security BearerAuth
+// @Router /api/skills [get]
+func (h *SkillHandler) ListSkills(c *gin.Context) {
+	skills, err := h.skillService.List()
+	if err != nil {
+		utils.SendInternalServerError(c, "Failed to retrieve skills", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Skills retrieved successfully", skills)
+}
+
+// GetUserSkills godoc
+// @Summary Get user skills
+// @Description Get skills and certifications held by a user
+// @Tags skills
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.APIResponse{data=[]models.UserSkill}
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/users/{id}/skills [get]
+func (h *SkillHandler) GetUserSkills(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	skills, err := h.skillService.GetUserSkills(userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			utils.SendNotFound(c, "User not found")
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to retrieve user skills", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "User skills retrieved successfully", skills)
+}
+
+// AssignUserSkill godoc
+// @Summary Assign skill to user
+// @Description Grant a skill or certification to a user; re-assigning updates the certification
+// @Tags skills
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body models.UserSkillAssignRequest true "Skill assignment"
+// @Success 200 {object} utils.APIResponse{data=models.UserSkill}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/users/{id}/skills [post]
+func (h *SkillHandler) AssignUserSkill(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	var req models.UserSkillAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	skill, err := h.skillService.AssignToUser(userID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			utils.SendNotFound(c, "User not found")
+		case "skill not found":
+			utils.SendNotFound(c, "Skill not found")
+		default:
+			utils.SendBadRequest(c, "Failed to assign skill", err.Error())
+		}
+		return
+	}
+
+	utils.SendSuccess(c, "Skill assigned successfully", skill)
+}
+
+// RemoveUserSkill godoc
+// @Summary Remove skill from user
+// @Description Remove a skill or certification from a user
+// @Tags skills
+// @Param id path int true "User ID"
+// @Param skill_id path int true "Skill ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/users/{id}/skills/{skill_id} [delete]
+func (h *SkillHandler) RemoveUserSkill(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	skillID, err := strconv.Atoi(c.Param("skill_id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid skill ID", err.Error())
+		return
+	}
+
+	if err := h.skillService.RemoveFromUser(userID, skillID); err != nil {
+		if err.Error() == "user skill not found" {
+			utils.SendNotFound(c, "User skill not found")
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to remove skill", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Skill removed successfully", nil)
+}
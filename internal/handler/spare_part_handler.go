@@ -285,6 +285,46 @@ func (h *SparePartHandler) GetLowStockItems(c *gin.Context) {
 	})
 }
 
+// GetStockMovements handles GET /api/spare-parts/:id/movements
+func (h *SparePartHandler) GetStockMovements(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid spare part ID", "Spare part ID must be a number")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	movements, total, err := h.sparePartService.GetMovements(id, page, limit)
+	if err != nil {
+		if err.Error() == "spare part not found" {
+			utils.SendError(c, http.StatusNotFound, "Spare part not found", "Spare part with this ID does not exist")
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to get stock movements", err.Error())
+		return
+	}
+
+	totalPages := (int(total) + limit - 1) / limit
+
+	utils.SendSuccess(c, "Stock movements retrieved successfully", gin.H{
+		"movements": movements,
+		"pagination": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total":        total,
+			"total_pages":  totalPages,
+		},
+	})
+}
+
 // CheckStockAvailability handles GET /api/spare-parts/:id/stock-check?quantity=N
 func (h *SparePartHandler) CheckStockAvailability(c *gin.Context) {
 	idParam := c.Param("id")
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/service"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+// VehicleAttributeHandler handles the custom attributes defined per vehicle type
+type VehicleAttributeHandler struct {
+	attributeService service.VehicleAttributeService
+}
+
+func NewVehicleAttributeHandler(attributeService service.VehicleAttributeService) *VehicleAttributeHandler {
+	return &VehicleAttributeHandler{
+		attributeService: attributeService,
+	}
+}
+
+// CreateAttribute godoc
+// @Summary Create vehicle attribute
+// @Description Define a custom attribute for the vehicles of a type, e.g. seats for cars or ABS for motorcycles
+// @Tags vehicle-attributes
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle Type ID"
+// @Param request body models.VehicleAttributeDefinitionCreateRequest true "Attribute data"
+// @Success 201 {object} utils.APIResponse{data=models.VehicleAttributeDefinition}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-types/{id}/attributes [post]
+func (h *VehicleAttributeHandler) CreateAttribute(c *gin.Context) {
+	typeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle type ID", err.Error())
+		return
+	}
+
+	var req models.VehicleAttributeDefinitionCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	definition, err := h.attributeService.CreateDefinition(typeID, &req)
+	if err != nil {
+		sendVehicleAttributeError(c, "Failed to create vehicle attribute", err)
+		return
+	}
+
+	utils.SendCreated(c, "Vehicle attribute created successfully", definition)
+}
+
+// ListAttributes godoc
+// @Summary List vehicle attributes
+// @Description Get the custom attributes of a vehicle type in display order
+// @Tags vehicle-attributes
+// @Produce json
+// @Param id path int true "Vehicle Type ID"
+// @Param active_only query bool false "Only attributes still accepted on vehicles"
+// @Success 200 {object} utils.APIResponse{data=[]models.VehicleAttributeDefinition}
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-types/{id}/attributes [get]
+func (h *VehicleAttributeHandler) ListAttributes(c *gin.Context) {
+	typeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle type ID", err.Error())
+		return
+	}
+
+	definitions, err := h.attributeService.ListDefinitions(typeID, c.Query("active_only") == "true")
+	if err != nil {
+		sendVehicleAttributeError(c, "Failed to get vehicle attributes", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle attributes retrieved successfully", definitions)
+}
+
+// GetAttribute godoc
+// @Summary Get vehicle attribute by ID
+// @Description Get a custom attribute definition
+// @Tags vehicle-attributes
+// @Produce json
+// @Param id path int true "Vehicle Attribute ID"
+// @Success 200 {object} utils.APIResponse{data=models.VehicleAttributeDefinition}
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-attributes/{id} [get]
+func (h *VehicleAttributeHandler) GetAttribute(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle attribute ID", err.Error())
+		return
+	}
+
+	definition, err := h.attributeService.GetDefinition(id)
+	if err != nil {
+		sendVehicleAttributeError(c, "Failed to get vehicle attribute", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle attribute retrieved successfully", definition)
+}
+
+// UpdateAttribute godoc
+// @Summary Update vehicle attribute
+// @Description Update the name, enum options, required flag, order or availability of an attribute; code and data type are fixed
+// @Tags vehicle-attributes
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle Attribute ID"
+// @Param request body models.VehicleAttributeDefinitionUpdateRequest true "Attribute update data"
+// @Success 200 {object} utils.APIResponse{data=models.VehicleAttributeDefinition}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-attributes/{id} [put]
+func (h *VehicleAttributeHandler) UpdateAttribute(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle attribute ID", err.Error())
+		return
+	}
+
+	var req models.VehicleAttributeDefinitionUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	definition, err := h.attributeService.UpdateDefinition(id, &req)
+	if err != nil {
+		sendVehicleAttributeError(c, "Failed to update vehicle attribute", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle attribute updated successfully", definition)
+}
+
+// DeleteAttribute godoc
+// @Summary Delete vehicle attribute
+// @Description Delete a custom attribute; attributes with values on vehicles can only be deactivated
+// @Tags vehicle-attributes
+// @Param id path int true "Vehicle Attribute ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-attributes/{id} [delete]
+func (h *VehicleAttributeHandler) DeleteAttribute(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle attribute ID", err.Error())
+		return
+	}
+
+	if err := h.attributeService.DeleteDefinition(id); err != nil {
+		sendVehicleAttributeError(c, "Failed to delete vehicle attribute", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle attribute deleted successfully", nil)
+}
+
+func sendVehicleAttributeError(c *gin.Context, message string, err error) {
+	msg := err.Error()
+	switch {
+	case strings.HasSuffix(msg, "not found"):
+		utils.SendNotFound(c, msg)
+	case strings.Contains(msg, "already exists"):
+		utils.SendConflict(c, message, msg)
+	case strings.HasPrefix(msg, "invalid ") || strings.HasPrefix(msg, "cannot "):
+		utils.SendBadRequest(c, message, msg)
+	default:
+		utils.SendInternalServerError(c, message, msg)
+	}
+}
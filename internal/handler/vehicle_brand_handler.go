@@ -2,6 +2,7 @@ package handler
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
@@ -37,6 +38,11 @@ func (h *VehicleBrandHandler) CreateVehicleBrand(c *gin.Context) {
 		return
 	}
 
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
 	brand, err := h.brandService.Create(&req)
 	if err != nil {
 		utils.SendInternalServerError(c, "Failed to create vehicle brand", err.Error())
@@ -117,7 +123,11 @@ func (h *VehicleBrandHandler) GetVehicleBrand(c *gin.Context) {
 
 	brand, err := h.brandService.GetByID(id)
 	if err != nil {
-		utils.SendNotFound(c, "Vehicle brand not found")
+		if strings.HasSuffix(err.Error(), "not found") {
+			utils.SendNotFound(c, "Vehicle brand not found")
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to get vehicle brand", err.Error())
 		return
 	}
 
@@ -151,9 +161,14 @@ func (h *VehicleBrandHandler) UpdateVehicleBrand(c *gin.Context) {
 		return
 	}
 
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
 	brand, err := h.brandService.Update(id, &req)
 	if err != nil {
-		if err.Error() == "vehicle brand not found" {
+		if strings.HasSuffix(err.Error(), "not found") {
 			utils.SendNotFound(c, "Vehicle brand not found")
 			return
 		}
@@ -184,7 +199,7 @@ func (h *VehicleBrandHandler) DeleteVehicleBrand(c *gin.Context) {
 
 	err = h.brandService.Delete(id)
 	if err != nil {
-		if err.Error() == "vehicle brand not found" {
+		if strings.HasSuffix(err.Error(), "not found") {
 			utils.SendNotFound(c, "Vehicle brand not found")
 			return
 		}
@@ -0,0 +1,400 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/service"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+// VehicleDocumentHandler handles the register of BPKB, STNK, receipts and keys held per vehicle
+type VehicleDocumentHandler struct {
+	documentService service.VehicleDocumentService
+}
+
+func NewVehicleDocumentHandler(documentService service.VehicleDocumentService) *VehicleDocumentHandler {
+	return &VehicleDocumentHandler{
+		documentService: documentService,
+	}
+}
+
+// CreateVehicleDocument registers a document held for a vehicle
+// @Summary Register vehicle document
+// @Description Register a BPKB, STNK, purchase invoice, spare key or other document held for a vehicle
+// @Tags vehicle-documents
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param request body models.VehicleDocumentCreateRequest true "Document data"
+// @Success 201 {object} utils.Response{data=models.VehicleDocument}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /vehicles/{id}/documents [post]
+func (h *VehicleDocumentHandler) CreateVehicleDocument(c *gin.Context) {
+	vehicleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid vehicle ID", err.Error())
+		return
+	}
+
+	var req models.VehicleDocumentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	document, err := h.documentService.CreateDocument(vehicleID, &req, userID.(int))
+	if err != nil {
+		sendVehicleDocumentError(c, "Failed to register document", err)
+		return
+	}
+
+	utils.SendCreated(c, "Document registered successfully", document)
+}
+
+// ListVehicleDocuments lists the documents of a vehicle
+// @Summary List vehicle documents
+// @Description Get the documents registered for a vehicle with custody status and scanned attachments
+// @Tags vehicle-documents
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Success 200 {object} utils.Response{data=[]models.VehicleDocument}
+// @Failure 404 {object} utils.Response
+// @Router /vehicles/{id}/documents [get]
+func (h *VehicleDocumentHandler) ListVehicleDocuments(c *gin.Context) {
+	vehicleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid vehicle ID", err.Error())
+		return
+	}
+
+	documents, err := h.documentService.ListVehicleDocuments(vehicleID)
+	if err != nil {
+		sendVehicleDocumentError(c, "Failed to get vehicle documents", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle documents retrieved successfully", documents)
+}
+
+// GetVehicleDocument gets a vehicle document by ID
+// @Summary Get vehicle document
+// @Description Get a vehicle document with its scanned attachments
+// @Tags vehicle-documents
+// @Accept json
+// @Produce json
+// @Param id path int true "Document ID"
+// @Success 200 {object} utils.Response{data=models.VehicleDocument}
+// @Failure 404 {object} utils.Response
+// @Router /vehicle-documents/{id} [get]
+func (h *VehicleDocumentHandler) GetVehicleDocument(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid document ID", err.Error())
+		return
+	}
+
+	document, err := h.documentService.GetDocument(id)
+	if err != nil {
+		sendVehicleDocumentError(c, "Failed to get document", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Document retrieved successfully", document)
+}
+
+// UpdateVehicleDocument updates a vehicle document
+// @Summary Update vehicle document
+// @Description Update number, expiry, storage location or custody status; handed over documents keep their custody status
+// @Tags vehicle-documents
+// @Accept json
+// @Produce json
+// @Param id path int true "Document ID"
+// @Param request body models.VehicleDocumentUpdateRequest true "Document data"
+// @Success 200 {object} utils.Response{data=models.VehicleDocument}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /vehicle-documents/{id} [put]
+func (h *VehicleDocumentHandler) UpdateVehicleDocument(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid document ID", err.Error())
+		return
+	}
+
+	var req models.VehicleDocumentUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	document, err := h.documentService.UpdateDocument(id, &req)
+	if err != nil {
+		sendVehicleDocumentError(c, "Failed to update document", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Document updated successfully", document)
+}
+
+// DeleteVehicleDocument deletes a vehicle document
+// @Summary Delete vehicle document
+// @Description Delete a document registered by mistake together with its scans; handed over documents cannot be deleted
+// @Tags vehicle-documents
+// @Accept json
+// @Produce json
+// @Param id path int true "Document ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /vehicle-documents/{id} [delete]
+func (h *VehicleDocumentHandler) DeleteVehicleDocument(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid document ID", err.Error())
+		return
+	}
+
+	if err := h.documentService.DeleteDocument(id); err != nil {
+		sendVehicleDocumentError(c, "Failed to delete document", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Document deleted successfully", nil)
+}
+
+// UploadVehicleDocumentAttachment uploads a scan of a vehicle document
+// @Summary Upload document scan
+// @Description Upload a scan (JPEG/PNG/PDF) of a vehicle document; thumbnails are generated for images
+// @Tags vehicle-documents
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Document ID"
+// @Param file formData file true "Scan"
+// @Param caption formData string false "Caption"
+// @Success 201 {object} utils.Response{data=models.VehicleDocumentAttachment}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /vehicle-documents/{id}/attachments [post]
+func (h *VehicleDocumentHandler) UploadVehicleDocumentAttachment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid document ID", err.Error())
+		return
+	}
+
+	var req models.VehicleDocumentAttachmentUploadRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "File is required", err.Error())
+		return
+	}
+
+	uploadedBy, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	attachment, err := h.documentService.UploadAttachment(id, &req, file, uploadedBy.(int))
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "not found") {
+			utils.SendError(c, http.StatusNotFound, "Failed to upload attachment", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusBadRequest, "Failed to upload attachment", err.Error())
+		return
+	}
+
+	utils.SendCreated(c, "Attachment uploaded successfully", attachment)
+}
+
+// DeleteVehicleDocumentAttachment deletes a scan of a vehicle document
+// @Summary Delete document scan
+// @Description Delete a scan and its stored files
+// @Tags vehicle-documents
+// @Accept json
+// @Produce json
+// @Param id path int true "Document ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /vehicle-documents/{id}/attachments/{attachment_id} [delete]
+func (h *VehicleDocumentHandler) DeleteVehicleDocumentAttachment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid document ID", err.Error())
+		return
+	}
+
+	attachmentID, err := strconv.Atoi(c.Param("attachment_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid attachment ID", err.Error())
+		return
+	}
+
+	if err := h.documentService.DeleteAttachment(id, attachmentID); err != nil {
+		sendVehicleDocumentError(c, "Failed to delete attachment", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Attachment deleted successfully", nil)
+}
+
+// HandOverDocuments records documents handed over to the buyer of a sale
+// @Summary Hand over documents
+// @Description Record documents of the sold vehicle given to the buyer; each document can be handed over once
+// @Tags vehicle-documents
+// @Accept json
+// @Produce json
+// @Param id path int true "Sales Transaction ID"
+// @Param request body models.DocumentHandoverRequest true "Handover data"
+// @Success 201 {object} utils.Response{data=models.DocumentHandover}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /sales/transactions/{id}/document-handovers [post]
+func (h *VehicleDocumentHandler) HandOverDocuments(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid transaction ID", err.Error())
+		return
+	}
+
+	var req models.DocumentHandoverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	handover, err := h.documentService.HandOver(id, &req, userID.(int))
+	if err != nil {
+		sendVehicleDocumentError(c, "Failed to hand over documents", err)
+		return
+	}
+
+	utils.SendCreated(c, "Documents handed over successfully", handover)
+}
+
+// GetDocumentHandovers lists the document handovers of a sale
+// @Summary Get document handovers
+// @Description Get the handovers of a sale with the documents given to the buyer in each
+// @Tags vehicle-documents
+// @Accept json
+// @Produce json
+// @Param id path int true "Sales Transaction ID"
+// @Success 200 {object} utils.Response{data=[]models.DocumentHandover}
+// @Failure 404 {object} utils.Response
+// @Router /sales/transactions/{id}/document-handovers [get]
+func (h *VehicleDocumentHandler) GetDocumentHandovers(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid transaction ID", err.Error())
+		return
+	}
+
+	handovers, err := h.documentService.GetHandovers(id)
+	if err != nil {
+		sendVehicleDocumentError(c, "Failed to get document handovers", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Document handovers retrieved successfully", handovers)
+}
+
+// ListPendingHandovers lists sold vehicles whose documents were not handed over yet
+// @Summary Pending document handovers
+// @Description Get sold vehicles with documents still held by the showroom, oldest sale first
+// @Tags vehicle-documents
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param document_type query string false "Only this document type (bpkb, stnk, purchase_invoice, spare_key, other)"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /vehicle-documents/pending-handover [get]
+func (h *VehicleDocumentHandler) ListPendingHandovers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	documentType := c.Query("document_type")
+
+	pending, total, err := h.documentService.ListPendingHandovers(documentType, page, limit)
+	if err != nil {
+		sendVehicleDocumentError(c, "Failed to get pending document handovers", err)
+		return
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	totalPages := (total + limit - 1) / limit
+	utils.SendSuccess(c, "Pending document handovers retrieved successfully", gin.H{
+		"data": pending,
+		"pagination": gin.H{
+			"current_page": page,
+			"per_page":     limit,
+			"total":        total,
+			"total_pages":  totalPages,
+		},
+	})
+}
+
+func sendVehicleDocumentError(c *gin.Context, message string, err error) {
+	msg := err.Error()
+	switch {
+	case strings.HasSuffix(msg, "not found"):
+		utils.SendError(c, http.StatusNotFound, message, msg)
+	case strings.HasPrefix(msg, "invalid ") || strings.HasPrefix(msg, "handover time") || strings.HasPrefix(msg, "document ") ||
+		strings.HasPrefix(msg, "handed over documents"):
+		utils.SendError(c, http.StatusBadRequest, message, msg)
+	default:
+		utils.SendError(c, http.StatusInternalServerError, message, msg)
+	}
+}
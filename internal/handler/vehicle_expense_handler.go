@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/service"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+// VehicleExpenseHandler handles taxes and fees paid for vehicles in stock and their due dates
+type VehicleExpenseHandler struct {
+	expenseService service.VehicleExpenseService
+}
+
+func NewVehicleExpenseHandler(expenseService service.VehicleExpenseService) *VehicleExpenseHandler {
+	return &VehicleExpenseHandler{
+		expenseService: expenseService,
+	}
+}
+
+// AddVehicleExpense records a cost paid for a vehicle
+// @Summary Add vehicle expense
+// @Description Record an STNK tax payment, plate renewal or other cost; the amount is added to the vehicle HPP and tax payments move the due dates on
+// @Tags vehicle-expenses
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param request body models.VehicleExpenseCreateRequest true "Expense data"
+// @Success 201 {object} utils.Response{data=models.VehicleExpense}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /vehicles/{id}/expenses [post]
+func (h *VehicleExpenseHandler) AddVehicleExpense(c *gin.Context) {
+	vehicleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid vehicle ID", err.Error())
+		return
+	}
+
+	var req models.VehicleExpenseCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	expense, err := h.expenseService.AddExpense(vehicleID, &req, userID.(int))
+	if err != nil {
+		sendVehicleExpenseError(c, "Failed to add vehicle expense", err)
+		return
+	}
+
+	utils.SendCreated(c, "Vehicle expense added successfully", expense)
+}
+
+// ListVehicleExpenses lists the expenses of a vehicle
+// @Summary List vehicle expenses
+// @Description Get taxes and fees paid for a vehicle, newest first
+// @Tags vehicle-expenses
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Success 200 {object} utils.Response{data=[]models.VehicleExpense}
+// @Failure 404 {object} utils.Response
+// @Router /vehicles/{id}/expenses [get]
+func (h *VehicleExpenseHandler) ListVehicleExpenses(c *gin.Context) {
+	vehicleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid vehicle ID", err.Error())
+		return
+	}
+
+	expenses, err := h.expenseService.ListExpenses(vehicleID)
+	if err != nil {
+		sendVehicleExpenseError(c, "Failed to get vehicle expenses", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle expenses retrieved successfully", expenses)
+}
+
+// DeleteVehicleExpense deletes an expense recorded by mistake
+// @Summary Delete vehicle expense
+// @Description Delete an expense and take it out of the vehicle HPP; due dates are not moved back
+// @Tags vehicle-expenses
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param expense_id path int true "Expense ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /vehicles/{id}/expenses/{expense_id} [delete]
+func (h *VehicleExpenseHandler) DeleteVehicleExpense(c *gin.Context) {
+	vehicleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid vehicle ID", err.Error())
+		return
+	}
+
+	expenseID, err := strconv.Atoi(c.Param("expense_id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid expense ID", err.Error())
+		return
+	}
+
+	if err := h.expenseService.DeleteExpense(vehicleID, expenseID); err != nil {
+		sendVehicleExpenseError(c, "Failed to delete vehicle expense", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle expense deleted successfully", nil)
+}
+
+// ListRegistrationDue lists vehicles in stock with STNK tax or plate renewal coming up
+// @Summary Registration due dates
+// @Description Get STNK tax and plate renewal dates of vehicles in stock that are overdue or due within the given days
+// @Tags vehicle-expenses
+// @Accept json
+// @Produce json
+// @Param days query int false "Look-ahead in days, defaults to REGISTRATION_DUE_DAYS"
+// @Success 200 {object} utils.Response{data=[]models.VehicleRegistrationDue}
+// @Failure 500 {object} utils.Response
+// @Router /vehicles/registration-due [get]
+func (h *VehicleExpenseHandler) ListRegistrationDue(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "0"))
+
+	dues, err := h.expenseService.ListRegistrationDue(days)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Failed to get registration due dates", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Registration due dates retrieved successfully", dues)
+}
+
+func sendVehicleExpenseError(c *gin.Context, message string, err error) {
+	msg := err.Error()
+	switch {
+	case strings.HasSuffix(msg, "not found"):
+		utils.SendError(c, http.StatusNotFound, message, msg)
+	case strings.HasPrefix(msg, "invalid ") || strings.HasPrefix(msg, "cannot ") || strings.HasPrefix(msg, "next due date"):
+		utils.SendError(c, http.StatusBadRequest, message, msg)
+	default:
+		utils.SendError(c, http.StatusInternalServerError, message, msg)
+	}
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
@@ -148,6 +149,10 @@ func (h *VehicleHandler) CreateVehicle(c *gin.Context) {
 			utils.SendConflict(c, "Vehicle already exists", err.Error())
 			return
 		}
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			utils.SendBadRequest(c, "Invalid vehicle data", err.Error())
+			return
+		}
 		utils.SendInternalServerError(c, "Failed to create vehicle", err.Error())
 		return
 	}
@@ -209,12 +214,22 @@ func (h *VehicleHandler) UpdateVehicle(c *gin.Context) {
 		return
 	}
 
-	vehicle, err := h.vehicleService.Update(id, &req)
+	userID, _, _, _, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.SendUnauthorized(c, "Invalid token")
+		return
+	}
+
+	vehicle, err := h.vehicleService.Update(id, &req, userID)
 	if err != nil {
 		if err.Error() == "vehicle not found" {
 			utils.SendNotFound(c, "Vehicle not found")
 			return
 		}
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			utils.SendBadRequest(c, "Invalid vehicle data", err.Error())
+			return
+		}
 		utils.SendInternalServerError(c, "Failed to update vehicle", err.Error())
 		return
 	}
@@ -342,7 +357,13 @@ func (h *VehicleHandler) SetSellingPrice(c *gin.Context) {
 		return
 	}
 
-	err = h.vehicleService.SetSellingPrice(id, req.SellingPrice)
+	userID, _, _, _, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.SendUnauthorized(c, "Invalid token")
+		return
+	}
+
+	err = h.vehicleService.SetSellingPrice(id, req.SellingPrice, userID)
 	if err != nil {
 		if err.Error() == "vehicle not found" {
 			utils.SendNotFound(c, "Vehicle not found")
@@ -359,6 +380,37 @@ func (h *VehicleHandler) SetSellingPrice(c *gin.Context) {
 	utils.SendSuccess(c, "Selling price updated successfully", nil)
 }
 
+// GetVehicleTimeline godoc
+// @Summary Get vehicle timeline
+// @Description Get every event of a vehicle in order: intake and purchase, inspections, repairs and their parts, expenses, price changes, reservations, the sale, payments and document handover, each with its actor and amount
+// @Tags vehicles
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Success 200 {object} utils.APIResponse{data=[]models.VehicleTimelineEvent}
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicles/{id}/timeline [get]
+func (h *VehicleHandler) GetVehicleTimeline(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle ID", err.Error())
+		return
+	}
+
+	events, err := h.vehicleService.GetTimeline(id)
+	if err != nil {
+		if err.Error() == "vehicle not found" {
+			utils.SendNotFound(c, "Vehicle not found")
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to get vehicle timeline", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle timeline retrieved successfully", events)
+}
+
 // SearchVehicles godoc
 // @Summary Search vehicles with advanced filters
 // @Description Search vehicles by brand, model, year, color, odometer, etc.
@@ -368,6 +420,8 @@ func (h *VehicleHandler) SetSellingPrice(c *gin.Context) {
 // @Param limit query int false "Items per page" default(10)
 // @Param brand_id query int false "Filter by brand ID"
 // @Param model query string false "Filter by model (partial match)"
+// @Param model_id query int false "Filter by catalog model ID"
+// @Param variant_id query int false "Filter by catalog variant ID"
 // @Param year_min query int false "Minimum year"
 // @Param year_max query int false "Maximum year"
 // @Param color query string false "Filter by color"
@@ -376,6 +430,9 @@ func (h *VehicleHandler) SetSellingPrice(c *gin.Context) {
 // @Param price_min query float64 false "Minimum price"
 // @Param price_max query float64 false "Maximum price"
 // @Param status query string false "Filter by status"
+// @Param attr[code] query string false "Filter by custom attribute value, e.g. attr[drive_type]=4WD"
+// @Param attr_min[code] query number false "Minimum of a number attribute, e.g. attr_min[seats]=7"
+// @Param attr_max[code] query number false "Maximum of a number attribute"
 // @Success 200 {object} utils.APIResponse{data=[]models.Vehicle}
 // @Security BearerAuth
 // @Router /api/vehicles/search [get]
@@ -387,6 +444,8 @@ func (h *VehicleHandler) SearchVehicles(c *gin.Context) {
 	filters := models.VehicleSearchFilters{
 		BrandID:     parseIntQuery(c, "brand_id"),
 		Model:       c.Query("model"),
+		ModelID:     parseIntQuery(c, "model_id"),
+		VariantID:   parseIntQuery(c, "variant_id"),
 		YearMin:     parseIntQuery(c, "year_min"),
 		YearMax:     parseIntQuery(c, "year_max"),
 		Color:       c.Query("color"),
@@ -395,10 +454,18 @@ func (h *VehicleHandler) SearchVehicles(c *gin.Context) {
 		PriceMin:    parseFloatQuery(c, "price_min"),
 		PriceMax:    parseFloatQuery(c, "price_max"),
 		Status:      c.Query("status"),
+		// attr[seats]=7, attr_min[engine_cc]=150
+		Attributes:   c.QueryMap("attr"),
+		AttributeMin: c.QueryMap("attr_min"),
+		AttributeMax: c.QueryMap("attr_max"),
 	}
 
 	vehicles, total, err := h.vehicleService.SearchVehicles(page, limit, filters)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			utils.SendBadRequest(c, "Invalid search filters", err.Error())
+			return
+		}
 		utils.SendInternalServerError(c, "Failed to search vehicles", err.Error())
 		return
 	}
@@ -425,6 +492,215 @@ func (h *VehicleHandler) GetVehicleBrands(c *gin.Context) {
 	utils.SendSuccess(c, "Vehicle brands retrieved successfully", brands)
 }
 
+// UploadVehiclePhoto godoc
+// @Summary Upload vehicle photo
+// @Description Upload a JPEG or PNG photo to the vehicle gallery; a thumbnail is generated and the first photo becomes primary
+// @Tags vehicles
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param file formData file true "Photo"
+// @Param caption formData string false "Caption"
+// @Param is_primary formData bool false "Make this the primary photo"
+// @Success 201 {object} utils.APIResponse{data=models.VehiclePhoto}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicles/{id}/photos [post]
+func (h *VehicleHandler) UploadVehiclePhoto(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle ID", err.Error())
+		return
+	}
+
+	var req models.VehiclePhotoUploadRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.SendBadRequest(c, "File is required", err.Error())
+		return
+	}
+
+	userID, _, _, _, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		utils.SendUnauthorized(c, "Invalid token")
+		return
+	}
+
+	photo, err := h.vehicleService.UploadPhoto(id, &req, file, userID)
+	if err != nil {
+		if err.Error() == "vehicle not found" {
+			utils.SendNotFound(c, "Vehicle not found")
+			return
+		}
+		utils.SendBadRequest(c, "Failed to upload photo", err.Error())
+		return
+	}
+
+	utils.SendCreated(c, "Photo uploaded successfully", photo)
+}
+
+// GetVehiclePhotos godoc
+// @Summary Get vehicle photos
+// @Description Get the vehicle gallery in display order with download and thumbnail links
+// @Tags vehicles
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Success 200 {object} utils.APIResponse{data=[]models.VehiclePhoto}
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicles/{id}/photos [get]
+func (h *VehicleHandler) GetVehiclePhotos(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle ID", err.Error())
+		return
+	}
+
+	photos, err := h.vehicleService.GetPhotos(id)
+	if err != nil {
+		if err.Error() == "vehicle not found" {
+			utils.SendNotFound(c, "Vehicle not found")
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to get photos", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Photos retrieved successfully", photos)
+}
+
+// ReorderVehiclePhotos godoc
+// @Summary Reorder vehicle photos
+// @Description Set the display order of the gallery; photo_ids must list every photo of the vehicle
+// @Tags vehicles
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param request body models.VehiclePhotoReorderRequest true "Photo IDs in display order"
+// @Success 200 {object} utils.APIResponse{data=[]models.VehiclePhoto}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicles/{id}/photos/order [put]
+func (h *VehicleHandler) ReorderVehiclePhotos(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle ID", err.Error())
+		return
+	}
+
+	var req models.VehiclePhotoReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	photos, err := h.vehicleService.ReorderPhotos(id, &req)
+	if err != nil {
+		if err.Error() == "vehicle not found" {
+			utils.SendNotFound(c, "Vehicle not found")
+			return
+		}
+		if strings.HasPrefix(err.Error(), "photo order") {
+			utils.SendBadRequest(c, "Invalid photo order", err.Error())
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to reorder photos", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Photos reordered successfully", photos)
+}
+
+// SetPrimaryVehiclePhoto godoc
+// @Summary Set primary vehicle photo
+// @Description Make a photo the primary photo of the vehicle, replacing the previous one
+// @Tags vehicles
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param photo_id path int true "Photo ID"
+// @Success 200 {object} utils.APIResponse{data=[]models.VehiclePhoto}
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicles/{id}/photos/{photo_id}/primary [put]
+func (h *VehicleHandler) SetPrimaryVehiclePhoto(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle ID", err.Error())
+		return
+	}
+
+	photoID, err := strconv.Atoi(c.Param("photo_id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid photo ID", err.Error())
+		return
+	}
+
+	photos, err := h.vehicleService.SetPrimaryPhoto(id, photoID)
+	if err != nil {
+		if err.Error() == "vehicle not found" || err.Error() == "vehicle photo not found" {
+			utils.SendNotFound(c, "Photo not found")
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to set primary photo", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Primary photo updated successfully", photos)
+}
+
+// DeleteVehiclePhoto godoc
+// @Summary Delete vehicle photo
+// @Description Delete a photo and its stored files; the next photo becomes primary when the primary photo is deleted
+// @Tags vehicles
+// @Param id path int true "Vehicle ID"
+// @Param photo_id path int true "Photo ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicles/{id}/photos/{photo_id} [delete]
+func (h *VehicleHandler) DeleteVehiclePhoto(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle ID", err.Error())
+		return
+	}
+
+	photoID, err := strconv.Atoi(c.Param("photo_id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid photo ID", err.Error())
+		return
+	}
+
+	err = h.vehicleService.DeletePhoto(id, photoID)
+	if err != nil {
+		if err.Error() == "vehicle not found" || err.Error() == "vehicle photo not found" {
+			utils.SendNotFound(c, "Photo not found")
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to delete photo", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Photo deleted successfully", nil)
+}
+
 // Helper functions for parsing query parameters
 func parseIntQuery(c *gin.Context, key string) *int {
 	if val := c.Query(key); val != "" {
@@ -0,0 +1,427 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/service"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+// VehicleModelHandler handles the model and variant catalog under vehicle brands
+type VehicleModelHandler struct {
+	modelService service.VehicleModelService
+}
+
+func NewVehicleModelHandler(modelService service.VehicleModelService) *VehicleModelHandler {
+	return &VehicleModelHandler{
+		modelService: modelService,
+	}
+}
+
+// CreateModel godoc
+// @Summary Create vehicle model
+// @Description Add a model to the catalog of a brand
+// @Tags vehicle-models
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle Brand ID"
+// @Param request body models.VehicleModelCreateRequest true "Model data"
+// @Success 201 {object} utils.APIResponse{data=models.VehicleModel}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-brands/{id}/models [post]
+func (h *VehicleModelHandler) CreateModel(c *gin.Context) {
+	brandID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle brand ID", err.Error())
+		return
+	}
+
+	var req models.VehicleModelCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	model, err := h.modelService.CreateModel(brandID, &req)
+	if err != nil {
+		sendVehicleModelError(c, "Failed to create vehicle model", err)
+		return
+	}
+
+	utils.SendCreated(c, "Vehicle model created successfully", model)
+}
+
+// ListBrandModels godoc
+// @Summary List vehicle models of a brand
+// @Description Get the catalog of a brand, each model with its variants and their default specs
+// @Tags vehicle-models
+// @Produce json
+// @Param id path int true "Vehicle Brand ID"
+// @Param active_only query bool false "Only models and variants still offered"
+// @Success 200 {object} utils.APIResponse{data=[]models.VehicleModel}
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-brands/{id}/models [get]
+func (h *VehicleModelHandler) ListBrandModels(c *gin.Context) {
+	brandID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle brand ID", err.Error())
+		return
+	}
+
+	activeOnly := c.Query("active_only") == "true"
+
+	vehicleModels, err := h.modelService.ListBrandModels(brandID, activeOnly)
+	if err != nil {
+		sendVehicleModelError(c, "Failed to get vehicle models", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle models retrieved successfully", vehicleModels)
+}
+
+// GetModel godoc
+// @Summary Get vehicle model by ID
+// @Description Get a catalog model with its variants
+// @Tags vehicle-models
+// @Produce json
+// @Param id path int true "Vehicle Model ID"
+// @Success 200 {object} utils.APIResponse{data=models.VehicleModel}
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-models/{id} [get]
+func (h *VehicleModelHandler) GetModel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle model ID", err.Error())
+		return
+	}
+
+	model, err := h.modelService.GetModel(id)
+	if err != nil {
+		sendVehicleModelError(c, "Failed to get vehicle model", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle model retrieved successfully", model)
+}
+
+// UpdateModel godoc
+// @Summary Update vehicle model
+// @Description Rename or deactivate a catalog model; vehicles of the model take the new name
+// @Tags vehicle-models
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle Model ID"
+// @Param request body models.VehicleModelUpdateRequest true "Model update data"
+// @Success 200 {object} utils.APIResponse{data=models.VehicleModel}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-models/{id} [put]
+func (h *VehicleModelHandler) UpdateModel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle model ID", err.Error())
+		return
+	}
+
+	var req models.VehicleModelUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	model, err := h.modelService.UpdateModel(id, &req)
+	if err != nil {
+		sendVehicleModelError(c, "Failed to update vehicle model", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle model updated successfully", model)
+}
+
+// DeleteModel godoc
+// @Summary Delete vehicle model
+// @Description Delete a catalog model and its variants; models used by vehicles can only be deactivated
+// @Tags vehicle-models
+// @Param id path int true "Vehicle Model ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-models/{id} [delete]
+func (h *VehicleModelHandler) DeleteModel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle model ID", err.Error())
+		return
+	}
+
+	if err := h.modelService.DeleteModel(id); err != nil {
+		sendVehicleModelError(c, "Failed to delete vehicle model", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle model deleted successfully", nil)
+}
+
+// CreateVariant godoc
+// @Summary Create vehicle variant
+// @Description Add a variant with its default engine capacity, fuel and transmission to a model
+// @Tags vehicle-models
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle Model ID"
+// @Param request body models.VehicleVariantCreateRequest true "Variant data"
+// @Success 201 {object} utils.APIResponse{data=models.VehicleVariant}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-models/{id}/variants [post]
+func (h *VehicleModelHandler) CreateVariant(c *gin.Context) {
+	modelID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle model ID", err.Error())
+		return
+	}
+
+	var req models.VehicleVariantCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	variant, err := h.modelService.CreateVariant(modelID, &req)
+	if err != nil {
+		sendVehicleModelError(c, "Failed to create vehicle variant", err)
+		return
+	}
+
+	utils.SendCreated(c, "Vehicle variant created successfully", variant)
+}
+
+// ListVariants godoc
+// @Summary List vehicle variants
+// @Description Get the variants of a catalog model
+// @Tags vehicle-models
+// @Produce json
+// @Param id path int true "Vehicle Model ID"
+// @Param active_only query bool false "Only variants still offered"
+// @Success 200 {object} utils.APIResponse{data=[]models.VehicleVariant}
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-models/{id}/variants [get]
+func (h *VehicleModelHandler) ListVariants(c *gin.Context) {
+	modelID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle model ID", err.Error())
+		return
+	}
+
+	variants, err := h.modelService.ListVariants(modelID, c.Query("active_only") == "true")
+	if err != nil {
+		sendVehicleModelError(c, "Failed to get vehicle variants", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle variants retrieved successfully", variants)
+}
+
+// GetVariant godoc
+// @Summary Get vehicle variant by ID
+// @Description Get a variant with the default specs used to prefill a new vehicle
+// @Tags vehicle-models
+// @Produce json
+// @Param id path int true "Vehicle Variant ID"
+// @Success 200 {object} utils.APIResponse{data=models.VehicleVariant}
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-variants/{id} [get]
+func (h *VehicleModelHandler) GetVariant(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle variant ID", err.Error())
+		return
+	}
+
+	variant, err := h.modelService.GetVariant(id)
+	if err != nil {
+		sendVehicleModelError(c, "Failed to get vehicle variant", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle variant retrieved successfully", variant)
+}
+
+// UpdateVariant godoc
+// @Summary Update vehicle variant
+// @Description Update the name, default specs, production years or availability of a variant
+// @Tags vehicle-models
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle Variant ID"
+// @Param request body models.VehicleVariantUpdateRequest true "Variant update data"
+// @Success 200 {object} utils.APIResponse{data=models.VehicleVariant}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-variants/{id} [put]
+func (h *VehicleModelHandler) UpdateVariant(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle variant ID", err.Error())
+		return
+	}
+
+	var req models.VehicleVariantUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	variant, err := h.modelService.UpdateVariant(id, &req)
+	if err != nil {
+		sendVehicleModelError(c, "Failed to update vehicle variant", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle variant updated successfully", variant)
+}
+
+// DeleteVariant godoc
+// @Summary Delete vehicle variant
+// @Description Delete a variant; variants used by vehicles can only be deactivated
+// @Tags vehicle-models
+// @Param id path int true "Vehicle Variant ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-variants/{id} [delete]
+func (h *VehicleModelHandler) DeleteVariant(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid vehicle variant ID", err.Error())
+		return
+	}
+
+	if err := h.modelService.DeleteVariant(id); err != nil {
+		sendVehicleModelError(c, "Failed to delete vehicle variant", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle variant deleted successfully", nil)
+}
+
+// ListUnmapped godoc
+// @Summary List free-text vehicle models
+// @Description Get the free-text models still used by vehicles, grouped per brand with a vehicle count and the catalog entry whose name matches
+// @Tags vehicle-models
+// @Produce json
+// @Success 200 {object} utils.APIResponse{data=[]models.UnmappedVehicleModel}
+// @Security BearerAuth
+// @Router /api/vehicle-models/unmapped [get]
+func (h *VehicleModelHandler) ListUnmapped(c *gin.Context) {
+	unmapped, err := h.modelService.ListUnmapped()
+	if err != nil {
+		utils.SendInternalServerError(c, "Failed to get unmapped vehicle models", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Unmapped vehicle models retrieved successfully", unmapped)
+}
+
+// MapFreeText godoc
+// @Summary Map a free-text vehicle model
+// @Description Link every vehicle of the brand with the given free-text model to a catalog model and variant; the vehicles take the catalog name and missing specs from the variant
+// @Tags vehicle-models
+// @Accept json
+// @Produce json
+// @Param request body models.VehicleModelMappingRequest true "Mapping"
+// @Success 200 {object} utils.APIResponse{data=models.VehicleModelMappingResult}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/vehicle-models/map [post]
+func (h *VehicleModelHandler) MapFreeText(c *gin.Context) {
+	var req models.VehicleModelMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	result, err := h.modelService.MapFreeText(&req)
+	if err != nil {
+		sendVehicleModelError(c, "Failed to map vehicle model", err)
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle model mapped successfully", result)
+}
+
+// AutoMap godoc
+// @Summary Map matching free-text vehicle models
+// @Description Map every free-text model whose name matches a catalog model, or a model and variant such as 'Beat FI'
+// @Tags vehicle-models
+// @Produce json
+// @Success 200 {object} utils.APIResponse{data=models.VehicleModelMappingResult}
+// @Security BearerAuth
+// @Router /api/vehicle-models/auto-map [post]
+func (h *VehicleModelHandler) AutoMap(c *gin.Context) {
+	result, err := h.modelService.AutoMap()
+	if err != nil {
+		utils.SendInternalServerError(c, "Failed to map vehicle models", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Vehicle models mapped successfully", result)
+}
+
+func sendVehicleModelError(c *gin.Context, message string, err error) {
+	msg := err.Error()
+	switch {
+	case strings.HasSuffix(msg, "not found"):
+		utils.SendNotFound(c, msg)
+	case strings.Contains(msg, "already exists"):
+		utils.SendConflict(c, message, msg)
+	case strings.HasPrefix(msg, "invalid ") || strings.HasPrefix(msg, "cannot "):
+		utils.SendBadRequest(c, message, msg)
+	default:
+		utils.SendInternalServerError(c, message, msg)
+	}
+}
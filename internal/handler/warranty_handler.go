@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/service"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+// WarrantyHandler handles after-sales warranty terms and claims on sold vehicles
+type WarrantyHandler struct {
+	warrantyService service.WarrantyService
+}
+
+func NewWarrantyHandler(warrantyService service.WarrantyService) *WarrantyHandler {
+	return &WarrantyHandler{
+		warrantyService: warrantyService,
+	}
+}
+
+// SetWarranty sets the warranty terms of a sale
+// @Summary Set sales warranty
+// @Description Set coverage, duration and odometer limit of the showroom warranty given with a sale; coverage starts on the sale date
+// @Tags warranties
+// @Accept json
+// @Produce json
+// @Param id path int true "Sales Transaction ID"
+// @Param request body models.SalesWarrantyRequest true "Warranty terms"
+// @Success 200 {object} utils.Response{data=models.SalesWarranty}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /sales/transactions/{id}/warranty [put]
+func (h *WarrantyHandler) SetWarranty(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid transaction ID", err.Error())
+		return
+	}
+
+	var req models.SalesWarrantyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	warranty, err := h.warrantyService.SetWarranty(id, &req, userID.(int))
+	if err != nil {
+		if err.Error() == "sales transaction not found" {
+			utils.SendError(c, http.StatusNotFound, "Transaction not found", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to set warranty", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Warranty saved successfully", warranty)
+}
+
+// GetWarranty gets the warranty of a sale with its claims
+// @Summary Get sales warranty
+// @Description Get the warranty terms of a sale, its claims and the warranty expense booked against the sale
+// @Tags warranties
+// @Accept json
+// @Produce json
+// @Param id path int true "Sales Transaction ID"
+// @Success 200 {object} utils.Response{data=models.SalesWarranty}
+// @Failure 404 {object} utils.Response
+// @Router /sales/transactions/{id}/warranty [get]
+func (h *WarrantyHandler) GetWarranty(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid transaction ID", err.Error())
+		return
+	}
+
+	warranty, err := h.warrantyService.GetWarranty(id)
+	if err != nil {
+		if err.Error() == "sales warranty not found" {
+			utils.SendError(c, http.StatusNotFound, "Warranty not found", err.Error())
+			return
+		}
+		utils.SendError(c, http.StatusInternalServerError, "Failed to get warranty", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Warranty retrieved successfully", warranty)
+}
+
+// CreateWarrantyClaim opens a warranty repair order for a sold vehicle
+// @Summary Create warranty claim
+// @Description Open a warranty repair order on the buyer's vehicle; its cost is booked as warranty expense of the sale instead of vehicle HPP
+// @Tags warranties
+// @Accept json
+// @Produce json
+// @Param id path int true "Sales Transaction ID"
+// @Param request body models.WarrantyClaimCreateRequest true "Claim data"
+// @Success 200 {object} utils.Response{data=models.WarrantyClaim}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /sales/transactions/{id}/warranty/claims [post]
+func (h *WarrantyHandler) CreateWarrantyClaim(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid transaction ID", err.Error())
+		return
+	}
+
+	var req models.WarrantyClaimCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request data", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	claim, err := h.warrantyService.CreateClaim(id, &req, userID.(int))
+	if err != nil {
+		switch {
+		case err.Error() == "sales warranty not found" || err.Error() == "sales transaction not found":
+			utils.SendError(c, http.StatusNotFound, "Warranty not found", err.Error())
+		case strings.HasPrefix(err.Error(), "warranty expired") || strings.HasPrefix(err.Error(), "warranty odometer limit") ||
+			strings.HasPrefix(err.Error(), "odometer cannot be lower"):
+			utils.SendError(c, http.StatusBadRequest, "Claim not covered by warranty", err.Error())
+		default:
+			utils.SendError(c, http.StatusInternalServerError, "Failed to create warranty claim", err.Error())
+		}
+		return
+	}
+
+	utils.SendSuccess(c, "Warranty claim created successfully", claim)
+}
+
+// GetWarrantyCostReport reports warranty costs per brand and model
+// @Summary Warranty cost report
+// @Description Warranty repairs completed in the period per brand and model of the sold vehicle
+// @Tags warranties
+// @Accept json
+// @Produce json
+// @Param date_from query string false "Date from (YYYY-MM-DD), defaults to start of month"
+// @Param date_to query string false "Date to (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} utils.Response{data=models.WarrantyCostReport}
+// @Failure 400 {object} utils.Response
+// @Router /sales/warranty-report [get]
+func (h *WarrantyHandler) GetWarrantyCostReport(c *gin.Context) {
+	report, err := h.warrantyService.GetCostReport(c.Query("date_from"), c.Query("date_to"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Failed to get warranty cost report", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Warranty cost report retrieved successfully", report)
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/service"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+type WorkshopHandler struct {
+	workshopService service.WorkshopService
+}
+
+func NewWorkshopHandler(workshopService service.WorkshopService) *WorkshopHandler {
+	return &WorkshopHandler{
+		workshopService: workshopService,
+	}
+}
+
+// CreateBay godoc
+// @Summary Create workshop bay
+// @Description Create a workshop bay or lift that repairs can be scheduled on
+// @Tags workshop
+// @Accept json
+// @Produce json
+// @Param request body models.WorkshopBayCreateRequest true "Bay data"
+// @Success 201 {object} utils.APIResponse{data=models.WorkshopBay}
+// @Failure 400 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/workshop/bays [post]
+func (h *WorkshopHandler) CreateBay(c *gin.Context) {
+	var req models.WorkshopBayCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		utils.SendBadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	bay, err := h.workshopService.CreateBay(&req)
+	if err != nil {
+		utils.SendInternalServerError(c, "Failed to create workshop bay", err.Error())
+		return
+	}
+
+	utils.SendCreated(c, "Workshop bay created successfully", bay)
+}
+
+// GetBay godoc
+// @Summary Get workshop bay by ID
+// @Description Get workshop bay details by ID
+// @Tags workshop
+// @Produce json
+// @Param id path int true "Bay ID"
+// @Success 200 {object} utils.APIResponse{data=models.WorkshopBay}
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/workshop/bays/{id} [get]
+func (h *WorkshopHandler) GetBay(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid bay ID", err.Error())
+		return
+	}
+
+	bay, err := h.workshopService.GetBay(id)
+	if err != nil {
+		utils.SendNotFound(c, "Workshop bay not found")
+		return
+	}
+
+	utils.SendSuccess(c, "Workshop bay retrieved successfully", bay)
+}
+
+// UpdateBay godoc
+// @Summary Update workshop bay
+// @Description Update workshop bay information
+// @Tags workshop
+// @Accept json
+// @Produce json
+// @Param id path int true "Bay ID"
+// @Param request body models.WorkshopBayUpdateRequest true "Bay update data"
+// @Success 200 {object} utils.APIResponse{data=models.WorkshopBay}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/workshop/bays/{id} [put]
+func (h *WorkshopHandler) UpdateBay(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid bay ID", err.Error())
+		return
+	}
+
+	var req models.WorkshopBayUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	bay, err := h.workshopService.UpdateBay(id, &req)
+	if err != nil {
+		if err.Error() == "workshop bay not found" {
+			utils.SendNotFound(c, "Workshop bay not found")
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to update workshop bay", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Workshop bay updated successfully", bay)
+}
+
+// DeleteBay godoc
+// @Summary Delete workshop bay
+// @Description Delete a workshop bay, or deactivate it when it has booking history
+// @Tags workshop
+// @Param id path int true "Bay ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/workshop/bays/{id} [delete]
+func (h *WorkshopHandler) DeleteBay(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SendBadRequest(c, "Invalid bay ID", err.Error())
+		return
+	}
+
+	if err := h.workshopService.DeleteBay(id); err != nil {
+		if err.Error() == "workshop bay not found" {
+			utils.SendNotFound(c, "Workshop bay not found")
+			return
+		}
+		utils.SendInternalServerError(c, "Failed to delete workshop bay", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Workshop bay deleted successfully", nil)
+}
+
+// ListBays godoc
+// @Summary List workshop bays
+// @Description Get list of workshop bays and lifts
+// @Tags workshop
+// @Produce json
+// @Param active query bool false "Only active bays"
+// @Success 200 {object} utils.APIResponse{data=[]models.WorkshopBay}
+// @Security BearerAuth
+// @Router /api/workshop/bays [get]
+func (h *WorkshopHandler) ListBays(c *gin.Context) {
+	activeOnly, _ := strconv.ParseBool(c.Query("active"))
+
+	bays, err := h.workshopService.ListBays(activeOnly)
+	if err != nil {
+		utils.SendInternalServerError(c, "Failed to retrieve workshop bays", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Workshop bays retrieved successfully", bays)
+}
+
+// GetCalendar godoc
+// @Summary Get workshop calendar
+// @Description Get scheduled repairs per bay for a day or week (weeks start on Monday)
+// @Tags workshop
+// @Produce json
+// @Param view query string false "day or week" default(day)
+// @Param date query string false "Date inside the period (YYYY-MM-DD), defaults to today"
+// @Param bay_id query int false "Filter by bay"
+// @Param mechanic_id query int false "Filter by mechanic"
+// @Success 200 {object} utils.APIResponse{data=models.WorkshopCalendar}
+// @Failure 400 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /api/workshop/calendar [get]
+func (h *WorkshopHandler) GetCalendar(c *gin.Context) {
+	var filter models.WorkshopCalendarFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.SendBadRequest(c, "Invalid query parameters", err.Error())
+		return
+	}
+
+	calendar, err := h.workshopService.GetCalendar(filter)
+	if err != nil {
+		utils.SendBadRequest(c, "Failed to retrieve workshop calendar", err.Error())
+		return
+	}
+
+	utils.SendSuccess(c, "Workshop calendar retrieved successfully", calendar)
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimit allows each client IP at most limit requests per window.
+// Counters are kept in memory, so the limit applies per server instance.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	clients := make(map[string]*rateWindow)
+	lastSweep := time.Now()
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		ip := c.ClientIP()
+
+		mu.Lock()
+		// Drop expired windows now and then so the map does not grow forever
+		if now.Sub(lastSweep) > window {
+			for key, w := range clients {
+				if now.Sub(w.start) > window {
+					delete(clients, key)
+				}
+			}
+			lastSweep = now
+		}
+
+		w, exists := clients[ip]
+		if !exists || now.Sub(w.start) > window {
+			w = &rateWindow{start: now}
+			clients[ip] = w
+		}
+		w.count++
+		count := w.count
+		retryAfter := w.start.Add(window).Sub(now)
+		mu.Unlock()
+
+		if count > limit {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			utils.SendError(c, http.StatusTooManyRequests, "Too many requests", "Please try again later")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
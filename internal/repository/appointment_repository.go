@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+)
+
+type AppointmentRepository interface {
+	Create(appointment *models.ServiceAppointment) error
+	GetByID(id int) (*models.ServiceAppointment, error)
+	List(filter models.ServiceAppointmentFilter, page, limit int) ([]models.ServiceAppointment, int, error)
+	Reschedule(id int, start, end time.Time) error
+	MarkArrived(id int, repairOrderID int) error
+	Cancel(id int, reason *string) error
+	MarkNoShow(id int) error
+	MarkNoShows(endedBefore time.Time) (int, error)
+	GetCapacity(start, end time.Time, excludeID int) (*models.AppointmentCapacity, error)
+	GetStats(dateFrom, dateTo string) (*models.AppointmentStats, error)
+}
+
+type appointmentRepository struct {
+	db *database.Database
+}
+
+func NewAppointmentRepository(db *database.Database) AppointmentRepository {
+	return &appointmentRepository{db: db}
+}
+
+const serviceAppointmentSelect = `
+		SELECT sa.id, sa.code, sa.customer_id, sa.customer_vehicle_id, sa.service_id, sa.requested_service,
+			   sa.scheduled_start, sa.scheduled_end, sa.status, sa.source, sa.repair_order_id, sa.notes,
+			   sa.cancel_reason, sa.arrived_at, sa.cancelled_at, sa.no_show_at, sa.booked_by,
+			   sa.created_at, sa.updated_at,
+			   c.name as customer_name, c.phone as customer_phone,
+			   cv.license_plate, vb.name || ' ' || cv.model as vehicle_model,
+			   sc.name as service_name, ro.code as repair_code
+		FROM service_appointments sa
+		JOIN customers c ON sa.customer_id = c.id
+		JOIN customer_vehicles cv ON sa.customer_vehicle_id = cv.id
+		JOIN vehicle_brands vb ON cv.brand_id = vb.id
+		LEFT JOIN service_catalog sc ON sa.service_id = sc.id
+		LEFT JOIN repair_orders ro ON sa.repair_order_id = ro.id`
+
+func (r *appointmentRepository) Create(appointment *models.ServiceAppointment) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkCapacityTx(tx, appointment.ScheduledStart, appointment.ScheduledEnd, 0); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO service_appointments (code, customer_id, customer_vehicle_id, service_id, requested_service,
+										  scheduled_start, scheduled_end, status, source, notes, booked_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 'booked', $8, $9, $10)
+		RETURNING id, status, created_at, updated_at`
+
+	err = tx.QueryRow(query, appointment.Code, appointment.CustomerID, appointment.CustomerVehicleID,
+		appointment.ServiceID, appointment.RequestedService, appointment.ScheduledStart, appointment.ScheduledEnd,
+		appointment.Source, appointment.Notes, appointment.BookedBy).
+		Scan(&appointment.ID, &appointment.Status, &appointment.CreatedAt, &appointment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create service appointment: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *appointmentRepository) GetByID(id int) (*models.ServiceAppointment, error) {
+	query := serviceAppointmentSelect + `
+		WHERE sa.id = $1`
+
+	var appointment models.ServiceAppointment
+	err := r.db.Get(&appointment, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("service appointment not found")
+		}
+		return nil, fmt.Errorf("failed to get service appointment: %w", err)
+	}
+
+	return &appointment, nil
+}
+
+func (r *appointmentRepository) List(filter models.ServiceAppointmentFilter, page, limit int) ([]models.ServiceAppointment, int, error) {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("sa.status = $%d", argIndex))
+		args = append(args, filter.Status)
+		argIndex++
+	}
+
+	if filter.CustomerID > 0 {
+		conditions = append(conditions, fmt.Sprintf("sa.customer_id = $%d", argIndex))
+		args = append(args, filter.CustomerID)
+		argIndex++
+	}
+
+	if filter.DateFrom != "" {
+		conditions = append(conditions, fmt.Sprintf("DATE(sa.scheduled_start) >= $%d", argIndex))
+		args = append(args, filter.DateFrom)
+		argIndex++
+	}
+
+	if filter.DateTo != "" {
+		conditions = append(conditions, fmt.Sprintf("DATE(sa.scheduled_start) <= $%d", argIndex))
+		args = append(args, filter.DateTo)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := r.db.Get(&total, fmt.Sprintf(`SELECT COUNT(*) FROM service_appointments sa %s`, whereClause), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count service appointments: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	query := fmt.Sprintf(serviceAppointmentSelect+`
+		%s
+		ORDER BY sa.scheduled_start ASC, sa.id ASC
+		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+
+	args = append(args, limit, offset)
+
+	appointments := []models.ServiceAppointment{}
+	if err := r.db.Select(&appointments, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list service appointments: %w", err)
+	}
+
+	return appointments, total, nil
+}
+
+func (r *appointmentRepository) Reschedule(id int, start, end time.Time) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkCapacityTx(tx, start, end, id); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE service_appointments
+		SET scheduled_start = $1, scheduled_end = $2, updated_at = NOW()
+		WHERE id = $3 AND status = 'booked'`, start, end, id)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule service appointment: %w", err)
+	}
+	if err := appointmentChanged(result); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *appointmentRepository) MarkArrived(id int, repairOrderID int) error {
+	result, err := r.db.Exec(`
+		UPDATE service_appointments
+		SET status = 'arrived', repair_order_id = $1, arrived_at = NOW(), updated_at = NOW()
+		WHERE id = $2 AND status = 'booked'`, repairOrderID, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark service appointment arrived: %w", err)
+	}
+
+	return appointmentChanged(result)
+}
+
+func (r *appointmentRepository) Cancel(id int, reason *string) error {
+	result, err := r.db.Exec(`
+		UPDATE service_appointments
+		SET status = 'cancelled', cancel_reason = $1, cancelled_at = NOW(), updated_at = NOW()
+		WHERE id = $2 AND status = 'booked'`, reason, id)
+	if err != nil {
+		return fmt.Errorf("failed to cancel service appointment: %w", err)
+	}
+
+	return appointmentChanged(result)
+}
+
+func (r *appointmentRepository) MarkNoShow(id int) error {
+	result, err := r.db.Exec(`
+		UPDATE service_appointments
+		SET status = 'no_show', no_show_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'booked'`, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark service appointment no-show: %w", err)
+	}
+
+	return appointmentChanged(result)
+}
+
+// MarkNoShows flags booked appointments whose slot ended before the given time
+func (r *appointmentRepository) MarkNoShows(endedBefore time.Time) (int, error) {
+	result, err := r.db.Exec(`
+		UPDATE service_appointments
+		SET status = 'no_show', no_show_at = NOW(), updated_at = NOW()
+		WHERE status = 'booked' AND scheduled_end < $1`, endedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark no-show appointments: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+func (r *appointmentRepository) GetCapacity(start, end time.Time, excludeID int) (*models.AppointmentCapacity, error) {
+	return getCapacity(r.db, start, end, excludeID)
+}
+
+func (r *appointmentRepository) GetStats(dateFrom, dateTo string) (*models.AppointmentStats, error) {
+	stats := &models.AppointmentStats{DateFrom: dateFrom, DateTo: dateTo}
+
+	err := r.db.Get(stats, `
+		SELECT COUNT(*) as total,
+			   COUNT(*) FILTER (WHERE status = 'booked') as booked,
+			   COUNT(*) FILTER (WHERE status = 'arrived') as arrived,
+			   COUNT(*) FILTER (WHERE status = 'no_show') as no_show,
+			   COUNT(*) FILTER (WHERE status = 'cancelled') as cancelled
+		FROM service_appointments
+		WHERE DATE(scheduled_start) BETWEEN $1 AND $2`, dateFrom, dateTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get appointment stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// checkCapacityTx rejects a slot once the workshop is fully booked. The active bays are locked
+// so concurrent bookings, and bay schedules, are counted one at a time.
+func checkCapacityTx(tx *sqlx.Tx, start, end time.Time, excludeID int) error {
+	_, err := tx.Exec(`SELECT id FROM workshop_bays WHERE is_active = true FOR UPDATE`)
+	if err != nil {
+		return fmt.Errorf("failed to lock workshop bays: %w", err)
+	}
+
+	capacity, err := getCapacity(tx, start, end, excludeID)
+	if err != nil {
+		return err
+	}
+
+	if !capacity.IsBookable {
+		return fmt.Errorf("no capacity: %d of %d slots booked between %s and %s", capacity.Booked, capacity.Capacity,
+			start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"))
+	}
+
+	return nil
+}
+
+// getCapacity counts every booking that overlaps the slot, which errs on the safe side
+// when shorter jobs follow each other inside it
+func getCapacity(q sqlx.Queryer, start, end time.Time, excludeID int) (*models.AppointmentCapacity, error) {
+	capacity := &models.AppointmentCapacity{SlotStart: start, SlotEnd: end}
+
+	err := q.QueryRowx(`
+		SELECT
+			(SELECT COUNT(*) FROM workshop_bays WHERE is_active = true),
+			(SELECT COUNT(*) FROM users u JOIN roles r ON u.role_id = r.id
+			 WHERE r.name = 'mekanik' AND u.is_active = true),
+			(SELECT COUNT(*) FROM service_appointments
+			 WHERE status = 'booked' AND id <> $3 AND scheduled_start < $2 AND scheduled_end > $1)
+			+ (SELECT COUNT(*) FROM repair_schedules rs
+			   JOIN repair_orders ro ON rs.repair_order_id = ro.id
+			   WHERE ro.status NOT IN ('completed', 'cancelled') AND rs.scheduled_start < $2 AND rs.scheduled_end > $1)`,
+		start, end, excludeID).Scan(&capacity.Bays, &capacity.Mechanics, &capacity.Booked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workshop capacity: %w", err)
+	}
+
+	capacity.Capacity = capacity.Bays
+	if capacity.Mechanics < capacity.Capacity {
+		capacity.Capacity = capacity.Mechanics
+	}
+	capacity.Available = capacity.Capacity - capacity.Booked
+	if capacity.Available < 0 {
+		capacity.Available = 0
+	}
+	capacity.IsBookable = capacity.Available > 0
+
+	return capacity, nil
+}
+
+// appointmentChanged turns an update that matched nothing into an error; only booked appointments change
+func appointmentChanged(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("service appointment not found or no longer booked")
+	}
+
+	return nil
+}
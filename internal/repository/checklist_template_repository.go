@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+	"github.com/jmoiron/sqlx"
+)
+
+type ChecklistTemplateRepository interface {
+	Create(req *models.ChecklistTemplateCreateRequest) (*models.ChecklistTemplate, error)
+	GetByID(id int) (*models.ChecklistTemplate, error)
+	Update(id int, req *models.ChecklistTemplateUpdateRequest) (*models.ChecklistTemplate, error)
+	Delete(id int) error
+	List(vehicleTypeID int, activeOnly bool) ([]models.ChecklistTemplate, error)
+	GetForVehicleType(vehicleTypeID int) (*models.ChecklistTemplate, error)
+}
+
+type checklistTemplateRepository struct {
+	db *database.Database
+}
+
+func NewChecklistTemplateRepository(db *database.Database) ChecklistTemplateRepository {
+	return &checklistTemplateRepository{db: db}
+}
+
+func (r *checklistTemplateRepository) Create(req *models.ChecklistTemplateCreateRequest) (*models.ChecklistTemplate, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(`
+		INSERT INTO checklist_templates (name, vehicle_type_id, description)
+		VALUES ($1, $2, $3)
+		RETURNING id`, req.Name, req.VehicleTypeID, req.Description).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checklist template: %w", err)
+	}
+
+	if err := r.insertItemsTx(tx, id, req.Items); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *checklistTemplateRepository) insertItemsTx(tx *sqlx.Tx, templateID int, items []models.ChecklistTemplateItemRequest) error {
+	for i, item := range items {
+		_, err := tx.Exec(`
+			INSERT INTO checklist_template_items (template_id, label, category, is_required, sort_order)
+			VALUES ($1, $2, $3, $4, $5)`, templateID, item.Label, item.Category, item.IsRequired, i+1)
+		if err != nil {
+			return fmt.Errorf("failed to create checklist item: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *checklistTemplateRepository) GetByID(id int) (*models.ChecklistTemplate, error) {
+	query := `
+		SELECT id, name, vehicle_type_id, description, is_active, created_at, updated_at
+		FROM checklist_templates
+		WHERE id = $1`
+
+	var template models.ChecklistTemplate
+	err := r.db.Get(&template, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("checklist template not found")
+		}
+		return nil, fmt.Errorf("failed to get checklist template: %w", err)
+	}
+
+	items, err := r.getItems(id)
+	if err != nil {
+		return nil, err
+	}
+	template.Items = items
+
+	return &template, nil
+}
+
+func (r *checklistTemplateRepository) getItems(templateID int) ([]models.ChecklistTemplateItem, error) {
+	query := `
+		SELECT id, template_id, label, category, is_required, sort_order
+		FROM checklist_template_items
+		WHERE template_id = $1
+		ORDER BY sort_order, id`
+
+	items := []models.ChecklistTemplateItem{}
+	if err := r.db.Select(&items, query, templateID); err != nil {
+		return nil, fmt.Errorf("failed to get checklist items: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *checklistTemplateRepository) Update(id int, req *models.ChecklistTemplateUpdateRequest) (*models.ChecklistTemplate, error) {
+	// Build dynamic update query
+	setParts := []string{}
+	args := []interface{}{}
+	argCounter := 1
+
+	if req.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argCounter))
+		args = append(args, *req.Name)
+		argCounter++
+	}
+	if req.Description != nil {
+		setParts = append(setParts, fmt.Sprintf("description = $%d", argCounter))
+		args = append(args, *req.Description)
+		argCounter++
+	}
+	if req.IsActive != nil {
+		setParts = append(setParts, fmt.Sprintf("is_active = $%d", argCounter))
+		args = append(args, *req.IsActive)
+		argCounter++
+	}
+
+	if len(setParts) == 0 && req.Items == nil {
+		return nil, fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, "updated_at = NOW()")
+	args = append(args, id)
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`
+		UPDATE checklist_templates
+		SET %s
+		WHERE id = $%d`,
+		strings.Join(setParts, ", "), argCounter)
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update checklist template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("checklist template not found")
+	}
+
+	// Items already copied onto repair orders are snapshots, so replacing them here is safe
+	if req.Items != nil {
+		if _, err := tx.Exec(`DELETE FROM checklist_template_items WHERE template_id = $1`, id); err != nil {
+			return nil, fmt.Errorf("failed to replace checklist items: %w", err)
+		}
+		if err := r.insertItemsTx(tx, id, req.Items); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *checklistTemplateRepository) Delete(id int) error {
+	// Templates already used on repair orders are deactivated instead of removed
+	query := `
+		UPDATE checklist_templates SET is_active = false, updated_at = NOW()
+		WHERE id = $1 AND EXISTS (SELECT 1 FROM repair_checklist_items WHERE template_id = $1)`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete checklist template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	result, err = r.db.Exec(`DELETE FROM checklist_templates WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete checklist template: %w", err)
+	}
+
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("checklist template not found")
+	}
+
+	return nil
+}
+
+func (r *checklistTemplateRepository) List(vehicleTypeID int, activeOnly bool) ([]models.ChecklistTemplate, error) {
+	var conditions []string
+	var args []interface{}
+
+	if vehicleTypeID > 0 {
+		// Generic templates (no vehicle type) apply to every type
+		args = append(args, vehicleTypeID)
+		conditions = append(conditions, fmt.Sprintf("(vehicle_type_id = $%d OR vehicle_type_id IS NULL)", len(args)))
+	}
+	if activeOnly {
+		conditions = append(conditions, "is_active = true")
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, name, vehicle_type_id, description, is_active, created_at, updated_at
+		FROM checklist_templates
+		%s
+		ORDER BY name ASC`, whereClause)
+
+	templates := []models.ChecklistTemplate{}
+	if err := r.db.Select(&templates, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list checklist templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+func (r *checklistTemplateRepository) GetForVehicleType(vehicleTypeID int) (*models.ChecklistTemplate, error) {
+	// Prefer a template made for the vehicle type over a generic one
+	query := `
+		SELECT id FROM checklist_templates
+		WHERE is_active = true AND (vehicle_type_id = $1 OR vehicle_type_id IS NULL)
+		ORDER BY vehicle_type_id IS NULL, id
+		LIMIT 1`
+
+	var id int
+	err := r.db.Get(&id, query, vehicleTypeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("checklist template not found")
+		}
+		return nil, fmt.Errorf("failed to get checklist template: %w", err)
+	}
+
+	return r.GetByID(id)
+}
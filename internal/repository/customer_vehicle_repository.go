@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+)
+
+type CustomerVehicleRepository interface {
+	Create(customerID int, req *models.CustomerVehicleCreateRequest) (*models.CustomerVehicle, error)
+	GetByID(id int) (*models.CustomerVehicle, error)
+	Update(id int, req *models.CustomerVehicleUpdateRequest) (*models.CustomerVehicle, error)
+	ListByCustomer(customerID int) ([]models.CustomerVehicle, error)
+}
+
+type customerVehicleRepository struct {
+	db *database.Database
+}
+
+func NewCustomerVehicleRepository(db *database.Database) CustomerVehicleRepository {
+	return &customerVehicleRepository{db: db}
+}
+
+const customerVehicleColumns = `
+		cv.id, cv.customer_id, cv.brand_id, cv.model, cv.year, cv.color, cv.license_plate,
+		cv.chassis_number, cv.engine_number, cv.odometer, cv.notes, cv.created_at, cv.updated_at,
+		vb.name as brand_name, vb.type_id as vehicle_type_id, c.name as customer_name`
+
+const customerVehicleJoins = `
+		FROM customer_vehicles cv
+		JOIN vehicle_brands vb ON cv.brand_id = vb.id
+		JOIN customers c ON cv.customer_id = c.id`
+
+func (r *customerVehicleRepository) Create(customerID int, req *models.CustomerVehicleCreateRequest) (*models.CustomerVehicle, error) {
+	query := `
+		INSERT INTO customer_vehicles (customer_id, brand_id, model, year, color, license_plate,
+									   chassis_number, engine_number, odometer, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id`
+
+	var id int
+	err := r.db.QueryRow(query, customerID, req.BrandID, req.Model, req.Year, req.Color,
+		strings.ToUpper(req.LicensePlate), req.ChassisNumber, req.EngineNumber, req.Odometer, req.Notes).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create customer vehicle: %w", err)
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *customerVehicleRepository) GetByID(id int) (*models.CustomerVehicle, error) {
+	query := `SELECT` + customerVehicleColumns + customerVehicleJoins + `
+		WHERE cv.id = $1`
+
+	var vehicle models.CustomerVehicle
+	err := r.db.Get(&vehicle, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("customer vehicle not found")
+		}
+		return nil, fmt.Errorf("failed to get customer vehicle: %w", err)
+	}
+
+	return &vehicle, nil
+}
+
+func (r *customerVehicleRepository) Update(id int, req *models.CustomerVehicleUpdateRequest) (*models.CustomerVehicle, error) {
+	// Build dynamic update query
+	setParts := []string{}
+	args := []interface{}{}
+	argCounter := 1
+
+	if req.BrandID != nil {
+		setParts = append(setParts, fmt.Sprintf("brand_id = $%d", argCounter))
+		args = append(args, *req.BrandID)
+		argCounter++
+	}
+	if req.Model != nil {
+		setParts = append(setParts, fmt.Sprintf("model = $%d", argCounter))
+		args = append(args, *req.Model)
+		argCounter++
+	}
+	if req.Year != nil {
+		setParts = append(setParts, fmt.Sprintf("year = $%d", argCounter))
+		args = append(args, *req.Year)
+		argCounter++
+	}
+	if req.Color != nil {
+		setParts = append(setParts, fmt.Sprintf("color = $%d", argCounter))
+		args = append(args, *req.Color)
+		argCounter++
+	}
+	if req.LicensePlate != nil {
+		setParts = append(setParts, fmt.Sprintf("license_plate = $%d", argCounter))
+		args = append(args, strings.ToUpper(*req.LicensePlate))
+		argCounter++
+	}
+	if req.ChassisNumber != nil {
+		setParts = append(setParts, fmt.Sprintf("chassis_number = $%d", argCounter))
+		args = append(args, *req.ChassisNumber)
+		argCounter++
+	}
+	if req.EngineNumber != nil {
+		setParts = append(setParts, fmt.Sprintf("engine_number = $%d", argCounter))
+		args = append(args, *req.EngineNumber)
+		argCounter++
+	}
+	if req.Odometer != nil {
+		setParts = append(setParts, fmt.Sprintf("odometer = $%d", argCounter))
+		args = append(args, *req.Odometer)
+		argCounter++
+	}
+	if req.Notes != nil {
+		setParts = append(setParts, fmt.Sprintf("notes = $%d", argCounter))
+		args = append(args, *req.Notes)
+		argCounter++
+	}
+
+	if len(setParts) == 0 {
+		return nil, fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, "updated_at = NOW()")
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE customer_vehicles
+		SET %s
+		WHERE id = $%d`,
+		strings.Join(setParts, ", "), argCounter)
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update customer vehicle: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("customer vehicle not found")
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *customerVehicleRepository) ListByCustomer(customerID int) ([]models.CustomerVehicle, error) {
+	query := `SELECT` + customerVehicleColumns + customerVehicleJoins + `
+		WHERE cv.customer_id = $1
+		ORDER BY cv.created_at DESC`
+
+	vehicles := []models.CustomerVehicle{}
+	if err := r.db.Select(&vehicles, query, customerID); err != nil {
+		return nil, fmt.Errorf("failed to list customer vehicles: %w", err)
+	}
+
+	return vehicles, nil
+}
@@ -13,6 +13,7 @@ type DashboardRepository interface {
 	GetDashboardMetrics(date time.Time) (*models.DashboardMetric, error)
 	GetRecentTransactions(limit int) ([]interface{}, error)
 	GetPendingRepairs(limit int) ([]models.RepairOrder, error)
+	GetRepairSLACounts(atRiskDays int) (overdue int, atRisk int, err error)
 	GetLowStockItems(limit int) ([]models.SparePart, error)
 	GetAvailableVehicles(limit int) ([]models.Vehicle, error)
 	GetTodayTransactions(date time.Time) ([]interface{}, error)
@@ -101,7 +102,7 @@ func (r *dashboardRepository) calculateRealTimeMetrics(date time.Time) (*models.
 
 	// Count low stock items
 	err = r.db.Get(&metric.LowStockItems,
-		"SELECT COUNT(*) FROM spare_parts WHERE stock_quantity <= minimum_stock")
+		"SELECT COUNT(*) FROM spare_parts sp WHERE sp.stock_quantity - "+reservedQuantitySQL+" <= sp.minimum_stock")
 	if err != nil {
 		return nil, fmt.Errorf("failed to count low stock items: %w", err)
 	}
@@ -206,15 +207,16 @@ func (r *dashboardRepository) GetRecentTransactions(limit int) ([]interface{}, e
 func (r *dashboardRepository) GetPendingRepairs(limit int) ([]models.RepairOrder, error) {
 	repairs := []models.RepairOrder{}
 	query := `
-		SELECT ro.*, vb.name as brand, vt.name as type_name, v.license_plate,
+		SELECT ro.*, vb.name as brand, vt.name as type_name, COALESCE(v.license_plate, cv.license_plate) as license_plate,
 		       u.full_name as mechanic_name
 		FROM repair_orders ro
-		JOIN vehicles v ON ro.vehicle_id = v.id
-		LEFT JOIN vehicle_brands vb ON v.brand_id = vb.id
+		LEFT JOIN vehicles v ON ro.vehicle_id = v.id
+		LEFT JOIN customer_vehicles cv ON ro.customer_vehicle_id = cv.id
+		LEFT JOIN vehicle_brands vb ON COALESCE(v.brand_id, cv.brand_id) = vb.id
 		LEFT JOIN vehicle_types vt ON vb.type_id = vt.id
 		LEFT JOIN users u ON ro.mechanic_id = u.id
-		WHERE ro.status IN ('pending', 'in_progress')
-		ORDER BY ro.created_at ASC
+		WHERE ro.status NOT IN ('completed', 'cancelled')
+		ORDER BY ro.target_completion_date ASC NULLS LAST, ro.created_at ASC
 		LIMIT $1`
 
 	err := r.db.Select(&repairs, query, limit)
@@ -225,12 +227,30 @@ func (r *dashboardRepository) GetPendingRepairs(limit int) ([]models.RepairOrder
 	return repairs, nil
 }
 
+// GetRepairSLACounts counts the open repairs past their target date and those due within atRiskDays
+func (r *dashboardRepository) GetRepairSLACounts(atRiskDays int) (overdue int, atRisk int, err error) {
+	query := `
+		SELECT COUNT(*) FILTER (WHERE target_completion_date < CURRENT_DATE),
+		       COUNT(*) FILTER (WHERE target_completion_date >= CURRENT_DATE AND target_completion_date <= CURRENT_DATE + $1::int)
+		FROM repair_orders
+		WHERE status NOT IN ('completed', 'cancelled')`
+
+	err = r.db.QueryRow(query, atRiskDays).Scan(&overdue, &atRisk)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get repair SLA counts: %w", err)
+	}
+
+	return overdue, atRisk, nil
+}
+
 func (r *dashboardRepository) GetLowStockItems(limit int) ([]models.SparePart, error) {
 	spareParts := []models.SparePart{}
 	query := `
-		SELECT * FROM spare_parts 
-		WHERE stock_quantity <= minimum_stock
-		ORDER BY (stock_quantity::float / minimum_stock::float) ASC
+		SELECT sp.*, ` + reservedQuantitySQL + ` AS reserved_quantity,
+		       sp.stock_quantity - ` + reservedQuantitySQL + ` AS available_quantity
+		FROM spare_parts sp
+		WHERE sp.stock_quantity - ` + reservedQuantitySQL + ` <= sp.minimum_stock
+		ORDER BY ((sp.stock_quantity - ` + reservedQuantitySQL + `)::float / NULLIF(sp.minimum_stock, 0)::float) ASC NULLS FIRST
 		LIMIT $1`
 
 	err := r.db.Select(&spareParts, query, limit)
@@ -439,13 +459,15 @@ func (r *dashboardRepository) GetPendingPayments(limit int) ([]interface{}, erro
 func (r *dashboardRepository) GetAssignedRepairs(mechanicID int) ([]models.RepairOrder, error) {
 	repairs := []models.RepairOrder{}
 	query := `
-		SELECT ro.*, vb.name as brand, vt.name as type_name, v.license_plate
+		SELECT ro.*, vb.name as brand, vt.name as type_name, COALESCE(v.license_plate, cv.license_plate) as license_plate
 		FROM repair_orders ro
-		JOIN vehicles v ON ro.vehicle_id = v.id
-		LEFT JOIN vehicle_brands vb ON v.brand_id = vb.id
+		LEFT JOIN vehicles v ON ro.vehicle_id = v.id
+		LEFT JOIN customer_vehicles cv ON ro.customer_vehicle_id = cv.id
+		LEFT JOIN vehicle_brands vb ON COALESCE(v.brand_id, cv.brand_id) = vb.id
 		LEFT JOIN vehicle_types vt ON vb.type_id = vt.id
-		WHERE ro.mechanic_id = $1 AND ro.status IN ('pending', 'in_progress')
-		ORDER BY ro.created_at ASC`
+		WHERE ro.mechanic_id = $1 AND ro.status NOT IN ('completed', 'cancelled')
+		      AND NOT (ro.order_type = 'customer_service' AND ro.estimate_status IS DISTINCT FROM 'approved') -- estimate not yet approved
+		ORDER BY ro.target_completion_date ASC NULLS LAST, ro.created_at ASC`
 
 	err := r.db.Select(&repairs, query, mechanicID)
 	if err != nil {
@@ -459,10 +481,11 @@ func (r *dashboardRepository) GetCompletedRepairsToday(mechanicID int, date time
 	dateStr := date.Format("2006-01-02")
 	repairs := []models.RepairOrder{}
 	query := `
-		SELECT ro.*, vb.name as brand, vt.name as type_name, v.license_plate
+		SELECT ro.*, vb.name as brand, vt.name as type_name, COALESCE(v.license_plate, cv.license_plate) as license_plate
 		FROM repair_orders ro
-		JOIN vehicles v ON ro.vehicle_id = v.id
-		LEFT JOIN vehicle_brands vb ON v.brand_id = vb.id
+		LEFT JOIN vehicles v ON ro.vehicle_id = v.id
+		LEFT JOIN customer_vehicles cv ON ro.customer_vehicle_id = cv.id
+		LEFT JOIN vehicle_brands vb ON COALESCE(v.brand_id, cv.brand_id) = vb.id
 		LEFT JOIN vehicle_types vt ON vb.type_id = vt.id
 		WHERE ro.mechanic_id = $1 AND ro.status = 'completed' 
 		      AND ro.updated_at::date = $2
@@ -483,7 +506,7 @@ func (r *dashboardRepository) GetRequiredPartsForRepairs(mechanicID int) ([]mode
 		FROM spare_parts sp
 		JOIN repair_spare_parts rsp ON sp.id = rsp.spare_part_id
 		JOIN repair_orders ro ON rsp.repair_order_id = ro.id
-		WHERE ro.mechanic_id = $1 AND ro.status IN ('pending', 'in_progress')
+		WHERE ro.mechanic_id = $1 AND ro.status NOT IN ('completed', 'cancelled')
 		      AND sp.stock_quantity < rsp.quantity_used
 		ORDER BY sp.name`
 
@@ -536,7 +559,7 @@ func (r *dashboardRepository) calculateMonthlyStats(month, year int) (*models.Mo
 	err = r.db.Get(&closing.TotalRepairCost,
 		`SELECT COALESCE(SUM(actual_cost), 0) FROM repair_orders 
 		 WHERE EXTRACT(MONTH FROM updated_at) = $1 AND EXTRACT(YEAR FROM updated_at) = $2 
-		       AND status = 'completed'`,
+		       AND status = 'completed' AND order_type = 'reconditioning'`,
 		month, year)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate total repair cost: %w", err)
@@ -681,7 +704,7 @@ func (r *dashboardRepository) CreateDailyClosing(userID int, req *models.DailyCl
 
 	// Calculate total repair cost for the day
 	err = r.db.Get(&closing.TotalRepairCost,
-		"SELECT COALESCE(SUM(actual_cost), 0) FROM repair_orders WHERE updated_at::date = $1 AND status = 'completed'",
+		"SELECT COALESCE(SUM(actual_cost), 0) FROM repair_orders WHERE updated_at::date = $1 AND status = 'completed' AND order_type = 'reconditioning'",
 		dateStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate total repair cost: %w", err)
@@ -740,7 +763,7 @@ func (r *dashboardRepository) CreateMonthlyClosing(userID int, req *models.Month
 	err = r.db.Get(&closing.TotalRepairCost,
 		`SELECT COALESCE(SUM(actual_cost), 0) FROM repair_orders 
 		 WHERE EXTRACT(MONTH FROM updated_at) = $1 AND EXTRACT(YEAR FROM updated_at) = $2 
-		       AND status = 'completed'`,
+		       AND status = 'completed' AND order_type = 'reconditioning'`,
 		req.Month, req.Year)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate total repair cost: %w", err)
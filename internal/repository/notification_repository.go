@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+)
+
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	ListByUser(userID int, unreadOnly bool, page, limit int) ([]models.Notification, int, error)
+	MarkAsRead(userID int, id int) error
+	MarkAllAsRead(userID int) error
+}
+
+type notificationRepository struct {
+	db *database.Database
+}
+
+func NewNotificationRepository(db *database.Database) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(notification *models.Notification) error {
+	query := `
+		INSERT INTO notifications (user_id, type, title, message, reference_type, reference_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, is_read, created_at`
+
+	err := r.db.QueryRow(query, notification.UserID, notification.Type, notification.Title,
+		notification.Message, notification.ReferenceType, notification.ReferenceID).
+		Scan(&notification.ID, &notification.IsRead, &notification.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return nil
+}
+
+func (r *notificationRepository) ListByUser(userID int, unreadOnly bool, page, limit int) ([]models.Notification, int, error) {
+	whereClause := "WHERE user_id = $1"
+	if unreadOnly {
+		whereClause += " AND is_read = false"
+	}
+
+	var total int
+	err := r.db.Get(&total, `SELECT COUNT(*) FROM notifications `+whereClause, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	query := `
+		SELECT id, user_id, type, title, message, reference_type, reference_id, is_read, read_at, created_at
+		FROM notifications
+		` + whereClause + `
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	notifications := []models.Notification{}
+	err = r.db.Select(&notifications, query, userID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	return notifications, total, nil
+}
+
+func (r *notificationRepository) MarkAsRead(userID int, id int) error {
+	query := `
+		UPDATE notifications SET is_read = true, read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("notification not found")
+	}
+
+	return nil
+}
+
+func (r *notificationRepository) MarkAllAsRead(userID int) error {
+	query := `UPDATE notifications SET is_read = true, read_at = NOW() WHERE user_id = $1 AND is_read = false`
+
+	_, err := r.db.Exec(query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+
+	return nil
+}
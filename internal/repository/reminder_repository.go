@@ -0,0 +1,451 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+)
+
+type ReminderRepository interface {
+	CreateRule(rule *models.ServiceReminderRule) error
+	GetRuleByID(id int) (*models.ServiceReminderRule, error)
+	ListRules(activeOnly bool) ([]models.ServiceReminderRule, error)
+	UpdateRule(id int, req *models.ServiceReminderRuleUpdateRequest) (*models.ServiceReminderRule, error)
+	DeleteRule(id int) error
+	FindDue(rule *models.ServiceReminderRule, today time.Time) ([]models.ServiceReminder, error)
+	CreateIfNew(reminder *models.ServiceReminder) (bool, error)
+	ListToSend(maxAttempts int) ([]models.ServiceReminder, error)
+	MarkSent(id int, channel string) error
+	MarkFailed(id int, channel string, sendErr string) error
+	GetByID(id int) (*models.ServiceReminder, error)
+	List(filter models.ServiceReminderFilter, page, limit int) ([]models.ServiceReminder, int, error)
+	RecordOutcome(id int, req *models.ServiceReminderOutcomeRequest, contactedBy int) error
+}
+
+type reminderRepository struct {
+	db *database.Database
+}
+
+func NewReminderRepository(db *database.Database) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+const reminderRuleColumns = `
+		SELECT rr.id, rr.name, rr.trigger_type, rr.interval_months, rr.interval_km, rr.avg_km_per_month,
+			   rr.days_before, rr.service_id, rr.message, rr.is_active, rr.created_by, rr.created_at, rr.updated_at,
+			   sc.name as service_name
+		FROM service_reminder_rules rr
+		LEFT JOIN service_catalog sc ON rr.service_id = sc.id`
+
+// Reminders name the customer vehicle, or for warranty reminders the vehicle sold
+const reminderColumns = `
+		SELECT sr.id, sr.rule_id, sr.customer_id, sr.customer_vehicle_id, sr.sales_warranty_id, sr.due_date,
+			   sr.due_odometer, sr.estimated_odometer, sr.message, sr.status, sr.channel, sr.attempts, sr.last_error,
+			   sr.sent_at, sr.outcome, sr.outcome_notes, sr.appointment_id, sr.contacted_by, sr.contacted_at,
+			   sr.created_at, sr.updated_at,
+			   rr.name as rule_name, rr.trigger_type,
+			   c.name as customer_name, c.phone as customer_phone, c.email as customer_email,
+			   COALESCE(cv.license_plate, v.license_plate) as license_plate,
+			   COALESCE(cv.model, v.model, '') as vehicle_model
+		FROM service_reminders sr
+		JOIN service_reminder_rules rr ON sr.rule_id = rr.id
+		JOIN customers c ON sr.customer_id = c.id
+		LEFT JOIN customer_vehicles cv ON sr.customer_vehicle_id = cv.id
+		LEFT JOIN sales_warranties sw ON sr.sales_warranty_id = sw.id
+		LEFT JOIN sales_transactions st ON sw.sales_transaction_id = st.id
+		LEFT JOIN vehicles v ON st.vehicle_id = v.id`
+
+func (r *reminderRepository) CreateRule(rule *models.ServiceReminderRule) error {
+	query := `
+		INSERT INTO service_reminder_rules (name, trigger_type, interval_months, interval_km, avg_km_per_month,
+											days_before, service_id, message, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, is_active, created_at, updated_at`
+
+	err := r.db.QueryRow(query, rule.Name, rule.TriggerType, rule.IntervalMonths, rule.IntervalKm, rule.AvgKmPerMonth,
+		rule.DaysBefore, rule.ServiceID, rule.Message, rule.CreatedBy).
+		Scan(&rule.ID, &rule.IsActive, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create reminder rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *reminderRepository) GetRuleByID(id int) (*models.ServiceReminderRule, error) {
+	var rule models.ServiceReminderRule
+	err := r.db.Get(&rule, reminderRuleColumns+` WHERE rr.id = $1`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("reminder rule not found")
+		}
+		return nil, fmt.Errorf("failed to get reminder rule: %w", err)
+	}
+
+	return &rule, nil
+}
+
+func (r *reminderRepository) ListRules(activeOnly bool) ([]models.ServiceReminderRule, error) {
+	query := reminderRuleColumns
+	if activeOnly {
+		query += ` WHERE rr.is_active = true`
+	}
+	query += ` ORDER BY rr.name`
+
+	var rules []models.ServiceReminderRule
+	if err := r.db.Select(&rules, query); err != nil {
+		return nil, fmt.Errorf("failed to list reminder rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *reminderRepository) UpdateRule(id int, req *models.ServiceReminderRuleUpdateRequest) (*models.ServiceReminderRule, error) {
+	// Build dynamic update query
+	setParts := []string{}
+	args := []interface{}{}
+	argCounter := 1
+
+	if req.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argCounter))
+		args = append(args, *req.Name)
+		argCounter++
+	}
+	if req.IntervalMonths != nil {
+		setParts = append(setParts, fmt.Sprintf("interval_months = $%d", argCounter))
+		args = append(args, *req.IntervalMonths)
+		argCounter++
+	}
+	if req.IntervalKm != nil {
+		setParts = append(setParts, fmt.Sprintf("interval_km = $%d", argCounter))
+		args = append(args, *req.IntervalKm)
+		argCounter++
+	}
+	if req.AvgKmPerMonth != nil {
+		setParts = append(setParts, fmt.Sprintf("avg_km_per_month = $%d", argCounter))
+		args = append(args, *req.AvgKmPerMonth)
+		argCounter++
+	}
+	if req.DaysBefore != nil {
+		setParts = append(setParts, fmt.Sprintf("days_before = $%d", argCounter))
+		args = append(args, *req.DaysBefore)
+		argCounter++
+	}
+	if req.ServiceID != nil {
+		// 0 clears the suggested operation
+		var serviceID interface{}
+		if *req.ServiceID > 0 {
+			serviceID = *req.ServiceID
+		}
+		setParts = append(setParts, fmt.Sprintf("service_id = $%d", argCounter))
+		args = append(args, serviceID)
+		argCounter++
+	}
+	if req.Message != nil {
+		setParts = append(setParts, fmt.Sprintf("message = $%d", argCounter))
+		args = append(args, *req.Message)
+		argCounter++
+	}
+	if req.IsActive != nil {
+		setParts = append(setParts, fmt.Sprintf("is_active = $%d", argCounter))
+		args = append(args, *req.IsActive)
+		argCounter++
+	}
+
+	if len(setParts) == 0 {
+		return nil, fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, "updated_at = NOW()")
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE service_reminder_rules
+		SET %s
+		WHERE id = $%d`,
+		strings.Join(setParts, ", "), argCounter)
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update reminder rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("reminder rule not found")
+	}
+
+	return r.GetRuleByID(id)
+}
+
+func (r *reminderRepository) DeleteRule(id int) error {
+	result, err := r.db.Exec(`DELETE FROM service_reminder_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete reminder rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("reminder rule not found")
+	}
+
+	return nil
+}
+
+// FindDue lists the reminders a rule calls for on the given day. Time and odometer rules count
+// from the last completed service of a customer vehicle (or its registration when it has none)
+// and skip vehicles already in the workshop or booked in. The odometer of a customer vehicle is
+// the reading of its last visit, so the due date is estimated from the rule's monthly usage.
+func (r *reminderRepository) FindDue(rule *models.ServiceReminderRule, today time.Time) ([]models.ServiceReminder, error) {
+	var reminders []models.ServiceReminder
+
+	switch rule.TriggerType {
+	case models.ReminderTriggerWarrantyExpiry:
+		query := `
+			SELECT sw.id as sales_warranty_id, st.customer_id, sw.end_date as due_date,
+				   c.name as customer_name, c.phone as customer_phone, c.email as customer_email,
+				   v.license_plate, v.model as vehicle_model
+			FROM sales_warranties sw
+			JOIN sales_transactions st ON sw.sales_transaction_id = st.id
+			JOIN customers c ON st.customer_id = c.id
+			JOIN vehicles v ON st.vehicle_id = v.id
+			WHERE sw.end_date >= $1::date AND sw.end_date <= $1::date + $2::int
+			ORDER BY sw.end_date`
+
+		if err := r.db.Select(&reminders, query, today, rule.DaysBefore); err != nil {
+			return nil, fmt.Errorf("failed to find due warranty reminders: %w", err)
+		}
+
+	case models.ReminderTriggerTime, models.ReminderTriggerOdometer:
+		var dueDate, dueOdometer, estimatedOdometer string
+		var args []interface{}
+		if rule.TriggerType == models.ReminderTriggerTime {
+			if rule.IntervalMonths == nil {
+				return nil, fmt.Errorf("reminder rule %s has no interval months", rule.Name)
+			}
+			dueDate = `(ls.last_service + make_interval(months => $3))::date`
+			dueOdometer = `NULL::int`
+			estimatedOdometer = `NULL::int`
+			args = []interface{}{today, rule.DaysBefore, *rule.IntervalMonths}
+		} else {
+			if rule.IntervalKm == nil {
+				return nil, fmt.Errorf("reminder rule %s has no interval km", rule.Name)
+			}
+			dueDate = `(ls.last_service + make_interval(days => CEIL($3::int * 30.0 / $4::int)::int))::date`
+			dueOdometer = `cv.odometer + $3::int`
+			estimatedOdometer = `cv.odometer + ($1::date - ls.last_service::date) * $4::int / 30`
+			args = []interface{}{today, rule.DaysBefore, *rule.IntervalKm, rule.AvgKmPerMonth}
+		}
+
+		query := fmt.Sprintf(`
+			SELECT cv.id as customer_vehicle_id, cv.customer_id, %[1]s as due_date,
+				   %[2]s as due_odometer, %[3]s as estimated_odometer,
+				   c.name as customer_name, c.phone as customer_phone, c.email as customer_email,
+				   cv.license_plate, cv.model as vehicle_model
+			FROM (
+				SELECT cv.id, COALESCE(MAX(ro.completed_at), cv.created_at) as last_service
+				FROM customer_vehicles cv
+				LEFT JOIN repair_orders ro ON ro.customer_vehicle_id = cv.id AND ro.status = 'completed'
+				GROUP BY cv.id
+			) ls
+			JOIN customer_vehicles cv ON ls.id = cv.id
+			JOIN customers c ON cv.customer_id = c.id
+			WHERE %[1]s <= $1::date + $2::int
+			AND NOT EXISTS (
+				SELECT 1 FROM repair_orders ro
+				WHERE ro.customer_vehicle_id = cv.id AND ro.status NOT IN ('completed', 'cancelled')
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM service_appointments sa
+				WHERE sa.customer_vehicle_id = cv.id AND sa.status = 'booked' AND sa.scheduled_start >= $1::date
+			)
+			ORDER BY due_date`, dueDate, dueOdometer, estimatedOdometer)
+
+		if err := r.db.Select(&reminders, query, args...); err != nil {
+			return nil, fmt.Errorf("failed to find due service reminders: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown reminder trigger: %s", rule.TriggerType)
+	}
+
+	for i := range reminders {
+		reminders[i].RuleID = rule.ID
+		reminders[i].RuleName = rule.Name
+		reminders[i].TriggerType = rule.TriggerType
+	}
+
+	return reminders, nil
+}
+
+// CreateIfNew stores a due reminder unless the rule already produced one for the same due date,
+// so the daily job can run any number of times
+func (r *reminderRepository) CreateIfNew(reminder *models.ServiceReminder) (bool, error) {
+	query := `
+		INSERT INTO service_reminders (rule_id, customer_id, customer_vehicle_id, sales_warranty_id, due_date,
+									   due_odometer, estimated_odometer, message)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT DO NOTHING
+		RETURNING id, status, attempts, created_at, updated_at`
+
+	err := r.db.QueryRow(query, reminder.RuleID, reminder.CustomerID, reminder.CustomerVehicleID,
+		reminder.SalesWarrantyID, reminder.DueDate, reminder.DueOdometer, reminder.EstimatedOdometer,
+		reminder.Message).Scan(&reminder.ID, &reminder.Status, &reminder.Attempts, &reminder.CreatedAt,
+		&reminder.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create service reminder: %w", err)
+	}
+
+	return true, nil
+}
+
+// ListToSend lists pending reminders and failed ones that can still be retried
+func (r *reminderRepository) ListToSend(maxAttempts int) ([]models.ServiceReminder, error) {
+	query := reminderColumns + `
+		WHERE sr.status IN ('pending', 'failed') AND sr.attempts < $1 AND sr.outcome IS NULL
+		ORDER BY sr.due_date, sr.id`
+
+	var reminders []models.ServiceReminder
+	if err := r.db.Select(&reminders, query, maxAttempts); err != nil {
+		return nil, fmt.Errorf("failed to list reminders to send: %w", err)
+	}
+
+	return reminders, nil
+}
+
+func (r *reminderRepository) MarkSent(id int, channel string) error {
+	_, err := r.db.Exec(`
+		UPDATE service_reminders
+		SET status = 'sent', channel = $1, attempts = attempts + 1, last_error = NULL, sent_at = NOW(), updated_at = NOW()
+		WHERE id = $2`, channel, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark reminder sent: %w", err)
+	}
+
+	return nil
+}
+
+func (r *reminderRepository) MarkFailed(id int, channel string, sendErr string) error {
+	_, err := r.db.Exec(`
+		UPDATE service_reminders
+		SET status = 'failed', channel = $1, attempts = attempts + 1, last_error = $2, updated_at = NOW()
+		WHERE id = $3`, channel, sendErr, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark reminder failed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *reminderRepository) GetByID(id int) (*models.ServiceReminder, error) {
+	var reminder models.ServiceReminder
+	err := r.db.Get(&reminder, reminderColumns+` WHERE sr.id = $1`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("service reminder not found")
+		}
+		return nil, fmt.Errorf("failed to get service reminder: %w", err)
+	}
+
+	return &reminder, nil
+}
+
+func (r *reminderRepository) List(filter models.ServiceReminderFilter, page, limit int) ([]models.ServiceReminder, int, error) {
+	conditions := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	if filter.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("sr.status = $%d", argIndex))
+		args = append(args, filter.Status)
+		argIndex++
+	}
+	if filter.Outcome != "" {
+		conditions = append(conditions, fmt.Sprintf("sr.outcome = $%d", argIndex))
+		args = append(args, filter.Outcome)
+		argIndex++
+	}
+	if filter.NoOutcome {
+		conditions = append(conditions, "sr.outcome IS NULL")
+	}
+	if filter.RuleID > 0 {
+		conditions = append(conditions, fmt.Sprintf("sr.rule_id = $%d", argIndex))
+		args = append(args, filter.RuleID)
+		argIndex++
+	}
+	if filter.CustomerID > 0 {
+		conditions = append(conditions, fmt.Sprintf("sr.customer_id = $%d", argIndex))
+		args = append(args, filter.CustomerID)
+		argIndex++
+	}
+	if filter.DueDateFrom != "" {
+		conditions = append(conditions, fmt.Sprintf("sr.due_date >= $%d", argIndex))
+		args = append(args, filter.DueDateFrom)
+		argIndex++
+	}
+	if filter.DueDateTo != "" {
+		conditions = append(conditions, fmt.Sprintf("sr.due_date <= $%d", argIndex))
+		args = append(args, filter.DueDateTo)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM service_reminders sr` + whereClause
+	if err := r.db.Get(&total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count service reminders: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	query := reminderColumns + whereClause + fmt.Sprintf(`
+		ORDER BY sr.due_date DESC, sr.id DESC
+		LIMIT $%d OFFSET $%d`, argIndex, argIndex+1)
+	args = append(args, limit, offset)
+
+	var reminders []models.ServiceReminder
+	if err := r.db.Select(&reminders, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list service reminders: %w", err)
+	}
+
+	return reminders, total, nil
+}
+
+func (r *reminderRepository) RecordOutcome(id int, req *models.ServiceReminderOutcomeRequest, contactedBy int) error {
+	result, err := r.db.Exec(`
+		UPDATE service_reminders
+		SET outcome = $1, appointment_id = $2, outcome_notes = $3, contacted_by = $4, contacted_at = NOW(), updated_at = NOW()
+		WHERE id = $5`, req.Outcome, req.AppointmentID, req.Notes, contactedBy, id)
+	if err != nil {
+		return fmt.Errorf("failed to record reminder outcome: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("service reminder not found")
+	}
+
+	return nil
+}
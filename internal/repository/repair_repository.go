@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
 	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
@@ -17,17 +18,64 @@ type RepairRepository interface {
 	GetByID(id int) (*models.RepairOrder, error)
 	GetByCode(code string) (*models.RepairOrder, error)
 	List(filter models.RepairOrderFilter, page, limit int) ([]models.RepairOrder, int, error)
-	Update(id int, updates *models.RepairOrderUpdateRequest) error
-	UpdateProgress(id int, progress *models.RepairProgressUpdateRequest) error
+	Update(id int, updates *models.RepairOrderUpdateRequest, change *models.RepairStatusChange) error
+	UpdateProgress(id int, progress *models.RepairProgressUpdateRequest, change *models.RepairStatusChange) error
+	GetStatusHistory(repairID int) ([]models.RepairStatusHistory, error)
+	GetStatusTransitions() ([]models.RepairStatusTransition, error)
+	ReplaceStatusTransitions(transitions []models.RepairStatusTransition) error
+	Cancel(id int, dispositions []models.RepairPartDispositionRequest, change *models.RepairStatusChange) error
+	DecideEstimate(id int, status models.EstimateStatus, decidedBy string) error
+	CreateEstimateLink(link *models.RepairEstimateLink) error
+	GetEstimateLink(token string) (*models.RepairEstimateLink, error)
 	Delete(id int) error
 	
 	// Spare parts in repair
 	AddSparePart(repairID int, sparePart *models.RepairSparePartCreateRequest) error
 	RemoveSparePart(repairID int, sparePartID int) error
 	GetSpareParts(repairID int) ([]models.RepairSparePart, error)
+	GetVehicleRepairCost(vehicleID int) (float64, error)
+	HasOpenRepairs(vehicleID int) (bool, error)
+	GetVehiclesNeedingRepairOrders(filter models.VehicleRepairNeedFilter, page, limit int) ([]models.Vehicle, int, error)
+
+	// Labor management
+	AddLaborLine(line *models.RepairLaborLine) error
+	RemoveLaborLine(repairID int, lineID int) error
+	GetLaborLines(repairID int) ([]models.RepairLaborLine, error)
+
+	// Inspection checklist
+	AttachChecklist(repairID int, templateID int) error
+	GetChecklistItems(repairID int) ([]models.RepairChecklistItem, error)
+	UpdateChecklistItem(repairID int, itemID int, req *models.RepairChecklistItemUpdateRequest, checkedBy int) (*models.RepairChecklistItem, error)
+
+	// Photos and documents
+	AddAttachment(attachment *models.RepairAttachment) error
+	GetAttachment(repairID int, attachmentID int) (*models.RepairAttachment, error)
+	GetAttachments(repairID int) ([]models.RepairAttachment, error)
+	DeleteAttachment(repairID int, attachmentID int) error
+	
+	// Time tracking
+	StartWorkSession(repairID int, mechanicID int, notes *string) (*models.RepairWorkSession, error)
+	EndWorkSession(repairID int, mechanicID int, reason models.WorkSessionEndReason, notes *string) (*models.RepairWorkSession, error)
+	CloseWorkSessions(repairID int) error
+	GetWorkSessions(repairID int) ([]models.RepairWorkSession, error)
+	GetStandardHours(repairID int) (float64, error)
+	GetMechanicUtilization(dateFrom, dateTo time.Time, orderType models.RepairOrderType) ([]models.MechanicUtilization, error)
+	GetMechanicWorkload() ([]models.MechanicWorkload, error)
+
+	// SLA targets
+	GetDefaultSLADays(vehicleTypeID int, categories []string) (int, error)
+	GetOverdueRepairs() ([]models.RepairOrder, error)
+	MarkSLAAlerted(id int) error
+	GetTurnaround(dateFrom, dateTo time.Time, orderType models.RepairOrderType) ([]models.RepairTurnaround, []models.RepairTurnaround, error)
+
+	// Spare part reservations for open repairs
+	ReserveSparePart(repairID int, reservation *models.SparePartReservationCreateRequest, reservedBy int) (*models.SparePartReservation, error)
+	ReleaseReservation(repairID int, reservationID int) error
+	ReleaseReservations(repairID int) error
+	GetReservations(repairID int) ([]models.SparePartReservation, error)
 	
 	// Statistics
-	GetRepairStats(mechanicID *int, dateFrom, dateTo *time.Time) (map[string]interface{}, error)
+	GetRepairStats(mechanicID *int, dateFrom, dateTo *time.Time, orderType models.RepairOrderType) (map[string]interface{}, error)
 }
 
 type repairRepository struct {
@@ -39,23 +87,38 @@ func NewRepairRepository(db *database.Database) RepairRepository {
 }
 
 func (r *repairRepository) Create(repair *models.RepairOrder) error {
+	if repair.OrderType == "" {
+		repair.OrderType = models.RepairOrderTypeReconditioning
+	}
+
+	// The first status history entry is written together with the order
 	query := `
-		INSERT INTO repair_orders (code, vehicle_id, mechanic_id, assigned_by, description, estimated_cost, status, notes)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, created_at, updated_at`
+		WITH ro AS (
+			INSERT INTO repair_orders (code, order_type, vehicle_id, customer_id, customer_vehicle_id, mechanic_id, assigned_by,
+									   description, estimated_cost, status, notes, estimate_status, target_completion_date, is_warranty)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING id, status, assigned_by, created_at, updated_at
+		), history AS (
+			INSERT INTO repair_status_history (repair_order_id, to_status, changed_by, changed_at)
+			SELECT id, status, assigned_by, created_at FROM ro
+		)
+		SELECT id, created_at, updated_at FROM ro`
 	
-	return r.db.QueryRow(query, repair.Code, repair.VehicleID, repair.MechanicID, repair.AssignedBy, 
-		repair.Description, repair.EstimatedCost, repair.Status, repair.Notes).
+	return r.db.QueryRow(query, repair.Code, repair.OrderType, repair.VehicleID, repair.CustomerID, repair.CustomerVehicleID,
+		repair.MechanicID, repair.AssignedBy, repair.Description, repair.EstimatedCost, repair.Status, repair.Notes,
+		repair.EstimateStatus, repair.TargetCompletionDate, repair.IsWarranty).
 		Scan(&repair.ID, &repair.CreatedAt, &repair.UpdatedAt)
 }
 
 func (r *repairRepository) GetByID(id int) (*models.RepairOrder, error) {
 	repair := &models.RepairOrder{}
 	query := `
-		SELECT ro.id, ro.code, ro.vehicle_id, ro.mechanic_id, ro.assigned_by, ro.description,
-			   ro.estimated_cost, ro.actual_cost, ro.status, ro.started_at, ro.completed_at,
-			   ro.notes, ro.created_at, ro.updated_at,
-			   v.id, v.code, v.model, v.year, v.color, v.license_plate, v.status,
+		SELECT ro.id, ro.code, ro.order_type, ro.vehicle_id, ro.customer_id, ro.customer_vehicle_id,
+			   ro.mechanic_id, ro.assigned_by, ro.description,
+			   ro.estimated_cost, ro.actual_cost, ro.actual_hours, ro.status, ro.started_at, ro.completed_at,
+			   ro.notes, ro.estimate_status, ro.estimate_decided_at, ro.estimate_decided_by,
+			   ro.target_completion_date, ro.is_warranty, ro.created_at, ro.updated_at,
+			   v.code, v.model, v.year, v.color, v.license_plate, v.status,
 			   m.id, m.username, m.full_name,
 			   a.id, a.username, a.full_name
 		FROM repair_orders ro
@@ -71,16 +134,21 @@ func (r *repairRepository) GetByID(id int) (*models.RepairOrder, error) {
 	defer rows.Close()
 	
 	if rows.Next() {
-		var vehicle models.Vehicle
 		var mechanic models.User
 		var assigner models.User
+		// Inventory vehicle columns are NULL for customer service orders
+		var vehicleCode, vehicleModel, vehicleStatus sql.NullString
+		var vehicleYear sql.NullInt32
+		var vehicleColor, vehiclePlate *string
 		
 		err := rows.Scan(
-			&repair.ID, &repair.Code, &repair.VehicleID, &repair.MechanicID, &repair.AssignedBy,
-			&repair.Description, &repair.EstimatedCost, &repair.ActualCost, &repair.Status,
-			&repair.StartedAt, &repair.CompletedAt, &repair.Notes, &repair.CreatedAt, &repair.UpdatedAt,
-			&vehicle.ID, &vehicle.Code, &vehicle.Model, &vehicle.Year, &vehicle.Color,
-			&vehicle.LicensePlate, &vehicle.Status,
+			&repair.ID, &repair.Code, &repair.OrderType, &repair.VehicleID, &repair.CustomerID, &repair.CustomerVehicleID,
+			&repair.MechanicID, &repair.AssignedBy,
+			&repair.Description, &repair.EstimatedCost, &repair.ActualCost, &repair.ActualHours, &repair.Status,
+			&repair.StartedAt, &repair.CompletedAt, &repair.Notes,
+			&repair.EstimateStatus, &repair.EstimateDecidedAt, &repair.EstimateDecidedBy,
+			&repair.TargetCompletionDate, &repair.IsWarranty, &repair.CreatedAt, &repair.UpdatedAt,
+			&vehicleCode, &vehicleModel, &vehicleYear, &vehicleColor, &vehiclePlate, &vehicleStatus,
 			&mechanic.ID, &mechanic.Username, &mechanic.FullName,
 			&assigner.ID, &assigner.Username, &assigner.FullName,
 		)
@@ -88,9 +156,40 @@ func (r *repairRepository) GetByID(id int) (*models.RepairOrder, error) {
 			return nil, err
 		}
 		
-		repair.Vehicle = &vehicle
+		if repair.VehicleID != nil && vehicleCode.Valid {
+			repair.Vehicle = &models.Vehicle{
+				ID:           *repair.VehicleID,
+				Code:         vehicleCode.String,
+				Model:        vehicleModel.String,
+				Year:         int(vehicleYear.Int32),
+				Color:        vehicleColor,
+				LicensePlate: vehiclePlate,
+				Status:       models.VehicleStatus(vehicleStatus.String),
+			}
+		}
 		repair.Mechanic = &mechanic
 		repair.Assigner = &assigner
+
+		// Load customer and vehicle of customer service orders
+		if repair.CustomerVehicleID != nil {
+			var customerVehicle models.CustomerVehicle
+			err = r.db.Get(&customerVehicle, `SELECT`+customerVehicleColumns+customerVehicleJoins+`
+				WHERE cv.id = $1`, *repair.CustomerVehicleID)
+			if err != nil {
+				return nil, err
+			}
+			repair.CustomerVehicle = &customerVehicle
+		}
+		if repair.CustomerID != nil {
+			var customer models.Customer
+			err = r.db.Get(&customer, `
+				SELECT id, name, phone, email, address, id_card_number, created_at, updated_at
+				FROM customers WHERE id = $1`, *repair.CustomerID)
+			if err != nil {
+				return nil, err
+			}
+			repair.Customer = &customer
+		}
 		
 		// Load spare parts
 		spareParts, err := r.GetSpareParts(repair.ID)
@@ -98,6 +197,41 @@ func (r *repairRepository) GetByID(id int) (*models.RepairOrder, error) {
 			return nil, err
 		}
 		repair.SpareParts = spareParts
+
+		// Load labor lines
+		laborLines, err := r.GetLaborLines(repair.ID)
+		if err != nil {
+			return nil, err
+		}
+		repair.LaborLines = laborLines
+
+		// Load inspection checklist
+		checklist, err := r.GetChecklistItems(repair.ID)
+		if err != nil {
+			return nil, err
+		}
+		repair.Checklist = checklist
+
+		// Load photos and documents
+		attachments, err := r.GetAttachments(repair.ID)
+		if err != nil {
+			return nil, err
+		}
+		repair.Attachments = attachments
+
+		// Load active spare part reservations
+		reservations, err := r.GetReservations(repair.ID)
+		if err != nil {
+			return nil, err
+		}
+		repair.Reservations = reservations
+
+		// Load status history
+		history, err := r.GetStatusHistory(repair.ID)
+		if err != nil {
+			return nil, err
+		}
+		repair.StatusHistory = history
 		
 		return repair, nil
 	}
@@ -108,9 +242,9 @@ func (r *repairRepository) GetByID(id int) (*models.RepairOrder, error) {
 func (r *repairRepository) GetByCode(code string) (*models.RepairOrder, error) {
 	repair := &models.RepairOrder{}
 	query := `
-		SELECT id, code, vehicle_id, mechanic_id, assigned_by, description,
-			   estimated_cost, actual_cost, status, started_at, completed_at,
-			   notes, created_at, updated_at
+		SELECT id, code, order_type, vehicle_id, customer_id, customer_vehicle_id, mechanic_id, assigned_by, description,
+			   estimated_cost, actual_cost, actual_hours, status, started_at, completed_at,
+			   notes, estimate_status, estimate_decided_at, estimate_decided_by, created_at, updated_at
 		FROM repair_orders
 		WHERE code = $1`
 	
@@ -134,6 +268,18 @@ func (r *repairRepository) List(filter models.RepairOrderFilter, page, limit int
 		argIndex++
 	}
 	
+	if filter.OrderType != "" {
+		conditions = append(conditions, fmt.Sprintf("ro.order_type = $%d", argIndex))
+		args = append(args, filter.OrderType)
+		argIndex++
+	}
+	
+	if filter.CustomerID > 0 {
+		conditions = append(conditions, fmt.Sprintf("ro.customer_id = $%d", argIndex))
+		args = append(args, filter.CustomerID)
+		argIndex++
+	}
+	
 	if filter.MechanicID > 0 {
 		conditions = append(conditions, fmt.Sprintf("ro.mechanic_id = $%d", argIndex))
 		args = append(args, filter.MechanicID)
@@ -146,6 +292,14 @@ func (r *repairRepository) List(filter models.RepairOrderFilter, page, limit int
 		argIndex++
 	}
 	
+	if filter.Overdue {
+		conditions = append(conditions, "ro.status NOT IN ('completed', 'cancelled') AND ro.target_completion_date < CURRENT_DATE")
+	}
+	
+	if filter.Warranty {
+		conditions = append(conditions, "ro.is_warranty")
+	}
+	
 	if filter.DateFrom != "" {
 		conditions = append(conditions, fmt.Sprintf("ro.created_at >= $%d", argIndex))
 		args = append(args, filter.DateFrom)
@@ -180,14 +334,19 @@ func (r *repairRepository) List(filter models.RepairOrderFilter, page, limit int
 	offset := (page - 1) * limit
 	
 	query := fmt.Sprintf(`
-		SELECT ro.id, ro.code, ro.vehicle_id, ro.mechanic_id, ro.assigned_by,
-			   ro.description, ro.estimated_cost, ro.actual_cost, ro.status,
-			   ro.started_at, ro.completed_at, ro.notes, ro.created_at, ro.updated_at,
+		SELECT ro.id, ro.code, ro.order_type, ro.vehicle_id, ro.customer_id, ro.customer_vehicle_id,
+			   ro.mechanic_id, ro.assigned_by,
+			   ro.description, ro.estimated_cost, ro.actual_cost, ro.actual_hours, ro.status,
+			   ro.started_at, ro.completed_at, ro.notes, ro.estimate_status, ro.target_completion_date, ro.is_warranty,
+			   ro.created_at, ro.updated_at,
 			   v.code as vehicle_code, v.model, v.year, v.color, v.license_plate,
+			   cv.model as customer_vehicle_model, cv.license_plate as customer_vehicle_plate, c.name as customer_name,
 			   m.username as mechanic_username, m.full_name as mechanic_name,
 			   a.username as assigner_username, a.full_name as assigner_name
 		FROM repair_orders ro
 		LEFT JOIN vehicles v ON ro.vehicle_id = v.id
+		LEFT JOIN customer_vehicles cv ON ro.customer_vehicle_id = cv.id
+		LEFT JOIN customers c ON ro.customer_id = c.id
 		LEFT JOIN users m ON ro.mechanic_id = m.id
 		LEFT JOIN users a ON ro.assigned_by = a.id
 		%s
@@ -208,13 +367,17 @@ func (r *repairRepository) List(filter models.RepairOrderFilter, page, limit int
 		var repair models.RepairOrder
 		var vehicleCode, vehicleModel, vehicleColor, vehiclePlate sql.NullString
 		var vehicleYear sql.NullInt32
+		var customerVehicleModel, customerVehiclePlate, customerName sql.NullString
 		var mechanicUsername, mechanicName, assignerUsername, assignerName sql.NullString
 		
 		err := rows.Scan(
-			&repair.ID, &repair.Code, &repair.VehicleID, &repair.MechanicID, &repair.AssignedBy,
-			&repair.Description, &repair.EstimatedCost, &repair.ActualCost, &repair.Status,
-			&repair.StartedAt, &repair.CompletedAt, &repair.Notes, &repair.CreatedAt, &repair.UpdatedAt,
+			&repair.ID, &repair.Code, &repair.OrderType, &repair.VehicleID, &repair.CustomerID, &repair.CustomerVehicleID,
+			&repair.MechanicID, &repair.AssignedBy,
+			&repair.Description, &repair.EstimatedCost, &repair.ActualCost, &repair.ActualHours, &repair.Status,
+			&repair.StartedAt, &repair.CompletedAt, &repair.Notes, &repair.EstimateStatus, &repair.TargetCompletionDate,
+			&repair.IsWarranty, &repair.CreatedAt, &repair.UpdatedAt,
 			&vehicleCode, &vehicleModel, &vehicleYear, &vehicleColor, &vehiclePlate,
+			&customerVehicleModel, &customerVehiclePlate, &customerName,
 			&mechanicUsername, &mechanicName,
 			&assignerUsername, &assignerName,
 		)
@@ -223,9 +386,9 @@ func (r *repairRepository) List(filter models.RepairOrderFilter, page, limit int
 		}
 		
 		// Set vehicle info if available
-		if vehicleCode.Valid {
+		if repair.VehicleID != nil && vehicleCode.Valid {
 			repair.Vehicle = &models.Vehicle{
-				ID:           repair.VehicleID,
+				ID:           *repair.VehicleID,
 				Code:         vehicleCode.String,
 				Model:        vehicleModel.String,
 				Year:         int(vehicleYear.Int32),
@@ -234,6 +397,19 @@ func (r *repairRepository) List(filter models.RepairOrderFilter, page, limit int
 			}
 		}
 		
+		// Set customer vehicle info for customer service orders
+		if repair.CustomerVehicleID != nil && customerVehiclePlate.Valid {
+			repair.CustomerVehicle = &models.CustomerVehicle{
+				ID:           *repair.CustomerVehicleID,
+				Model:        customerVehicleModel.String,
+				LicensePlate: customerVehiclePlate.String,
+				CustomerName: customerName.String,
+			}
+			if repair.CustomerID != nil {
+				repair.CustomerVehicle.CustomerID = *repair.CustomerID
+			}
+		}
+		
 		// Set mechanic info if available
 		if mechanicUsername.Valid {
 			repair.Mechanic = &models.User{
@@ -258,7 +434,7 @@ func (r *repairRepository) List(filter models.RepairOrderFilter, page, limit int
 	return repairs, total, nil
 }
 
-func (r *repairRepository) Update(id int, updates *models.RepairOrderUpdateRequest) error {
+func (r *repairRepository) Update(id int, updates *models.RepairOrderUpdateRequest, change *models.RepairStatusChange) error {
 	var setParts []string
 	var args []interface{}
 	argIndex := 1
@@ -303,6 +479,13 @@ func (r *repairRepository) Update(id int, updates *models.RepairOrderUpdateReque
 		argIndex++
 	}
 	
+	if updates.TargetCompletionDate != nil {
+		// A new target re-arms the overdue alert
+		setParts = append(setParts, fmt.Sprintf("target_completion_date = $%d", argIndex), "sla_alerted_at = NULL")
+		args = append(args, *updates.TargetCompletionDate)
+		argIndex++
+	}
+	
 	if len(setParts) == 0 {
 		return nil // Nothing to update
 	}
@@ -316,30 +499,51 @@ func (r *repairRepository) Update(id int, updates *models.RepairOrderUpdateReque
 	
 	args = append(args, id)
 	
-	result, err := r.db.Exec(query, args...)
+	tx, err := r.db.Beginx()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 	
-	rowsAffected, err := result.RowsAffected()
+	fromStatus, err := r.lockStatusTx(tx, id)
 	if err != nil {
 		return err
 	}
 	
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
+	_, err = tx.Exec(query, args...)
+	if err != nil {
+		return err
 	}
 	
-	return nil
+	if updates.Status != nil {
+		err = r.addStatusHistoryTx(tx, id, fromStatus, *updates.Status, change)
+		if err != nil {
+			return err
+		}
+		
+		if *updates.Status == models.RepairStatusCancelled {
+			err = r.disposeSparePartsTx(tx, id, nil, change)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	
+	return tx.Commit()
 }
 
-func (r *repairRepository) UpdateProgress(id int, progress *models.RepairProgressUpdateRequest) error {
+func (r *repairRepository) UpdateProgress(id int, progress *models.RepairProgressUpdateRequest, change *models.RepairStatusChange) error {
 	tx, err := r.db.Beginx()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 	
+	fromStatus, err := r.lockStatusTx(tx, id)
+	if err != nil {
+		return err
+	}
+	
 	// Update repair order
 	var setParts []string
 	var args []interface{}
@@ -382,6 +586,18 @@ func (r *repairRepository) UpdateProgress(id int, progress *models.RepairProgres
 		return err
 	}
 	
+	err = r.addStatusHistoryTx(tx, id, fromStatus, progress.Status, change)
+	if err != nil {
+		return err
+	}
+	
+	if progress.Status == models.RepairStatusCancelled {
+		err = r.disposeSparePartsTx(tx, id, nil, change)
+		if err != nil {
+			return err
+		}
+	}
+	
 	// Add spare parts if provided
 	for _, sp := range progress.SpareParts {
 		err = r.addSparePartTx(tx, id, &sp)
@@ -393,9 +609,286 @@ func (r *repairRepository) UpdateProgress(id int, progress *models.RepairProgres
 	return tx.Commit()
 }
 
+func (r *repairRepository) Cancel(id int, dispositions []models.RepairPartDispositionRequest, change *models.RepairStatusChange) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	fromStatus, err := r.lockStatusTx(tx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE repair_orders SET status = 'cancelled', updated_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	err = r.addStatusHistoryTx(tx, id, fromStatus, models.RepairStatusCancelled, change)
+	if err != nil {
+		return err
+	}
+
+	err = r.disposeSparePartsTx(tx, id, dispositions, change)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// disposeSparePartsTx settles the part lines still in use on a cancelled repair;
+// lines without a chosen disposition are returned to stock
+func (r *repairRepository) disposeSparePartsTx(tx *sqlx.Tx, repairID int, dispositions []models.RepairPartDispositionRequest, change *models.RepairStatusChange) error {
+	if change == nil {
+		change = &models.RepairStatusChange{}
+	}
+
+	chosen := make(map[int]models.PartDisposition)
+	for _, d := range dispositions {
+		chosen[d.RepairSparePartID] = d.Disposition
+	}
+
+	var lines []models.RepairSparePart
+	err := tx.Select(&lines, `
+		SELECT id, spare_part_id, quantity_used
+		FROM repair_spare_parts
+		WHERE repair_order_id = $1 AND disposition IS NULL
+		ORDER BY id
+		FOR UPDATE`, repairID)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		disposition, ok := chosen[line.ID]
+		if !ok {
+			disposition = models.PartDispositionReturnToStock
+		}
+
+		switch disposition {
+		case models.PartDispositionReturnToStock:
+			_, err = tx.Exec(`UPDATE spare_parts SET stock_quantity = stock_quantity + $1 WHERE id = $2`, line.QuantityUsed, line.SparePartID)
+			if err != nil {
+				return err
+			}
+			err = r.addMovementTx(tx, line.SparePartID, models.StockMovementRepairReturn, line.QuantityUsed, line.QuantityUsed, repairID, change.Note, change.ChangedBy)
+		case models.PartDispositionScrap:
+			// The part left stock when it was issued; only the write-off is recorded
+			err = r.addMovementTx(tx, line.SparePartID, models.StockMovementRepairScrap, 0, line.QuantityUsed, repairID, change.Note, change.ChangedBy)
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			UPDATE repair_spare_parts
+			SET disposition = $1, disposed_at = NOW()
+			WHERE id = $2`, disposition, line.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addMovementTx records a stock movement; quantity is the change to stock_quantity
+func (r *repairRepository) addMovementTx(tx *sqlx.Tx, sparePartID int, movementType models.StockMovementType, quantity, units int, repairID int, notes *string, createdBy *int) error {
+	_, err := tx.Exec(`
+		INSERT INTO spare_part_movements (spare_part_id, movement_type, quantity, units, repair_order_id, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		sparePartID, movementType, quantity, units, repairID, notes, createdBy)
+	if err != nil {
+		return fmt.Errorf("failed to record stock movement: %v", err)
+	}
+
+	return nil
+}
+
+func (r *repairRepository) DecideEstimate(id int, status models.EstimateStatus, decidedBy string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	fromStatus, err := r.lockStatusTx(tx, id)
+	if err != nil {
+		return err
+	}
+
+	// An approved estimate moves the order to 'approved' so mechanics can pick it up,
+	// a rejected one cancels it
+	query := `
+		UPDATE repair_orders
+		SET estimate_status = $1, estimate_decided_at = NOW(), estimate_decided_by = $2, updated_at = NOW(),
+			status = CASE WHEN status <> 'pending' THEN status
+			              WHEN $1 = 'approved' THEN 'approved'
+			              ELSE 'cancelled' END
+		WHERE id = $3 AND estimate_status = 'pending'
+		RETURNING status`
+
+	var toStatus models.RepairStatus
+	err = tx.QueryRow(query, status, decidedBy, id).Scan(&toStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("estimate is not awaiting a decision")
+		}
+		return err
+	}
+
+	note := "Estimate " + string(status)
+	change := &models.RepairStatusChange{ActorName: &decidedBy, Note: &note}
+	err = r.addStatusHistoryTx(tx, id, fromStatus, toStatus, change)
+	if err != nil {
+		return err
+	}
+
+	if toStatus == models.RepairStatusCancelled {
+		err = r.disposeSparePartsTx(tx, id, nil, change)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// lockStatusTx locks a repair order for a status change and returns its current status
+func (r *repairRepository) lockStatusTx(tx *sqlx.Tx, id int) (models.RepairStatus, error) {
+	var status models.RepairStatus
+	err := tx.QueryRow(`SELECT status FROM repair_orders WHERE id = $1 FOR UPDATE`, id).Scan(&status)
+	if err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// addStatusHistoryTx records a status change; updates that keep the status are not recorded
+func (r *repairRepository) addStatusHistoryTx(tx *sqlx.Tx, id int, fromStatus, toStatus models.RepairStatus, change *models.RepairStatusChange) error {
+	if fromStatus == toStatus {
+		return nil
+	}
+	if change == nil {
+		change = &models.RepairStatusChange{}
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO repair_status_history (repair_order_id, from_status, to_status, changed_by, actor_name, note)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		id, fromStatus, toStatus, change.ChangedBy, change.ActorName, change.Note)
+	if err != nil {
+		return fmt.Errorf("failed to record status history: %v", err)
+	}
+
+	return nil
+}
+
+func (r *repairRepository) GetStatusHistory(repairID int) ([]models.RepairStatusHistory, error) {
+	query := `
+		SELECT h.id, h.repair_order_id, h.from_status, h.to_status, h.changed_by, h.actor_name, h.note, h.changed_at,
+			   u.full_name as changed_by_name
+		FROM repair_status_history h
+		LEFT JOIN users u ON h.changed_by = u.id
+		WHERE h.repair_order_id = $1
+		ORDER BY h.changed_at, h.id`
+
+	history := []models.RepairStatusHistory{}
+	err := r.db.Select(&history, query, repairID)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (r *repairRepository) GetStatusTransitions() ([]models.RepairStatusTransition, error) {
+	query := `
+		SELECT from_status, to_status
+		FROM repair_status_transitions
+		ORDER BY from_status, to_status`
+
+	transitions := []models.RepairStatusTransition{}
+	err := r.db.Select(&transitions, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return transitions, nil
+}
+
+func (r *repairRepository) ReplaceStatusTransitions(transitions []models.RepairStatusTransition) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM repair_status_transitions`)
+	if err != nil {
+		return err
+	}
+
+	for _, transition := range transitions {
+		_, err = tx.Exec(`
+			INSERT INTO repair_status_transitions (from_status, to_status)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING`, transition.FromStatus, transition.ToStatus)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *repairRepository) CreateEstimateLink(link *models.RepairEstimateLink) error {
+	query := `
+		INSERT INTO repair_estimate_links (repair_order_id, token, expires_at, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+
+	return r.db.QueryRow(query, link.RepairOrderID, link.Token, link.ExpiresAt, link.CreatedBy).
+		Scan(&link.ID, &link.CreatedAt)
+}
+
+func (r *repairRepository) GetEstimateLink(token string) (*models.RepairEstimateLink, error) {
+	query := `
+		SELECT id, repair_order_id, token, expires_at, created_by, created_at
+		FROM repair_estimate_links
+		WHERE token = $1`
+
+	var link models.RepairEstimateLink
+	err := r.db.Get(&link, query, token)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("estimate link not found")
+		}
+		return nil, err
+	}
+
+	return &link, nil
+}
+
 func (r *repairRepository) Delete(id int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	// Parts still in use go back to stock before their lines are removed
+	note := "Repair order deleted"
+	err = r.disposeSparePartsTx(tx, id, nil, &models.RepairStatusChange{Note: &note})
+	if err != nil {
+		return err
+	}
+	
 	query := `DELETE FROM repair_orders WHERE id = $1`
-	result, err := r.db.Exec(query, id)
+	result, err := tx.Exec(query, id)
 	if err != nil {
 		return err
 	}
@@ -409,7 +902,7 @@ func (r *repairRepository) Delete(id int) error {
 		return sql.ErrNoRows
 	}
 	
-	return nil
+	return tx.Commit()
 }
 
 func (r *repairRepository) AddSparePart(repairID int, sparePart *models.RepairSparePartCreateRequest) error {
@@ -431,15 +924,27 @@ func (r *repairRepository) addSparePartTx(tx *sqlx.Tx, repairID int, sparePart *
 	// Get spare part details and check stock
 	var unitPrice float64
 	var currentStock int
-	query := `SELECT selling_price, stock_quantity FROM spare_parts WHERE id = $1`
+	query := `SELECT selling_price, stock_quantity FROM spare_parts WHERE id = $1 FOR UPDATE`
 	err := tx.QueryRow(query, sparePart.SparePartID).Scan(&unitPrice, &currentStock)
 	if err != nil {
 		return err
 	}
 	
+	// Stock reserved by other repair orders cannot be used here
+	var reservedByOthers int
+	query = `
+		SELECT COALESCE(SUM(quantity - consumed_quantity), 0)
+		FROM spare_part_reservations
+		WHERE spare_part_id = $1 AND repair_order_id <> $2 AND status = 'active'`
+	err = tx.QueryRow(query, sparePart.SparePartID, repairID).Scan(&reservedByOthers)
+	if err != nil {
+		return err
+	}
+	
 	// Check if enough stock
-	if currentStock < sparePart.QuantityUsed {
-		return fmt.Errorf("insufficient stock: available %d, required %d", currentStock, sparePart.QuantityUsed)
+	available := currentStock - reservedByOthers
+	if available < sparePart.QuantityUsed {
+		return fmt.Errorf("insufficient stock: available %d, required %d", available, sparePart.QuantityUsed)
 	}
 	
 	// Calculate total price
@@ -462,26 +967,47 @@ func (r *repairRepository) addSparePartTx(tx *sqlx.Tx, repairID int, sparePart *
 		return err
 	}
 	
-	return nil
-}
-
-func (r *repairRepository) RemoveSparePart(repairID int, sparePartID int) error {
-	tx, err := r.db.Beginx()
+	err = r.addMovementTx(tx, sparePart.SparePartID, models.StockMovementRepairIssue, -sparePart.QuantityUsed, sparePart.QuantityUsed, repairID, nil, nil)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
 	
-	// Get quantity used to restore stock
+	// Consume this repair's own reservation for the part, if any
+	query = `
+		UPDATE spare_part_reservations
+		SET consumed_quantity = LEAST(quantity, consumed_quantity + $1),
+			status = CASE WHEN consumed_quantity + $1 >= quantity THEN 'consumed'::reservation_status_enum ELSE status END,
+			updated_at = NOW()
+		WHERE repair_order_id = $2 AND spare_part_id = $3 AND status = 'active'`
+	_, err = tx.Exec(query, sparePart.QuantityUsed, repairID, sparePart.SparePartID)
+	if err != nil {
+		return err
+	}
+	
+	return nil
+}
+
+func (r *repairRepository) RemoveSparePart(repairID int, sparePartID int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	// Get quantity used to restore stock; parts settled on cancellation are not returned twice
 	var quantityUsed int
-	query := `SELECT quantity_used FROM repair_spare_parts WHERE repair_order_id = $1 AND spare_part_id = $2`
+	query := `SELECT COALESCE(SUM(quantity_used), 0) FROM repair_spare_parts WHERE repair_order_id = $1 AND spare_part_id = $2 AND disposition IS NULL`
 	err = tx.QueryRow(query, repairID, sparePartID).Scan(&quantityUsed)
 	if err != nil {
 		return err
 	}
 	
+	if quantityUsed == 0 {
+		return sql.ErrNoRows
+	}
+	
 	// Delete repair spare part record
-	query = `DELETE FROM repair_spare_parts WHERE repair_order_id = $1 AND spare_part_id = $2`
+	query = `DELETE FROM repair_spare_parts WHERE repair_order_id = $1 AND spare_part_id = $2 AND disposition IS NULL`
 	_, err = tx.Exec(query, repairID, sparePartID)
 	if err != nil {
 		return err
@@ -494,13 +1020,18 @@ func (r *repairRepository) RemoveSparePart(repairID int, sparePartID int) error
 		return err
 	}
 	
+	err = r.addMovementTx(tx, sparePartID, models.StockMovementRepairReturn, quantityUsed, quantityUsed, repairID, nil, nil)
+	if err != nil {
+		return err
+	}
+	
 	return tx.Commit()
 }
 
 func (r *repairRepository) GetSpareParts(repairID int) ([]models.RepairSparePart, error) {
 	query := `
 		SELECT rsp.id, rsp.repair_order_id, rsp.spare_part_id, rsp.quantity_used,
-			   rsp.unit_price, rsp.total_price, rsp.created_at,
+			   rsp.unit_price, rsp.total_price, rsp.disposition, rsp.disposed_at, rsp.created_at,
 			   sp.code, sp.name, sp.unit
 		FROM repair_spare_parts rsp
 		LEFT JOIN spare_parts sp ON rsp.spare_part_id = sp.id
@@ -521,7 +1052,7 @@ func (r *repairRepository) GetSpareParts(repairID int) ([]models.RepairSparePart
 		
 		err := rows.Scan(
 			&rsp.ID, &rsp.RepairOrderID, &rsp.SparePartID, &rsp.QuantityUsed,
-			&rsp.UnitPrice, &rsp.TotalPrice, &rsp.CreatedAt,
+			&rsp.UnitPrice, &rsp.TotalPrice, &rsp.Disposition, &rsp.DisposedAt, &rsp.CreatedAt,
 			&sparePartCode, &sparePartName, &sparePartUnit,
 		)
 		if err != nil {
@@ -543,11 +1074,759 @@ func (r *repairRepository) GetSpareParts(repairID int) ([]models.RepairSparePart
 	return spareParts, nil
 }
 
-func (r *repairRepository) GetRepairStats(mechanicID *int, dateFrom, dateTo *time.Time) (map[string]interface{}, error) {
+// GetVehicleRepairCost sums the parts and labor of completed repairs on a vehicle plus
+// the parts kept as cost on cancelled ones
+func (r *repairRepository) GetVehicleRepairCost(vehicleID int) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(cost), 0) FROM (
+			SELECT rsp.total_price AS cost
+			FROM repair_spare_parts rsp
+			JOIN repair_orders ro ON rsp.repair_order_id = ro.id
+			WHERE ro.vehicle_id = $1
+			  AND ((ro.status = 'completed' AND rsp.disposition IS NULL) OR rsp.disposition = 'keep_as_cost')
+			UNION ALL
+			SELECT rll.total_price
+			FROM repair_labor_lines rll
+			JOIN repair_orders ro ON rll.repair_order_id = ro.id
+			WHERE ro.vehicle_id = $1 AND ro.status = 'completed'
+		) costs`
+	
+	var total float64
+	err := r.db.Get(&total, query, vehicleID)
+	if err != nil {
+		return 0, err
+	}
+	
+	return total, nil
+}
+
+func (r *repairRepository) HasOpenRepairs(vehicleID int) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM repair_orders
+			WHERE vehicle_id = $1 AND status NOT IN ('completed', 'cancelled')
+		)`
+	
+	var exists bool
+	err := r.db.Get(&exists, query, vehicleID)
+	if err != nil {
+		return false, err
+	}
+	
+	return exists, nil
+}
+
+// GetVehiclesNeedingRepairOrders lists stock vehicles that are in repair or in poor
+// condition but have no open repair order
+func (r *repairRepository) GetVehiclesNeedingRepairOrders(filter models.VehicleRepairNeedFilter, page, limit int) ([]models.Vehicle, int, error) {
+	conditions := []string{
+		"v.status <> 'sold'",
+		"(v.status = 'in_repair' OR v.condition_status IN ('needs_repair', 'poor'))",
+		`NOT EXISTS (
+			SELECT 1 FROM repair_orders ro
+			WHERE ro.vehicle_id = v.id AND ro.status NOT IN ('completed', 'cancelled')
+		)`,
+	}
+	var args []interface{}
+	argIndex := 1
+
+	if filter.BrandID > 0 {
+		conditions = append(conditions, fmt.Sprintf("v.brand_id = $%d", argIndex))
+		args = append(args, filter.BrandID)
+		argIndex++
+	}
+
+	if filter.TypeID > 0 {
+		conditions = append(conditions, fmt.Sprintf("vb.type_id = $%d", argIndex))
+		args = append(args, filter.TypeID)
+		argIndex++
+	}
+
+	baseQuery := `
+		FROM vehicles v
+		JOIN vehicle_brands vb ON v.brand_id = vb.id
+		WHERE ` + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.Get(&total, "SELECT COUNT(*) "+baseQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count vehicles needing repair orders: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	query := fmt.Sprintf(`
+		SELECT
+			v.id, v.code, v.brand_id, v.model, v.year, v.color, v.engine_capacity,
+			v.fuel_type, v.transmission_type, v.license_plate, v.chassis_number,
+			v.engine_number, v.odometer, v.source_type, v.source_id, v.purchase_price,
+			v.condition_status, v.status, v.repair_cost, v.hpp_price, v.selling_price,
+			v.sold_price, v.sold_date, v.notes, v.created_by, v.created_at, v.updated_at,
+			vb.id as "brand.id", vb.name as "brand.name", vb.type_id as "brand.type_id", vb.created_at as "brand.created_at"
+		%s
+		ORDER BY (v.status = 'in_repair') DESC, v.created_at ASC
+		LIMIT $%d OFFSET $%d`, baseQuery, argIndex, argIndex+1)
+
+	args = append(args, limit, offset)
+
+	vehicles := []models.Vehicle{}
+	if err := r.db.Select(&vehicles, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to get vehicles needing repair orders: %w", err)
+	}
+
+	return vehicles, total, nil
+}
+
+func (r *repairRepository) AddLaborLine(line *models.RepairLaborLine) error {
+	query := `
+		INSERT INTO repair_labor_lines (repair_order_id, service_id, description, hours, labor_rate, total_price)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	return r.db.QueryRow(query, line.RepairOrderID, line.ServiceID, line.Description,
+		line.Hours, line.LaborRate, line.TotalPrice).
+		Scan(&line.ID, &line.CreatedAt)
+}
+
+func (r *repairRepository) RemoveLaborLine(repairID int, lineID int) error {
+	query := `DELETE FROM repair_labor_lines WHERE id = $1 AND repair_order_id = $2`
+
+	result, err := r.db.Exec(query, lineID, repairID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *repairRepository) GetLaborLines(repairID int) ([]models.RepairLaborLine, error) {
+	query := `
+		SELECT rll.id, rll.repair_order_id, rll.service_id, rll.description, rll.hours,
+			   rll.labor_rate, rll.total_price, rll.created_at,
+			   sc.code, sc.name, sc.category, sc.standard_hours
+		FROM repair_labor_lines rll
+		LEFT JOIN service_catalog sc ON rll.service_id = sc.id
+		WHERE rll.repair_order_id = $1
+		ORDER BY rll.created_at`
+
+	rows, err := r.db.Query(query, repairID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var laborLines []models.RepairLaborLine
+
+	for rows.Next() {
+		var line models.RepairLaborLine
+		var serviceCode, serviceName, serviceCategory sql.NullString
+		var standardHours sql.NullFloat64
+
+		err := rows.Scan(
+			&line.ID, &line.RepairOrderID, &line.ServiceID, &line.Description, &line.Hours,
+			&line.LaborRate, &line.TotalPrice, &line.CreatedAt,
+			&serviceCode, &serviceName, &serviceCategory, &standardHours,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if line.ServiceID != nil && serviceCode.Valid {
+			line.Service = &models.ServiceCatalog{
+				ID:            *line.ServiceID,
+				Code:          serviceCode.String,
+				Name:          serviceName.String,
+				Category:      serviceCategory.String,
+				StandardHours: standardHours.Float64,
+			}
+		}
+
+		laborLines = append(laborLines, line)
+	}
+
+	return laborLines, nil
+}
+
+func (r *repairRepository) AttachChecklist(repairID int, templateID int) error {
+	// Template items are copied so later template edits don't change existing checklists
+	query := `
+		INSERT INTO repair_checklist_items (repair_order_id, template_id, label, category, is_required, sort_order)
+		SELECT $1, cti.template_id, cti.label, cti.category, cti.is_required, cti.sort_order
+		FROM checklist_template_items cti
+		WHERE cti.template_id = $2`
+
+	_, err := r.db.Exec(query, repairID, templateID)
+	return err
+}
+
+func (r *repairRepository) GetChecklistItems(repairID int) ([]models.RepairChecklistItem, error) {
+	query := `
+		SELECT id, repair_order_id, template_id, label, category, is_required, sort_order,
+			   result, note, photo_path, checked_by, checked_at, created_at
+		FROM repair_checklist_items
+		WHERE repair_order_id = $1
+		ORDER BY template_id, sort_order, id`
+
+	var items []models.RepairChecklistItem
+	if err := r.db.Select(&items, query, repairID); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *repairRepository) UpdateChecklistItem(repairID int, itemID int, req *models.RepairChecklistItemUpdateRequest, checkedBy int) (*models.RepairChecklistItem, error) {
+	query := `
+		UPDATE repair_checklist_items
+		SET result = $1, note = $2, photo_path = COALESCE($3, photo_path), checked_by = $4, checked_at = NOW()
+		WHERE id = $5 AND repair_order_id = $6
+		RETURNING id, repair_order_id, template_id, label, category, is_required, sort_order,
+				  result, note, photo_path, checked_by, checked_at, created_at`
+
+	var item models.RepairChecklistItem
+	err := r.db.QueryRowx(query, req.Result, req.Note, req.PhotoPath, checkedBy, itemID, repairID).StructScan(&item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (r *repairRepository) AddAttachment(attachment *models.RepairAttachment) error {
+	query := `
+		INSERT INTO repair_attachments (repair_order_id, stage, file_path, thumbnail_path, caption,
+										file_name, content_type, file_size, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at`
+
+	return r.db.QueryRow(query, attachment.RepairOrderID, attachment.Stage, attachment.FilePath,
+		attachment.ThumbnailPath, attachment.Caption, attachment.FileName, attachment.ContentType,
+		attachment.FileSize, attachment.UploadedBy).
+		Scan(&attachment.ID, &attachment.CreatedAt)
+}
+
+func (r *repairRepository) GetAttachment(repairID int, attachmentID int) (*models.RepairAttachment, error) {
+	query := `
+		SELECT id, repair_order_id, stage, file_path, thumbnail_path, caption,
+			   file_name, content_type, file_size, uploaded_by, created_at
+		FROM repair_attachments
+		WHERE id = $1 AND repair_order_id = $2`
+
+	var attachment models.RepairAttachment
+	if err := r.db.Get(&attachment, query, attachmentID, repairID); err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+func (r *repairRepository) GetAttachments(repairID int) ([]models.RepairAttachment, error) {
+	query := `
+		SELECT id, repair_order_id, stage, file_path, thumbnail_path, caption,
+			   file_name, content_type, file_size, uploaded_by, created_at
+		FROM repair_attachments
+		WHERE repair_order_id = $1
+		ORDER BY created_at, id`
+
+	var attachments []models.RepairAttachment
+	if err := r.db.Select(&attachments, query, repairID); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (r *repairRepository) DeleteAttachment(repairID int, attachmentID int) error {
+	query := `DELETE FROM repair_attachments WHERE id = $1 AND repair_order_id = $2`
+
+	result, err := r.db.Exec(query, attachmentID, repairID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *repairRepository) StartWorkSession(repairID int, mechanicID int, notes *string) (*models.RepairWorkSession, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// A mechanic can only have one running timer
+	var runningCode string
+	query := `
+		SELECT ro.code
+		FROM repair_work_sessions rws
+		JOIN repair_orders ro ON rws.repair_order_id = ro.id
+		WHERE rws.mechanic_id = $1 AND rws.ended_at IS NULL`
+	err = tx.QueryRow(query, mechanicID).Scan(&runningCode)
+	if err == nil {
+		return nil, fmt.Errorf("timer already running on repair order %s", runningCode)
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	query = `
+		INSERT INTO repair_work_sessions (repair_order_id, mechanic_id, notes)
+		VALUES ($1, $2, $3)
+		RETURNING id, repair_order_id, mechanic_id, started_at, ended_at, end_reason, notes, created_at`
+
+	session := &models.RepairWorkSession{}
+	err = tx.Get(session, query, repairID, mechanicID, notes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *repairRepository) EndWorkSession(repairID int, mechanicID int, reason models.WorkSessionEndReason, notes *string) (*models.RepairWorkSession, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE repair_work_sessions
+		SET ended_at = NOW(), end_reason = $1, notes = COALESCE($2, notes)
+		WHERE repair_order_id = $3 AND mechanic_id = $4 AND ended_at IS NULL
+		RETURNING id, repair_order_id, mechanic_id, started_at, ended_at, end_reason, notes, created_at,
+			EXTRACT(EPOCH FROM (ended_at - started_at))/3600 as duration_hours`
+
+	session := &models.RepairWorkSession{}
+	err = tx.Get(session, query, reason, notes, repairID, mechanicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.refreshActualHoursTx(tx, repairID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *repairRepository) CloseWorkSessions(repairID int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE repair_work_sessions
+		SET ended_at = NOW(), end_reason = 'closed'
+		WHERE repair_order_id = $1 AND ended_at IS NULL`
+	_, err = tx.Exec(query, repairID)
+	if err != nil {
+		return err
+	}
+
+	if err := r.refreshActualHoursTx(tx, repairID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// refreshActualHoursTx recalculates the hours booked on a repair from its closed sessions
+func (r *repairRepository) refreshActualHoursTx(tx *sqlx.Tx, repairID int) error {
+	query := `
+		UPDATE repair_orders
+		SET actual_hours = (
+				SELECT COALESCE(SUM(EXTRACT(EPOCH FROM (ended_at - started_at))/3600), 0)
+				FROM repair_work_sessions
+				WHERE repair_order_id = $1 AND ended_at IS NOT NULL
+			),
+			updated_at = NOW()
+		WHERE id = $1`
+
+	_, err := tx.Exec(query, repairID)
+	return err
+}
+
+func (r *repairRepository) GetWorkSessions(repairID int) ([]models.RepairWorkSession, error) {
+	query := `
+		SELECT rws.id, rws.repair_order_id, rws.mechanic_id, rws.started_at, rws.ended_at,
+			   rws.end_reason, rws.notes, rws.created_at,
+			   EXTRACT(EPOCH FROM (COALESCE(rws.ended_at, NOW()) - rws.started_at))/3600 as duration_hours,
+			   u.full_name as mechanic_name
+		FROM repair_work_sessions rws
+		LEFT JOIN users u ON rws.mechanic_id = u.id
+		WHERE rws.repair_order_id = $1
+		ORDER BY rws.started_at`
+
+	sessions := []models.RepairWorkSession{}
+	err := r.db.Select(&sessions, query, repairID)
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *repairRepository) GetStandardHours(repairID int) (float64, error) {
+	// Catalog labor uses its standard hours; free-text labor counts its booked hours
+	query := `
+		SELECT COALESCE(SUM(COALESCE(sc.standard_hours, rll.hours)), 0)
+		FROM repair_labor_lines rll
+		LEFT JOIN service_catalog sc ON rll.service_id = sc.id
+		WHERE rll.repair_order_id = $1`
+
+	var hours float64
+	err := r.db.Get(&hours, query, repairID)
+	if err != nil {
+		return 0, err
+	}
+
+	return hours, nil
+}
+
+// GetMechanicUtilization reports the work on orders of orderType, or on every order when it is empty
+func (r *repairRepository) GetMechanicUtilization(dateFrom, dateTo time.Time, orderType models.RepairOrderType) ([]models.MechanicUtilization, error) {
+	// Logged hours are clipped to the period; efficiency only counts completed jobs with tracked time
+	query := `
+		SELECT u.id as mechanic_id, u.full_name as mechanic_name,
+			   COALESCE(ws.logged_hours, 0) as logged_hours,
+			   COALESCE(cj.completed_jobs, 0) as completed_jobs,
+			   COALESCE(cj.standard_hours, 0) as standard_hours,
+			   COALESCE(cj.actual_hours, 0) as actual_hours
+		FROM users u
+		JOIN roles r ON u.role_id = r.id
+		LEFT JOIN (
+			SELECT mechanic_id,
+				   SUM(EXTRACT(EPOCH FROM (LEAST(COALESCE(ws.ended_at, NOW()), $2) - GREATEST(ws.started_at, $1)))/3600) as logged_hours
+			FROM repair_work_sessions ws
+			JOIN repair_orders ro ON ro.id = ws.repair_order_id
+			WHERE ws.started_at < $2 AND COALESCE(ws.ended_at, NOW()) > $1
+			  AND ($3 = '' OR ro.order_type::text = $3)
+			GROUP BY ws.mechanic_id
+		) ws ON ws.mechanic_id = u.id
+		LEFT JOIN (
+			SELECT ro.mechanic_id,
+				   COUNT(*) as completed_jobs,
+				   SUM(CASE WHEN ro.actual_hours > 0 THEN std.hours ELSE 0 END) as standard_hours,
+				   SUM(ro.actual_hours) as actual_hours
+			FROM repair_orders ro
+			LEFT JOIN LATERAL (
+				SELECT COALESCE(SUM(COALESCE(sc.standard_hours, rll.hours)), 0) as hours
+				FROM repair_labor_lines rll
+				LEFT JOIN service_catalog sc ON rll.service_id = sc.id
+				WHERE rll.repair_order_id = ro.id
+			) std ON true
+			WHERE ro.status = 'completed' AND ro.completed_at >= $1 AND ro.completed_at < $2
+			  AND ($3 = '' OR ro.order_type::text = $3)
+			GROUP BY ro.mechanic_id
+		) cj ON cj.mechanic_id = u.id
+		WHERE r.name = 'mekanik' AND u.is_active = true
+		ORDER BY u.full_name`
+
+	utilization := []models.MechanicUtilization{}
+	err := r.db.Select(&utilization, query, dateFrom, dateTo, string(orderType))
+	if err != nil {
+		return nil, err
+	}
+
+	return utilization, nil
+}
+
+func (r *repairRepository) GetMechanicWorkload() ([]models.MechanicWorkload, error) {
+	// Remaining hours are catalog standard hours not yet covered by tracked time.
+	// Open jobs past their target completion date count as overdue.
+	query := `
+		SELECT u.id as mechanic_id, u.full_name as mechanic_name,
+			   COUNT(ro.id) as open_jobs,
+			   COUNT(CASE WHEN ro.status IN ('pending', 'approved', 'on_hold') THEN 1 END) as pending_jobs,
+			   COUNT(CASE WHEN ro.status IN ('in_progress', 'waiting_parts', 'quality_check') THEN 1 END) as in_progress_jobs,
+			   COALESCE(SUM(GREATEST(std.hours - ro.actual_hours, 0)), 0) as estimated_hours_remaining,
+			   COUNT(CASE WHEN ro.target_completion_date < CURRENT_DATE THEN 1 END) as overdue_jobs
+		FROM users u
+		JOIN roles r ON u.role_id = r.id
+		LEFT JOIN repair_orders ro ON ro.mechanic_id = u.id AND ro.status NOT IN ('completed', 'cancelled')
+		LEFT JOIN LATERAL (
+			SELECT COALESCE(SUM(COALESCE(sc.standard_hours, rll.hours)), 0) as hours
+			FROM repair_labor_lines rll
+			LEFT JOIN service_catalog sc ON rll.service_id = sc.id
+			WHERE rll.repair_order_id = ro.id
+		) std ON true
+		WHERE r.name = 'mekanik' AND u.is_active = true
+		GROUP BY u.id, u.full_name
+		ORDER BY estimated_hours_remaining ASC, open_jobs ASC, u.id ASC`
+
+	workload := []models.MechanicWorkload{}
+	err := r.db.Select(&workload, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return workload, nil
+}
+
+// GetDefaultSLADays returns the longest target of the catalog operations in the given categories,
+// falling back to the target of the vehicle type; 0 when neither is set
+func (r *repairRepository) GetDefaultSLADays(vehicleTypeID int, categories []string) (int, error) {
+	query := `
+		SELECT COALESCE(
+			(SELECT MAX(sla_days) FROM service_catalog
+			 WHERE is_active = true AND category = ANY($2)
+			   AND (vehicle_type_id IS NULL OR vehicle_type_id = $1)),
+			(SELECT repair_sla_days FROM vehicle_types WHERE id = $1),
+			0)`
+
+	var days int
+	err := r.db.Get(&days, query, vehicleTypeID, pq.Array(categories))
+	if err != nil {
+		return 0, err
+	}
+
+	return days, nil
+}
+
+// GetOverdueRepairs returns open repair orders past their target that were not alerted yet
+func (r *repairRepository) GetOverdueRepairs() ([]models.RepairOrder, error) {
+	query := `
+		SELECT id, code, mechanic_id, assigned_by, status, target_completion_date
+		FROM repair_orders
+		WHERE status NOT IN ('completed', 'cancelled')
+		  AND target_completion_date < CURRENT_DATE
+		  AND sla_alerted_at IS NULL
+		ORDER BY target_completion_date, id`
+
+	repairs := []models.RepairOrder{}
+	err := r.db.Select(&repairs, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return repairs, nil
+}
+
+func (r *repairRepository) MarkSLAAlerted(id int) error {
+	_, err := r.db.Exec(`UPDATE repair_orders SET sla_alerted_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+// GetTurnaround averages the created-to-completed time of repairs of orderType completed in the
+// period, grouped per mechanic and per vehicle type; an empty orderType covers every order
+func (r *repairRepository) GetTurnaround(dateFrom, dateTo time.Time, orderType models.RepairOrderType) ([]models.RepairTurnaround, []models.RepairTurnaround, error) {
+	const columns = `
+			   COUNT(*) as completed_jobs,
+			   ROUND(AVG(EXTRACT(EPOCH FROM (ro.completed_at - ro.created_at)) / 86400)::numeric, 2) as avg_turnaround_days,
+			   COUNT(CASE WHEN ro.completed_at::date <= ro.target_completion_date THEN 1 END) as on_time_jobs,
+			   COUNT(CASE WHEN ro.completed_at::date > ro.target_completion_date THEN 1 END) as late_jobs`
+
+	byMechanic := []models.RepairTurnaround{}
+	err := r.db.Select(&byMechanic, `
+		SELECT u.id as group_id, u.full_name as group_name,`+columns+`
+		FROM repair_orders ro
+		JOIN users u ON ro.mechanic_id = u.id
+		WHERE ro.status = 'completed' AND ro.completed_at >= $1 AND ro.completed_at < $2
+		  AND ($3 = '' OR ro.order_type::text = $3)
+		GROUP BY u.id, u.full_name
+		ORDER BY avg_turnaround_days ASC, u.full_name`, dateFrom, dateTo, string(orderType))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byVehicleType := []models.RepairTurnaround{}
+	err = r.db.Select(&byVehicleType, `
+		SELECT vt.id as group_id, vt.name as group_name,`+columns+`
+		FROM repair_orders ro
+		LEFT JOIN vehicles v ON ro.vehicle_id = v.id
+		LEFT JOIN customer_vehicles cv ON ro.customer_vehicle_id = cv.id
+		JOIN vehicle_brands vb ON COALESCE(v.brand_id, cv.brand_id) = vb.id
+		JOIN vehicle_types vt ON vb.type_id = vt.id
+		WHERE ro.status = 'completed' AND ro.completed_at >= $1 AND ro.completed_at < $2
+		  AND ($3 = '' OR ro.order_type::text = $3)
+		GROUP BY vt.id, vt.name
+		ORDER BY vt.name`, dateFrom, dateTo, string(orderType))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return byMechanic, byVehicleType, nil
+}
+
+func (r *repairRepository) ReserveSparePart(repairID int, reservation *models.SparePartReservationCreateRequest, reservedBy int) (*models.SparePartReservation, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the spare part row so concurrent reservations see a consistent stock
+	var stock int
+	query := `SELECT stock_quantity FROM spare_parts WHERE id = $1 AND is_active = true FOR UPDATE`
+	err = tx.QueryRow(query, reservation.SparePartID).Scan(&stock)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("spare part not found or inactive")
+		}
+		return nil, err
+	}
+
+	var reserved int
+	query = `
+		SELECT COALESCE(SUM(quantity - consumed_quantity), 0)
+		FROM spare_part_reservations
+		WHERE spare_part_id = $1 AND status = 'active'`
+	err = tx.QueryRow(query, reservation.SparePartID).Scan(&reserved)
+	if err != nil {
+		return nil, err
+	}
+
+	available := stock - reserved
+	if available < reservation.Quantity {
+		return nil, fmt.Errorf("insufficient stock: available %d, requested %d", available, reservation.Quantity)
+	}
+
+	// Merge into the existing active reservation for the same part
+	query = `
+		INSERT INTO spare_part_reservations (repair_order_id, spare_part_id, quantity, reserved_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (repair_order_id, spare_part_id) WHERE status = 'active'
+		DO UPDATE SET quantity = spare_part_reservations.quantity + EXCLUDED.quantity,
+			reserved_by = EXCLUDED.reserved_by,
+			updated_at = NOW()
+		RETURNING id, repair_order_id, spare_part_id, quantity, consumed_quantity, status,
+			reserved_by, released_at, created_at, updated_at`
+
+	result := &models.SparePartReservation{}
+	err = tx.Get(result, query, repairID, reservation.SparePartID, reservation.Quantity, reservedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *repairRepository) ReleaseReservation(repairID int, reservationID int) error {
+	query := `
+		UPDATE spare_part_reservations
+		SET status = 'released', released_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND repair_order_id = $2 AND status = 'active'`
+
+	result, err := r.db.Exec(query, reservationID, repairID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *repairRepository) ReleaseReservations(repairID int) error {
+	query := `
+		UPDATE spare_part_reservations
+		SET status = 'released', released_at = NOW(), updated_at = NOW()
+		WHERE repair_order_id = $1 AND status = 'active'`
+
+	_, err := r.db.Exec(query, repairID)
+	return err
+}
+
+func (r *repairRepository) GetReservations(repairID int) ([]models.SparePartReservation, error) {
+	query := `
+		SELECT spr.id, spr.repair_order_id, spr.spare_part_id, spr.quantity, spr.consumed_quantity,
+			   spr.status, spr.reserved_by, spr.released_at, spr.created_at, spr.updated_at,
+			   sp.code, sp.name, sp.unit, sp.stock_quantity, sp.selling_price
+		FROM spare_part_reservations spr
+		LEFT JOIN spare_parts sp ON spr.spare_part_id = sp.id
+		WHERE spr.repair_order_id = $1 AND spr.status = 'active'
+		ORDER BY spr.created_at`
+
+	rows, err := r.db.Query(query, repairID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reservations []models.SparePartReservation
+
+	for rows.Next() {
+		var spr models.SparePartReservation
+		var sparePartCode, sparePartName, sparePartUnit sql.NullString
+		var sparePartStock sql.NullInt64
+		var sparePartPrice sql.NullFloat64
+
+		err := rows.Scan(
+			&spr.ID, &spr.RepairOrderID, &spr.SparePartID, &spr.Quantity, &spr.ConsumedQuantity,
+			&spr.Status, &spr.ReservedBy, &spr.ReleasedAt, &spr.CreatedAt, &spr.UpdatedAt,
+			&sparePartCode, &sparePartName, &sparePartUnit, &sparePartStock, &sparePartPrice,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if sparePartCode.Valid {
+			spr.SparePart = &models.SparePart{
+				ID:            spr.SparePartID,
+				Code:          sparePartCode.String,
+				Name:          sparePartName.String,
+				Unit:          sparePartUnit.String,
+				StockQuantity: int(sparePartStock.Int64),
+				SellingPrice:  sparePartPrice.Float64,
+			}
+		}
+
+		reservations = append(reservations, spr)
+	}
+
+	return reservations, nil
+}
+
+// GetRepairStats summarizes orders of orderType, or every order when it is empty
+func (r *repairRepository) GetRepairStats(mechanicID *int, dateFrom, dateTo *time.Time, orderType models.RepairOrderType) (map[string]interface{}, error) {
 	var conditions []string
 	var args []interface{}
 	argIndex := 1
 	
+	if orderType != "" {
+		conditions = append(conditions, fmt.Sprintf("order_type = $%d", argIndex))
+		args = append(args, orderType)
+		argIndex++
+	}
+	
 	if mechanicID != nil {
 		conditions = append(conditions, fmt.Sprintf("mechanic_id = $%d", argIndex))
 		args = append(args, *mechanicID)
@@ -575,7 +1854,11 @@ func (r *repairRepository) GetRepairStats(mechanicID *int, dateFrom, dateTo *tim
 		SELECT 
 			COUNT(*) as total_repairs,
 			COUNT(CASE WHEN status = 'pending' THEN 1 END) as pending_repairs,
+			COUNT(CASE WHEN status = 'approved' THEN 1 END) as approved_repairs,
 			COUNT(CASE WHEN status = 'in_progress' THEN 1 END) as in_progress_repairs,
+			COUNT(CASE WHEN status = 'waiting_parts' THEN 1 END) as waiting_parts_repairs,
+			COUNT(CASE WHEN status = 'quality_check' THEN 1 END) as quality_check_repairs,
+			COUNT(CASE WHEN status = 'on_hold' THEN 1 END) as on_hold_repairs,
 			COUNT(CASE WHEN status = 'completed' THEN 1 END) as completed_repairs,
 			COUNT(CASE WHEN status = 'cancelled' THEN 1 END) as cancelled_repairs,
 			COALESCE(SUM(estimated_cost), 0) as total_estimated_cost,
@@ -586,11 +1869,11 @@ func (r *repairRepository) GetRepairStats(mechanicID *int, dateFrom, dateTo *tim
 		%s`, whereClause)
 	
 	var stats map[string]interface{} = make(map[string]interface{})
-	var totalRepairs, pending, inProgress, completed, cancelled int
+	var totalRepairs, pending, approved, inProgress, waitingParts, qualityCheck, onHold, completed, cancelled int
 	var totalEstimated, totalActual, avgHours float64
 	
 	err := r.db.QueryRow(query, args...).Scan(
-		&totalRepairs, &pending, &inProgress, &completed, &cancelled,
+		&totalRepairs, &pending, &approved, &inProgress, &waitingParts, &qualityCheck, &onHold, &completed, &cancelled,
 		&totalEstimated, &totalActual, &avgHours,
 	)
 	if err != nil {
@@ -599,12 +1882,66 @@ func (r *repairRepository) GetRepairStats(mechanicID *int, dateFrom, dateTo *tim
 	
 	stats["total_repairs"] = totalRepairs
 	stats["pending_repairs"] = pending
+	stats["approved_repairs"] = approved
 	stats["in_progress_repairs"] = inProgress
+	stats["waiting_parts_repairs"] = waitingParts
+	stats["quality_check_repairs"] = qualityCheck
+	stats["on_hold_repairs"] = onHold
 	stats["completed_repairs"] = completed
 	stats["cancelled_repairs"] = cancelled
 	stats["total_estimated_cost"] = totalEstimated
 	stats["total_actual_cost"] = totalActual
 	stats["average_completion_hours"] = avgHours
 	
+	// Split actual cost into spare parts and labor
+	costQuery := fmt.Sprintf(`
+		SELECT
+			COALESCE((SELECT SUM(rsp.total_price) FROM repair_spare_parts rsp
+				WHERE rsp.repair_order_id IN (SELECT id FROM repair_orders %s)), 0) as total_parts_cost,
+			COALESCE((SELECT SUM(rll.total_price) FROM repair_labor_lines rll
+				WHERE rll.repair_order_id IN (SELECT id FROM repair_orders %s)), 0) as total_labor_cost,
+			COALESCE((SELECT SUM(rll.hours) FROM repair_labor_lines rll
+				WHERE rll.repair_order_id IN (SELECT id FROM repair_orders %s)), 0) as total_labor_hours`,
+		whereClause, whereClause, whereClause)
+	
+	var totalParts, totalLabor, totalLaborHours float64
+	err = r.db.QueryRow(costQuery, args...).Scan(&totalParts, &totalLabor, &totalLaborHours)
+	if err != nil {
+		return nil, err
+	}
+	
+	stats["total_parts_cost"] = totalParts
+	stats["total_labor_cost"] = totalLabor
+	stats["total_labor_hours"] = totalLaborHours
+	
+	// Tracked time against catalog standard hours on jobs with timer sessions
+	timeQuery := fmt.Sprintf(`
+		SELECT
+			COALESCE(SUM(ro.actual_hours), 0) as total_actual_hours,
+			COALESCE(SUM(std.hours), 0) as total_standard_hours
+		FROM (SELECT id, actual_hours FROM repair_orders %s) ro
+		LEFT JOIN LATERAL (
+			SELECT COALESCE(SUM(COALESCE(sc.standard_hours, rll.hours)), 0) as hours
+			FROM repair_labor_lines rll
+			LEFT JOIN service_catalog sc ON rll.service_id = sc.id
+			WHERE rll.repair_order_id = ro.id
+		) std ON true
+		WHERE ro.actual_hours > 0`, whereClause)
+	
+	var totalActualHours, totalStandardHours float64
+	err = r.db.QueryRow(timeQuery, args...).Scan(&totalActualHours, &totalStandardHours)
+	if err != nil {
+		return nil, err
+	}
+	
+	efficiency := 0.0
+	if totalActualHours > 0 {
+		efficiency = totalStandardHours / totalActualHours * 100
+	}
+	
+	stats["total_actual_hours"] = totalActualHours
+	stats["total_standard_hours"] = totalStandardHours
+	stats["efficiency_percent"] = efficiency
+	
 	return stats, nil
 }
\ No newline at end of file
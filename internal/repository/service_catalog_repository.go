@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+)
+
+type ServiceCatalogRepository interface {
+	Create(req *models.ServiceCatalogCreateRequest) (*models.ServiceCatalog, error)
+	GetByID(id int) (*models.ServiceCatalog, error)
+	GetByCode(code string) (*models.ServiceCatalog, error)
+	Update(id int, req *models.ServiceCatalogUpdateRequest) (*models.ServiceCatalog, error)
+	Delete(id int) error
+	List(filter models.ServiceCatalogFilter, page, limit int) ([]models.ServiceCatalog, int, error)
+}
+
+type serviceCatalogRepository struct {
+	db *database.Database
+}
+
+func NewServiceCatalogRepository(db *database.Database) ServiceCatalogRepository {
+	return &serviceCatalogRepository{db: db}
+}
+
+const serviceCatalogColumns = `
+		sc.id, sc.code, sc.name, sc.description, sc.category, sc.vehicle_type_id,
+		sc.standard_hours, sc.labor_rate, sc.sla_days, sc.is_active, sc.created_at, sc.updated_at,
+		vt.name as vehicle_type_name`
+
+func (r *serviceCatalogRepository) Create(req *models.ServiceCatalogCreateRequest) (*models.ServiceCatalog, error) {
+	query := `
+		INSERT INTO service_catalog (code, name, description, category, vehicle_type_id, standard_hours, labor_rate, sla_days)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`
+
+	var id int
+	err := r.db.QueryRow(query, req.Code, req.Name, req.Description, req.Category,
+		req.VehicleTypeID, req.StandardHours, req.LaborRate, req.SLADays).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create service catalog entry: %w", err)
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *serviceCatalogRepository) GetByID(id int) (*models.ServiceCatalog, error) {
+	query := `
+		SELECT` + serviceCatalogColumns + `
+		FROM service_catalog sc
+		LEFT JOIN vehicle_types vt ON sc.vehicle_type_id = vt.id
+		WHERE sc.id = $1`
+
+	var service models.ServiceCatalog
+	err := r.db.Get(&service, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("service catalog entry not found")
+		}
+		return nil, fmt.Errorf("failed to get service catalog entry: %w", err)
+	}
+
+	return &service, nil
+}
+
+func (r *serviceCatalogRepository) GetByCode(code string) (*models.ServiceCatalog, error) {
+	query := `
+		SELECT` + serviceCatalogColumns + `
+		FROM service_catalog sc
+		LEFT JOIN vehicle_types vt ON sc.vehicle_type_id = vt.id
+		WHERE sc.code = $1`
+
+	var service models.ServiceCatalog
+	err := r.db.Get(&service, query, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("service catalog entry not found")
+		}
+		return nil, fmt.Errorf("failed to get service catalog entry: %w", err)
+	}
+
+	return &service, nil
+}
+
+func (r *serviceCatalogRepository) Update(id int, req *models.ServiceCatalogUpdateRequest) (*models.ServiceCatalog, error) {
+	// Build dynamic update query
+	setParts := []string{}
+	args := []interface{}{}
+	argCounter := 1
+
+	if req.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argCounter))
+		args = append(args, *req.Name)
+		argCounter++
+	}
+	if req.Description != nil {
+		setParts = append(setParts, fmt.Sprintf("description = $%d", argCounter))
+		args = append(args, *req.Description)
+		argCounter++
+	}
+	if req.Category != nil {
+		setParts = append(setParts, fmt.Sprintf("category = $%d", argCounter))
+		args = append(args, *req.Category)
+		argCounter++
+	}
+	if req.VehicleTypeID != nil {
+		// 0 clears the vehicle type so the entry applies to every type
+		var vehicleTypeID interface{}
+		if *req.VehicleTypeID > 0 {
+			vehicleTypeID = *req.VehicleTypeID
+		}
+		setParts = append(setParts, fmt.Sprintf("vehicle_type_id = $%d", argCounter))
+		args = append(args, vehicleTypeID)
+		argCounter++
+	}
+	if req.StandardHours != nil {
+		setParts = append(setParts, fmt.Sprintf("standard_hours = $%d", argCounter))
+		args = append(args, *req.StandardHours)
+		argCounter++
+	}
+	if req.LaborRate != nil {
+		setParts = append(setParts, fmt.Sprintf("labor_rate = $%d", argCounter))
+		args = append(args, *req.LaborRate)
+		argCounter++
+	}
+	if req.SLADays != nil {
+		// 0 clears the target
+		var slaDays interface{}
+		if *req.SLADays > 0 {
+			slaDays = *req.SLADays
+		}
+		setParts = append(setParts, fmt.Sprintf("sla_days = $%d", argCounter))
+		args = append(args, slaDays)
+		argCounter++
+	}
+	if req.IsActive != nil {
+		setParts = append(setParts, fmt.Sprintf("is_active = $%d", argCounter))
+		args = append(args, *req.IsActive)
+		argCounter++
+	}
+
+	if len(setParts) == 0 {
+		return nil, fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, "updated_at = NOW()")
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE service_catalog 
+		SET %s
+		WHERE id = $%d`,
+		strings.Join(setParts, ", "), argCounter)
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update service catalog entry: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("service catalog entry not found")
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *serviceCatalogRepository) Delete(id int) error {
+	// Entries already used on repair orders are deactivated instead of removed
+	query := `
+		UPDATE service_catalog SET is_active = false, updated_at = NOW()
+		WHERE id = $1 AND EXISTS (SELECT 1 FROM repair_labor_lines WHERE service_id = $1)`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete service catalog entry: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	result, err = r.db.Exec(`DELETE FROM service_catalog WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete service catalog entry: %w", err)
+	}
+
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("service catalog entry not found")
+	}
+
+	return nil
+}
+
+func (r *serviceCatalogRepository) List(filter models.ServiceCatalogFilter, page, limit int) ([]models.ServiceCatalog, int, error) {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter.Search != "" {
+		conditions = append(conditions, fmt.Sprintf("(sc.code ILIKE $%d OR sc.name ILIKE $%d)", argIndex, argIndex))
+		args = append(args, "%"+filter.Search+"%")
+		argIndex++
+	}
+
+	if filter.Category != "" {
+		conditions = append(conditions, fmt.Sprintf("sc.category = $%d", argIndex))
+		args = append(args, filter.Category)
+		argIndex++
+	}
+
+	if filter.VehicleTypeID > 0 {
+		// Generic entries (no vehicle type) apply to every type
+		conditions = append(conditions, fmt.Sprintf("(sc.vehicle_type_id = $%d OR sc.vehicle_type_id IS NULL)", argIndex))
+		args = append(args, filter.VehicleTypeID)
+		argIndex++
+	}
+
+	if filter.IsActive != nil {
+		conditions = append(conditions, fmt.Sprintf("sc.is_active = $%d", argIndex))
+		args = append(args, *filter.IsActive)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM service_catalog sc %s`, whereClause)
+	err := r.db.Get(&total, countQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count service catalog entries: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	query := fmt.Sprintf(`
+		SELECT`+serviceCatalogColumns+`
+		FROM service_catalog sc
+		LEFT JOIN vehicle_types vt ON sc.vehicle_type_id = vt.id
+		%s
+		ORDER BY sc.category ASC, sc.name ASC
+		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+	args = append(args, limit, offset)
+
+	services := []models.ServiceCatalog{}
+	err = r.db.Select(&services, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list service catalog entries: %w", err)
+	}
+
+	return services, total, nil
+}
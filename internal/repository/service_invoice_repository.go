@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+)
+
+type ServiceInvoiceRepository interface {
+	Create(invoice *models.ServiceInvoice) error
+	GetByID(id int) (*models.ServiceInvoice, error)
+	GetByRepairOrder(repairOrderID int) (*models.ServiceInvoice, error)
+	List(filter models.ServiceInvoiceFilter, page, limit int) ([]models.ServiceInvoice, int, error)
+	AddPayment(payment *models.ServicePayment) error
+	GetReport(dateFrom, dateTo string) (*models.WorkshopServiceReport, error)
+}
+
+type serviceInvoiceRepository struct {
+	db *database.Database
+}
+
+func NewServiceInvoiceRepository(db *database.Database) ServiceInvoiceRepository {
+	return &serviceInvoiceRepository{db: db}
+}
+
+const serviceInvoiceColumns = `
+		si.id, si.invoice_number, si.repair_order_id, si.customer_id, si.invoice_date,
+		si.parts_total, si.labor_total, si.discount, si.total_amount, si.paid_amount,
+		si.payment_status, si.notes, si.processed_by, si.created_at, si.updated_at,
+		ro.code as repair_code, c.name as customer_name, cv.license_plate`
+
+const serviceInvoiceJoins = `
+		FROM service_invoices si
+		JOIN repair_orders ro ON si.repair_order_id = ro.id
+		JOIN customers c ON si.customer_id = c.id
+		JOIN customer_vehicles cv ON ro.customer_vehicle_id = cv.id`
+
+func (r *serviceInvoiceRepository) Create(invoice *models.ServiceInvoice) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO service_invoices (invoice_number, repair_order_id, customer_id, invoice_date, parts_total, labor_total,
+			discount, total_amount, paid_amount, payment_status, notes, processed_by)
+		VALUES ($1, $2, $3, CURRENT_DATE, $4, $5, $6, $7, 0, 'pending', $8, $9)
+		RETURNING id, invoice_date, created_at, updated_at`,
+		invoice.InvoiceNumber, invoice.RepairOrderID, invoice.CustomerID, invoice.PartsTotal, invoice.LaborTotal,
+		invoice.Discount, invoice.TotalAmount, invoice.Notes, invoice.ProcessedBy,
+	).Scan(&invoice.ID, &invoice.InvoiceDate, &invoice.CreatedAt, &invoice.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create service invoice: %w", err)
+	}
+
+	for i := range invoice.Items {
+		item := &invoice.Items[i]
+		item.InvoiceID = invoice.ID
+		err = tx.QueryRow(`
+			INSERT INTO service_invoice_items (invoice_id, item_type, description, quantity, unit_price, total_price)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id`,
+			item.InvoiceID, item.ItemType, item.Description, item.Quantity, item.UnitPrice, item.TotalPrice,
+		).Scan(&item.ID)
+		if err != nil {
+			return fmt.Errorf("failed to create service invoice item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	invoice.PaymentStatus = models.PaymentStatusPending
+	return nil
+}
+
+func (r *serviceInvoiceRepository) GetByID(id int) (*models.ServiceInvoice, error) {
+	return r.getOne("si.id = $1", id)
+}
+
+func (r *serviceInvoiceRepository) GetByRepairOrder(repairOrderID int) (*models.ServiceInvoice, error) {
+	return r.getOne("si.repair_order_id = $1", repairOrderID)
+}
+
+func (r *serviceInvoiceRepository) getOne(condition string, arg interface{}) (*models.ServiceInvoice, error) {
+	query := `SELECT` + serviceInvoiceColumns + serviceInvoiceJoins + `
+		WHERE ` + condition
+
+	var invoice models.ServiceInvoice
+	err := r.db.Get(&invoice, query, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("service invoice not found")
+		}
+		return nil, fmt.Errorf("failed to get service invoice: %w", err)
+	}
+
+	invoice.Items = []models.ServiceInvoiceItem{}
+	err = r.db.Select(&invoice.Items, `
+		SELECT id, invoice_id, item_type, description, quantity, unit_price, total_price
+		FROM service_invoice_items
+		WHERE invoice_id = $1
+		ORDER BY id`, invoice.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service invoice items: %w", err)
+	}
+
+	invoice.Payments = []models.ServicePayment{}
+	err = r.db.Select(&invoice.Payments, `
+		SELECT id, invoice_id, amount, payment_method, notes, received_by, paid_at
+		FROM service_payments
+		WHERE invoice_id = $1
+		ORDER BY paid_at`, invoice.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service payments: %w", err)
+	}
+
+	return &invoice, nil
+}
+
+func (r *serviceInvoiceRepository) List(filter models.ServiceInvoiceFilter, page, limit int) ([]models.ServiceInvoice, int, error) {
+	var conditions []string
+	var args []interface{}
+	argIndex := 1
+
+	if filter.PaymentStatus != "" {
+		conditions = append(conditions, fmt.Sprintf("si.payment_status = $%d", argIndex))
+		args = append(args, filter.PaymentStatus)
+		argIndex++
+	}
+
+	if filter.CustomerID > 0 {
+		conditions = append(conditions, fmt.Sprintf("si.customer_id = $%d", argIndex))
+		args = append(args, filter.CustomerID)
+		argIndex++
+	}
+
+	if filter.DateFrom != "" {
+		conditions = append(conditions, fmt.Sprintf("si.invoice_date >= $%d", argIndex))
+		args = append(args, filter.DateFrom)
+		argIndex++
+	}
+
+	if filter.DateTo != "" {
+		conditions = append(conditions, fmt.Sprintf("si.invoice_date <= $%d", argIndex))
+		args = append(args, filter.DateTo)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := r.db.Get(&total, fmt.Sprintf(`SELECT COUNT(*) FROM service_invoices si %s`, whereClause), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count service invoices: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	query := fmt.Sprintf(`SELECT`+serviceInvoiceColumns+serviceInvoiceJoins+`
+		%s
+		ORDER BY si.invoice_date DESC, si.id DESC
+		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+
+	args = append(args, limit, offset)
+
+	invoices := []models.ServiceInvoice{}
+	if err := r.db.Select(&invoices, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list service invoices: %w", err)
+	}
+
+	return invoices, total, nil
+}
+
+func (r *serviceInvoiceRepository) AddPayment(payment *models.ServicePayment) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the invoice so concurrent payments cannot overpay it
+	var totalAmount, paidAmount float64
+	err = tx.QueryRow(`
+		SELECT total_amount, paid_amount FROM service_invoices
+		WHERE id = $1
+		FOR UPDATE`, payment.InvoiceID).Scan(&totalAmount, &paidAmount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("service invoice not found")
+		}
+		return fmt.Errorf("failed to get service invoice: %w", err)
+	}
+
+	if payment.Amount > totalAmount-paidAmount {
+		return fmt.Errorf("payment exceeds outstanding amount of %.2f", totalAmount-paidAmount)
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO service_payments (invoice_id, amount, payment_method, notes, received_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, paid_at`,
+		payment.InvoiceID, payment.Amount, payment.PaymentMethod, payment.Notes, payment.ReceivedBy,
+	).Scan(&payment.ID, &payment.PaidAt)
+	if err != nil {
+		return fmt.Errorf("failed to create service payment: %w", err)
+	}
+
+	paidAmount += payment.Amount
+	status := models.PaymentStatusPartial
+	if paidAmount >= totalAmount {
+		status = models.PaymentStatusPaid
+	}
+
+	_, err = tx.Exec(`
+		UPDATE service_invoices
+		SET paid_amount = $1, payment_status = $2, updated_at = NOW()
+		WHERE id = $3`, paidAmount, status, payment.InvoiceID)
+	if err != nil {
+		return fmt.Errorf("failed to update service invoice: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *serviceInvoiceRepository) GetReport(dateFrom, dateTo string) (*models.WorkshopServiceReport, error) {
+	report := &models.WorkshopServiceReport{DateFrom: dateFrom, DateTo: dateTo}
+
+	err := r.db.QueryRow(`
+		SELECT
+			COUNT(*) FILTER (WHERE order_type = 'customer_service' AND NOT is_warranty),
+			COUNT(*) FILTER (WHERE order_type = 'customer_service' AND NOT is_warranty AND status = 'completed'),
+			COUNT(*) FILTER (WHERE order_type = 'reconditioning'),
+			COALESCE(SUM(actual_cost) FILTER (WHERE order_type = 'reconditioning' AND status = 'completed'), 0),
+			COUNT(*) FILTER (WHERE is_warranty),
+			COALESCE(SUM(actual_cost) FILTER (WHERE is_warranty AND status = 'completed'), 0)
+		FROM repair_orders
+		WHERE DATE(created_at) BETWEEN $1 AND $2`, dateFrom, dateTo,
+	).Scan(&report.ServiceOrders, &report.CompletedOrders, &report.ReconditioningJobs, &report.ReconditioningCost,
+		&report.WarrantyJobs, &report.WarrantyCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service order summary: %w", err)
+	}
+
+	err = r.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(parts_total), 0), COALESCE(SUM(labor_total), 0),
+			COALESCE(SUM(discount), 0), COALESCE(SUM(total_amount), 0), COALESCE(SUM(paid_amount), 0)
+		FROM service_invoices
+		WHERE invoice_date BETWEEN $1 AND $2`, dateFrom, dateTo,
+	).Scan(&report.InvoiceCount, &report.PartsRevenue, &report.LaborRevenue,
+		&report.Discounts, &report.TotalInvoiced, &report.TotalPaid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service invoice summary: %w", err)
+	}
+
+	report.Outstanding = report.TotalInvoiced - report.TotalPaid
+
+	return report, nil
+}
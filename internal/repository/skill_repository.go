@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+)
+
+type SkillRepository interface {
+	Create(req *models.SkillCreateRequest) (*models.Skill, error)
+	GetByID(id int) (*models.Skill, error)
+	Delete(id int) error
+	List() ([]models.Skill, error)
+
+	// User skills
+	AssignToUser(userID int, skillID int, certificationNumber *string, certifiedAt, expiresAt *time.Time) (*models.UserSkill, error)
+	RemoveFromUser(userID int, skillID int) error
+	GetUserSkills(userID int) ([]models.UserSkill, error)
+}
+
+type skillRepository struct {
+	db *database.Database
+}
+
+func NewSkillRepository(db *database.Database) SkillRepository {
+	return &skillRepository{db: db}
+}
+
+func (r *skillRepository) Create(req *models.SkillCreateRequest) (*models.Skill, error) {
+	query := `
+		INSERT INTO skills (name, description, vehicle_type_id, service_category)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+
+	var id int
+	err := r.db.QueryRow(query, req.Name, req.Description, req.VehicleTypeID, req.ServiceCategory).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create skill: %w", err)
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *skillRepository) GetByID(id int) (*models.Skill, error) {
+	query := `
+		SELECT s.id, s.name, s.description, s.vehicle_type_id, s.service_category, s.created_at,
+			   vt.name as vehicle_type_name
+		FROM skills s
+		LEFT JOIN vehicle_types vt ON s.vehicle_type_id = vt.id
+		WHERE s.id = $1`
+
+	var skill models.Skill
+	err := r.db.Get(&skill, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("skill not found")
+		}
+		return nil, fmt.Errorf("failed to get skill: %w", err)
+	}
+
+	return &skill, nil
+}
+
+func (r *skillRepository) Delete(id int) error {
+	query := `DELETE FROM skills WHERE id = $1`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete skill: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("skill not found")
+	}
+
+	return nil
+}
+
+func (r *skillRepository) List() ([]models.Skill, error) {
+	query := `
+		SELECT s.id, s.name, s.description, s.vehicle_type_id, s.service_category, s.created_at,
+			   vt.name as vehicle_type_name
+		FROM skills s
+		LEFT JOIN vehicle_types vt ON s.vehicle_type_id = vt.id
+		ORDER BY s.name ASC`
+
+	skills := []models.Skill{}
+	err := r.db.Select(&skills, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list skills: %w", err)
+	}
+
+	return skills, nil
+}
+
+func (r *skillRepository) AssignToUser(userID int, skillID int, certificationNumber *string, certifiedAt, expiresAt *time.Time) (*models.UserSkill, error) {
+	// Re-assigning a skill updates its certification details
+	query := `
+		INSERT INTO user_skills (user_id, skill_id, certification_number, certified_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, skill_id)
+		DO UPDATE SET certification_number = EXCLUDED.certification_number,
+			certified_at = EXCLUDED.certified_at,
+			expires_at = EXCLUDED.expires_at`
+
+	_, err := r.db.Exec(query, userID, skillID, certificationNumber, certifiedAt, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign skill: %w", err)
+	}
+
+	skills, err := r.GetUserSkills(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, skill := range skills {
+		if skill.SkillID == skillID {
+			return &skill, nil
+		}
+	}
+
+	return nil, fmt.Errorf("skill not found")
+}
+
+func (r *skillRepository) RemoveFromUser(userID int, skillID int) error {
+	query := `DELETE FROM user_skills WHERE user_id = $1 AND skill_id = $2`
+
+	result, err := r.db.Exec(query, userID, skillID)
+	if err != nil {
+		return fmt.Errorf("failed to remove skill: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user skill not found")
+	}
+
+	return nil
+}
+
+func (r *skillRepository) GetUserSkills(userID int) ([]models.UserSkill, error) {
+	query := `
+		SELECT us.id, us.user_id, us.skill_id, us.certification_number, us.certified_at,
+			   us.expires_at, us.created_at,
+			   s.name as skill_name, s.vehicle_type_id, s.service_category
+		FROM user_skills us
+		JOIN skills s ON us.skill_id = s.id
+		WHERE us.user_id = $1
+		ORDER BY s.name ASC`
+
+	skills := []models.UserSkill{}
+	err := r.db.Select(&skills, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user skills: %w", err)
+	}
+
+	return skills, nil
+}
@@ -22,8 +22,12 @@ type SparePartRepository interface {
 	CheckStockAvailability(id int, requestedQuantity int) (bool, error)
 	BulkUpdateStock(updates []models.SparePartStockUpdate) error
 	GetCategories() ([]string, error)
+	GetMovements(sparePartID int, page, limit int) ([]models.SparePartMovement, int64, error)
 }
 
+// reservedQuantitySQL sums the active repair reservations held against the spare part aliased as sp
+const reservedQuantitySQL = `COALESCE((SELECT SUM(spr.quantity - spr.consumed_quantity) FROM spare_part_reservations spr WHERE spr.spare_part_id = sp.id AND spr.status = 'active'), 0)`
+
 type sparePartRepository struct {
 	db *database.Database
 }
@@ -44,14 +48,19 @@ func (r *sparePartRepository) Create(req *models.SparePartCreateRequest) (*model
 		return nil, fmt.Errorf("failed to create spare part: %w", err)
 	}
 
+	// A new spare part has no reservations yet
+	sparePart.AvailableQuantity = sparePart.StockQuantity
+
 	return &sparePart, nil
 }
 
 func (r *sparePartRepository) GetByID(id int) (*models.SparePart, error) {
 	query := `
-		SELECT id, code, name, description, category, unit, purchase_price, selling_price, stock_quantity, minimum_stock, is_active, created_at, updated_at
-		FROM spare_parts 
-		WHERE id = $1`
+		SELECT sp.id, sp.code, sp.name, sp.description, sp.category, sp.unit, sp.purchase_price, sp.selling_price, sp.stock_quantity, sp.minimum_stock, sp.is_active, sp.created_at, sp.updated_at,
+		       ` + reservedQuantitySQL + ` AS reserved_quantity,
+		       sp.stock_quantity - ` + reservedQuantitySQL + ` AS available_quantity
+		FROM spare_parts sp
+		WHERE sp.id = $1`
 
 	var sparePart models.SparePart
 	err := r.db.Get(&sparePart, query, id)
@@ -67,9 +76,11 @@ func (r *sparePartRepository) GetByID(id int) (*models.SparePart, error) {
 
 func (r *sparePartRepository) GetByCode(code string) (*models.SparePart, error) {
 	query := `
-		SELECT id, code, name, description, category, unit, purchase_price, selling_price, stock_quantity, minimum_stock, is_active, created_at, updated_at
-		FROM spare_parts 
-		WHERE code = $1`
+		SELECT sp.id, sp.code, sp.name, sp.description, sp.category, sp.unit, sp.purchase_price, sp.selling_price, sp.stock_quantity, sp.minimum_stock, sp.is_active, sp.created_at, sp.updated_at,
+		       ` + reservedQuantitySQL + ` AS reserved_quantity,
+		       sp.stock_quantity - ` + reservedQuantitySQL + ` AS available_quantity
+		FROM spare_parts sp
+		WHERE sp.code = $1`
 
 	var sparePart models.SparePart
 	err := r.db.Get(&sparePart, query, code)
@@ -151,7 +162,8 @@ func (r *sparePartRepository) Update(id int, req *models.SparePartUpdateRequest)
 		return nil, fmt.Errorf("failed to update spare part: %w", err)
 	}
 
-	return &sparePart, nil
+	// Reload to include reserved and available quantities
+	return r.GetByID(sparePart.ID)
 }
 
 func (r *sparePartRepository) Delete(id int) error {
@@ -202,10 +214,12 @@ func (r *sparePartRepository) List(page, limit int, isActive *bool) ([]models.Sp
 
 	// Get spare parts with pagination
 	query := fmt.Sprintf(`
-		SELECT id, code, name, description, category, unit, purchase_price, selling_price, stock_quantity, minimum_stock, is_active, created_at, updated_at
-		FROM spare_parts
+		SELECT sp.id, sp.code, sp.name, sp.description, sp.category, sp.unit, sp.purchase_price, sp.selling_price, sp.stock_quantity, sp.minimum_stock, sp.is_active, sp.created_at, sp.updated_at,
+		       `+reservedQuantitySQL+` AS reserved_quantity,
+		       sp.stock_quantity - `+reservedQuantitySQL+` AS available_quantity
+		FROM spare_parts sp
 		%s
-		ORDER BY created_at DESC
+		ORDER BY sp.created_at DESC
 		LIMIT $1 OFFSET $2`, whereClause)
 
 	var spareParts []models.SparePart
@@ -252,7 +266,8 @@ func (r *sparePartRepository) GetLowStockItems(page, limit int) ([]models.SpareP
 
 	// Get total count
 	var total int64
-	countQuery := `SELECT COUNT(*) FROM spare_parts WHERE stock_quantity <= minimum_stock AND is_active = true`
+	// Reserved stock is not available, so it counts towards the low stock threshold
+	countQuery := `SELECT COUNT(*) FROM spare_parts sp WHERE sp.stock_quantity - ` + reservedQuantitySQL + ` <= sp.minimum_stock AND sp.is_active = true`
 	err := r.db.Get(&total, countQuery)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count low stock items: %w", err)
@@ -260,10 +275,12 @@ func (r *sparePartRepository) GetLowStockItems(page, limit int) ([]models.SpareP
 
 	// Get low stock items with pagination
 	query := `
-		SELECT id, code, name, description, category, unit, purchase_price, selling_price, stock_quantity, minimum_stock, is_active, created_at, updated_at
-		FROM spare_parts
-		WHERE stock_quantity <= minimum_stock AND is_active = true
-		ORDER BY (stock_quantity - minimum_stock) ASC, created_at DESC
+		SELECT sp.id, sp.code, sp.name, sp.description, sp.category, sp.unit, sp.purchase_price, sp.selling_price, sp.stock_quantity, sp.minimum_stock, sp.is_active, sp.created_at, sp.updated_at,
+		       ` + reservedQuantitySQL + ` AS reserved_quantity,
+		       sp.stock_quantity - ` + reservedQuantitySQL + ` AS available_quantity
+		FROM spare_parts sp
+		WHERE sp.stock_quantity - ` + reservedQuantitySQL + ` <= sp.minimum_stock AND sp.is_active = true
+		ORDER BY (sp.stock_quantity - ` + reservedQuantitySQL + ` - sp.minimum_stock) ASC, sp.created_at DESC
 		LIMIT $1 OFFSET $2`
 
 	var spareParts []models.SparePart
@@ -275,8 +292,37 @@ func (r *sparePartRepository) GetLowStockItems(page, limit int) ([]models.SpareP
 	return spareParts, total, nil
 }
 
+func (r *sparePartRepository) GetMovements(sparePartID int, page, limit int) ([]models.SparePartMovement, int64, error) {
+	offset := (page - 1) * limit
+
+	var total int64
+	err := r.db.Get(&total, `SELECT COUNT(*) FROM spare_part_movements WHERE spare_part_id = $1`, sparePartID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count stock movements: %w", err)
+	}
+
+	query := `
+		SELECT m.id, m.spare_part_id, m.movement_type, m.quantity, m.units, m.repair_order_id,
+		       ro.code AS repair_code, m.notes, m.created_by, u.full_name AS created_by_name, m.created_at
+		FROM spare_part_movements m
+		LEFT JOIN repair_orders ro ON m.repair_order_id = ro.id
+		LEFT JOIN users u ON m.created_by = u.id
+		WHERE m.spare_part_id = $1
+		ORDER BY m.created_at DESC, m.id DESC
+		LIMIT $2 OFFSET $3`
+
+	movements := []models.SparePartMovement{}
+	err = r.db.Select(&movements, query, sparePartID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get stock movements: %w", err)
+	}
+
+	return movements, total, nil
+}
+
 func (r *sparePartRepository) CheckStockAvailability(id int, requestedQuantity int) (bool, error) {
-	query := `SELECT stock_quantity FROM spare_parts WHERE id = $1 AND is_active = true`
+	// Available-to-promise stock: physical stock minus what open repairs have reserved
+	query := `SELECT sp.stock_quantity - ` + reservedQuantitySQL + ` FROM spare_parts sp WHERE sp.id = $1 AND sp.is_active = true`
 
 	var currentStock int
 	err := r.db.Get(&currentStock, query, id)
@@ -394,10 +440,12 @@ func (r *sparePartRepository) ListWithFilters(page, limit int, search, category
 
 	// Get spare parts with filtering and pagination
 	query := fmt.Sprintf(`
-		SELECT id, code, name, description, category, unit, purchase_price, selling_price, stock_quantity, minimum_stock, is_active, created_at, updated_at
-		FROM spare_parts
+		SELECT sp.id, sp.code, sp.name, sp.description, sp.category, sp.unit, sp.purchase_price, sp.selling_price, sp.stock_quantity, sp.minimum_stock, sp.is_active, sp.created_at, sp.updated_at,
+		       `+reservedQuantitySQL+` AS reserved_quantity,
+		       sp.stock_quantity - `+reservedQuantitySQL+` AS available_quantity
+		FROM spare_parts sp
 		%s
-		ORDER BY created_at DESC
+		ORDER BY sp.created_at DESC
 		LIMIT $%d OFFSET $%d`, whereClause, paramCount, paramCount+1)
 
 	var spareParts []models.SparePart
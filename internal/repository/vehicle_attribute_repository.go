@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+)
+
+type VehicleAttributeRepository interface {
+	CreateDefinition(definition *models.VehicleAttributeDefinition) error
+	GetDefinition(id int) (*models.VehicleAttributeDefinition, error)
+	ListDefinitions(typeID int, activeOnly bool) ([]models.VehicleAttributeDefinition, error)
+	ListDefinitionsForBrand(brandID int) ([]models.VehicleAttributeDefinition, error)
+	FindDefinitionByCode(typeID int, code string) (*models.VehicleAttributeDefinition, error)
+	UpdateDefinition(id int, req *models.VehicleAttributeDefinitionUpdateRequest) error
+	DeleteDefinition(id int) error
+	GetValues(vehicleID int) ([]models.VehicleAttributeValue, error)
+	GetValuesByVehicles(vehicleIDs []int) (map[int][]models.VehicleAttributeValue, error)
+	SetValues(vehicleID int, values map[int]*string) error
+}
+
+type vehicleAttributeRepository struct {
+	db *database.Database
+}
+
+func NewVehicleAttributeRepository(db *database.Database) VehicleAttributeRepository {
+	return &vehicleAttributeRepository{db: db}
+}
+
+// attributeDefinitionRow reads the options array, which the model keeps as a plain slice
+type attributeDefinitionRow struct {
+	models.VehicleAttributeDefinition
+	OptionList pq.StringArray `db:"options"`
+}
+
+func (row attributeDefinitionRow) definition() models.VehicleAttributeDefinition {
+	definition := row.VehicleAttributeDefinition
+	definition.Options = []string(row.OptionList)
+	if definition.Options == nil {
+		definition.Options = []string{}
+	}
+	return definition
+}
+
+const attributeDefinitionSelect = `
+	SELECT id, type_id, code, name, data_type, options, is_required, sort_order, is_active,
+		   created_at, updated_at
+	FROM vehicle_attribute_definitions`
+
+const attributeValueSelect = `
+	SELECT vav.vehicle_id, vav.definition_id, vad.code, vad.name, vad.data_type, vav.value
+	FROM vehicle_attribute_values vav
+	JOIN vehicle_attribute_definitions vad ON vad.id = vav.definition_id`
+
+func (r *vehicleAttributeRepository) CreateDefinition(definition *models.VehicleAttributeDefinition) error {
+	query := `
+		INSERT INTO vehicle_attribute_definitions (type_id, code, name, data_type, options, is_required, sort_order)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, is_active, created_at, updated_at`
+
+	err := r.db.QueryRow(query, definition.TypeID, definition.Code, definition.Name, definition.DataType,
+		pq.Array(definition.Options), definition.IsRequired, definition.SortOrder).
+		Scan(&definition.ID, &definition.IsActive, &definition.CreatedAt, &definition.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create vehicle attribute: %w", err)
+	}
+
+	return nil
+}
+
+func (r *vehicleAttributeRepository) GetDefinition(id int) (*models.VehicleAttributeDefinition, error) {
+	var row attributeDefinitionRow
+	err := r.db.Get(&row, attributeDefinitionSelect+` WHERE id = $1`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("vehicle attribute not found")
+		}
+		return nil, fmt.Errorf("failed to get vehicle attribute: %w", err)
+	}
+
+	definition := row.definition()
+	return &definition, nil
+}
+
+func (r *vehicleAttributeRepository) ListDefinitions(typeID int, activeOnly bool) ([]models.VehicleAttributeDefinition, error) {
+	query := attributeDefinitionSelect + ` WHERE type_id = $1`
+	if activeOnly {
+		query += ` AND is_active = TRUE`
+	}
+	query += ` ORDER BY sort_order, name`
+
+	return r.selectDefinitions(query, typeID)
+}
+
+// ListDefinitionsForBrand returns the active attributes of the vehicle type the brand belongs to
+func (r *vehicleAttributeRepository) ListDefinitionsForBrand(brandID int) ([]models.VehicleAttributeDefinition, error) {
+	query := attributeDefinitionSelect + `
+		WHERE type_id = (SELECT type_id FROM vehicle_brands WHERE id = $1)
+		  AND is_active = TRUE
+		ORDER BY sort_order, name`
+
+	return r.selectDefinitions(query, brandID)
+}
+
+func (r *vehicleAttributeRepository) selectDefinitions(query string, args ...interface{}) ([]models.VehicleAttributeDefinition, error) {
+	var rows []attributeDefinitionRow
+	if err := r.db.Select(&rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list vehicle attributes: %w", err)
+	}
+
+	definitions := make([]models.VehicleAttributeDefinition, len(rows))
+	for i, row := range rows {
+		definitions[i] = row.definition()
+	}
+
+	return definitions, nil
+}
+
+func (r *vehicleAttributeRepository) FindDefinitionByCode(typeID int, code string) (*models.VehicleAttributeDefinition, error) {
+	var row attributeDefinitionRow
+	err := r.db.Get(&row, attributeDefinitionSelect+` WHERE type_id = $1 AND code = $2`, typeID, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("vehicle attribute not found")
+		}
+		return nil, fmt.Errorf("failed to find vehicle attribute: %w", err)
+	}
+
+	definition := row.definition()
+	return &definition, nil
+}
+
+func (r *vehicleAttributeRepository) UpdateDefinition(id int, req *models.VehicleAttributeDefinitionUpdateRequest) error {
+	setParts := []string{"updated_at = CURRENT_TIMESTAMP"}
+	args := []interface{}{}
+	argCounter := 1
+
+	if req.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argCounter))
+		args = append(args, *req.Name)
+		argCounter++
+	}
+	if req.Options != nil {
+		setParts = append(setParts, fmt.Sprintf("options = $%d", argCounter))
+		args = append(args, pq.Array(req.Options))
+		argCounter++
+	}
+	if req.IsRequired != nil {
+		setParts = append(setParts, fmt.Sprintf("is_required = $%d", argCounter))
+		args = append(args, *req.IsRequired)
+		argCounter++
+	}
+	if req.SortOrder != nil {
+		setParts = append(setParts, fmt.Sprintf("sort_order = $%d", argCounter))
+		args = append(args, *req.SortOrder)
+		argCounter++
+	}
+	if req.IsActive != nil {
+		setParts = append(setParts, fmt.Sprintf("is_active = $%d", argCounter))
+		args = append(args, *req.IsActive)
+		argCounter++
+	}
+
+	args = append(args, id)
+	query := fmt.Sprintf(`UPDATE vehicle_attribute_definitions SET %s WHERE id = $%d`,
+		strings.Join(setParts, ", "), argCounter)
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update vehicle attribute: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("vehicle attribute not found")
+	}
+
+	return nil
+}
+
+// DeleteDefinition removes an attribute. Attributes with values on vehicles can only be deactivated.
+func (r *vehicleAttributeRepository) DeleteDefinition(id int) error {
+	var inUse bool
+	err := r.db.Get(&inUse, `SELECT EXISTS (SELECT 1 FROM vehicle_attribute_values WHERE definition_id = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("failed to check vehicle attribute usage: %w", err)
+	}
+	if inUse {
+		return fmt.Errorf("cannot delete a vehicle attribute with values on vehicles, deactivate it instead")
+	}
+
+	result, err := r.db.Exec(`DELETE FROM vehicle_attribute_definitions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete vehicle attribute: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("vehicle attribute not found")
+	}
+
+	return nil
+}
+
+func (r *vehicleAttributeRepository) GetValues(vehicleID int) ([]models.VehicleAttributeValue, error) {
+	values := []models.VehicleAttributeValue{}
+	err := r.db.Select(&values, attributeValueSelect+`
+		WHERE vav.vehicle_id = $1
+		ORDER BY vad.sort_order, vad.name`, vehicleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vehicle attributes: %w", err)
+	}
+
+	return values, nil
+}
+
+// GetValuesByVehicles loads the attribute values of several vehicles at once
+func (r *vehicleAttributeRepository) GetValuesByVehicles(vehicleIDs []int) (map[int][]models.VehicleAttributeValue, error) {
+	byVehicle := make(map[int][]models.VehicleAttributeValue)
+	if len(vehicleIDs) == 0 {
+		return byVehicle, nil
+	}
+
+	var values []models.VehicleAttributeValue
+	err := r.db.Select(&values, attributeValueSelect+`
+		WHERE vav.vehicle_id = ANY($1)
+		ORDER BY vav.vehicle_id, vad.sort_order, vad.name`, pq.Array(vehicleIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vehicle attributes: %w", err)
+	}
+
+	for _, value := range values {
+		byVehicle[value.VehicleID] = append(byVehicle[value.VehicleID], value)
+	}
+
+	return byVehicle, nil
+}
+
+// SetValues stores attribute values of a vehicle by definition ID; a nil value removes it
+func (r *vehicleAttributeRepository) SetValues(vehicleID int, values map[int]*string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := saveAttributeValues(tx, vehicleID, values); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// saveAttributeValues writes the values of SetValues through e, so other writes can share its transaction
+func saveAttributeValues(e sqlx.Execer, vehicleID int, values map[int]*string) error {
+	var err error
+	for definitionID, value := range values {
+		if value == nil {
+			_, err = e.Exec(`DELETE FROM vehicle_attribute_values WHERE vehicle_id = $1 AND definition_id = $2`,
+				vehicleID, definitionID)
+		} else {
+			_, err = e.Exec(`
+				INSERT INTO vehicle_attribute_values (vehicle_id, definition_id, value)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (vehicle_id, definition_id)
+				DO UPDATE SET value = EXCLUDED.value, updated_at = CURRENT_TIMESTAMP`,
+				vehicleID, definitionID, *value)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save vehicle attribute: %w", err)
+		}
+	}
+
+	return nil
+}
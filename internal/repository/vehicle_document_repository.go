@@ -0,0 +1,416 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type VehicleDocumentRepository interface {
+	Create(document *models.VehicleDocument) error
+	GetByID(id int) (*models.VehicleDocument, error)
+	ListByVehicle(vehicleID int) ([]models.VehicleDocument, error)
+	Update(id int, req *models.VehicleDocumentUpdateRequest) error
+	Delete(id int) error
+	AddAttachment(attachment *models.VehicleDocumentAttachment) error
+	GetAttachment(documentID int, attachmentID int) (*models.VehicleDocumentAttachment, error)
+	GetAttachments(documentIDs []int) (map[int][]models.VehicleDocumentAttachment, error)
+	DeleteAttachment(documentID int, attachmentID int) error
+	CreateHandover(handover *models.DocumentHandover, vehicleID int, documentIDs []int) error
+	GetHandovers(salesTransactionID int) ([]models.DocumentHandover, error)
+	ListPendingHandovers(documentType string, page, limit int) ([]models.PendingDocumentHandover, int, error)
+}
+
+type vehicleDocumentRepository struct {
+	db *database.Database
+}
+
+func NewVehicleDocumentRepository(db *database.Database) VehicleDocumentRepository {
+	return &vehicleDocumentRepository{db: db}
+}
+
+const vehicleDocumentColumns = `
+	SELECT id, vehicle_id, document_type, document_number, expiry_date, storage_location,
+		   custody_status, handover_id, notes, created_by, created_at, updated_at
+	FROM vehicle_documents`
+
+const vehicleDocumentAttachmentColumns = `
+	SELECT id, document_id, file_path, thumbnail_path, caption,
+		   file_name, content_type, file_size, uploaded_by, created_at
+	FROM vehicle_document_attachments`
+
+func (r *vehicleDocumentRepository) Create(document *models.VehicleDocument) error {
+	query := `
+		INSERT INTO vehicle_documents (vehicle_id, document_type, document_number, expiry_date,
+									   storage_location, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, custody_status, created_at, updated_at`
+
+	err := r.db.QueryRow(query, document.VehicleID, document.DocumentType, document.DocumentNumber,
+		document.ExpiryDate, document.StorageLocation, document.Notes, document.CreatedBy).
+		Scan(&document.ID, &document.CustodyStatus, &document.CreatedAt, &document.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create vehicle document: %w", err)
+	}
+
+	return nil
+}
+
+func (r *vehicleDocumentRepository) GetByID(id int) (*models.VehicleDocument, error) {
+	var document models.VehicleDocument
+	if err := r.db.Get(&document, vehicleDocumentColumns+` WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("vehicle document not found")
+		}
+		return nil, fmt.Errorf("failed to get vehicle document: %w", err)
+	}
+
+	attachments, err := r.GetAttachments([]int{document.ID})
+	if err != nil {
+		return nil, err
+	}
+	document.Attachments = attachments[document.ID]
+
+	return &document, nil
+}
+
+func (r *vehicleDocumentRepository) ListByVehicle(vehicleID int) ([]models.VehicleDocument, error) {
+	documents := []models.VehicleDocument{}
+	err := r.db.Select(&documents, vehicleDocumentColumns+` WHERE vehicle_id = $1 ORDER BY document_type, id`, vehicleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vehicle documents: %w", err)
+	}
+
+	if err := r.attachAttachments(documents); err != nil {
+		return nil, err
+	}
+
+	return documents, nil
+}
+
+func (r *vehicleDocumentRepository) Update(id int, req *models.VehicleDocumentUpdateRequest) error {
+	setParts := []string{}
+	args := []interface{}{}
+	argCounter := 1
+
+	if req.DocumentNumber != nil {
+		setParts = append(setParts, fmt.Sprintf("document_number = $%d", argCounter))
+		args = append(args, *req.DocumentNumber)
+		argCounter++
+	}
+
+	if req.ExpiryDate != nil {
+		// An empty date clears the expiry
+		var expiryDate interface{}
+		if *req.ExpiryDate != "" {
+			expiryDate = *req.ExpiryDate
+		}
+		setParts = append(setParts, fmt.Sprintf("expiry_date = $%d", argCounter))
+		args = append(args, expiryDate)
+		argCounter++
+	}
+
+	if req.StorageLocation != nil {
+		setParts = append(setParts, fmt.Sprintf("storage_location = $%d", argCounter))
+		args = append(args, *req.StorageLocation)
+		argCounter++
+	}
+
+	if req.CustodyStatus != nil {
+		setParts = append(setParts, fmt.Sprintf("custody_status = $%d", argCounter))
+		args = append(args, *req.CustodyStatus)
+		argCounter++
+	}
+
+	if req.Notes != nil {
+		setParts = append(setParts, fmt.Sprintf("notes = $%d", argCounter))
+		args = append(args, *req.Notes)
+		argCounter++
+	}
+
+	if len(setParts) == 0 {
+		return nil
+	}
+
+	setParts = append(setParts, "updated_at = NOW()")
+	query := fmt.Sprintf("UPDATE vehicle_documents SET %s WHERE id = $%d", strings.Join(setParts, ", "), argCounter)
+	args = append(args, id)
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update vehicle document: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("vehicle document not found")
+	}
+
+	return nil
+}
+
+func (r *vehicleDocumentRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM vehicle_documents WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete vehicle document: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("vehicle document not found")
+	}
+
+	return nil
+}
+
+func (r *vehicleDocumentRepository) AddAttachment(attachment *models.VehicleDocumentAttachment) error {
+	query := `
+		INSERT INTO vehicle_document_attachments (document_id, file_path, thumbnail_path, caption,
+												  file_name, content_type, file_size, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`
+
+	return r.db.QueryRow(query, attachment.DocumentID, attachment.FilePath, attachment.ThumbnailPath,
+		attachment.Caption, attachment.FileName, attachment.ContentType, attachment.FileSize, attachment.UploadedBy).
+		Scan(&attachment.ID, &attachment.CreatedAt)
+}
+
+func (r *vehicleDocumentRepository) GetAttachment(documentID int, attachmentID int) (*models.VehicleDocumentAttachment, error) {
+	var attachment models.VehicleDocumentAttachment
+	err := r.db.Get(&attachment, vehicleDocumentAttachmentColumns+` WHERE id = $1 AND document_id = $2`, attachmentID, documentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("attachment not found")
+		}
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// GetAttachments returns the scans of the given documents keyed by document ID
+func (r *vehicleDocumentRepository) GetAttachments(documentIDs []int) (map[int][]models.VehicleDocumentAttachment, error) {
+	attachmentsByDocument := make(map[int][]models.VehicleDocumentAttachment)
+	if len(documentIDs) == 0 {
+		return attachmentsByDocument, nil
+	}
+
+	var attachments []models.VehicleDocumentAttachment
+	err := r.db.Select(&attachments, vehicleDocumentAttachmentColumns+` WHERE document_id = ANY($1) ORDER BY document_id, created_at, id`,
+		pq.Array(documentIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document attachments: %w", err)
+	}
+
+	for _, attachment := range attachments {
+		attachmentsByDocument[attachment.DocumentID] = append(attachmentsByDocument[attachment.DocumentID], attachment)
+	}
+
+	return attachmentsByDocument, nil
+}
+
+func (r *vehicleDocumentRepository) DeleteAttachment(documentID int, attachmentID int) error {
+	result, err := r.db.Exec(`DELETE FROM vehicle_document_attachments WHERE id = $1 AND document_id = $2`, attachmentID, documentID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("attachment not found")
+	}
+
+	return nil
+}
+
+// CreateHandover records the handover and marks the documents as handed over. Every document
+// must belong to vehicleID and still be held by the showroom.
+func (r *vehicleDocumentRepository) CreateHandover(handover *models.DocumentHandover, vehicleID int, documentIDs []int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkDocumentsForHandover(tx, vehicleID, documentIDs); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO document_handovers (sales_transaction_id, recipient_name, recipient_phone,
+										handed_over_at, notes, handed_over_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	err = tx.QueryRow(query, handover.SalesTransactionID, handover.RecipientName, handover.RecipientPhone,
+		handover.HandedOverAt, handover.Notes, handover.HandedOverBy).Scan(&handover.ID, &handover.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create document handover: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE vehicle_documents
+		SET custody_status = 'handed_over', handover_id = $1, updated_at = NOW()
+		WHERE id = ANY($2)`, handover.ID, pq.Array(documentIDs))
+	if err != nil {
+		return fmt.Errorf("failed to update handed over documents: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// checkDocumentsForHandover locks the documents and checks they can be handed over
+func checkDocumentsForHandover(tx *sqlx.Tx, vehicleID int, documentIDs []int) error {
+	var documents []models.VehicleDocument
+	err := tx.Select(&documents, vehicleDocumentColumns+` WHERE id = ANY($1) ORDER BY id FOR UPDATE`, pq.Array(documentIDs))
+	if err != nil {
+		return fmt.Errorf("failed to lock vehicle documents: %w", err)
+	}
+
+	found := make(map[int]models.VehicleDocument, len(documents))
+	for _, document := range documents {
+		found[document.ID] = document
+	}
+
+	for _, id := range documentIDs {
+		document, ok := found[id]
+		switch {
+		case !ok:
+			return fmt.Errorf("vehicle document %d not found", id)
+		case document.VehicleID != vehicleID:
+			return fmt.Errorf("document %d does not belong to the sold vehicle", id)
+		case document.CustodyStatus == models.DocumentCustodyHandedOver:
+			return fmt.Errorf("document %d was already handed over", id)
+		case document.CustodyStatus == models.DocumentCustodyMissing:
+			return fmt.Errorf("document %d is marked missing", id)
+		}
+	}
+
+	return nil
+}
+
+func (r *vehicleDocumentRepository) GetHandovers(salesTransactionID int) ([]models.DocumentHandover, error) {
+	query := `
+		SELECT dh.id, dh.sales_transaction_id, dh.recipient_name, dh.recipient_phone, dh.handed_over_at,
+			   dh.notes, dh.handed_over_by, dh.created_at,
+			   st.invoice_number, st.vehicle_id, u.full_name AS handed_over_by_name
+		FROM document_handovers dh
+		JOIN sales_transactions st ON st.id = dh.sales_transaction_id
+		JOIN users u ON u.id = dh.handed_over_by
+		WHERE dh.sales_transaction_id = $1
+		ORDER BY dh.handed_over_at, dh.id`
+
+	handovers := []models.DocumentHandover{}
+	if err := r.db.Select(&handovers, query, salesTransactionID); err != nil {
+		return nil, fmt.Errorf("failed to get document handovers: %w", err)
+	}
+	if len(handovers) == 0 {
+		return handovers, nil
+	}
+
+	handoverIDs := make([]int, len(handovers))
+	for i, handover := range handovers {
+		handoverIDs[i] = handover.ID
+	}
+
+	var documents []models.VehicleDocument
+	err := r.db.Select(&documents, vehicleDocumentColumns+` WHERE handover_id = ANY($1) ORDER BY document_type, id`,
+		pq.Array(handoverIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get handed over documents: %w", err)
+	}
+	if err := r.attachAttachments(documents); err != nil {
+		return nil, err
+	}
+
+	for i := range handovers {
+		for _, document := range documents {
+			if document.HandoverID != nil && *document.HandoverID == handovers[i].ID {
+				handovers[i].Documents = append(handovers[i].Documents, document)
+			}
+		}
+	}
+
+	return handovers, nil
+}
+
+// ListPendingHandovers lists sold vehicles whose documents are still held by the showroom,
+// oldest sale first. Only the latest sale of a vehicle counts.
+func (r *vehicleDocumentRepository) ListPendingHandovers(documentType string, page, limit int) ([]models.PendingDocumentHandover, int, error) {
+	var args []interface{}
+	documentCondition := ""
+	if documentType != "" {
+		documentCondition = "AND vd.document_type = $1"
+		args = append(args, documentType)
+	}
+
+	fromClause := fmt.Sprintf(`
+		FROM sales_transactions st
+		JOIN vehicles v ON v.id = st.vehicle_id
+		JOIN vehicle_brands vb ON vb.id = v.brand_id
+		JOIN customers c ON c.id = st.customer_id
+		JOIN vehicle_documents vd ON vd.vehicle_id = st.vehicle_id
+			AND vd.custody_status <> 'handed_over' %s
+		WHERE v.status = 'sold'
+		  AND st.id = (SELECT MAX(id) FROM sales_transactions WHERE vehicle_id = st.vehicle_id)`, documentCondition)
+
+	var total int
+	err := r.db.Get(&total, `SELECT COUNT(DISTINCT st.id) `+fromClause, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count pending document handovers: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	query := fmt.Sprintf(`
+		SELECT st.id AS sales_transaction_id, st.invoice_number, st.transaction_date,
+			   (CURRENT_DATE - st.transaction_date) AS days_since_sale,
+			   c.id AS customer_id, c.name AS customer_name, c.phone AS customer_phone,
+			   v.id AS vehicle_id, v.code AS vehicle_code, v.license_plate, vb.name AS brand_name, v.model,
+			   COUNT(vd.id) AS pending_documents,
+			   STRING_AGG(DISTINCT vd.document_type::text, ', ') AS pending_types,
+			   COUNT(vd.id) FILTER (WHERE vd.custody_status = 'missing') AS missing_documents
+		%s
+		GROUP BY st.id, c.id, v.id, vb.name
+		ORDER BY st.transaction_date ASC, st.id ASC
+		LIMIT $%d OFFSET $%d`, fromClause, len(args)+1, len(args)+2)
+
+	args = append(args, limit, offset)
+
+	pending := []models.PendingDocumentHandover{}
+	if err := r.db.Select(&pending, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list pending document handovers: %w", err)
+	}
+
+	return pending, total, nil
+}
+
+func (r *vehicleDocumentRepository) attachAttachments(documents []models.VehicleDocument) error {
+	documentIDs := make([]int, len(documents))
+	for i, document := range documents {
+		documentIDs[i] = document.ID
+	}
+
+	attachments, err := r.GetAttachments(documentIDs)
+	if err != nil {
+		return err
+	}
+	for i := range documents {
+		documents[i].Attachments = attachments[documents[i].ID]
+	}
+
+	return nil
+}
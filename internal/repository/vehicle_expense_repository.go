@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+)
+
+type VehicleExpenseRepository interface {
+	Create(expense *models.VehicleExpense, taxDueDate, plateRenewalDue *time.Time) error
+	GetByID(vehicleID int, expenseID int) (*models.VehicleExpense, error)
+	ListByVehicle(vehicleID int) ([]models.VehicleExpense, error)
+	Delete(vehicleID int, expenseID int) error
+	ListRegistrationDue(until time.Time) ([]models.VehicleRegistrationDue, error)
+	MarkAlerted(due *models.VehicleRegistrationDue) (bool, error)
+}
+
+type vehicleExpenseRepository struct {
+	db *database.Database
+}
+
+func NewVehicleExpenseRepository(db *database.Database) VehicleExpenseRepository {
+	return &vehicleExpenseRepository{db: db}
+}
+
+const vehicleExpenseSelect = `
+	SELECT ve.id, ve.vehicle_id, ve.expense_type, ve.amount, ve.expense_date, ve.description,
+		   ve.reference_number, ve.created_by, ve.created_at, u.full_name AS created_by_name
+	FROM vehicle_expenses ve
+	JOIN users u ON u.id = ve.created_by`
+
+// Create records the expense, adds it to the vehicle HPP and moves the given due dates
+func (r *vehicleExpenseRepository) Create(expense *models.VehicleExpense, taxDueDate, plateRenewalDue *time.Time) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO vehicle_expenses (vehicle_id, expense_type, amount, expense_date, description,
+									  reference_number, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`
+
+	err = tx.QueryRow(query, expense.VehicleID, expense.ExpenseType, expense.Amount, expense.ExpenseDate,
+		expense.Description, expense.ReferenceNumber, expense.CreatedBy).Scan(&expense.ID, &expense.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create vehicle expense: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE vehicles
+		SET expense_cost = expense_cost + $1,
+			hpp_price = purchase_price + COALESCE(repair_cost, 0) + expense_cost + $1,
+			tax_due_date = COALESCE($2, tax_due_date),
+			plate_renewal_due_date = COALESCE($3, plate_renewal_due_date),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4`, expense.Amount, taxDueDate, plateRenewalDue, expense.VehicleID)
+	if err != nil {
+		return fmt.Errorf("failed to update vehicle HPP: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *vehicleExpenseRepository) GetByID(vehicleID int, expenseID int) (*models.VehicleExpense, error) {
+	var expense models.VehicleExpense
+	err := r.db.Get(&expense, vehicleExpenseSelect+` WHERE ve.id = $1 AND ve.vehicle_id = $2`, expenseID, vehicleID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("vehicle expense not found")
+		}
+		return nil, fmt.Errorf("failed to get vehicle expense: %w", err)
+	}
+
+	return &expense, nil
+}
+
+func (r *vehicleExpenseRepository) ListByVehicle(vehicleID int) ([]models.VehicleExpense, error) {
+	expenses := []models.VehicleExpense{}
+	err := r.db.Select(&expenses, vehicleExpenseSelect+` WHERE ve.vehicle_id = $1 ORDER BY ve.expense_date DESC, ve.id DESC`, vehicleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vehicle expenses: %w", err)
+	}
+
+	return expenses, nil
+}
+
+// Delete removes the expense and takes it out of the vehicle HPP; due dates are left as they are
+func (r *vehicleExpenseRepository) Delete(vehicleID int, expenseID int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var amount float64
+	err = tx.QueryRow(`DELETE FROM vehicle_expenses WHERE id = $1 AND vehicle_id = $2 RETURNING amount`,
+		expenseID, vehicleID).Scan(&amount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("vehicle expense not found")
+		}
+		return fmt.Errorf("failed to delete vehicle expense: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE vehicles
+		SET expense_cost = expense_cost - $1,
+			hpp_price = purchase_price + COALESCE(repair_cost, 0) + expense_cost - $1,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2`, amount, vehicleID)
+	if err != nil {
+		return fmt.Errorf("failed to update vehicle HPP: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// ListRegistrationDue lists tax and plate renewal dates up to until of vehicles still in stock,
+// overdue ones first
+func (r *vehicleExpenseRepository) ListRegistrationDue(until time.Time) ([]models.VehicleRegistrationDue, error) {
+	query := `
+		SELECT v.id AS vehicle_id, v.code AS vehicle_code, v.license_plate, vb.name AS brand_name,
+			   v.model, v.status, due.due_type, due.due_date,
+			   (due.due_date - CURRENT_DATE) AS days_left,
+			   (due.due_date < CURRENT_DATE) AS is_overdue
+		FROM vehicles v
+		JOIN vehicle_brands vb ON vb.id = v.brand_id
+		CROSS JOIN LATERAL (
+			VALUES ('stnk_tax'::vehicle_expense_type_enum, v.tax_due_date),
+				   ('plate_renewal'::vehicle_expense_type_enum, v.plate_renewal_due_date)
+		) AS due(due_type, due_date)
+		WHERE v.status <> 'sold'
+		  AND due.due_date IS NOT NULL
+		  AND due.due_date <= $1
+		ORDER BY due.due_date ASC, v.id ASC, due.due_type ASC`
+
+	dues := []models.VehicleRegistrationDue{}
+	if err := r.db.Select(&dues, query, until.Format("2006-01-02")); err != nil {
+		return nil, fmt.Errorf("failed to list registration due dates: %w", err)
+	}
+
+	return dues, nil
+}
+
+// MarkAlerted records the alert for a due date, reporting false when it was alerted before
+func (r *vehicleExpenseRepository) MarkAlerted(due *models.VehicleRegistrationDue) (bool, error) {
+	query := `
+		INSERT INTO vehicle_registration_alerts (vehicle_id, due_type, due_date)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (vehicle_id, due_type, due_date) DO NOTHING
+		RETURNING id`
+
+	var id int
+	err := r.db.QueryRow(query, due.VehicleID, due.DueType, due.DueDate).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to record registration alert: %w", err)
+	}
+
+	return true, nil
+}
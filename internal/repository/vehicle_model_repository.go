@@ -0,0 +1,407 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+)
+
+type VehicleModelRepository interface {
+	CreateModel(model *models.VehicleModel) error
+	GetModel(id int) (*models.VehicleModel, error)
+	ListModels(brandID int, activeOnly bool) ([]models.VehicleModel, error)
+	FindModelByName(brandID int, name string) (*models.VehicleModel, error)
+	UpdateModel(id int, req *models.VehicleModelUpdateRequest) error
+	DeleteModel(id int) error
+	CreateVariant(variant *models.VehicleVariant) error
+	GetVariant(id int) (*models.VehicleVariant, error)
+	ListVariants(modelIDs []int, activeOnly bool) ([]models.VehicleVariant, error)
+	FindVariantByName(modelID int, name string) (*models.VehicleVariant, error)
+	UpdateVariant(id int, req *models.VehicleVariantUpdateRequest) error
+	DeleteVariant(id int) error
+	ListUnmapped() ([]models.UnmappedVehicleModel, error)
+	MapFreeText(req *models.VehicleModelMappingRequest) (int64, error)
+}
+
+type vehicleModelRepository struct {
+	db *database.Database
+}
+
+func NewVehicleModelRepository(db *database.Database) VehicleModelRepository {
+	return &vehicleModelRepository{db: db}
+}
+
+const vehicleModelSelect = `
+	SELECT vm.id, vm.brand_id, vm.name, vm.is_active, vm.created_at, vm.updated_at,
+		   vb.name AS brand_name
+	FROM vehicle_models vm
+	JOIN vehicle_brands vb ON vb.id = vm.brand_id`
+
+const vehicleVariantSelect = `
+	SELECT id, model_id, name, engine_capacity, fuel_type, transmission_type, year_from, year_to,
+		   is_active, created_at, updated_at
+	FROM vehicle_variants`
+
+func (r *vehicleModelRepository) CreateModel(model *models.VehicleModel) error {
+	query := `
+		INSERT INTO vehicle_models (brand_id, name, is_active)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRow(query, model.BrandID, model.Name, model.IsActive).
+		Scan(&model.ID, &model.CreatedAt, &model.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create vehicle model: %w", err)
+	}
+
+	return nil
+}
+
+func (r *vehicleModelRepository) GetModel(id int) (*models.VehicleModel, error) {
+	var model models.VehicleModel
+	err := r.db.Get(&model, vehicleModelSelect+` WHERE vm.id = $1`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("vehicle model not found")
+		}
+		return nil, fmt.Errorf("failed to get vehicle model: %w", err)
+	}
+
+	return &model, nil
+}
+
+func (r *vehicleModelRepository) ListModels(brandID int, activeOnly bool) ([]models.VehicleModel, error) {
+	query := vehicleModelSelect + ` WHERE vm.brand_id = $1`
+	if activeOnly {
+		query += ` AND vm.is_active = TRUE`
+	}
+	query += ` ORDER BY vm.name ASC`
+
+	vehicleModels := []models.VehicleModel{}
+	if err := r.db.Select(&vehicleModels, query, brandID); err != nil {
+		return nil, fmt.Errorf("failed to list vehicle models: %w", err)
+	}
+
+	return vehicleModels, nil
+}
+
+// FindModelByName looks a model of the brand up by name, ignoring case, spaces and punctuation
+func (r *vehicleModelRepository) FindModelByName(brandID int, name string) (*models.VehicleModel, error) {
+	var model models.VehicleModel
+	err := r.db.Get(&model, vehicleModelSelect+`
+		WHERE vm.brand_id = $1 AND normalize_model_name(vm.name) = normalize_model_name($2)`, brandID, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("vehicle model not found")
+		}
+		return nil, fmt.Errorf("failed to find vehicle model: %w", err)
+	}
+
+	return &model, nil
+}
+
+func (r *vehicleModelRepository) UpdateModel(id int, req *models.VehicleModelUpdateRequest) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	setParts := []string{"updated_at = CURRENT_TIMESTAMP"}
+	args := []interface{}{}
+	argCounter := 1
+
+	if req.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argCounter))
+		args = append(args, *req.Name)
+		argCounter++
+	}
+	if req.IsActive != nil {
+		setParts = append(setParts, fmt.Sprintf("is_active = $%d", argCounter))
+		args = append(args, *req.IsActive)
+		argCounter++
+	}
+
+	args = append(args, id)
+	query := fmt.Sprintf(`UPDATE vehicle_models SET %s WHERE id = $%d`, strings.Join(setParts, ", "), argCounter)
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update vehicle model: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("vehicle model not found")
+	}
+
+	// Vehicles show the model name, keep it in step with the catalog
+	if req.Name != nil {
+		_, err = tx.Exec(`UPDATE vehicles SET model = $1, updated_at = CURRENT_TIMESTAMP WHERE model_id = $2`, *req.Name, id)
+		if err != nil {
+			return fmt.Errorf("failed to rename model on vehicles: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeleteModel removes a model and its variants. Models used by vehicles can only be deactivated.
+func (r *vehicleModelRepository) DeleteModel(id int) error {
+	var inUse bool
+	err := r.db.Get(&inUse, `SELECT EXISTS (SELECT 1 FROM vehicles WHERE model_id = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("failed to check vehicle model usage: %w", err)
+	}
+	if inUse {
+		return fmt.Errorf("cannot delete a vehicle model used by vehicles, deactivate it instead")
+	}
+
+	result, err := r.db.Exec(`DELETE FROM vehicle_models WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete vehicle model: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("vehicle model not found")
+	}
+
+	return nil
+}
+
+func (r *vehicleModelRepository) CreateVariant(variant *models.VehicleVariant) error {
+	query := `
+		INSERT INTO vehicle_variants (model_id, name, engine_capacity, fuel_type, transmission_type,
+									  year_from, year_to, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRow(query, variant.ModelID, variant.Name, variant.EngineCapacity, variant.FuelType,
+		variant.TransmissionType, variant.YearFrom, variant.YearTo, variant.IsActive).
+		Scan(&variant.ID, &variant.CreatedAt, &variant.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create vehicle variant: %w", err)
+	}
+
+	return nil
+}
+
+func (r *vehicleModelRepository) GetVariant(id int) (*models.VehicleVariant, error) {
+	var variant models.VehicleVariant
+	err := r.db.Get(&variant, vehicleVariantSelect+` WHERE id = $1`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("vehicle variant not found")
+		}
+		return nil, fmt.Errorf("failed to get vehicle variant: %w", err)
+	}
+
+	return &variant, nil
+}
+
+// ListVariants loads the variants of several models at once
+func (r *vehicleModelRepository) ListVariants(modelIDs []int, activeOnly bool) ([]models.VehicleVariant, error) {
+	variants := []models.VehicleVariant{}
+	if len(modelIDs) == 0 {
+		return variants, nil
+	}
+
+	query := vehicleVariantSelect + ` WHERE model_id = ANY($1)`
+	if activeOnly {
+		query += ` AND is_active = TRUE`
+	}
+	query += ` ORDER BY model_id, name`
+
+	if err := r.db.Select(&variants, query, pq.Array(modelIDs)); err != nil {
+		return nil, fmt.Errorf("failed to list vehicle variants: %w", err)
+	}
+
+	return variants, nil
+}
+
+func (r *vehicleModelRepository) FindVariantByName(modelID int, name string) (*models.VehicleVariant, error) {
+	var variant models.VehicleVariant
+	err := r.db.Get(&variant, vehicleVariantSelect+`
+		WHERE model_id = $1 AND normalize_model_name(name) = normalize_model_name($2)`, modelID, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("vehicle variant not found")
+		}
+		return nil, fmt.Errorf("failed to find vehicle variant: %w", err)
+	}
+
+	return &variant, nil
+}
+
+func (r *vehicleModelRepository) UpdateVariant(id int, req *models.VehicleVariantUpdateRequest) error {
+	setParts := []string{"updated_at = CURRENT_TIMESTAMP"}
+	args := []interface{}{}
+	argCounter := 1
+
+	if req.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argCounter))
+		args = append(args, *req.Name)
+		argCounter++
+	}
+	if req.EngineCapacity != nil {
+		setParts = append(setParts, fmt.Sprintf("engine_capacity = $%d", argCounter))
+		args = append(args, *req.EngineCapacity)
+		argCounter++
+	}
+	if req.FuelType != nil {
+		setParts = append(setParts, fmt.Sprintf("fuel_type = $%d", argCounter))
+		args = append(args, *req.FuelType)
+		argCounter++
+	}
+	if req.TransmissionType != nil {
+		setParts = append(setParts, fmt.Sprintf("transmission_type = $%d", argCounter))
+		args = append(args, *req.TransmissionType)
+		argCounter++
+	}
+	if req.YearFrom != nil {
+		setParts = append(setParts, fmt.Sprintf("year_from = $%d", argCounter))
+		args = append(args, *req.YearFrom)
+		argCounter++
+	}
+	if req.YearTo != nil {
+		setParts = append(setParts, fmt.Sprintf("year_to = $%d", argCounter))
+		args = append(args, *req.YearTo)
+		argCounter++
+	}
+	if req.IsActive != nil {
+		setParts = append(setParts, fmt.Sprintf("is_active = $%d", argCounter))
+		args = append(args, *req.IsActive)
+		argCounter++
+	}
+
+	args = append(args, id)
+	query := fmt.Sprintf(`UPDATE vehicle_variants SET %s WHERE id = $%d`, strings.Join(setParts, ", "), argCounter)
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update vehicle variant: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("vehicle variant not found")
+	}
+
+	return nil
+}
+
+// DeleteVariant removes a variant. Variants used by vehicles can only be deactivated.
+func (r *vehicleModelRepository) DeleteVariant(id int) error {
+	var inUse bool
+	err := r.db.Get(&inUse, `SELECT EXISTS (SELECT 1 FROM vehicles WHERE variant_id = $1)`, id)
+	if err != nil {
+		return fmt.Errorf("failed to check vehicle variant usage: %w", err)
+	}
+	if inUse {
+		return fmt.Errorf("cannot delete a vehicle variant used by vehicles, deactivate it instead")
+	}
+
+	result, err := r.db.Exec(`DELETE FROM vehicle_variants WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete vehicle variant: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("vehicle variant not found")
+	}
+
+	return nil
+}
+
+// ListUnmapped groups the vehicles not yet linked to the catalog by brand and free-text model.
+// A free text matching "model variant" (e.g. 'Beat FI') is suggested before a plain model match.
+func (r *vehicleModelRepository) ListUnmapped() ([]models.UnmappedVehicleModel, error) {
+	query := `
+		WITH unmapped AS (
+			SELECT brand_id, model, COUNT(*) AS vehicle_count
+			FROM vehicles
+			WHERE model_id IS NULL
+			GROUP BY brand_id, model
+		)
+		SELECT u.brand_id, vb.name AS brand_name, u.model, u.vehicle_count,
+			   s.model_id AS suggested_model_id, s.variant_id AS suggested_variant_id
+		FROM unmapped u
+		JOIN vehicle_brands vb ON vb.id = u.brand_id
+		LEFT JOIN LATERAL (
+			SELECT m.model_id, m.variant_id
+			FROM (
+				SELECT vm.id AS model_id, vv.id AS variant_id, 1 AS rank
+				FROM vehicle_models vm
+				JOIN vehicle_variants vv ON vv.model_id = vm.id
+				WHERE vm.brand_id = u.brand_id AND vm.is_active AND vv.is_active
+				  AND normalize_model_name(vm.name || vv.name) = normalize_model_name(u.model)
+				UNION ALL
+				SELECT vm.id, NULL, 2
+				FROM vehicle_models vm
+				WHERE vm.brand_id = u.brand_id AND vm.is_active
+				  AND normalize_model_name(vm.name) = normalize_model_name(u.model)
+			) m
+			ORDER BY m.rank, m.model_id, m.variant_id
+			LIMIT 1
+		) s ON TRUE
+		ORDER BY vb.name, u.model`
+
+	unmapped := []models.UnmappedVehicleModel{}
+	if err := r.db.Select(&unmapped, query); err != nil {
+		return nil, fmt.Errorf("failed to list unmapped vehicle models: %w", err)
+	}
+
+	return unmapped, nil
+}
+
+// MapFreeText links the vehicles with the free-text model to the catalog entry, renames them to
+// the catalog name and fills specs they lack from the variant
+func (r *vehicleModelRepository) MapFreeText(req *models.VehicleModelMappingRequest) (int64, error) {
+	query := `
+		UPDATE vehicles v
+		SET model_id = vm.id,
+			variant_id = vv.id,
+			model = vm.name,
+			engine_capacity = COALESCE(v.engine_capacity, vv.engine_capacity),
+			fuel_type = COALESCE(v.fuel_type, vv.fuel_type),
+			transmission_type = COALESCE(v.transmission_type, vv.transmission_type),
+			updated_at = CURRENT_TIMESTAMP
+		FROM vehicle_models vm
+		LEFT JOIN vehicle_variants vv ON vv.id = $4 AND vv.model_id = vm.id
+		WHERE vm.id = $3
+		  AND v.brand_id = vm.brand_id
+		  AND v.brand_id = $1
+		  AND v.model = $2
+		  AND v.model_id IS NULL`
+
+	result, err := r.db.Exec(query, req.BrandID, req.Model, req.ModelID, req.VariantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to map vehicle model: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
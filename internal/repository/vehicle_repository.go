@@ -4,8 +4,13 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
 	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
 	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
 )
@@ -15,6 +20,7 @@ type VehicleRepository interface {
 	GetByID(id int) (*models.Vehicle, error)
 	GetByCode(code string) (*models.Vehicle, error)
 	Update(id int, req *models.VehicleUpdateRequest) (*models.Vehicle, error)
+	UpdateWithHistory(id int, req *models.VehicleUpdateRequest, attributeValues map[int]*string, changedBy int) (*models.Vehicle, error)
 	Delete(id int) error
 	List(page, limit int, status *models.VehicleStatus) ([]models.Vehicle, int64, error)
 	GetAvailableVehicles(page, limit int) ([]models.Vehicle, int64, error)
@@ -27,6 +33,13 @@ type VehicleRepository interface {
 	SearchVehicles(offset, limit int, filters models.VehicleSearchFilters) ([]models.Vehicle, int64, error)
 	SearchAvailable(search, brand string, yearFrom, yearTo *int, sortBy, status string) ([]models.Vehicle, error)
 	GetAllBrands() ([]models.VehicleBrand, error)
+	AddPhoto(photo *models.VehiclePhoto) error
+	GetPhoto(vehicleID int, photoID int) (*models.VehiclePhoto, error)
+	GetPhotos(vehicleID int) ([]models.VehiclePhoto, error)
+	GetPhotosByVehicles(vehicleIDs []int) (map[int][]models.VehiclePhoto, error)
+	ReorderPhotos(vehicleID int, photoIDs []int) error
+	SetPrimaryPhoto(vehicleID int, photoID int) error
+	DeletePhoto(vehicleID int, photoID int) (*models.VehiclePhoto, error)
 }
 
 type vehicleRepository struct {
@@ -70,21 +83,22 @@ func (r *vehicleRepository) attemptCreate(req *models.VehicleCreateRequest, crea
 			code, brand_id, model, year, color, engine_capacity, fuel_type, 
 			transmission_type, license_plate, chassis_number, engine_number, 
 			odometer, source_type, source_id, purchase_price, condition_status, 
-			notes, created_by
+			tax_due_date, plate_renewal_due_date, model_id, variant_id, notes, created_by
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
 		RETURNING id, code, brand_id, model, year, color, engine_capacity, fuel_type,
 				  transmission_type, license_plate, chassis_number, engine_number,
 				  odometer, source_type, source_id, purchase_price, condition_status,
-				  status, repair_cost, hpp_price, selling_price, sold_price, sold_date,
-				  notes, created_by, created_at, updated_at`
+				  status, repair_cost, expense_cost, hpp_price, selling_price, sold_price, sold_date,
+				  tax_due_date, plate_renewal_due_date, model_id, variant_id, notes, created_by, created_at, updated_at`
 
 	var vehicle models.Vehicle
 	err := r.db.Get(&vehicle, query,
 		req.Code, req.BrandID, req.Model, req.Year, req.Color, req.EngineCapacity,
 		req.FuelType, req.TransmissionType, req.LicensePlate, req.ChassisNumber,
 		req.EngineNumber, req.Odometer, req.SourceType, req.SourceID,
-		req.PurchasePrice, req.ConditionStatus, req.Notes, createdBy)
+		req.PurchasePrice, req.ConditionStatus, nullableDate(req.TaxDueDate), nullableDate(req.PlateRenewalDue),
+		req.ModelID, req.VariantID, req.Notes, createdBy)
 
 	return &vehicle, err
 }
@@ -106,8 +120,8 @@ func (r *vehicleRepository) GetByID(id int) (*models.Vehicle, error) {
 			id, code, brand_id, model, year, color, engine_capacity,
 			fuel_type, transmission_type, license_plate, chassis_number,
 			engine_number, odometer, source_type, source_id, purchase_price,
-			condition_status, status, repair_cost, hpp_price, selling_price,
-			sold_price, sold_date, notes, created_by, created_at, updated_at
+			condition_status, status, repair_cost, expense_cost, hpp_price, selling_price,
+			sold_price, sold_date, tax_due_date, plate_renewal_due_date, model_id, variant_id, notes, created_by, created_at, updated_at
 		FROM vehicles
 		WHERE id = $1`
 
@@ -146,8 +160,8 @@ func (r *vehicleRepository) GetByCode(code string) (*models.Vehicle, error) {
 			v.id, v.code, v.brand_id, v.model, v.year, v.color, v.engine_capacity,
 			v.fuel_type, v.transmission_type, v.license_plate, v.chassis_number,
 			v.engine_number, v.odometer, v.source_type, v.source_id, v.purchase_price,
-			v.condition_status, v.status, v.repair_cost, v.hpp_price, v.selling_price,
-			v.sold_price, v.sold_date, v.notes, v.created_by, v.created_at, v.updated_at
+			v.condition_status, v.status, v.repair_cost, v.expense_cost, v.hpp_price, v.selling_price,
+			v.sold_price, v.sold_date, v.tax_due_date, v.plate_renewal_due_date, v.model_id, v.variant_id, v.notes, v.created_by, v.created_at, v.updated_at
 		FROM vehicles v
 		WHERE v.code = $1`
 
@@ -164,6 +178,56 @@ func (r *vehicleRepository) GetByCode(code string) (*models.Vehicle, error) {
 }
 
 func (r *vehicleRepository) Update(id int, req *models.VehicleUpdateRequest) (*models.Vehicle, error) {
+	return updateVehicle(r.db, id, req)
+}
+
+// UpdateWithHistory saves an edit of a vehicle, its attribute values and the price and status
+// changes it makes for the timeline in one transaction
+func (r *vehicleRepository) UpdateWithHistory(id int, req *models.VehicleUpdateRequest, attributeValues map[int]*string, changedBy int) (*models.Vehicle, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldStatus models.VehicleStatus
+	var oldPrice *float64
+	err = tx.QueryRow(`SELECT status, selling_price FROM vehicles WHERE id = $1 FOR UPDATE`, id).Scan(&oldStatus, &oldPrice)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("vehicle not found")
+		}
+		return nil, fmt.Errorf("failed to lock vehicle: %w", err)
+	}
+
+	vehicle, err := updateVehicle(tx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := saveAttributeValues(tx, id, attributeValues); err != nil {
+		return nil, err
+	}
+
+	if req.SellingPrice != nil {
+		if err := insertPriceChange(tx, id, oldPrice, *req.SellingPrice, changedBy); err != nil {
+			return nil, err
+		}
+	}
+	if req.Status != nil {
+		if err := insertStatusChange(tx, id, oldStatus, *req.Status, changedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return vehicle, nil
+}
+
+func updateVehicle(q sqlx.Queryer, id int, req *models.VehicleUpdateRequest) (*models.Vehicle, error) {
 	// Build dynamic update query
 	setParts := []string{"updated_at = CURRENT_TIMESTAMP"}
 	args := []interface{}{}
@@ -174,6 +238,15 @@ func (r *vehicleRepository) Update(id int, req *models.VehicleUpdateRequest) (*m
 		args = append(args, *req.Model)
 		argCounter++
 	}
+	if req.ModelID != nil {
+		// A variant belongs to one model, so it is replaced along with it
+		setParts = append(setParts, fmt.Sprintf("model_id = $%d", argCounter), fmt.Sprintf("variant_id = $%d", argCounter+1))
+		args = append(args, *req.ModelID, req.VariantID)
+		argCounter += 2
+	} else if req.Model != nil {
+		// Free text no longer matches the catalog entry
+		setParts = append(setParts, "model_id = NULL", "variant_id = NULL")
+	}
 	if req.Year != nil {
 		setParts = append(setParts, fmt.Sprintf("year = $%d", argCounter))
 		args = append(args, *req.Year)
@@ -234,6 +307,16 @@ func (r *vehicleRepository) Update(id int, req *models.VehicleUpdateRequest) (*m
 		args = append(args, *req.SellingPrice)
 		argCounter++
 	}
+	if req.TaxDueDate != nil {
+		setParts = append(setParts, fmt.Sprintf("tax_due_date = $%d", argCounter))
+		args = append(args, nullableDate(req.TaxDueDate))
+		argCounter++
+	}
+	if req.PlateRenewalDue != nil {
+		setParts = append(setParts, fmt.Sprintf("plate_renewal_due_date = $%d", argCounter))
+		args = append(args, nullableDate(req.PlateRenewalDue))
+		argCounter++
+	}
 	if req.Notes != nil {
 		setParts = append(setParts, fmt.Sprintf("notes = $%d", argCounter))
 		args = append(args, *req.Notes)
@@ -250,12 +333,12 @@ func (r *vehicleRepository) Update(id int, req *models.VehicleUpdateRequest) (*m
 		RETURNING id, code, brand_id, model, year, color, engine_capacity, fuel_type,
 				  transmission_type, license_plate, chassis_number, engine_number,
 				  odometer, source_type, source_id, purchase_price, condition_status,
-				  status, repair_cost, hpp_price, selling_price, sold_price, sold_date,
-				  notes, created_by, created_at, updated_at`,
+				  status, repair_cost, expense_cost, hpp_price, selling_price, sold_price, sold_date,
+				  tax_due_date, plate_renewal_due_date, model_id, variant_id, notes, created_by, created_at, updated_at`,
 		strings.Join(setParts, ", "), argCounter)
 
 	var vehicle models.Vehicle
-	err := r.db.Get(&vehicle, query, args...)
+	err := sqlx.Get(q, &vehicle, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update vehicle: %w", err)
 	}
@@ -317,8 +400,8 @@ func (r *vehicleRepository) List(page, limit int, status *models.VehicleStatus)
 				v.id, v.code, v.brand_id, v.model, v.year, v.color, v.engine_capacity,
 				v.fuel_type, v.transmission_type, v.license_plate, v.chassis_number,
 				v.engine_number, v.odometer, v.source_type, v.source_id, v.purchase_price,
-				v.condition_status, v.status, v.repair_cost, v.hpp_price, v.selling_price,
-				v.sold_price, v.sold_date, v.notes, v.created_by, v.created_at, v.updated_at,
+				v.condition_status, v.status, v.repair_cost, v.expense_cost, v.hpp_price, v.selling_price,
+				v.sold_price, v.sold_date, v.tax_due_date, v.plate_renewal_due_date, v.model_id, v.variant_id, v.notes, v.created_by, v.created_at, v.updated_at,
 				vb.id as "brand.id", vb.name as "brand.name", vb.type_id as "brand.type_id", vb.created_at as "brand.created_at"
 			FROM vehicles v
 			JOIN vehicle_brands vb ON v.brand_id = vb.id
@@ -331,8 +414,8 @@ func (r *vehicleRepository) List(page, limit int, status *models.VehicleStatus)
 				v.id, v.code, v.brand_id, v.model, v.year, v.color, v.engine_capacity,
 				v.fuel_type, v.transmission_type, v.license_plate, v.chassis_number,
 				v.engine_number, v.odometer, v.source_type, v.source_id, v.purchase_price,
-				v.condition_status, v.status, v.repair_cost, v.hpp_price, v.selling_price,
-				v.sold_price, v.sold_date, v.notes, v.created_by, v.created_at, v.updated_at,
+				v.condition_status, v.status, v.repair_cost, v.expense_cost, v.hpp_price, v.selling_price,
+				v.sold_price, v.sold_date, v.tax_due_date, v.plate_renewal_due_date, v.model_id, v.variant_id, v.notes, v.created_by, v.created_at, v.updated_at,
 				vb.id as "brand.id", vb.name as "brand.name", vb.type_id as "brand.type_id", vb.created_at as "brand.created_at"
 			FROM vehicles v
 			JOIN vehicle_brands vb ON v.brand_id = vb.id
@@ -420,7 +503,7 @@ func (r *vehicleRepository) UpdateSellingPrice(id int, sellingPrice float64) err
 }
 
 func (r *vehicleRepository) UpdateRepairCost(id int, repairCost float64) error {
-	query := `UPDATE vehicles SET repair_cost = $1, hpp_price = purchase_price + $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	query := `UPDATE vehicles SET repair_cost = $1, hpp_price = purchase_price + $1 + expense_cost, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
 
 	result, err := r.db.Exec(query, repairCost, id)
 	if err != nil {
@@ -467,7 +550,6 @@ func (r *vehicleRepository) SearchVehicles(offset, limit int, filters models.Veh
 	baseQuery := `
 		FROM vehicles v
 		LEFT JOIN vehicle_brands vb ON v.brand_id = vb.id
-		WHERE v.deleted_at IS NULL
 	`
 
 	var args []interface{}
@@ -487,6 +569,18 @@ func (r *vehicleRepository) SearchVehicles(offset, limit int, filters models.Veh
 		argIndex++
 	}
 
+	if filters.ModelID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("v.model_id = $%d", argIndex))
+		args = append(args, *filters.ModelID)
+		argIndex++
+	}
+
+	if filters.VariantID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("v.variant_id = $%d", argIndex))
+		args = append(args, *filters.VariantID)
+		argIndex++
+	}
+
 	if filters.YearMin != nil {
 		whereConditions = append(whereConditions, fmt.Sprintf("v.year >= $%d", argIndex))
 		args = append(args, *filters.YearMin)
@@ -530,14 +624,47 @@ func (r *vehicleRepository) SearchVehicles(offset, limit int, filters models.Veh
 	}
 
 	if filters.Status != "" {
-		whereConditions = append(whereConditions, fmt.Sprintf("LOWER(v.status) = LOWER($%d)", argIndex))
+		whereConditions = append(whereConditions, fmt.Sprintf("v.status::text = LOWER($%d)", argIndex))
 		args = append(args, filters.Status)
 		argIndex++
 	}
 
-	// Add additional WHERE conditions
+	// Custom attributes, compared case-insensitively; number attributes also match in their stored
+	// form, so 7.0 finds 7. Bounds apply to number attributes only.
+	for _, code := range sortedKeys(filters.Attributes) {
+		value := filters.Attributes[code]
+		number := value
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			number = strconv.FormatFloat(parsed, 'f', -1, 64)
+		}
+		whereConditions = append(whereConditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM vehicle_attribute_values vav
+			JOIN vehicle_attribute_definitions vad ON vad.id = vav.definition_id
+			WHERE vav.vehicle_id = v.id AND vad.code = $%d
+			  AND (LOWER(vav.value) = LOWER($%d) OR (vad.data_type = 'number' AND vav.value = $%d)))`, argIndex, argIndex+1, argIndex+2))
+		args = append(args, code, value, number)
+		argIndex += 3
+	}
+
+	attributeBounds := []struct {
+		operator string
+		bounds   map[string]string
+	}{{">=", filters.AttributeMin}, {"<=", filters.AttributeMax}}
+	for _, bound := range attributeBounds {
+		for _, code := range sortedKeys(bound.bounds) {
+			whereConditions = append(whereConditions, fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM vehicle_attribute_values vav
+				JOIN vehicle_attribute_definitions vad ON vad.id = vav.definition_id
+				WHERE vav.vehicle_id = v.id AND vad.code = $%d
+				  AND CASE WHEN vad.data_type = 'number' THEN vav.value::numeric END %s $%d::numeric)`, argIndex, bound.operator, argIndex+1))
+			args = append(args, code, bound.bounds[code])
+			argIndex += 2
+		}
+	}
+
+	// Add WHERE conditions
 	if len(whereConditions) > 0 {
-		baseQuery += " AND " + strings.Join(whereConditions, " AND ")
+		baseQuery += " WHERE " + strings.Join(whereConditions, " AND ")
 	}
 
 	// Count total records
@@ -554,8 +681,8 @@ func (r *vehicleRepository) SearchVehicles(offset, limit int, filters models.Veh
 			   v.model, v.year, v.color, v.engine_capacity, v.fuel_type,
 			   v.transmission_type, v.license_plate, v.chassis_number, v.engine_number,
 			   v.odometer, v.source_type, v.source_id, v.purchase_price,
-			   v.condition_status, v.status, v.repair_cost, v.hpp_price, 
-			   v.selling_price, v.sold_price, v.sold_date, v.notes,
+			   v.condition_status, v.status, v.repair_cost, v.expense_cost, v.hpp_price, 
+			   v.selling_price, v.sold_price, v.sold_date, v.tax_due_date, v.plate_renewal_due_date, v.model_id, v.variant_id, v.notes,
 			   v.created_by, v.created_at, v.updated_at
 		%s
 		ORDER BY v.created_at DESC
@@ -579,8 +706,8 @@ func (r *vehicleRepository) SearchVehicles(offset, limit int, filters models.Veh
 			&v.Model, &v.Year, &v.Color, &v.EngineCapacity, &v.FuelType,
 			&v.TransmissionType, &v.LicensePlate, &v.ChassisNumber, &v.EngineNumber,
 			&v.Odometer, &v.SourceType, &v.SourceID, &v.PurchasePrice,
-			&v.ConditionStatus, &v.Status, &v.RepairCost, &v.HPPPrice,
-			&v.SellingPrice, &v.SoldPrice, &v.SoldDate, &v.Notes,
+			&v.ConditionStatus, &v.Status, &v.RepairCost, &v.ExpenseCost, &v.HPPPrice,
+			&v.SellingPrice, &v.SoldPrice, &v.SoldDate, &v.TaxDueDate, &v.PlateRenewalDue, &v.ModelID, &v.VariantID, &v.Notes,
 			&v.CreatedBy, &v.CreatedAt, &v.UpdatedAt,
 		)
 		if err != nil {
@@ -710,3 +837,253 @@ func (r *vehicleRepository) SearchAvailable(search, brand string, yearFrom, year
 
 	return vehicles, nil
 }
+
+const vehiclePhotoColumns = `
+		SELECT id, vehicle_id, photo_path, thumbnail_path, is_primary, sort_order, caption,
+			   content_type, file_size, uploaded_by, created_at
+		FROM vehicle_photos`
+
+// AddPhoto appends a photo to the vehicle gallery. The first photo of a vehicle becomes its
+// primary photo; a new primary photo replaces the previous one.
+func (r *vehicleRepository) AddPhoto(photo *models.VehiclePhoto) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockVehiclePhotos(tx, photo.VehicleID); err != nil {
+		return err
+	}
+
+	var count, lastOrder int
+	err = tx.QueryRow(`SELECT COUNT(*), COALESCE(MAX(sort_order), 0) FROM vehicle_photos WHERE vehicle_id = $1`,
+		photo.VehicleID).Scan(&count, &lastOrder)
+	if err != nil {
+		return fmt.Errorf("failed to count vehicle photos: %w", err)
+	}
+
+	if count == 0 {
+		photo.IsPrimary = true
+	}
+	if photo.IsPrimary {
+		if _, err := tx.Exec(`UPDATE vehicle_photos SET is_primary = false WHERE vehicle_id = $1 AND is_primary`, photo.VehicleID); err != nil {
+			return fmt.Errorf("failed to clear primary photo: %w", err)
+		}
+	}
+	photo.SortOrder = lastOrder + 1
+
+	query := `
+		INSERT INTO vehicle_photos (vehicle_id, photo_path, thumbnail_path, is_primary, sort_order, caption,
+									content_type, file_size, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at`
+
+	err = tx.QueryRow(query, photo.VehicleID, photo.PhotoPath, photo.ThumbnailPath, photo.IsPrimary, photo.SortOrder,
+		photo.Caption, photo.ContentType, photo.FileSize, photo.UploadedBy).Scan(&photo.ID, &photo.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add vehicle photo: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *vehicleRepository) GetPhoto(vehicleID int, photoID int) (*models.VehiclePhoto, error) {
+	var photo models.VehiclePhoto
+	err := r.db.Get(&photo, vehiclePhotoColumns+` WHERE id = $1 AND vehicle_id = $2`, photoID, vehicleID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("vehicle photo not found")
+		}
+		return nil, fmt.Errorf("failed to get vehicle photo: %w", err)
+	}
+
+	return &photo, nil
+}
+
+func (r *vehicleRepository) GetPhotos(vehicleID int) ([]models.VehiclePhoto, error) {
+	var photos []models.VehiclePhoto
+	err := r.db.Select(&photos, vehiclePhotoColumns+` WHERE vehicle_id = $1 ORDER BY sort_order, id`, vehicleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vehicle photos: %w", err)
+	}
+
+	return photos, nil
+}
+
+// GetPhotosByVehicles loads the galleries of a page of vehicles in one query
+func (r *vehicleRepository) GetPhotosByVehicles(vehicleIDs []int) (map[int][]models.VehiclePhoto, error) {
+	photosByVehicle := make(map[int][]models.VehiclePhoto)
+	if len(vehicleIDs) == 0 {
+		return photosByVehicle, nil
+	}
+
+	var photos []models.VehiclePhoto
+	err := r.db.Select(&photos, vehiclePhotoColumns+` WHERE vehicle_id = ANY($1) ORDER BY vehicle_id, sort_order, id`,
+		pq.Array(vehicleIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vehicle photos: %w", err)
+	}
+
+	for _, photo := range photos {
+		photosByVehicle[photo.VehicleID] = append(photosByVehicle[photo.VehicleID], photo)
+	}
+
+	return photosByVehicle, nil
+}
+
+// ReorderPhotos sets the display order; photoIDs must list every photo of the vehicle once
+func (r *vehicleRepository) ReorderPhotos(vehicleID int, photoIDs []int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockVehiclePhotos(tx, vehicleID); err != nil {
+		return err
+	}
+
+	var current []int
+	if err := tx.Select(&current, `SELECT id FROM vehicle_photos WHERE vehicle_id = $1`, vehicleID); err != nil {
+		return fmt.Errorf("failed to get vehicle photos: %w", err)
+	}
+
+	known := make(map[int]bool, len(current))
+	for _, id := range current {
+		known[id] = true
+	}
+	if len(photoIDs) != len(current) {
+		return fmt.Errorf("photo order must list all %d photos of the vehicle", len(current))
+	}
+	for _, id := range photoIDs {
+		if !known[id] {
+			return fmt.Errorf("photo order must list all %d photos of the vehicle exactly once", len(current))
+		}
+		delete(known, id)
+	}
+
+	for i, id := range photoIDs {
+		if _, err := tx.Exec(`UPDATE vehicle_photos SET sort_order = $1 WHERE id = $2`, i+1, id); err != nil {
+			return fmt.Errorf("failed to reorder vehicle photos: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *vehicleRepository) SetPrimaryPhoto(vehicleID int, photoID int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockVehiclePhotos(tx, vehicleID); err != nil {
+		return err
+	}
+
+	var exists bool
+	err = tx.Get(&exists, `SELECT EXISTS(SELECT 1 FROM vehicle_photos WHERE id = $1 AND vehicle_id = $2)`, photoID, vehicleID)
+	if err != nil {
+		return fmt.Errorf("failed to get vehicle photo: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("vehicle photo not found")
+	}
+
+	// Clear first, the partial unique index allows only one primary photo
+	if _, err := tx.Exec(`UPDATE vehicle_photos SET is_primary = false WHERE vehicle_id = $1 AND is_primary`, vehicleID); err != nil {
+		return fmt.Errorf("failed to clear primary photo: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE vehicle_photos SET is_primary = true WHERE id = $1`, photoID); err != nil {
+		return fmt.Errorf("failed to set primary photo: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePhoto removes a photo and returns it so its files can be removed. When the primary
+// photo goes, the next photo in display order takes its place.
+func (r *vehicleRepository) DeletePhoto(vehicleID int, photoID int) (*models.VehiclePhoto, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockVehiclePhotos(tx, vehicleID); err != nil {
+		return nil, err
+	}
+
+	var photo models.VehiclePhoto
+	err = tx.Get(&photo, vehiclePhotoColumns+` WHERE id = $1 AND vehicle_id = $2`, photoID, vehicleID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("vehicle photo not found")
+		}
+		return nil, fmt.Errorf("failed to get vehicle photo: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM vehicle_photos WHERE id = $1`, photoID); err != nil {
+		return nil, fmt.Errorf("failed to delete vehicle photo: %w", err)
+	}
+
+	if photo.IsPrimary {
+		_, err = tx.Exec(`
+			UPDATE vehicle_photos SET is_primary = true
+			WHERE id = (SELECT id FROM vehicle_photos WHERE vehicle_id = $1 ORDER BY sort_order, id LIMIT 1)`, vehicleID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set primary photo: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &photo, nil
+}
+
+// lockVehiclePhotos serialises gallery changes of a vehicle by locking its row
+func lockVehiclePhotos(tx *sqlx.Tx, vehicleID int) error {
+	var id int
+	err := tx.Get(&id, `SELECT id FROM vehicles WHERE id = $1 FOR UPDATE`, vehicleID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("vehicle not found")
+		}
+		return fmt.Errorf("failed to lock vehicle: %w", err)
+	}
+
+	return nil
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// nullableDate turns an optional YYYY-MM-DD request value into a query argument, empty meaning NULL
+func nullableDate(value *string) interface{} {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return *value
+}
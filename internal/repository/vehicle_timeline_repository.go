@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+)
+
+type VehicleTimelineRepository interface {
+	RecordPriceChange(vehicleID int, oldPrice *float64, newPrice float64, changedBy int) error
+	GetTimeline(vehicleID int) ([]models.VehicleTimelineEvent, error)
+}
+
+type vehicleTimelineRepository struct {
+	db *database.Database
+}
+
+func NewVehicleTimelineRepository(db *database.Database) VehicleTimelineRepository {
+	return &vehicleTimelineRepository{db: db}
+}
+
+// RecordPriceChange keeps the selling price history; an unchanged price is not recorded and an
+// old price of zero counts as not set
+func (r *vehicleTimelineRepository) RecordPriceChange(vehicleID int, oldPrice *float64, newPrice float64, changedBy int) error {
+	return insertPriceChange(r.db, vehicleID, oldPrice, newPrice, changedBy)
+}
+
+func insertPriceChange(e sqlx.Execer, vehicleID int, oldPrice *float64, newPrice float64, changedBy int) error {
+	if oldPrice != nil && *oldPrice == newPrice {
+		return nil
+	}
+	if oldPrice != nil && *oldPrice == 0 {
+		oldPrice = nil
+	}
+
+	query := `
+		INSERT INTO vehicle_price_changes (vehicle_id, old_price, new_price, changed_by)
+		VALUES ($1, $2, $3, $4)`
+
+	if _, err := e.Exec(query, vehicleID, oldPrice, newPrice, changedBy); err != nil {
+		return fmt.Errorf("failed to record price change: %w", err)
+	}
+
+	return nil
+}
+
+func insertStatusChange(e sqlx.Execer, vehicleID int, fromStatus, toStatus models.VehicleStatus, changedBy int) error {
+	if fromStatus == toStatus {
+		return nil
+	}
+
+	query := `
+		INSERT INTO vehicle_status_history (vehicle_id, from_status, to_status, changed_by)
+		VALUES ($1, $2, $3, $4)`
+
+	if _, err := e.Exec(query, vehicleID, fromStatus, toStatus, changedBy); err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+
+	return nil
+}
+
+// GetTimeline collects the events of a vehicle from the tables that record them, oldest first.
+// Events at the same moment keep the order of the lifecycle, e.g. a sale before its down payment.
+// Payments after the down payment are not recorded one by one, so a paid sale shows the rest as a
+// single settlement without an actor.
+func (r *vehicleTimelineRepository) GetTimeline(vehicleID int) ([]models.VehicleTimelineEvent, error) {
+	query := `
+		WITH events AS (
+			SELECT 'intake' AS event_type, v.created_at AS occurred_at, 0 AS seq,
+				   CONCAT_WS(', ', 'From ' || COALESCE(c.name, s.name, v.source_type::text),
+							 'condition ' || v.condition_status::text) AS description,
+				   v.purchase_price AS amount, v.created_by AS actor_id,
+				   'vehicle' AS reference_type, v.id AS reference_id, v.code AS reference_code
+			FROM vehicles v
+			LEFT JOIN customers c ON v.source_type = 'customer' AND c.id = v.source_id
+			LEFT JOIN suppliers s ON v.source_type = 'supplier' AND s.id = v.source_id
+			WHERE v.id = $1
+
+			UNION ALL
+			SELECT 'purchase', pt.created_at, 1,
+				   CONCAT_WS(', ', 'Payment ' || pt.payment_status::text, pt.payment_method),
+				   pt.purchase_price, pt.processed_by,
+				   'purchase_transaction', pt.id, pt.invoice_number
+			FROM purchase_transactions pt
+			WHERE pt.vehicle_id = $1
+
+			UNION ALL
+			SELECT 'inspection', MAX(rci.checked_at), 2,
+				   CONCAT(COUNT(*) FILTER (WHERE rci.result = 'pass'), ' passed, ',
+						  COUNT(*) FILTER (WHERE rci.result = 'fail'), ' failed, ',
+						  COUNT(*) FILTER (WHERE rci.result = 'needs_attention'), ' need attention'),
+				   NULL, (ARRAY_AGG(rci.checked_by ORDER BY rci.checked_at DESC))[1],
+				   'repair_order', ro.id, ro.code
+			FROM repair_checklist_items rci
+			JOIN repair_orders ro ON ro.id = rci.repair_order_id
+			WHERE ro.vehicle_id = $1 AND rci.checked_at IS NOT NULL
+			GROUP BY ro.id, ro.code
+
+			UNION ALL
+			SELECT 'repair_opened', ro.created_at, 3, ro.description,
+				   ro.estimated_cost, ro.assigned_by,
+				   'repair_order', ro.id, ro.code
+			FROM repair_orders ro
+			WHERE ro.vehicle_id = $1
+
+			UNION ALL
+			SELECT 'repair_part', rsp.created_at, 4,
+				   CONCAT_WS(', ', rsp.quantity_used || ' x ' || sp.name, 'disposition ' || rsp.disposition::text),
+				   rsp.total_price, ro.mechanic_id,
+				   'repair_order', ro.id, ro.code
+			FROM repair_spare_parts rsp
+			JOIN repair_orders ro ON ro.id = rsp.repair_order_id
+			JOIN spare_parts sp ON sp.id = rsp.spare_part_id
+			WHERE ro.vehicle_id = $1
+
+			UNION ALL
+			SELECT 'repair_completed', COALESCE(ro.completed_at, ro.updated_at), 5, ro.notes,
+				   ro.actual_cost,
+				   COALESCE((SELECT rsh.changed_by FROM repair_status_history rsh
+							 WHERE rsh.repair_order_id = ro.id AND rsh.to_status = 'completed'
+							 ORDER BY rsh.changed_at DESC LIMIT 1), ro.mechanic_id),
+				   'repair_order', ro.id, ro.code
+			FROM repair_orders ro
+			WHERE ro.vehicle_id = $1 AND ro.status = 'completed'
+
+			UNION ALL
+			SELECT 'expense', ve.created_at, 6,
+				   CONCAT_WS(': ', ve.expense_type::text, ve.description),
+				   ve.amount, ve.created_by,
+				   'vehicle_expense', ve.id, ve.reference_number
+			FROM vehicle_expenses ve
+			WHERE ve.vehicle_id = $1
+
+			UNION ALL
+			SELECT 'price_change', vpc.changed_at, 7,
+				   CASE WHEN vpc.old_price IS NULL THEN 'Selling price set'
+						ELSE 'Selling price changed from ' || vpc.old_price END,
+				   vpc.new_price, vpc.changed_by,
+				   'vehicle', vpc.vehicle_id, NULL
+			FROM vehicle_price_changes vpc
+			WHERE vpc.vehicle_id = $1
+
+			UNION ALL
+			SELECT CASE WHEN vsh.to_status = 'reserved' THEN 'reservation'
+						WHEN vsh.from_status = 'reserved' THEN 'reservation_released'
+						ELSE 'status_change' END,
+				   vsh.changed_at, 8,
+				   CONCAT_WS(', ', 'Status ' || COALESCE(vsh.from_status::text || ' to ', '') || vsh.to_status::text, vsh.note),
+				   NULL, vsh.changed_by,
+				   'vehicle', vsh.vehicle_id, NULL
+			FROM vehicle_status_history vsh
+			WHERE vsh.vehicle_id = $1
+
+			UNION ALL
+			SELECT 'sale', st.created_at, 9, 'Sold to ' || c.name,
+				   st.selling_price, st.processed_by,
+				   'sales_transaction', st.id, st.invoice_number
+			FROM sales_transactions st
+			JOIN customers c ON c.id = st.customer_id
+			WHERE st.vehicle_id = $1
+
+			UNION ALL
+			SELECT 'payment', st.created_at, 10, CONCAT_WS(', ', 'Down payment', st.payment_method),
+				   st.down_payment, st.processed_by,
+				   'sales_transaction', st.id, st.invoice_number
+			FROM sales_transactions st
+			WHERE st.vehicle_id = $1 AND st.down_payment > 0
+
+			UNION ALL
+			SELECT 'payment', st.updated_at, 11, CONCAT_WS(', ', 'Payment settled', st.payment_method),
+				   st.selling_price - st.down_payment, NULL,
+				   'sales_transaction', st.id, st.invoice_number
+			FROM sales_transactions st
+			WHERE st.vehicle_id = $1 AND st.payment_status = 'paid' AND st.selling_price > st.down_payment
+
+			UNION ALL
+			SELECT 'document_handover', dh.handed_over_at, 12,
+				   CONCAT_WS(': ', 'Handed to ' || dh.recipient_name,
+							 STRING_AGG(vd.document_type::text, ', ' ORDER BY vd.document_type)),
+				   NULL, dh.handed_over_by,
+				   'document_handover', dh.id, st.invoice_number
+			FROM document_handovers dh
+			JOIN sales_transactions st ON st.id = dh.sales_transaction_id
+			LEFT JOIN vehicle_documents vd ON vd.handover_id = dh.id
+			WHERE st.vehicle_id = $1
+			GROUP BY dh.id, st.invoice_number
+		)
+		SELECT e.event_type, e.occurred_at, e.description, e.amount, e.actor_id, u.full_name AS actor_name,
+			   e.reference_type, e.reference_id, e.reference_code
+		FROM events e
+		LEFT JOIN users u ON u.id = e.actor_id
+		ORDER BY e.occurred_at, e.seq`
+
+	events := []models.VehicleTimelineEvent{}
+	if err := r.db.Select(&events, query, vehicleID); err != nil {
+		return nil, fmt.Errorf("failed to get vehicle timeline: %w", err)
+	}
+
+	return events, nil
+}
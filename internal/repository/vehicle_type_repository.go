@@ -28,12 +28,12 @@ func NewVehicleTypeRepository(db *database.Database) VehicleTypeRepository {
 
 func (r *vehicleTypeRepository) Create(req *models.VehicleTypeCreateRequest) (*models.VehicleType, error) {
 	query := `
-		INSERT INTO vehicle_types (name, description)
-		VALUES ($1, $2)
-		RETURNING id, name, description, created_at`
+		INSERT INTO vehicle_types (name, description, repair_sla_days)
+		VALUES ($1, $2, $3)
+		RETURNING id, name, description, repair_sla_days, created_at`
 
 	var vehicleType models.VehicleType
-	err := r.db.Get(&vehicleType, query, req.Name, req.Description)
+	err := r.db.Get(&vehicleType, query, req.Name, req.Description, req.RepairSLADays)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create vehicle type: %w", err)
 	}
@@ -43,7 +43,7 @@ func (r *vehicleTypeRepository) Create(req *models.VehicleTypeCreateRequest) (*m
 
 func (r *vehicleTypeRepository) GetByID(id int) (*models.VehicleType, error) {
 	query := `
-		SELECT id, name, description, created_at
+		SELECT id, name, description, repair_sla_days, created_at
 		FROM vehicle_types
 		WHERE id = $1`
 
@@ -61,7 +61,7 @@ func (r *vehicleTypeRepository) GetByID(id int) (*models.VehicleType, error) {
 
 func (r *vehicleTypeRepository) GetByName(name string) (*models.VehicleType, error) {
 	query := `
-		SELECT id, name, description, created_at
+		SELECT id, name, description, repair_sla_days, created_at
 		FROM vehicle_types
 		WHERE name = $1`
 
@@ -93,6 +93,16 @@ func (r *vehicleTypeRepository) Update(id int, req *models.VehicleTypeUpdateRequ
 		args = append(args, *req.Description)
 		argCounter++
 	}
+	if req.RepairSLADays != nil {
+		// 0 clears the target so the workshop default applies
+		var slaDays interface{}
+		if *req.RepairSLADays > 0 {
+			slaDays = *req.RepairSLADays
+		}
+		setParts = append(setParts, fmt.Sprintf("repair_sla_days = $%d", argCounter))
+		args = append(args, slaDays)
+		argCounter++
+	}
 
 	if len(setParts) == 0 {
 		return nil, fmt.Errorf("no fields to update")
@@ -105,7 +115,7 @@ func (r *vehicleTypeRepository) Update(id int, req *models.VehicleTypeUpdateRequ
 		UPDATE vehicle_types 
 		SET %s
 		WHERE id = $%d
-		RETURNING id, name, description, created_at`,
+		RETURNING id, name, description, repair_sla_days, created_at`,
 		strings.Join(setParts, ", "), argCounter)
 
 	var vehicleType models.VehicleType
@@ -139,7 +149,7 @@ func (r *vehicleTypeRepository) Delete(id int) error {
 
 func (r *vehicleTypeRepository) List() ([]models.VehicleType, error) {
 	query := `
-		SELECT id, name, description, created_at
+		SELECT id, name, description, repair_sla_days, created_at
 		FROM vehicle_types
 		ORDER BY name ASC`
 
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+)
+
+type WarrantyRepository interface {
+	Upsert(warranty *models.SalesWarranty) error
+	GetBySale(salesTransactionID int) (*models.SalesWarranty, error)
+	CreateClaim(claim *models.WarrantyClaim) error
+	GetClaims(warrantyID int) ([]models.WarrantyClaim, error)
+	GetCostReport(dateFrom, dateTo string) (*models.WarrantyCostReport, error)
+}
+
+type warrantyRepository struct {
+	db *database.Database
+}
+
+func NewWarrantyRepository(db *database.Database) WarrantyRepository {
+	return &warrantyRepository{db: db}
+}
+
+// Upsert sets the warranty terms of a sale, replacing earlier terms
+func (r *warrantyRepository) Upsert(warranty *models.SalesWarranty) error {
+	query := `
+		INSERT INTO sales_warranties (sales_transaction_id, coverage, duration_months, odometer_limit,
+									  odometer_at_sale, start_date, end_date, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (sales_transaction_id) DO UPDATE
+		SET coverage = EXCLUDED.coverage,
+			duration_months = EXCLUDED.duration_months,
+			odometer_limit = EXCLUDED.odometer_limit,
+			odometer_at_sale = EXCLUDED.odometer_at_sale,
+			start_date = EXCLUDED.start_date,
+			end_date = EXCLUDED.end_date,
+			notes = EXCLUDED.notes,
+			updated_at = NOW()
+		RETURNING id, created_by, created_at, updated_at`
+
+	err := r.db.QueryRow(query, warranty.SalesTransactionID, warranty.Coverage, warranty.DurationMonths,
+		warranty.OdometerLimit, warranty.OdometerAtSale, warranty.StartDate, warranty.EndDate, warranty.Notes,
+		warranty.CreatedBy).Scan(&warranty.ID, &warranty.CreatedBy, &warranty.CreatedAt, &warranty.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save sales warranty: %w", err)
+	}
+
+	return nil
+}
+
+func (r *warrantyRepository) GetBySale(salesTransactionID int) (*models.SalesWarranty, error) {
+	query := `
+		SELECT sw.id, sw.sales_transaction_id, sw.coverage, sw.duration_months, sw.odometer_limit,
+			   sw.odometer_at_sale, sw.start_date, sw.end_date, sw.notes, sw.created_by, sw.created_at, sw.updated_at,
+			   st.invoice_number, st.customer_id, st.vehicle_id,
+			   COALESCE((
+				   SELECT SUM(ro.actual_cost)
+				   FROM warranty_claims wc
+				   JOIN repair_orders ro ON wc.repair_order_id = ro.id
+				   WHERE wc.warranty_id = sw.id AND ro.status = 'completed'
+			   ), 0) as warranty_expense
+		FROM sales_warranties sw
+		JOIN sales_transactions st ON sw.sales_transaction_id = st.id
+		WHERE sw.sales_transaction_id = $1`
+
+	var warranty models.SalesWarranty
+	err := r.db.Get(&warranty, query, salesTransactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("sales warranty not found")
+		}
+		return nil, fmt.Errorf("failed to get sales warranty: %w", err)
+	}
+
+	claims, err := r.GetClaims(warranty.ID)
+	if err != nil {
+		return nil, err
+	}
+	warranty.Claims = claims
+
+	return &warranty, nil
+}
+
+func (r *warrantyRepository) CreateClaim(claim *models.WarrantyClaim) error {
+	query := `
+		INSERT INTO warranty_claims (warranty_id, repair_order_id, complaint, odometer, claimed_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRow(query, claim.WarrantyID, claim.RepairOrderID, claim.Complaint, claim.Odometer,
+		claim.ClaimedBy).Scan(&claim.ID, &claim.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create warranty claim: %w", err)
+	}
+
+	return nil
+}
+
+func (r *warrantyRepository) GetClaims(warrantyID int) ([]models.WarrantyClaim, error) {
+	query := `
+		SELECT wc.id, wc.warranty_id, wc.repair_order_id, wc.complaint, wc.odometer, wc.claimed_by, wc.created_at,
+			   ro.code as repair_code, ro.status as repair_status, ro.actual_cost as cost
+		FROM warranty_claims wc
+		JOIN repair_orders ro ON wc.repair_order_id = ro.id
+		WHERE wc.warranty_id = $1
+		ORDER BY wc.created_at`
+
+	claims := []models.WarrantyClaim{}
+	err := r.db.Select(&claims, query, warrantyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warranty claims: %w", err)
+	}
+
+	return claims, nil
+}
+
+// GetCostReport sums the warranty repairs completed in the period per brand and model of the sold vehicle
+func (r *warrantyRepository) GetCostReport(dateFrom, dateTo string) (*models.WarrantyCostReport, error) {
+	report := &models.WarrantyCostReport{DateFrom: dateFrom, DateTo: dateTo}
+
+	query := `
+		SELECT vb.id as brand_id, vb.name as brand_name, v.model,
+			   COUNT(*) as claims,
+			   COALESCE(SUM(ro.actual_cost), 0) as total_cost,
+			   COALESCE(AVG(ro.actual_cost), 0) as average_cost,
+			   COUNT(DISTINCT v.id) as vehicles_claimed
+		FROM warranty_claims wc
+		JOIN repair_orders ro ON wc.repair_order_id = ro.id
+		JOIN sales_warranties sw ON wc.warranty_id = sw.id
+		JOIN sales_transactions st ON sw.sales_transaction_id = st.id
+		JOIN vehicles v ON st.vehicle_id = v.id
+		JOIN vehicle_brands vb ON v.brand_id = vb.id
+		WHERE ro.status = 'completed' AND DATE(ro.completed_at) BETWEEN $1 AND $2
+		GROUP BY vb.id, vb.name, v.model
+		ORDER BY total_cost DESC, vb.name, v.model`
+
+	report.Lines = []models.WarrantyCostLine{}
+	err := r.db.Select(&report.Lines, query, dateFrom, dateTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warranty costs: %w", err)
+	}
+
+	for _, line := range report.Lines {
+		report.Claims += line.Claims
+		report.TotalCost += line.TotalCost
+	}
+
+	// Claims opened in the period whose repair is still running
+	err = r.db.Get(&report.OpenClaims, `
+		SELECT COUNT(*)
+		FROM warranty_claims wc
+		JOIN repair_orders ro ON wc.repair_order_id = ro.id
+		WHERE ro.status NOT IN ('completed', 'cancelled') AND DATE(wc.created_at) BETWEEN $1 AND $2`, dateFrom, dateTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count open warranty claims: %w", err)
+	}
+
+	return report, nil
+}
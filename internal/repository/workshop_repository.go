@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/database"
+)
+
+type WorkshopRepository interface {
+	// Bays
+	CreateBay(req *models.WorkshopBayCreateRequest) (*models.WorkshopBay, error)
+	GetBayByID(id int) (*models.WorkshopBay, error)
+	UpdateBay(id int, req *models.WorkshopBayUpdateRequest) (*models.WorkshopBay, error)
+	DeleteBay(id int) error
+	ListBays(activeOnly bool) ([]models.WorkshopBay, error)
+
+	// Schedules
+	SaveSchedule(schedule *models.RepairSchedule) error
+	GetScheduleByRepair(repairID int) (*models.RepairSchedule, error)
+	DeleteSchedule(repairID int) error
+	ListSchedules(from, to time.Time, bayID, mechanicID int) ([]models.RepairSchedule, error)
+}
+
+type workshopRepository struct {
+	db *database.Database
+}
+
+func NewWorkshopRepository(db *database.Database) WorkshopRepository {
+	return &workshopRepository{db: db}
+}
+
+const repairScheduleSelect = `
+		SELECT rs.id, rs.repair_order_id, rs.bay_id, rs.mechanic_id, rs.scheduled_start, rs.scheduled_end,
+			   rs.scheduled_by, rs.created_at, rs.updated_at,
+			   ro.code as repair_code, ro.status as repair_status,
+			   wb.code as bay_code, wb.name as bay_name,
+			   u.full_name as mechanic_name,
+			   COALESCE(v.license_plate, cv.license_plate) as license_plate,
+			   COALESCE(v.model, cv.model) as vehicle_model
+		FROM repair_schedules rs
+		JOIN repair_orders ro ON rs.repair_order_id = ro.id
+		JOIN workshop_bays wb ON rs.bay_id = wb.id
+		JOIN users u ON rs.mechanic_id = u.id
+		LEFT JOIN vehicles v ON ro.vehicle_id = v.id
+		LEFT JOIN customer_vehicles cv ON ro.customer_vehicle_id = cv.id`
+
+func (r *workshopRepository) CreateBay(req *models.WorkshopBayCreateRequest) (*models.WorkshopBay, error) {
+	bayType := req.BayType
+	if bayType == "" {
+		bayType = "lift"
+	}
+
+	query := `
+		INSERT INTO workshop_bays (code, name, bay_type, notes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, code, name, bay_type, notes, is_active, created_at, updated_at`
+
+	var bay models.WorkshopBay
+	err := r.db.Get(&bay, query, req.Code, req.Name, bayType, req.Notes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workshop bay: %w", err)
+	}
+
+	return &bay, nil
+}
+
+func (r *workshopRepository) GetBayByID(id int) (*models.WorkshopBay, error) {
+	query := `
+		SELECT id, code, name, bay_type, notes, is_active, created_at, updated_at
+		FROM workshop_bays
+		WHERE id = $1`
+
+	var bay models.WorkshopBay
+	err := r.db.Get(&bay, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("workshop bay not found")
+		}
+		return nil, fmt.Errorf("failed to get workshop bay: %w", err)
+	}
+
+	return &bay, nil
+}
+
+func (r *workshopRepository) UpdateBay(id int, req *models.WorkshopBayUpdateRequest) (*models.WorkshopBay, error) {
+	// Build dynamic update query
+	setParts := []string{}
+	args := []interface{}{}
+	argCounter := 1
+
+	if req.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argCounter))
+		args = append(args, *req.Name)
+		argCounter++
+	}
+	if req.BayType != nil {
+		setParts = append(setParts, fmt.Sprintf("bay_type = $%d", argCounter))
+		args = append(args, *req.BayType)
+		argCounter++
+	}
+	if req.Notes != nil {
+		setParts = append(setParts, fmt.Sprintf("notes = $%d", argCounter))
+		args = append(args, *req.Notes)
+		argCounter++
+	}
+	if req.IsActive != nil {
+		setParts = append(setParts, fmt.Sprintf("is_active = $%d", argCounter))
+		args = append(args, *req.IsActive)
+		argCounter++
+	}
+
+	if len(setParts) == 0 {
+		return nil, fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, "updated_at = NOW()")
+	args = append(args, id)
+
+	query := fmt.Sprintf(`
+		UPDATE workshop_bays 
+		SET %s
+		WHERE id = $%d
+		RETURNING id, code, name, bay_type, notes, is_active, created_at, updated_at`,
+		strings.Join(setParts, ", "), argCounter)
+
+	var bay models.WorkshopBay
+	err := r.db.Get(&bay, query, args...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("workshop bay not found")
+		}
+		return nil, fmt.Errorf("failed to update workshop bay: %w", err)
+	}
+
+	return &bay, nil
+}
+
+func (r *workshopRepository) DeleteBay(id int) error {
+	// Bays with booking history are deactivated instead of removed
+	query := `
+		UPDATE workshop_bays SET is_active = false, updated_at = NOW()
+		WHERE id = $1 AND EXISTS (SELECT 1 FROM repair_schedules WHERE bay_id = $1)`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete workshop bay: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	result, err = r.db.Exec(`DELETE FROM workshop_bays WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete workshop bay: %w", err)
+	}
+
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("workshop bay not found")
+	}
+
+	return nil
+}
+
+func (r *workshopRepository) ListBays(activeOnly bool) ([]models.WorkshopBay, error) {
+	query := `
+		SELECT id, code, name, bay_type, notes, is_active, created_at, updated_at
+		FROM workshop_bays`
+	if activeOnly {
+		query += ` WHERE is_active = true`
+	}
+	query += ` ORDER BY code ASC`
+
+	bays := []models.WorkshopBay{}
+	err := r.db.Select(&bays, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workshop bays: %w", err)
+	}
+
+	return bays, nil
+}
+
+// SaveSchedule creates or moves the slot of a repair order, refusing overlaps on the bay or mechanic
+func (r *workshopRepository) SaveSchedule(schedule *models.RepairSchedule) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the bay and mechanic so concurrent bookings are checked one at a time
+	var bayCode string
+	err = tx.QueryRow(`SELECT code FROM workshop_bays WHERE id = $1 AND is_active = true FOR UPDATE`, schedule.BayID).Scan(&bayCode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("workshop bay not found or inactive")
+		}
+		return err
+	}
+
+	_, err = tx.Exec(`SELECT id FROM users WHERE id = $1 FOR UPDATE`, schedule.MechanicID)
+	if err != nil {
+		return err
+	}
+
+	var conflictCode string
+	var conflictBayID int
+	var conflictStart, conflictEnd time.Time
+	query := `
+		SELECT ro.code, rs.bay_id, rs.scheduled_start, rs.scheduled_end
+		FROM repair_schedules rs
+		JOIN repair_orders ro ON rs.repair_order_id = ro.id
+		WHERE rs.repair_order_id <> $1
+		  AND ro.status NOT IN ('completed', 'cancelled')
+		  AND (rs.bay_id = $2 OR rs.mechanic_id = $3)
+		  AND rs.scheduled_start < $5 AND rs.scheduled_end > $4
+		ORDER BY rs.scheduled_start
+		LIMIT 1`
+	err = tx.QueryRow(query, schedule.RepairOrderID, schedule.BayID, schedule.MechanicID,
+		schedule.ScheduledStart, schedule.ScheduledEnd).
+		Scan(&conflictCode, &conflictBayID, &conflictStart, &conflictEnd)
+	if err == nil {
+		resource := "mechanic"
+		if conflictBayID == schedule.BayID {
+			resource = "bay " + bayCode
+		}
+		return fmt.Errorf("schedule conflict: %s is booked for repair %s from %s to %s", resource, conflictCode,
+			conflictStart.Format("2006-01-02 15:04"), conflictEnd.Format("2006-01-02 15:04"))
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	query = `
+		INSERT INTO repair_schedules (repair_order_id, bay_id, mechanic_id, scheduled_start, scheduled_end, scheduled_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (repair_order_id)
+		DO UPDATE SET bay_id = EXCLUDED.bay_id,
+			mechanic_id = EXCLUDED.mechanic_id,
+			scheduled_start = EXCLUDED.scheduled_start,
+			scheduled_end = EXCLUDED.scheduled_end,
+			scheduled_by = EXCLUDED.scheduled_by,
+			updated_at = NOW()
+		RETURNING id, created_at, updated_at`
+	err = tx.QueryRow(query, schedule.RepairOrderID, schedule.BayID, schedule.MechanicID,
+		schedule.ScheduledStart, schedule.ScheduledEnd, schedule.ScheduledBy).
+		Scan(&schedule.ID, &schedule.CreatedAt, &schedule.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	// Keep the repair order assignee in sync with the booked mechanic
+	_, err = tx.Exec(`UPDATE repair_orders SET mechanic_id = $1, updated_at = NOW() WHERE id = $2 AND mechanic_id <> $1`,
+		schedule.MechanicID, schedule.RepairOrderID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *workshopRepository) GetScheduleByRepair(repairID int) (*models.RepairSchedule, error) {
+	query := repairScheduleSelect + `
+		WHERE rs.repair_order_id = $1`
+
+	var schedule models.RepairSchedule
+	err := r.db.Get(&schedule, query, repairID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("repair schedule not found")
+		}
+		return nil, fmt.Errorf("failed to get repair schedule: %w", err)
+	}
+
+	return &schedule, nil
+}
+
+func (r *workshopRepository) DeleteSchedule(repairID int) error {
+	result, err := r.db.Exec(`DELETE FROM repair_schedules WHERE repair_order_id = $1`, repairID)
+	if err != nil {
+		return fmt.Errorf("failed to delete repair schedule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("repair schedule not found")
+	}
+
+	return nil
+}
+
+func (r *workshopRepository) ListSchedules(from, to time.Time, bayID, mechanicID int) ([]models.RepairSchedule, error) {
+	conditions := []string{"rs.scheduled_start < $2", "rs.scheduled_end > $1", "ro.status <> 'cancelled'"}
+	args := []interface{}{from, to}
+	argIndex := 3
+
+	if bayID > 0 {
+		conditions = append(conditions, fmt.Sprintf("rs.bay_id = $%d", argIndex))
+		args = append(args, bayID)
+		argIndex++
+	}
+
+	if mechanicID > 0 {
+		conditions = append(conditions, fmt.Sprintf("rs.mechanic_id = $%d", argIndex))
+		args = append(args, mechanicID)
+		argIndex++
+	}
+
+	query := repairScheduleSelect + `
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY wb.code ASC, rs.scheduled_start ASC`
+
+	schedules := []models.RepairSchedule{}
+	err := r.db.Select(&schedules, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repair schedules: %w", err)
+	}
+
+	return schedules, nil
+}
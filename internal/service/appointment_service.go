@@ -0,0 +1,297 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
+)
+
+type AppointmentService interface {
+	BookAppointment(req *models.ServiceAppointmentCreateRequest, source models.AppointmentSource, bookedBy *int) (*models.ServiceAppointment, error)
+	GetAppointment(id int) (*models.ServiceAppointment, error)
+	ListAppointments(filter models.ServiceAppointmentFilter, page, limit int) ([]models.ServiceAppointment, int, error)
+	RescheduleAppointment(id int, req *models.ServiceAppointmentRescheduleRequest) (*models.ServiceAppointment, error)
+	ArriveAppointment(id int, req *models.ServiceAppointmentArrivalRequest, receivedBy int) (*models.ServiceAppointment, error)
+	CancelAppointment(id int, req *models.ServiceAppointmentCancelRequest) (*models.ServiceAppointment, error)
+	MarkNoShow(id int) (*models.ServiceAppointment, error)
+	MarkNoShows() (int, error)
+	GetCapacity(start time.Time, end *time.Time, serviceID *int) (*models.AppointmentCapacity, error)
+	GetStats(dateFrom, dateTo string) (*models.AppointmentStats, error)
+}
+
+type appointmentService struct {
+	appointmentRepo     repository.AppointmentRepository
+	customerVehicleRepo repository.CustomerVehicleRepository
+	serviceCatalogRepo  repository.ServiceCatalogRepository
+	repairService       RepairService
+	slotLength          time.Duration
+	noShowGrace         time.Duration
+}
+
+func NewAppointmentService(appointmentRepo repository.AppointmentRepository, customerVehicleRepo repository.CustomerVehicleRepository, serviceCatalogRepo repository.ServiceCatalogRepository, repairService RepairService, slotLength time.Duration, noShowGrace time.Duration) AppointmentService {
+	return &appointmentService{
+		appointmentRepo:     appointmentRepo,
+		customerVehicleRepo: customerVehicleRepo,
+		serviceCatalogRepo:  serviceCatalogRepo,
+		repairService:       repairService,
+		slotLength:          slotLength,
+		noShowGrace:         noShowGrace,
+	}
+}
+
+func (s *appointmentService) BookAppointment(req *models.ServiceAppointmentCreateRequest, source models.AppointmentSource, bookedBy *int) (*models.ServiceAppointment, error) {
+	customerVehicle, err := s.customerVehicleRepo.GetByID(req.CustomerVehicleID)
+	if err != nil {
+		return nil, err
+	}
+
+	requestedService := strings.TrimSpace(req.RequestedService)
+	if req.ServiceID != nil {
+		service, err := s.serviceCatalogRepo.GetByID(*req.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		if !service.IsActive {
+			return nil, fmt.Errorf("service %s is not active", service.Code)
+		}
+		if requestedService == "" {
+			requestedService = service.Name
+		}
+	}
+	if requestedService == "" {
+		return nil, fmt.Errorf("requested service is required")
+	}
+
+	if !req.ScheduledStart.After(time.Now()) {
+		return nil, fmt.Errorf("appointment must be in the future")
+	}
+
+	end, err := s.slotEnd(req.ScheduledStart, req.ScheduledEnd, req.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	appointment := &models.ServiceAppointment{
+		Code:              generateAppointmentCode(),
+		CustomerID:        customerVehicle.CustomerID,
+		CustomerVehicleID: customerVehicle.ID,
+		ServiceID:         req.ServiceID,
+		RequestedService:  requestedService,
+		ScheduledStart:    req.ScheduledStart,
+		ScheduledEnd:      end,
+		Source:            source,
+		Notes:             req.Notes,
+		BookedBy:          bookedBy,
+	}
+
+	if err := s.appointmentRepo.Create(appointment); err != nil {
+		return nil, err
+	}
+
+	return s.appointmentRepo.GetByID(appointment.ID)
+}
+
+func (s *appointmentService) GetAppointment(id int) (*models.ServiceAppointment, error) {
+	return s.appointmentRepo.GetByID(id)
+}
+
+func (s *appointmentService) ListAppointments(filter models.ServiceAppointmentFilter, page, limit int) ([]models.ServiceAppointment, int, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	return s.appointmentRepo.List(filter, page, limit)
+}
+
+func (s *appointmentService) RescheduleAppointment(id int, req *models.ServiceAppointmentRescheduleRequest) (*models.ServiceAppointment, error) {
+	appointment, err := s.appointmentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.Status != models.AppointmentStatusBooked {
+		return nil, fmt.Errorf("cannot reschedule appointment in %s status", appointment.Status)
+	}
+	if !req.ScheduledStart.After(time.Now()) {
+		return nil, fmt.Errorf("appointment must be in the future")
+	}
+
+	// Keep the booked length unless a new end is given
+	end := req.ScheduledStart.Add(appointment.ScheduledEnd.Sub(appointment.ScheduledStart))
+	if req.ScheduledEnd != nil {
+		end = *req.ScheduledEnd
+	}
+	if !end.After(req.ScheduledStart) {
+		return nil, fmt.Errorf("scheduled end must be after scheduled start")
+	}
+
+	if err := s.appointmentRepo.Reschedule(id, req.ScheduledStart, end); err != nil {
+		return nil, err
+	}
+
+	return s.appointmentRepo.GetByID(id)
+}
+
+// ArriveAppointment converts the appointment into a customer service order
+func (s *appointmentService) ArriveAppointment(id int, req *models.ServiceAppointmentArrivalRequest, receivedBy int) (*models.ServiceAppointment, error) {
+	appointment, err := s.appointmentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.Status != models.AppointmentStatusBooked {
+		return nil, fmt.Errorf("cannot check in appointment in %s status", appointment.Status)
+	}
+
+	// The requested operation drives the mechanic skill check
+	var categories []string
+	if appointment.ServiceID != nil {
+		service, err := s.serviceCatalogRepo.GetByID(*appointment.ServiceID)
+		if err == nil {
+			categories = []string{service.Category}
+		}
+	}
+
+	if req.Odometer != nil {
+		_, err = s.customerVehicleRepo.Update(appointment.CustomerVehicleID, &models.CustomerVehicleUpdateRequest{Odometer: req.Odometer})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	notes := req.Notes
+	if notes == nil {
+		notes = appointment.Notes
+	}
+	repair, err := s.repairService.CreateServiceOrder(&models.ServiceOrderCreateRequest{
+		CustomerVehicleID: appointment.CustomerVehicleID,
+		MechanicID:        req.MechanicID,
+		Description:       &appointment.RequestedService,
+		EstimatedCost:     req.EstimatedCost,
+		Notes:             notes,
+		ServiceCategories: categories,
+	}, receivedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.appointmentRepo.MarkArrived(id, repair.ID); err != nil {
+		// Another check-in won the race, drop the duplicate order
+		if deleteErr := s.repairService.DeleteRepairOrder(repair.ID); deleteErr != nil {
+			fmt.Printf("Warning: failed to delete service order %s: %v\n", repair.Code, deleteErr)
+		}
+		return nil, err
+	}
+
+	return s.appointmentRepo.GetByID(id)
+}
+
+func (s *appointmentService) CancelAppointment(id int, req *models.ServiceAppointmentCancelRequest) (*models.ServiceAppointment, error) {
+	if err := s.appointmentRepo.Cancel(id, req.Reason); err != nil {
+		return nil, err
+	}
+
+	return s.appointmentRepo.GetByID(id)
+}
+
+func (s *appointmentService) MarkNoShow(id int) (*models.ServiceAppointment, error) {
+	appointment, err := s.appointmentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.ScheduledStart.After(time.Now()) {
+		return nil, fmt.Errorf("appointment has not started yet")
+	}
+
+	if err := s.appointmentRepo.MarkNoShow(id); err != nil {
+		return nil, err
+	}
+
+	return s.appointmentRepo.GetByID(id)
+}
+
+// MarkNoShows flags booked appointments whose slot ended more than the grace period ago
+func (s *appointmentService) MarkNoShows() (int, error) {
+	return s.appointmentRepo.MarkNoShows(time.Now().Add(-s.noShowGrace))
+}
+
+func (s *appointmentService) GetCapacity(start time.Time, end *time.Time, serviceID *int) (*models.AppointmentCapacity, error) {
+	slotEnd, err := s.slotEnd(start, end, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.appointmentRepo.GetCapacity(start, slotEnd, 0)
+}
+
+func (s *appointmentService) GetStats(dateFrom, dateTo string) (*models.AppointmentStats, error) {
+	// Default to the current month
+	now := time.Now()
+	if dateFrom == "" {
+		dateFrom = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
+	}
+	if dateTo == "" {
+		dateTo = now.Format("2006-01-02")
+	}
+
+	if _, err := time.Parse("2006-01-02", dateFrom); err != nil {
+		return nil, fmt.Errorf("invalid date format, use YYYY-MM-DD")
+	}
+	if _, err := time.Parse("2006-01-02", dateTo); err != nil {
+		return nil, fmt.Errorf("invalid date format, use YYYY-MM-DD")
+	}
+
+	stats, err := s.appointmentRepo.GetStats(dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cancelled and upcoming appointments do not count towards the no-show rate
+	if due := stats.Arrived + stats.NoShow; due > 0 {
+		stats.NoShowRate = math.Round(float64(stats.NoShow)/float64(due)*10000) / 100
+	}
+
+	return stats, nil
+}
+
+// slotEnd defaults the end of a slot from the standard hours of the requested operation
+func (s *appointmentService) slotEnd(start time.Time, end *time.Time, serviceID *int) (time.Time, error) {
+	if end != nil {
+		if !end.After(start) {
+			return time.Time{}, fmt.Errorf("scheduled end must be after scheduled start")
+		}
+		return *end, nil
+	}
+
+	length := s.slotLength
+	if serviceID != nil {
+		service, err := s.serviceCatalogRepo.GetByID(*serviceID)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if service.StandardHours > 0 {
+			length = time.Duration(service.StandardHours * float64(time.Hour))
+		}
+	}
+
+	return start.Add(length), nil
+}
+
+// generateAppointmentCode generates a code in format APT-YYYYMMDD-XXXXXX
+func generateAppointmentCode() string {
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Sprintf("APT-%s-%06d", time.Now().Format("20060102"), time.Now().Nanosecond()%1000000)
+	}
+	return fmt.Sprintf("APT-%s-%s", time.Now().Format("20060102"), strings.ToUpper(hex.EncodeToString(suffix)))
+}
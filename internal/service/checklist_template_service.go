@@ -0,0 +1,63 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
+)
+
+type ChecklistTemplateService interface {
+	Create(req *models.ChecklistTemplateCreateRequest) (*models.ChecklistTemplate, error)
+	GetByID(id int) (*models.ChecklistTemplate, error)
+	Update(id int, req *models.ChecklistTemplateUpdateRequest) (*models.ChecklistTemplate, error)
+	Delete(id int) error
+	List(vehicleTypeID int, activeOnly bool) ([]models.ChecklistTemplate, error)
+}
+
+type checklistTemplateService struct {
+	checklistRepo   repository.ChecklistTemplateRepository
+	vehicleTypeRepo repository.VehicleTypeRepository
+}
+
+func NewChecklistTemplateService(checklistRepo repository.ChecklistTemplateRepository, vehicleTypeRepo repository.VehicleTypeRepository) ChecklistTemplateService {
+	return &checklistTemplateService{
+		checklistRepo:   checklistRepo,
+		vehicleTypeRepo: vehicleTypeRepo,
+	}
+}
+
+func (s *checklistTemplateService) Create(req *models.ChecklistTemplateCreateRequest) (*models.ChecklistTemplate, error) {
+	if req.VehicleTypeID != nil {
+		if _, err := s.vehicleTypeRepo.GetByID(*req.VehicleTypeID); err != nil {
+			return nil, fmt.Errorf("vehicle type not found")
+		}
+	}
+
+	template, err := s.checklistRepo.Create(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checklist template: %w", err)
+	}
+
+	return template, nil
+}
+
+func (s *checklistTemplateService) GetByID(id int) (*models.ChecklistTemplate, error) {
+	return s.checklistRepo.GetByID(id)
+}
+
+func (s *checklistTemplateService) Update(id int, req *models.ChecklistTemplateUpdateRequest) (*models.ChecklistTemplate, error) {
+	if req.Items != nil && len(req.Items) == 0 {
+		return nil, fmt.Errorf("checklist template needs at least one item")
+	}
+
+	return s.checklistRepo.Update(id, req)
+}
+
+func (s *checklistTemplateService) Delete(id int) error {
+	return s.checklistRepo.Delete(id)
+}
+
+func (s *checklistTemplateService) List(vehicleTypeID int, activeOnly bool) ([]models.ChecklistTemplate, error) {
+	return s.checklistRepo.List(vehicleTypeID, activeOnly)
+}
@@ -15,15 +15,25 @@ type CustomerService interface {
 	List(page, limit int) ([]models.Customer, int64, error)
 	GetByPhone(phone string) (*models.Customer, error)
 	GetByEmail(email string) (*models.Customer, error)
+
+	// Customer-owned vehicles serviced in the workshop
+	AddVehicle(customerID int, req *models.CustomerVehicleCreateRequest) (*models.CustomerVehicle, error)
+	GetVehicle(id int) (*models.CustomerVehicle, error)
+	UpdateVehicle(id int, req *models.CustomerVehicleUpdateRequest) (*models.CustomerVehicle, error)
+	ListVehicles(customerID int) ([]models.CustomerVehicle, error)
 }
 
 type customerService struct {
-	customerRepo repository.CustomerRepository
+	customerRepo        repository.CustomerRepository
+	customerVehicleRepo repository.CustomerVehicleRepository
+	vehicleBrandRepo    repository.VehicleBrandRepository
 }
 
-func NewCustomerService(customerRepo repository.CustomerRepository) CustomerService {
+func NewCustomerService(customerRepo repository.CustomerRepository, customerVehicleRepo repository.CustomerVehicleRepository, vehicleBrandRepo repository.VehicleBrandRepository) CustomerService {
 	return &customerService{
-		customerRepo: customerRepo,
+		customerRepo:        customerRepo,
+		customerVehicleRepo: customerVehicleRepo,
+		vehicleBrandRepo:    vehicleBrandRepo,
 	}
 }
 
@@ -142,4 +152,47 @@ func (s *customerService) GetByEmail(email string) (*models.Customer, error) {
 	}
 
 	return customer, nil
-}
\ No newline at end of file
+}
+
+func (s *customerService) AddVehicle(customerID int, req *models.CustomerVehicleCreateRequest) (*models.CustomerVehicle, error) {
+	// Check if customer exists
+	_, err := s.customerRepo.GetByID(customerID)
+	if err != nil {
+		return nil, fmt.Errorf("customer not found")
+	}
+
+	if _, err := s.vehicleBrandRepo.GetByID(req.BrandID); err != nil {
+		return nil, fmt.Errorf("vehicle brand not found")
+	}
+
+	vehicle, err := s.customerVehicleRepo.Create(customerID, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add customer vehicle: %w", err)
+	}
+
+	return vehicle, nil
+}
+
+func (s *customerService) GetVehicle(id int) (*models.CustomerVehicle, error) {
+	return s.customerVehicleRepo.GetByID(id)
+}
+
+func (s *customerService) UpdateVehicle(id int, req *models.CustomerVehicleUpdateRequest) (*models.CustomerVehicle, error) {
+	if req.BrandID != nil {
+		if _, err := s.vehicleBrandRepo.GetByID(*req.BrandID); err != nil {
+			return nil, fmt.Errorf("vehicle brand not found")
+		}
+	}
+
+	return s.customerVehicleRepo.Update(id, req)
+}
+
+func (s *customerService) ListVehicles(customerID int) ([]models.CustomerVehicle, error) {
+	// Check if customer exists
+	_, err := s.customerRepo.GetByID(customerID)
+	if err != nil {
+		return nil, fmt.Errorf("customer not found")
+	}
+
+	return s.customerVehicleRepo.ListByCustomer(customerID)
+}
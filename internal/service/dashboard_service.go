@@ -19,11 +19,13 @@ type DashboardService interface {
 
 type dashboardService struct {
 	dashboardRepo repository.DashboardRepository
+	slaAtRiskDays int
 }
 
-func NewDashboardService(dashboardRepo repository.DashboardRepository) DashboardService {
+func NewDashboardService(dashboardRepo repository.DashboardRepository, slaAtRiskDays int) DashboardService {
 	return &dashboardService{
 		dashboardRepo: dashboardRepo,
+		slaAtRiskDays: slaAtRiskDays,
 	}
 }
 
@@ -104,8 +106,22 @@ func (s *dashboardService) GetMechanicDashboard(mechanicID int) (*models.Mechani
 		return nil, fmt.Errorf("failed to get required parts: %w", err)
 	}
 	
+	// Flag jobs against their target completion date
+	overdue, atRisk := 0, 0
+	for i := range assignedRepairs {
+		assignedRepairs[i].SLAStatus = repairSLAStatus(&assignedRepairs[i], today, s.slaAtRiskDays)
+		switch assignedRepairs[i].SLAStatus {
+		case models.RepairSLAOverdue:
+			overdue++
+		case models.RepairSLAAtRisk:
+			atRisk++
+		}
+	}
+	
 	return &models.MechanicDashboardResponse{
 		AssignedRepairs: assignedRepairs,
+		OverdueRepairs:  overdue,
+		AtRiskRepairs:   atRisk,
 		CompletedToday:  completedToday,
 		RequiredParts:   requiredParts,
 	}, nil
@@ -124,11 +140,19 @@ func (s *dashboardService) getBaseDashboard(today time.Time) (*models.DashboardR
 		return nil, fmt.Errorf("failed to get recent transactions: %w", err)
 	}
 	
-	// Get pending repairs
+	// Get pending repairs, the most urgent first
 	pendingRepairs, err := s.dashboardRepo.GetPendingRepairs(5)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pending repairs: %w", err)
 	}
+	for i := range pendingRepairs {
+		pendingRepairs[i].SLAStatus = repairSLAStatus(&pendingRepairs[i], today, s.slaAtRiskDays)
+	}
+	
+	overdueRepairs, atRiskRepairs, err := s.dashboardRepo.GetRepairSLACounts(s.slaAtRiskDays)
+	if err != nil {
+		return nil, err
+	}
 	
 	// Get low stock items
 	lowStockItems, err := s.dashboardRepo.GetLowStockItems(5)
@@ -146,6 +170,8 @@ func (s *dashboardService) getBaseDashboard(today time.Time) (*models.DashboardR
 		Overview:          *overview,
 		RecentTransactions: recentTransactions,
 		PendingRepairs:    pendingRepairs,
+		OverdueRepairs:    overdueRepairs,
+		AtRiskRepairs:     atRiskRepairs,
 		LowStockItems:     lowStockItems,
 		AvailableVehicles: availableVehicles,
 	}, nil
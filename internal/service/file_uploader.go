@@ -0,0 +1,141 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/pos-baru/pkg/storage"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+// Longest side in pixels of generated thumbnails
+const thumbnailSize = 320
+
+// Upload types accepted for photos and documents, mapped to the stored file extension
+var (
+	imageUploadTypes = map[string]string{
+		"image/jpeg": ".jpg",
+		"image/png":  ".png",
+	}
+	documentUploadTypes = map[string]string{
+		"image/jpeg":      ".jpg",
+		"image/png":       ".png",
+		"application/pdf": ".pdf",
+	}
+)
+
+// StoredFile describes an uploaded file after it has been written to storage
+type StoredFile struct {
+	Path          string
+	ThumbnailPath *string
+	FileName      string
+	ContentType   string
+	Size          int64
+}
+
+// FileUploader validates uploaded files and writes them, with thumbnails for images, to storage.
+// Stored paths are storage keys; clients get signed links that expire after urlTTL.
+type FileUploader struct {
+	store   storage.Store
+	maxSize int64
+	urlTTL  time.Duration
+}
+
+func NewFileUploader(store storage.Store, maxSizeMB int, urlTTL time.Duration) *FileUploader {
+	return &FileUploader{
+		store:   store,
+		maxSize: int64(maxSizeMB) * 1024 * 1024,
+		urlTTL:  urlTTL,
+	}
+}
+
+// Store saves the file below dir when its detected content type is one of allowedTypes
+func (u *FileUploader) Store(dir string, fileHeader *multipart.FileHeader, allowedTypes map[string]string) (*StoredFile, error) {
+	if fileHeader.Size > u.maxSize {
+		return nil, fmt.Errorf("file is too large, maximum size is %d MB", u.maxSize/1024/1024)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %v", err)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, u.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %v", err)
+	}
+	if int64(len(content)) > u.maxSize {
+		return nil, fmt.Errorf("file is too large, maximum size is %d MB", u.maxSize/1024/1024)
+	}
+
+	// Trust the content, not the client supplied header
+	contentType := http.DetectContentType(content)
+	ext, ok := allowedTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("file type %s is not allowed", contentType)
+	}
+
+	name := fmt.Sprintf("%d", time.Now().UnixNano())
+	stored := &StoredFile{
+		Path:        path.Join(dir, name+ext),
+		FileName:    fileHeader.Filename,
+		ContentType: contentType,
+		Size:        int64(len(content)),
+	}
+
+	if err := u.store.Save(stored.Path, bytes.NewReader(content), contentType); err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(contentType, "image/") {
+		thumbnail, err := utils.MakeThumbnail(bytes.NewReader(content), thumbnailSize)
+		if err != nil {
+			u.Remove(stored.Path)
+			return nil, fmt.Errorf("invalid image: %v", err)
+		}
+
+		thumbnailPath := path.Join(dir, "thumb_"+name+".jpg")
+		if err := u.store.Save(thumbnailPath, bytes.NewReader(thumbnail), "image/jpeg"); err != nil {
+			u.Remove(stored.Path)
+			return nil, err
+		}
+		stored.ThumbnailPath = &thumbnailPath
+	}
+
+	return stored, nil
+}
+
+// Remove deletes stored files, logging failures instead of returning them
+func (u *FileUploader) Remove(paths ...string) {
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		if err := u.store.Delete(p); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+}
+
+// URL returns a signed, expiring link a client can use to download a stored file.
+// Paths that are already absolute URLs are returned unchanged.
+func (u *FileUploader) URL(p string) string {
+	if strings.HasPrefix(p, "http://") || strings.HasPrefix(p, "https://") {
+		return p
+	}
+
+	url, err := u.store.SignedURL(p, u.urlTTL)
+	if err != nil {
+		log.Printf("Warning: failed to sign link for %s: %v", p, err)
+		return ""
+	}
+	return url
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
+)
+
+type NotificationService interface {
+	Notify(userID int, notificationType, title, message, referenceType string, referenceID int) error
+	ListForUser(userID int, unreadOnly bool, page, limit int) ([]models.Notification, int, error)
+	MarkAsRead(userID int, id int) error
+	MarkAllAsRead(userID int) error
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *notificationService) Notify(userID int, notificationType, title, message, referenceType string, referenceID int) error {
+	notification := &models.Notification{
+		UserID:        userID,
+		Type:          notificationType,
+		Title:         title,
+		Message:       message,
+		ReferenceType: &referenceType,
+		ReferenceID:   &referenceID,
+	}
+
+	return s.notificationRepo.Create(notification)
+}
+
+func (s *notificationService) ListForUser(userID int, unreadOnly bool, page, limit int) ([]models.Notification, int, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	return s.notificationRepo.ListByUser(userID, unreadOnly, page, limit)
+}
+
+func (s *notificationService) MarkAsRead(userID int, id int) error {
+	return s.notificationRepo.MarkAsRead(userID, id)
+}
+
+func (s *notificationService) MarkAllAsRead(userID int) error {
+	return s.notificationRepo.MarkAllAsRead(userID)
+}
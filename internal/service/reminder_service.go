@@ -0,0 +1,240 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/notifier"
+)
+
+// Sending a reminder is retried on later runs until it has failed this many times
+const maxReminderAttempts = 3
+
+type ReminderService interface {
+	CreateRule(req *models.ServiceReminderRuleCreateRequest, createdBy int) (*models.ServiceReminderRule, error)
+	GetRule(id int) (*models.ServiceReminderRule, error)
+	ListRules(activeOnly bool) ([]models.ServiceReminderRule, error)
+	UpdateRule(id int, req *models.ServiceReminderRuleUpdateRequest) (*models.ServiceReminderRule, error)
+	DeleteRule(id int) error
+	RunDueReminders() (*models.ServiceReminderRunResult, error)
+	GetReminder(id int) (*models.ServiceReminder, error)
+	ListReminders(filter models.ServiceReminderFilter, page, limit int) ([]models.ServiceReminder, int, error)
+	RecordOutcome(id int, req *models.ServiceReminderOutcomeRequest, contactedBy int) (*models.ServiceReminder, error)
+}
+
+type reminderService struct {
+	reminderRepo       repository.ReminderRepository
+	serviceCatalogRepo repository.ServiceCatalogRepository
+	appointmentRepo    repository.AppointmentRepository
+	channel            notifier.Channel
+}
+
+func NewReminderService(reminderRepo repository.ReminderRepository, serviceCatalogRepo repository.ServiceCatalogRepository, appointmentRepo repository.AppointmentRepository, channel notifier.Channel) ReminderService {
+	return &reminderService{
+		reminderRepo:       reminderRepo,
+		serviceCatalogRepo: serviceCatalogRepo,
+		appointmentRepo:    appointmentRepo,
+		channel:            channel,
+	}
+}
+
+func (s *reminderService) CreateRule(req *models.ServiceReminderRuleCreateRequest, createdBy int) (*models.ServiceReminderRule, error) {
+	rule := &models.ServiceReminderRule{
+		Name:          req.Name,
+		TriggerType:   req.TriggerType,
+		AvgKmPerMonth: req.AvgKmPerMonth,
+		DaysBefore:    req.DaysBefore,
+		ServiceID:     req.ServiceID,
+		Message:       req.Message,
+		CreatedBy:     createdBy,
+	}
+
+	// Only keep the interval the trigger counts with
+	switch req.TriggerType {
+	case models.ReminderTriggerTime:
+		if req.IntervalMonths == nil {
+			return nil, fmt.Errorf("interval months is required for time reminders")
+		}
+		rule.IntervalMonths = req.IntervalMonths
+	case models.ReminderTriggerOdometer:
+		if req.IntervalKm == nil {
+			return nil, fmt.Errorf("interval km is required for odometer reminders")
+		}
+		rule.IntervalKm = req.IntervalKm
+	}
+	if rule.AvgKmPerMonth <= 0 {
+		rule.AvgKmPerMonth = 1000
+	}
+
+	if req.ServiceID != nil {
+		if _, err := s.serviceCatalogRepo.GetByID(*req.ServiceID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.reminderRepo.CreateRule(rule); err != nil {
+		return nil, err
+	}
+
+	return s.reminderRepo.GetRuleByID(rule.ID)
+}
+
+func (s *reminderService) GetRule(id int) (*models.ServiceReminderRule, error) {
+	return s.reminderRepo.GetRuleByID(id)
+}
+
+func (s *reminderService) ListRules(activeOnly bool) ([]models.ServiceReminderRule, error) {
+	return s.reminderRepo.ListRules(activeOnly)
+}
+
+func (s *reminderService) UpdateRule(id int, req *models.ServiceReminderRuleUpdateRequest) (*models.ServiceReminderRule, error) {
+	if req.ServiceID != nil && *req.ServiceID > 0 {
+		if _, err := s.serviceCatalogRepo.GetByID(*req.ServiceID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.reminderRepo.UpdateRule(id, req)
+}
+
+func (s *reminderService) DeleteRule(id int) error {
+	return s.reminderRepo.DeleteRule(id)
+}
+
+// RunDueReminders produces the reminders due under every active rule and sends the ones not
+// delivered yet. It is safe to run more than once a day.
+func (s *reminderService) RunDueReminders() (*models.ServiceReminderRunResult, error) {
+	result := &models.ServiceReminderRunResult{}
+	today := time.Now()
+
+	rules, err := s.reminderRepo.ListRules(true)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		due, err := s.reminderRepo.FindDue(rule, today)
+		if err != nil {
+			// One broken rule should not hold back the others
+			log.Printf("Warning: failed to find reminders due for rule %s: %v", rule.Name, err)
+			continue
+		}
+
+		for j := range due {
+			reminder := &due[j]
+			reminder.Message = renderReminderMessage(rule.Message, reminder)
+			created, err := s.reminderRepo.CreateIfNew(reminder)
+			if err != nil {
+				return nil, err
+			}
+			if created {
+				result.Generated++
+			}
+		}
+	}
+
+	toSend, err := s.reminderRepo.ListToSend(maxReminderAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reminder := range toSend {
+		if err := s.send(&reminder); err != nil {
+			if markErr := s.reminderRepo.MarkFailed(reminder.ID, s.channel.Name(), err.Error()); markErr != nil {
+				return nil, markErr
+			}
+			result.Failed++
+			continue
+		}
+
+		if err := s.reminderRepo.MarkSent(reminder.ID, s.channel.Name()); err != nil {
+			return nil, err
+		}
+		result.Sent++
+	}
+
+	return result, nil
+}
+
+func (s *reminderService) GetReminder(id int) (*models.ServiceReminder, error) {
+	return s.reminderRepo.GetByID(id)
+}
+
+func (s *reminderService) ListReminders(filter models.ServiceReminderFilter, page, limit int) ([]models.ServiceReminder, int, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	return s.reminderRepo.List(filter, page, limit)
+}
+
+// RecordOutcome records how the customer responded when followed up on a reminder
+func (s *reminderService) RecordOutcome(id int, req *models.ServiceReminderOutcomeRequest, contactedBy int) (*models.ServiceReminder, error) {
+	reminder, err := s.reminderRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AppointmentID != nil {
+		if req.Outcome != models.ReminderOutcomeBooked {
+			return nil, fmt.Errorf("appointment can only be linked to a booked outcome")
+		}
+		appointment, err := s.appointmentRepo.GetByID(*req.AppointmentID)
+		if err != nil {
+			return nil, err
+		}
+		if appointment.CustomerID != reminder.CustomerID {
+			return nil, fmt.Errorf("appointment belongs to another customer")
+		}
+	}
+
+	if err := s.reminderRepo.RecordOutcome(id, req, contactedBy); err != nil {
+		return nil, err
+	}
+
+	return s.reminderRepo.GetByID(id)
+}
+
+func (s *reminderService) send(reminder *models.ServiceReminder) error {
+	if reminder.CustomerPhone == nil && reminder.CustomerEmail == nil {
+		return fmt.Errorf("customer has no phone or email")
+	}
+
+	return s.channel.Send(notifier.Message{
+		Reference: "service_reminder:" + strconv.Itoa(reminder.ID),
+		Recipient: reminder.CustomerName,
+		Phone:     reminder.CustomerPhone,
+		Email:     reminder.CustomerEmail,
+		Subject:   reminder.RuleName,
+		Body:      reminder.Message,
+	})
+}
+
+// renderReminderMessage fills in the placeholders of a rule message
+func renderReminderMessage(template string, reminder *models.ServiceReminder) string {
+	plate := ""
+	if reminder.LicensePlate != nil {
+		plate = *reminder.LicensePlate
+	}
+	dueOdometer := ""
+	if reminder.DueOdometer != nil {
+		dueOdometer = strconv.Itoa(*reminder.DueOdometer)
+	}
+
+	return strings.NewReplacer(
+		"{customer}", reminder.CustomerName,
+		"{vehicle}", reminder.VehicleModel,
+		"{plate}", plate,
+		"{due_date}", reminder.DueDate.Format("02-01-2006"),
+		"{due_odometer}", dueOdometer,
+	).Replace(template)
+}
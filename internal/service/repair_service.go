@@ -1,7 +1,14 @@
 package service
 
 import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
 	"fmt"
+	"math"
+	"mime/multipart"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
@@ -13,36 +20,109 @@ type RepairService interface {
 	GetRepairOrder(id int) (*models.RepairOrder, error)
 	GetRepairOrderByCode(code string) (*models.RepairOrder, error)
 	ListRepairOrders(filter models.RepairOrderFilter, page, limit int) ([]models.RepairOrder, int, error)
-	UpdateRepairOrder(id int, request *models.RepairOrderUpdateRequest) error
-	UpdateRepairProgress(id int, request *models.RepairProgressUpdateRequest) error
+	UpdateRepairOrder(id int, request *models.RepairOrderUpdateRequest, changedBy int) error
+	UpdateRepairProgress(id int, request *models.RepairProgressUpdateRequest, changedBy int) error
+	GetRepairStatusHistory(repairID int) ([]models.RepairStatusHistory, error)
+	GetStatusTransitions() ([]models.RepairStatusTransition, error)
+	UpdateStatusTransitions(request *models.RepairStatusTransitionsUpdateRequest) ([]models.RepairStatusTransition, error)
+	CancelRepairOrder(id int, request *models.RepairCancelRequest, cancelledBy int) (*models.RepairOrder, error)
 	DeleteRepairOrder(id int) error
 
+	// Customer service orders
+	CreateServiceOrder(request *models.ServiceOrderCreateRequest, assignedBy int) (*models.RepairOrder, error)
+	CreateWarrantyOrder(request *models.ServiceOrderCreateRequest, assignedBy int) (*models.RepairOrder, error)
+	GetServiceEstimate(repairID int) (*models.ServiceEstimate, error)
+	DecideEstimate(repairID int, request *models.EstimateDecisionRequest) (*models.RepairOrder, error)
+	CreateEstimateLink(repairID int, createdBy int) (*models.RepairEstimateLink, error)
+	GetPublicEstimate(token string) (*models.ServiceEstimate, error)
+	DecidePublicEstimate(token string, request *models.EstimateDecisionRequest) (*models.ServiceEstimate, error)
+	TrackRepair(code string, phoneDigits string) (*models.RepairTracking, error)
+
 	// Spare parts management
 	AddSparePartToRepair(repairID int, request *models.RepairSparePartCreateRequest) error
 	RemoveSparePartFromRepair(repairID int, sparePartID int) error
 	GetRepairSpareParts(repairID int) ([]models.RepairSparePart, error)
 
+	// Labor management
+	AddLaborLine(repairID int, request *models.RepairLaborLineCreateRequest) (*models.RepairLaborLine, error)
+	RemoveLaborLine(repairID int, lineID int) error
+	GetRepairLaborLines(repairID int) ([]models.RepairLaborLine, error)
+
+	// Inspection checklist
+	AttachChecklist(repairID int, request *models.RepairChecklistAttachRequest) ([]models.RepairChecklistItem, error)
+	GetRepairChecklist(repairID int) ([]models.RepairChecklistItem, error)
+	UpdateChecklistItem(repairID int, itemID int, request *models.RepairChecklistItemUpdateRequest, checkedBy int) (*models.RepairChecklistItem, error)
+
+	// Photos and documents
+	UploadRepairAttachment(repairID int, request *models.RepairAttachmentUploadRequest, file *multipart.FileHeader, uploadedBy int) (*models.RepairAttachment, error)
+	GetRepairAttachments(repairID int) ([]models.RepairAttachment, error)
+	DeleteRepairAttachment(repairID int, attachmentID int) error
+
+	// Time tracking
+	StartRepairTimer(repairID int, mechanicID int, request *models.RepairTimerRequest) (*models.RepairWorkSession, error)
+	PauseRepairTimer(repairID int, mechanicID int, request *models.RepairTimerRequest) (*models.RepairWorkSession, error)
+	StopRepairTimer(repairID int, mechanicID int, request *models.RepairTimerRequest) (*models.RepairWorkSession, error)
+	GetRepairTimeSummary(repairID int) (*models.RepairTimeSummary, error)
+
+	// Workshop scheduling
+	ScheduleRepair(repairID int, request *models.RepairScheduleRequest, scheduledBy int) (*models.RepairSchedule, error)
+	UnscheduleRepair(repairID int) error
+
+	// Spare part reservations
+	ReserveSparePart(repairID int, request *models.SparePartReservationCreateRequest, reservedBy int) (*models.SparePartReservation, error)
+	ReleaseReservation(repairID int, reservationID int) error
+	GetRepairReservations(repairID int) ([]models.SparePartReservation, error)
+
 	// Statistics and reporting
-	GetRepairStats(mechanicID *int, dateFrom, dateTo *time.Time) (map[string]interface{}, error)
-	GetMechanicWorkload() ([]map[string]interface{}, error)
+	GetRepairStats(mechanicID *int, dateFrom, dateTo *time.Time, orderType models.RepairOrderType) (map[string]interface{}, error)
+	GetMechanicWorkload() ([]models.MechanicWorkload, error)
+	GetMechanicUtilization(dateFrom, dateTo time.Time, hoursPerDay float64, orderType models.RepairOrderType) ([]models.MechanicUtilization, error)
+	GetTurnaroundReport(dateFrom, dateTo time.Time, orderType models.RepairOrderType) (*models.RepairTurnaroundReport, error)
+
+	// SLA alerts
+	AlertOverdueRepairs() (int, error)
 
 	// Vehicle management
-	GetVehiclesNeedingRepairOrders() ([]models.Vehicle, error)
+	GetVehiclesNeedingRepairOrders(filter models.VehicleRepairNeedFilter, page, limit int) ([]models.Vehicle, int, error)
 }
 
 type repairService struct {
-	repairRepo    repository.RepairRepository
-	vehicleRepo   repository.VehicleRepository
-	userRepo      repository.UserRepository
-	sparePartRepo repository.SparePartRepository
+	repairRepo          repository.RepairRepository
+	vehicleRepo         repository.VehicleRepository
+	userRepo            repository.UserRepository
+	sparePartRepo       repository.SparePartRepository
+	customerVehicleRepo repository.CustomerVehicleRepository
+	serviceCatalogRepo  repository.ServiceCatalogRepository
+	checklistRepo       repository.ChecklistTemplateRepository
+	skillRepo           repository.SkillRepository
+	workshopRepo        repository.WorkshopRepository
+	notificationSvc     NotificationService
+	fileUploader        *FileUploader
+	skillCheckMode      models.SkillCheckMode
+	estimateLinkTTL     time.Duration
+	slaDays             int
+	slaAtRiskDays       int
+	trackingFailures    *attemptCounter
 }
 
-func NewRepairService(repairRepo repository.RepairRepository, vehicleRepo repository.VehicleRepository, userRepo repository.UserRepository, sparePartRepo repository.SparePartRepository) RepairService {
+func NewRepairService(repairRepo repository.RepairRepository, vehicleRepo repository.VehicleRepository, userRepo repository.UserRepository, sparePartRepo repository.SparePartRepository, customerVehicleRepo repository.CustomerVehicleRepository, serviceCatalogRepo repository.ServiceCatalogRepository, checklistRepo repository.ChecklistTemplateRepository, skillRepo repository.SkillRepository, workshopRepo repository.WorkshopRepository, notificationSvc NotificationService, fileUploader *FileUploader, skillCheckMode models.SkillCheckMode, estimateLinkTTL time.Duration, slaDays int, slaAtRiskDays int) RepairService {
 	return &repairService{
-		repairRepo:    repairRepo,
-		vehicleRepo:   vehicleRepo,
-		userRepo:      userRepo,
-		sparePartRepo: sparePartRepo,
+		repairRepo:          repairRepo,
+		vehicleRepo:         vehicleRepo,
+		userRepo:            userRepo,
+		sparePartRepo:       sparePartRepo,
+		customerVehicleRepo: customerVehicleRepo,
+		serviceCatalogRepo:  serviceCatalogRepo,
+		checklistRepo:       checklistRepo,
+		skillRepo:           skillRepo,
+		workshopRepo:        workshopRepo,
+		notificationSvc:     notificationSvc,
+		fileUploader:        fileUploader,
+		skillCheckMode:      skillCheckMode,
+		estimateLinkTTL:     estimateLinkTTL,
+		slaDays:             slaDays,
+		slaAtRiskDays:       slaAtRiskDays,
+		trackingFailures:    newAttemptCounter(maxTrackingFailures, trackingLockout),
 	}
 }
 
@@ -58,29 +138,38 @@ func (s *repairService) CreateRepairOrder(request *models.RepairOrderCreateReque
 		return nil, fmt.Errorf("cannot repair sold vehicle")
 	}
 
-	// Validate mechanic exists and has correct role
-	_, err = s.userRepo.GetByID(request.MechanicID)
+	// Skills are matched against the vehicle type of the brand
+	vehicleTypeID := 0
+	if vehicle.Brand != nil {
+		vehicleTypeID = vehicle.Brand.TypeID
+	}
+
+	mechanicID, warnings, err := s.assignMechanic(request.MechanicID, vehicleTypeID, request.ServiceCategories)
 	if err != nil {
-		return nil, fmt.Errorf("mechanic not found: %v", err)
+		return nil, err
 	}
+	request.MechanicID = mechanicID
 
-	// if mechanic.RoleID != 3 { // Assuming role_id 3 is mechanic
-	// 	return nil, fmt.Errorf("assigned user is not a mechanic")
-	// }
+	targetDate, err := s.targetCompletionDate(request.TargetCompletionDate, vehicleTypeID, request.ServiceCategories)
+	if err != nil {
+		return nil, err
+	}
 
 	// Generate repair order code
 	code := s.generateRepairCode()
 
 	// Create repair order
 	repair := &models.RepairOrder{
-		Code:          code,
-		VehicleID:     request.VehicleID,
-		MechanicID:    request.MechanicID,
-		AssignedBy:    assignedBy,
-		Description:   request.Description,
-		EstimatedCost: request.EstimatedCost,
-		Status:        models.RepairStatusPending,
-		Notes:         request.Notes,
+		Code:                 code,
+		OrderType:            models.RepairOrderTypeReconditioning,
+		VehicleID:            &request.VehicleID,
+		MechanicID:           request.MechanicID,
+		AssignedBy:           assignedBy,
+		Description:          request.Description,
+		EstimatedCost:        request.EstimatedCost,
+		Status:               models.RepairStatusPending,
+		Notes:                request.Notes,
+		TargetCompletionDate: targetDate,
 	}
 
 	err = s.repairRepo.Create(repair)
@@ -95,11 +184,423 @@ func (s *repairService) CreateRepairOrder(request *models.RepairOrderCreateReque
 	}
 
 	// Get complete repair order with relationships
-	return s.repairRepo.GetByID(repair.ID)
+	created, err := s.repairRepo.GetByID(repair.ID)
+	if err != nil {
+		return nil, err
+	}
+	created.Warnings = warnings
+
+	return created, nil
+}
+
+func (s *repairService) CreateServiceOrder(request *models.ServiceOrderCreateRequest, assignedBy int) (*models.RepairOrder, error) {
+	return s.createServiceOrder(request, assignedBy, false)
+}
+
+// CreateWarrantyOrder opens a customer service order for after-sales warranty work.
+// The showroom pays for it, so there is no estimate for the customer to approve.
+func (s *repairService) CreateWarrantyOrder(request *models.ServiceOrderCreateRequest, assignedBy int) (*models.RepairOrder, error) {
+	return s.createServiceOrder(request, assignedBy, true)
+}
+
+func (s *repairService) createServiceOrder(request *models.ServiceOrderCreateRequest, assignedBy int, warranty bool) (*models.RepairOrder, error) {
+	customerVehicle, err := s.customerVehicleRepo.GetByID(request.CustomerVehicleID)
+	if err != nil {
+		return nil, err
+	}
+
+	mechanicID, warnings, err := s.assignMechanic(request.MechanicID, customerVehicle.VehicleTypeID, request.ServiceCategories)
+	if err != nil {
+		return nil, err
+	}
+
+	targetDate, err := s.targetCompletionDate(request.TargetCompletionDate, customerVehicle.VehicleTypeID, request.ServiceCategories)
+	if err != nil {
+		return nil, err
+	}
+
+	// Work only starts once the customer approves the estimate
+	estimateStatus := models.EstimateStatusPending
+	if warranty {
+		estimateStatus = models.EstimateStatusApproved
+	}
+	repair := &models.RepairOrder{
+		Code:                 s.generateRepairCode(),
+		OrderType:            models.RepairOrderTypeCustomerService,
+		CustomerID:           &customerVehicle.CustomerID,
+		CustomerVehicleID:    &customerVehicle.ID,
+		MechanicID:           mechanicID,
+		AssignedBy:           assignedBy,
+		Description:          request.Description,
+		EstimatedCost:        request.EstimatedCost,
+		Status:               models.RepairStatusPending,
+		Notes:                request.Notes,
+		EstimateStatus:       &estimateStatus,
+		TargetCompletionDate: targetDate,
+		IsWarranty:           warranty,
+	}
+
+	err = s.repairRepo.Create(repair)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create service order: %v", err)
+	}
+
+	created, err := s.repairRepo.GetByID(repair.ID)
+	if err != nil {
+		return nil, err
+	}
+	created.Warnings = warnings
+
+	return created, nil
+}
+
+func (s *repairService) GetServiceEstimate(repairID int) (*models.ServiceEstimate, error) {
+	repair, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	if repair.OrderType != models.RepairOrderTypeCustomerService {
+		return nil, fmt.Errorf("repair order %s is not a customer service order", repair.Code)
+	}
+
+	estimate := &models.ServiceEstimate{
+		RepairOrderID:     repair.ID,
+		RepairCode:        repair.Code,
+		Description:       repair.Description,
+		Lines:             []models.ServiceEstimateLine{},
+		EstimateStatus:    repair.EstimateStatus,
+		EstimateDecidedAt: repair.EstimateDecidedAt,
+		EstimateDecidedBy: repair.EstimateDecidedBy,
+	}
+	if repair.Customer != nil {
+		estimate.CustomerName = repair.Customer.Name
+	}
+	if repair.CustomerVehicle != nil {
+		estimate.VehicleModel = repair.CustomerVehicle.BrandName + " " + repair.CustomerVehicle.Model
+		estimate.LicensePlate = repair.CustomerVehicle.LicensePlate
+	}
+
+	// Parts already used plus parts still reserved for the job
+	for _, part := range repair.SpareParts {
+		estimate.Lines = append(estimate.Lines, models.ServiceEstimateLine{
+			ItemType:    "part",
+			Description: sparePartName(part.SparePart, part.SparePartID),
+			Quantity:    float64(part.QuantityUsed),
+			UnitPrice:   part.UnitPrice,
+			TotalPrice:  part.TotalPrice,
+		})
+		estimate.PartsTotal += part.TotalPrice
+	}
+	for _, reservation := range repair.Reservations {
+		remaining := reservation.Quantity - reservation.ConsumedQuantity
+		if remaining <= 0 {
+			continue
+		}
+		unitPrice := 0.0
+		if reservation.SparePart != nil {
+			unitPrice = reservation.SparePart.SellingPrice
+		}
+		estimate.Lines = append(estimate.Lines, models.ServiceEstimateLine{
+			ItemType:    "part",
+			Description: sparePartName(reservation.SparePart, reservation.SparePartID),
+			Quantity:    float64(remaining),
+			UnitPrice:   unitPrice,
+			TotalPrice:  unitPrice * float64(remaining),
+		})
+		estimate.PartsTotal += unitPrice * float64(remaining)
+	}
+	for _, line := range repair.LaborLines {
+		estimate.Lines = append(estimate.Lines, models.ServiceEstimateLine{
+			ItemType:    "labor",
+			Description: line.Description,
+			Quantity:    line.Hours,
+			UnitPrice:   line.LaborRate,
+			TotalPrice:  line.TotalPrice,
+		})
+		estimate.LaborTotal += line.TotalPrice
+	}
+
+	estimate.Total = estimate.PartsTotal + estimate.LaborTotal
+	if len(estimate.Lines) == 0 {
+		// Nothing itemized yet, fall back to the rough estimate
+		estimate.Total = repair.EstimatedCost
+	}
+
+	return estimate, nil
+}
+
+func (s *repairService) DecideEstimate(repairID int, request *models.EstimateDecisionRequest) (*models.RepairOrder, error) {
+	repair, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	if repair.OrderType != models.RepairOrderTypeCustomerService {
+		return nil, fmt.Errorf("repair order %s is not a customer service order", repair.Code)
+	}
+
+	if repair.Status != models.RepairStatusPending {
+		return nil, fmt.Errorf("cannot decide estimate of repair order in %s status", repair.Status)
+	}
+
+	status := models.EstimateStatusApproved
+	if !request.Approved {
+		status = models.EstimateStatusRejected
+
+		// A rejected estimate ends the job
+		err = s.validateStatusTransition(repair, models.RepairStatusCancelled)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The cancellation of a rejected estimate is saved with the decision
+	err = s.repairRepo.DecideEstimate(repairID, status, request.DecidedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == models.EstimateStatusRejected {
+		err = s.releaseReservationsIfClosed(repairID, models.RepairStatusCancelled)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.notify(repair.MechanicID, models.NotificationEstimateDecided, "Estimate "+string(status),
+		fmt.Sprintf("The customer %s the estimate of repair order %s", status, repair.Code), repair.ID)
+
+	return s.repairRepo.GetByID(repairID)
+}
+
+func (s *repairService) CreateEstimateLink(repairID int, createdBy int) (*models.RepairEstimateLink, error) {
+	repair, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	if repair.OrderType != models.RepairOrderTypeCustomerService {
+		return nil, fmt.Errorf("repair order %s is not a customer service order", repair.Code)
+	}
+
+	if repair.EstimateStatus == nil || *repair.EstimateStatus != models.EstimateStatusPending {
+		return nil, fmt.Errorf("estimate is not awaiting a decision")
+	}
+
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate link token: %v", err)
+	}
+
+	link := &models.RepairEstimateLink{
+		RepairOrderID: repairID,
+		Token:         hex.EncodeToString(tokenBytes),
+		ExpiresAt:     time.Now().Add(s.estimateLinkTTL),
+		CreatedBy:     createdBy,
+	}
+
+	err = s.repairRepo.CreateEstimateLink(link)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create estimate link: %v", err)
+	}
+	link.URL = "/api/public/estimates/" + link.Token
+
+	return link, nil
+}
+
+func (s *repairService) GetPublicEstimate(token string) (*models.ServiceEstimate, error) {
+	link, err := s.validEstimateLink(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetServiceEstimate(link.RepairOrderID)
+}
+
+func (s *repairService) DecidePublicEstimate(token string, request *models.EstimateDecisionRequest) (*models.ServiceEstimate, error) {
+	link, err := s.validEstimateLink(token)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.DecideEstimate(link.RepairOrderID, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetServiceEstimate(link.RepairOrderID)
+}
+
+// validEstimateLink looks up a public estimate link and rejects expired ones
+func (s *repairService) validEstimateLink(token string) (*models.RepairEstimateLink, error) {
+	link, err := s.repairRepo.GetEstimateLink(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(link.ExpiresAt) {
+		return nil, fmt.Errorf("estimate link has expired")
+	}
+
+	return link, nil
+}
+
+func (s *repairService) TrackRepair(code string, phoneDigits string) (*models.RepairTracking, error) {
+	verification := digitsOnly(phoneDigits)
+	if len(verification) < 4 {
+		return nil, fmt.Errorf("enter at least the last 4 digits of the phone number")
+	}
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if s.trackingFailures.Blocked(code) {
+		return nil, fmt.Errorf("too many attempts for this repair order, try again later")
+	}
+
+	// Unknown codes and wrong phone digits get the same answer so codes cannot be probed.
+	// Failures count per code, so the digits cannot be guessed from many client IPs either.
+	notFound := fmt.Errorf("repair order not found")
+
+	found, err := s.repairRepo.GetByCode(code)
+	if err != nil {
+		s.trackingFailures.Fail(code)
+		return nil, notFound
+	}
+
+	repair, err := s.repairRepo.GetByID(found.ID)
+	if err != nil {
+		return nil, notFound
+	}
+
+	if repair.Customer == nil || repair.Customer.Phone == nil ||
+		!strings.HasSuffix(digitsOnly(*repair.Customer.Phone), verification) {
+		s.trackingFailures.Fail(code)
+		return nil, notFound
+	}
+
+	tracking := &models.RepairTracking{
+		Code:      repair.Code,
+		Status:    string(repair.Status),
+		UpdatedAt: repair.UpdatedAt,
+	}
+	if repair.CustomerVehicle != nil {
+		tracking.Vehicle = strings.TrimSpace(repair.CustomerVehicle.BrandName + " " + repair.CustomerVehicle.Model)
+		tracking.LicensePlate = repair.CustomerVehicle.LicensePlate
+	}
+	if repair.Schedule != nil && isRepairOpen(repair.Status) {
+		tracking.ExpectedCompletion = &repair.Schedule.ScheduledEnd
+	}
+
+	createdAt := repair.CreatedAt
+	tracking.Timeline = []models.RepairTrackingStep{
+		{Stage: "received", Label: "Received", Reached: true, ReachedAt: &createdAt},
+	}
+
+	if repair.OrderType == models.RepairOrderTypeCustomerService {
+		approved := repair.EstimateStatus != nil && *repair.EstimateStatus == models.EstimateStatusApproved
+		step := models.RepairTrackingStep{Stage: "approved", Label: "Estimate approved", Reached: approved}
+		if approved {
+			step.ReachedAt = repair.EstimateDecidedAt
+		}
+		tracking.Timeline = append(tracking.Timeline, step)
+	}
+
+	tracking.Timeline = append(tracking.Timeline, models.RepairTrackingStep{
+		Stage: "in_progress", Label: "In progress", Reached: repair.StartedAt != nil, ReachedAt: repair.StartedAt,
+	})
+
+	// Waiting for parts and quality check only show up once the job went through them
+	for _, stage := range []models.RepairStatus{models.RepairStatusWaitingParts, models.RepairStatusQualityCheck} {
+		var reachedAt *time.Time
+		for i := range repair.StatusHistory {
+			if repair.StatusHistory[i].ToStatus == stage {
+				reachedAt = &repair.StatusHistory[i].ChangedAt
+			}
+		}
+		if reachedAt != nil {
+			tracking.Timeline = append(tracking.Timeline, models.RepairTrackingStep{
+				Stage: string(stage), Label: trackingLabels[stage], Reached: true, ReachedAt: reachedAt,
+			})
+		}
+	}
+
+	if repair.Status == models.RepairStatusCancelled {
+		updatedAt := repair.UpdatedAt
+		tracking.Timeline = append(tracking.Timeline, models.RepairTrackingStep{
+			Stage: "cancelled", Label: "Cancelled", Reached: true, ReachedAt: &updatedAt,
+		})
+	} else {
+		tracking.Timeline = append(tracking.Timeline, models.RepairTrackingStep{
+			Stage: "done", Label: "Done", Reached: repair.CompletedAt != nil, ReachedAt: repair.CompletedAt,
+		})
+	}
+
+	tracking.StatusLabel = trackingLabels[repair.Status]
+
+	return tracking, nil
+}
+
+func (s *repairService) GetRepairStatusHistory(repairID int) ([]models.RepairStatusHistory, error) {
+	_, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	return s.repairRepo.GetStatusHistory(repairID)
+}
+
+func (s *repairService) GetStatusTransitions() ([]models.RepairStatusTransition, error) {
+	return s.repairRepo.GetStatusTransitions()
+}
+
+func (s *repairService) UpdateStatusTransitions(request *models.RepairStatusTransitionsUpdateRequest) ([]models.RepairStatusTransition, error) {
+	known := map[models.RepairStatus]bool{
+		models.RepairStatusPending:      true,
+		models.RepairStatusApproved:     true,
+		models.RepairStatusInProgress:   true,
+		models.RepairStatusWaitingParts: true,
+		models.RepairStatusQualityCheck: true,
+		models.RepairStatusOnHold:       true,
+		models.RepairStatusCompleted:    true,
+		models.RepairStatusCancelled:    true,
+	}
+
+	for _, transition := range request.Transitions {
+		if !known[transition.FromStatus] || !known[transition.ToStatus] {
+			return nil, fmt.Errorf("unknown repair status in transition %s -> %s", transition.FromStatus, transition.ToStatus)
+		}
+		if transition.FromStatus == transition.ToStatus {
+			return nil, fmt.Errorf("transition from %s to itself is not allowed", transition.FromStatus)
+		}
+		// Approval only comes from the customer's estimate decision
+		if transition.ToStatus == models.RepairStatusApproved {
+			return nil, fmt.Errorf("approved status is set by the customer's estimate decision")
+		}
+	}
+
+	err := s.repairRepo.ReplaceStatusTransitions(request.Transitions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update status transitions: %v", err)
+	}
+
+	return s.repairRepo.GetStatusTransitions()
 }
 
 func (s *repairService) GetRepairOrder(id int) (*models.RepairOrder, error) {
-	return s.repairRepo.GetByID(id)
+	repair, err := s.repairRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attach the workshop slot when the repair is scheduled
+	if schedule, err := s.workshopRepo.GetScheduleByRepair(id); err == nil {
+		repair.Schedule = schedule
+	}
+
+	s.setAttachmentURLs(repair.Attachments)
+	repair.SLAStatus = repairSLAStatus(repair, time.Now(), s.slaAtRiskDays)
+
+	return repair, nil
 }
 
 func (s *repairService) GetRepairOrderByCode(code string) (*models.RepairOrder, error) {
@@ -114,10 +615,20 @@ func (s *repairService) ListRepairOrders(filter models.RepairOrderFilter, page,
 		limit = 10
 	}
 
-	return s.repairRepo.List(filter, page, limit)
+	repairs, total, err := s.repairRepo.List(filter, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := time.Now()
+	for i := range repairs {
+		repairs[i].SLAStatus = repairSLAStatus(&repairs[i], now, s.slaAtRiskDays)
+	}
+
+	return repairs, total, nil
 }
 
-func (s *repairService) UpdateRepairOrder(id int, request *models.RepairOrderUpdateRequest) error {
+func (s *repairService) UpdateRepairOrder(id int, request *models.RepairOrderUpdateRequest, changedBy int) error {
 	// Check if repair order exists
 	repair, err := s.repairRepo.GetByID(id)
 	if err != nil {
@@ -126,16 +637,35 @@ func (s *repairService) UpdateRepairOrder(id int, request *models.RepairOrderUpd
 
 	// Validate status transition if status is being updated
 	if request.Status != nil {
-		err = s.validateStatusTransition(repair.Status, *request.Status)
+		err = s.validateStatusTransition(repair, *request.Status)
+		if err != nil {
+			return err
+		}
+	}
+
+	if request.TargetCompletionDate != nil {
+		if _, err := time.Parse("2006-01-02", *request.TargetCompletionDate); err != nil {
+			return fmt.Errorf("invalid target completion date, use YYYY-MM-DD")
+		}
+	}
+
+	err = s.repairRepo.Update(id, request, &models.RepairStatusChange{ChangedBy: &changedBy, Note: request.Reason})
+	if err != nil {
+		return err
+	}
+
+	if request.Status != nil {
+		err = s.releaseReservationsIfClosed(id, *request.Status)
 		if err != nil {
 			return err
 		}
+		return s.syncVehicle(repair, *request.Status)
 	}
 
-	return s.repairRepo.Update(id, request)
+	return nil
 }
 
-func (s *repairService) UpdateRepairProgress(id int, request *models.RepairProgressUpdateRequest) error {
+func (s *repairService) UpdateRepairProgress(id int, request *models.RepairProgressUpdateRequest, changedBy int) error {
 	// Check if repair order exists
 	repair, err := s.repairRepo.GetByID(id)
 	if err != nil {
@@ -145,7 +675,7 @@ func (s *repairService) UpdateRepairProgress(id int, request *models.RepairProgr
 	fmt.Printf("UpdateRepairProgress service: repair found ID=%d, current status=%s\n", repair.ID, repair.Status)
 
 	// Validate status transition
-	err = s.validateStatusTransition(repair.Status, request.Status)
+	err = s.validateStatusTransition(repair, request.Status)
 	if err != nil {
 		return err
 	}
@@ -153,57 +683,61 @@ func (s *repairService) UpdateRepairProgress(id int, request *models.RepairProgr
 	fmt.Printf("UpdateRepairProgress service: updating to status=%s\n", request.Status)
 
 	// Update repair progress
-	err = s.repairRepo.UpdateProgress(id, request)
+	err = s.repairRepo.UpdateProgress(id, request, &models.RepairStatusChange{ChangedBy: &changedBy, Note: request.Reason})
 	if err != nil {
 		return fmt.Errorf("failed to update repair progress: %v", err)
 	}
 
 	fmt.Printf("UpdateRepairProgress service: repair progress updated successfully\n")
 
-	// Update vehicle status based on repair status
-	var vehicleStatus models.VehicleStatus
-	switch request.Status {
-	case models.RepairStatusCompleted:
-		vehicleStatus = models.VehicleStatusAvailable
-
-		// Always calculate repair cost from spare parts used only
-		spareParts, err := s.repairRepo.GetSpareParts(id)
-		if err != nil {
-			return fmt.Errorf("failed to get spare parts for cost calculation: %v", err)
-		}
-
-		totalSparePartsCost := 0.0
-		for _, part := range spareParts {
-			totalSparePartsCost += part.UnitPrice * float64(part.QuantityUsed)
-		}
+	err = s.releaseReservationsIfClosed(id, request.Status)
+	if err != nil {
+		return err
+	}
 
-		// Update vehicle repair cost with spare parts total only
-		err = s.vehicleRepo.UpdateRepairCost(repair.VehicleID, totalSparePartsCost)
-		if err != nil {
-			return fmt.Errorf("failed to update vehicle repair cost: %v", err)
-		}
+	return s.syncVehicle(repair, request.Status)
+}
 
-		fmt.Printf("UpdateRepairProgress service: vehicle repair cost updated to %.2f (from spare parts only)\n", totalSparePartsCost)
+func (s *repairService) CancelRepairOrder(id int, request *models.RepairCancelRequest, cancelledBy int) (*models.RepairOrder, error) {
+	repair, err := s.repairRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
 
-	case models.RepairStatusCancelled:
-		vehicleStatus = models.VehicleStatusAvailable
+	err = s.validateStatusTransition(repair, models.RepairStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
 
-	case models.RepairStatusInProgress:
-		vehicleStatus = models.VehicleStatusInRepair
+	// Only part lines still in use on this order can be settled
+	inUse := make(map[int]bool)
+	for _, part := range repair.SpareParts {
+		if part.Disposition == nil {
+			inUse[part.ID] = true
+		}
+	}
+	for _, line := range request.Parts {
+		if !inUse[line.RepairSparePartID] {
+			return nil, fmt.Errorf("spare part line %d is not in use on repair order %s", line.RepairSparePartID, repair.Code)
+		}
+	}
 
-	default:
-		// No vehicle status update needed for pending
-		return nil
+	err = s.repairRepo.Cancel(id, request.Parts, &models.RepairStatusChange{ChangedBy: &cancelledBy, Note: request.Reason})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel repair order: %v", err)
 	}
 
-	err = s.vehicleRepo.UpdateStatus(repair.VehicleID, string(vehicleStatus))
+	err = s.releaseReservationsIfClosed(id, models.RepairStatusCancelled)
 	if err != nil {
-		return fmt.Errorf("failed to update vehicle status: %v", err)
+		return nil, err
 	}
 
-	fmt.Printf("UpdateRepairProgress service: vehicle status updated to %s\n", vehicleStatus)
+	err = s.syncVehicle(repair, models.RepairStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	return s.repairRepo.GetByID(id)
 }
 
 func (s *repairService) DeleteRepairOrder(id int) error {
@@ -213,8 +747,8 @@ func (s *repairService) DeleteRepairOrder(id int) error {
 		return fmt.Errorf("repair order not found: %v", err)
 	}
 
-	// Only allow deletion if repair is pending or cancelled
-	if repair.Status != models.RepairStatusPending && repair.Status != models.RepairStatusCancelled {
+	// Only allow deletion if work has not started or the repair is cancelled
+	if repair.Status != models.RepairStatusPending && repair.Status != models.RepairStatusApproved && repair.Status != models.RepairStatusCancelled {
 		return fmt.Errorf("cannot delete repair order in %s status", repair.Status)
 	}
 
@@ -223,12 +757,18 @@ func (s *repairService) DeleteRepairOrder(id int) error {
 		return fmt.Errorf("failed to delete repair order: %v", err)
 	}
 
-	// Update vehicle status back to available if it was in repair
-	if repair.Status == models.RepairStatusPending {
-		err = s.vehicleRepo.UpdateStatus(repair.VehicleID, string(models.VehicleStatusAvailable))
+	// Attachment rows are removed by the cascade, their files are not
+	for _, attachment := range repair.Attachments {
+		s.removeAttachmentFiles(attachment)
+	}
+
+	// Parts kept as cost on a cancelled order leave the vehicle cost with it
+	if repair.VehicleID != nil {
+		err = s.updateVehicleRepairCost(*repair.VehicleID)
 		if err != nil {
-			return fmt.Errorf("failed to update vehicle status: %v", err)
+			return err
 		}
+		return s.restoreVehicleStatus(*repair.VehicleID)
 	}
 
 	return nil
@@ -236,11 +776,15 @@ func (s *repairService) DeleteRepairOrder(id int) error {
 
 func (s *repairService) AddSparePartToRepair(repairID int, request *models.RepairSparePartCreateRequest) error {
 	// Check if repair order exists
-	_, err := s.repairRepo.GetByID(repairID)
+	repair, err := s.repairRepo.GetByID(repairID)
 	if err != nil {
 		return fmt.Errorf("repair order not found: %v", err)
 	}
 
+	if !isRepairOpen(repair.Status) {
+		return fmt.Errorf("cannot add spare parts to repair order in %s status", repair.Status)
+	}
+
 	// Check if spare part exists
 	_, err = s.sparePartRepo.GetByID(request.SparePartID)
 	if err != nil {
@@ -270,60 +814,778 @@ func (s *repairService) GetRepairSpareParts(repairID int) ([]models.RepairSpareP
 	return s.repairRepo.GetSpareParts(repairID)
 }
 
-func (s *repairService) GetRepairStats(mechanicID *int, dateFrom, dateTo *time.Time) (map[string]interface{}, error) {
-	return s.repairRepo.GetRepairStats(mechanicID, dateFrom, dateTo)
-}
-
-func (s *repairService) GetMechanicWorkload() ([]map[string]interface{}, error) {
-	// This would need a separate query to get mechanics and their current workload
-	// For now, return empty slice
-	return []map[string]interface{}{}, nil
-}
-
-func (s *repairService) GetVehiclesNeedingRepairOrders() ([]models.Vehicle, error) {
-	// Get all vehicles with in_repair status
-	inRepairStatus := models.VehicleStatusInRepair
-	vehicles, _, err := s.vehicleRepo.List(1, 100, &inRepairStatus) // Get up to 100 vehicles
-
+func (s *repairService) AddLaborLine(repairID int, request *models.RepairLaborLineCreateRequest) (*models.RepairLaborLine, error) {
+	// Check if repair order exists and is still open
+	repair, err := s.repairRepo.GetByID(repairID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get vehicles: %v", err)
+		return nil, fmt.Errorf("repair order not found: %v", err)
 	}
 
-	var vehiclesNeedingOrders []models.Vehicle
+	if !isRepairOpen(repair.Status) {
+		return nil, fmt.Errorf("cannot add labor to repair order in %s status", repair.Status)
+	}
 
-	// Check each vehicle to see if it has an active repair order
-	for _, vehicle := range vehicles {
-		// Check if vehicle has an active repair order (pending or in_progress)
-		repairs, _, err := s.repairRepo.List(models.RepairOrderFilter{
-			VehicleID: vehicle.ID,
-			Status:    "", // Get all statuses
-		}, 1, 10)
+	line := &models.RepairLaborLine{
+		RepairOrderID: repairID,
+		ServiceID:     request.ServiceID,
+	}
 
+	// Take defaults from the catalog entry when one is selected
+	if request.ServiceID != nil {
+		catalogEntry, err := s.serviceCatalogRepo.GetByID(*request.ServiceID)
 		if err != nil {
-			fmt.Printf("Error checking repairs for vehicle %d: %v\n", vehicle.ID, err)
-			continue
+			return nil, fmt.Errorf("service not found: %v", err)
 		}
-
-		// Check if there's any active repair order (pending or in_progress)
-		hasActiveRepair := false
-		for _, repair := range repairs {
-			if repair.Status == models.RepairStatusPending || repair.Status == models.RepairStatusInProgress {
-				hasActiveRepair = true
-				break
-			}
+		if !catalogEntry.IsActive {
+			return nil, fmt.Errorf("service %s is inactive", catalogEntry.Code)
 		}
 
-		// If no active repair order, add to list
-		if !hasActiveRepair {
-			vehiclesNeedingOrders = append(vehiclesNeedingOrders, vehicle)
-		}
+		line.Description = catalogEntry.Name
+		line.Hours = catalogEntry.StandardHours
+		line.LaborRate = catalogEntry.LaborRate
 	}
 
-	return vehiclesNeedingOrders, nil
-}
-
+	if request.Description != nil && *request.Description != "" {
+		line.Description = *request.Description
+	}
+	if request.Hours != nil {
+		line.Hours = *request.Hours
+	}
+	if request.LaborRate != nil {
+		line.LaborRate = *request.LaborRate
+	}
+
+	if line.Description == "" {
+		return nil, fmt.Errorf("labor description is required")
+	}
+	if line.Hours <= 0 {
+		return nil, fmt.Errorf("labor hours must be greater than zero")
+	}
+	if line.LaborRate < 0 {
+		return nil, fmt.Errorf("labor rate cannot be negative")
+	}
+
+	line.TotalPrice = line.Hours * line.LaborRate
+
+	err = s.repairRepo.AddLaborLine(line)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add labor line: %v", err)
+	}
+
+	return line, nil
+}
+
+func (s *repairService) RemoveLaborLine(repairID int, lineID int) error {
+	// Check if repair order exists and is still open
+	repair, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return fmt.Errorf("repair order not found: %v", err)
+	}
+
+	if !isRepairOpen(repair.Status) {
+		return fmt.Errorf("cannot remove labor from repair order in %s status", repair.Status)
+	}
+
+	err = s.repairRepo.RemoveLaborLine(repairID, lineID)
+	if err != nil {
+		return fmt.Errorf("labor line not found: %v", err)
+	}
+
+	return nil
+}
+
+func (s *repairService) GetRepairLaborLines(repairID int) ([]models.RepairLaborLine, error) {
+	// Check if repair order exists
+	_, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	return s.repairRepo.GetLaborLines(repairID)
+}
+
+func (s *repairService) AttachChecklist(repairID int, request *models.RepairChecklistAttachRequest) ([]models.RepairChecklistItem, error) {
+	// Check if repair order exists and is still open
+	repair, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	if !isRepairOpen(repair.Status) {
+		return nil, fmt.Errorf("cannot attach checklist to repair order in %s status", repair.Status)
+	}
+
+	var template *models.ChecklistTemplate
+	if request.TemplateID != nil {
+		template, err = s.checklistRepo.GetByID(*request.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		if !template.IsActive {
+			return nil, fmt.Errorf("checklist template %s is inactive", template.Name)
+		}
+	} else {
+		// Use the template of the vehicle's type
+		vehicleTypeID, err := s.repairVehicleTypeID(repair)
+		if err != nil {
+			return nil, err
+		}
+		template, err = s.checklistRepo.GetForVehicleType(vehicleTypeID)
+		if err != nil {
+			return nil, fmt.Errorf("no checklist template for this vehicle type")
+		}
+	}
+
+	for _, item := range repair.Checklist {
+		if item.TemplateID != nil && *item.TemplateID == template.ID {
+			return nil, fmt.Errorf("checklist %s is already attached", template.Name)
+		}
+	}
+
+	err = s.repairRepo.AttachChecklist(repairID, template.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach checklist: %v", err)
+	}
+
+	return s.repairRepo.GetChecklistItems(repairID)
+}
+
+func (s *repairService) GetRepairChecklist(repairID int) ([]models.RepairChecklistItem, error) {
+	// Check if repair order exists
+	_, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	return s.repairRepo.GetChecklistItems(repairID)
+}
+
+func (s *repairService) UpdateChecklistItem(repairID int, itemID int, request *models.RepairChecklistItemUpdateRequest, checkedBy int) (*models.RepairChecklistItem, error) {
+	// Check if repair order exists and is still open
+	repair, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	if !isRepairOpen(repair.Status) {
+		return nil, fmt.Errorf("cannot update checklist of repair order in %s status", repair.Status)
+	}
+
+	item, err := s.repairRepo.UpdateChecklistItem(repairID, itemID, request, checkedBy)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("checklist item not found")
+		}
+		return nil, fmt.Errorf("failed to update checklist item: %v", err)
+	}
+
+	return item, nil
+}
+
+func (s *repairService) UploadRepairAttachment(repairID int, request *models.RepairAttachmentUploadRequest, file *multipart.FileHeader, uploadedBy int) (*models.RepairAttachment, error) {
+	// Check if repair order exists
+	_, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	// Before, during and after stages only take photos
+	allowedTypes := imageUploadTypes
+	if request.Stage == models.RepairAttachmentStageDocument {
+		allowedTypes = documentUploadTypes
+	}
+
+	stored, err := s.fileUploader.Store(fmt.Sprintf("repairs/%d", repairID), file, allowedTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &models.RepairAttachment{
+		RepairOrderID: repairID,
+		Stage:         request.Stage,
+		FilePath:      stored.Path,
+		ThumbnailPath: stored.ThumbnailPath,
+		Caption:       request.Caption,
+		FileName:      stored.FileName,
+		ContentType:   stored.ContentType,
+		FileSize:      stored.Size,
+		UploadedBy:    uploadedBy,
+	}
+
+	err = s.repairRepo.AddAttachment(attachment)
+	if err != nil {
+		s.removeAttachmentFiles(*attachment)
+		return nil, fmt.Errorf("failed to save attachment: %v", err)
+	}
+
+	attachments := []models.RepairAttachment{*attachment}
+	s.setAttachmentURLs(attachments)
+
+	return &attachments[0], nil
+}
+
+func (s *repairService) GetRepairAttachments(repairID int) ([]models.RepairAttachment, error) {
+	// Check if repair order exists
+	_, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	attachments, err := s.repairRepo.GetAttachments(repairID)
+	if err != nil {
+		return nil, err
+	}
+	s.setAttachmentURLs(attachments)
+
+	return attachments, nil
+}
+
+func (s *repairService) DeleteRepairAttachment(repairID int, attachmentID int) error {
+	attachment, err := s.repairRepo.GetAttachment(repairID, attachmentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("attachment not found")
+		}
+		return err
+	}
+
+	err = s.repairRepo.DeleteAttachment(repairID, attachmentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %v", err)
+	}
+
+	s.removeAttachmentFiles(*attachment)
+
+	return nil
+}
+
+func (s *repairService) setAttachmentURLs(attachments []models.RepairAttachment) {
+	for i := range attachments {
+		attachments[i].URL = s.fileUploader.URL(attachments[i].FilePath)
+		if attachments[i].ThumbnailPath != nil {
+			thumbnailURL := s.fileUploader.URL(*attachments[i].ThumbnailPath)
+			attachments[i].ThumbnailURL = &thumbnailURL
+		}
+	}
+}
+
+func (s *repairService) removeAttachmentFiles(attachment models.RepairAttachment) {
+	paths := []string{attachment.FilePath}
+	if attachment.ThumbnailPath != nil {
+		paths = append(paths, *attachment.ThumbnailPath)
+	}
+	s.fileUploader.Remove(paths...)
+}
+
+func (s *repairService) StartRepairTimer(repairID int, mechanicID int, request *models.RepairTimerRequest) (*models.RepairWorkSession, error) {
+	// Check if repair order exists and is still open
+	repair, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	if !isRepairOpen(repair.Status) {
+		return nil, fmt.Errorf("cannot start timer on repair order in %s status", repair.Status)
+	}
+
+	if awaitingApproval(repair) {
+		return nil, fmt.Errorf("the customer has not approved the estimate yet")
+	}
+
+	// Starting the timer starts or resumes work, except during quality check
+	resume := repair.Status != models.RepairStatusInProgress && repair.Status != models.RepairStatusQualityCheck
+	if resume {
+		err = s.validateStatusTransition(repair, models.RepairStatusInProgress)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	session, err := s.repairRepo.StartWorkSession(repairID, mechanicID, request.Notes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start timer: %v", err)
+	}
+
+	if resume {
+		err = s.UpdateRepairProgress(repairID, &models.RepairProgressUpdateRequest{
+			Status: models.RepairStatusInProgress,
+		}, mechanicID)
+		if err != nil {
+			// Don't leave a running timer on an order that never moved to in progress
+			if _, endErr := s.repairRepo.EndWorkSession(repairID, mechanicID, models.WorkSessionStopped, nil); endErr != nil {
+				return nil, fmt.Errorf("%v; failed to stop timer: %v", err, endErr)
+			}
+			return nil, err
+		}
+	}
+
+	return session, nil
+}
+
+func (s *repairService) PauseRepairTimer(repairID int, mechanicID int, request *models.RepairTimerRequest) (*models.RepairWorkSession, error) {
+	return s.endRepairTimer(repairID, mechanicID, models.WorkSessionPaused, request)
+}
+
+func (s *repairService) StopRepairTimer(repairID int, mechanicID int, request *models.RepairTimerRequest) (*models.RepairWorkSession, error) {
+	return s.endRepairTimer(repairID, mechanicID, models.WorkSessionStopped, request)
+}
+
+func (s *repairService) GetRepairTimeSummary(repairID int) (*models.RepairTimeSummary, error) {
+	// Check if repair order exists
+	_, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	sessions, err := s.repairRepo.GetWorkSessions(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work sessions: %v", err)
+	}
+
+	standardHours, err := s.repairRepo.GetStandardHours(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get standard hours: %v", err)
+	}
+
+	summary := &models.RepairTimeSummary{
+		RepairOrderID: repairID,
+		TimerStatus:   models.TimerStatusIdle,
+		StandardHours: standardHours,
+		Sessions:      sessions,
+	}
+
+	// Running sessions count up to now so the app can show a live total
+	for _, session := range sessions {
+		summary.ActualHours += session.DurationHours
+	}
+
+	if len(sessions) > 0 {
+		summary.TimerStatus = models.TimerStatusStopped
+		last := sessions[len(sessions)-1]
+		for _, session := range sessions {
+			if session.EndedAt == nil {
+				summary.TimerStatus = models.TimerStatusRunning
+				break
+			}
+		}
+		if summary.TimerStatus != models.TimerStatusRunning && last.EndReason != nil && *last.EndReason == models.WorkSessionPaused {
+			summary.TimerStatus = models.TimerStatusPaused
+		}
+	}
+
+	summary.VarianceHours = summary.ActualHours - summary.StandardHours
+	if summary.ActualHours > 0 {
+		summary.EfficiencyPercent = summary.StandardHours / summary.ActualHours * 100
+	}
+
+	return summary, nil
+}
+
+func (s *repairService) ScheduleRepair(repairID int, request *models.RepairScheduleRequest, scheduledBy int) (*models.RepairSchedule, error) {
+	// Check if repair order exists and is still open
+	repair, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	if !isRepairOpen(repair.Status) {
+		return nil, fmt.Errorf("cannot schedule repair order in %s status", repair.Status)
+	}
+
+	if !request.ScheduledEnd.After(request.ScheduledStart) {
+		return nil, fmt.Errorf("scheduled_end must be after scheduled_start")
+	}
+
+	mechanicID := request.MechanicID
+	if mechanicID == 0 {
+		mechanicID = repair.MechanicID
+	}
+
+	// Moving the job to another mechanic is a manual reassignment
+	var warnings []string
+	if mechanicID != repair.MechanicID {
+		vehicleTypeID, err := s.repairVehicleTypeID(repair)
+		if err != nil {
+			return nil, err
+		}
+
+		warnings, err = s.validateMechanicAssignment(mechanicID, vehicleTypeID, laborCategories(repair.LaborLines))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	previous, _ := s.workshopRepo.GetScheduleByRepair(repairID)
+
+	schedule := &models.RepairSchedule{
+		RepairOrderID:  repairID,
+		BayID:          request.BayID,
+		MechanicID:     mechanicID,
+		ScheduledStart: request.ScheduledStart,
+		ScheduledEnd:   request.ScheduledEnd,
+		ScheduledBy:    scheduledBy,
+	}
+
+	err = s.workshopRepo.SaveSchedule(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := s.workshopRepo.GetScheduleByRepair(repairID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Let the mechanics know about the new slot
+	slot := fmt.Sprintf("%s, %s - %s", saved.BayName,
+		saved.ScheduledStart.Format("02 Jan 2006 15:04"), saved.ScheduledEnd.Format("15:04"))
+	if previous == nil {
+		s.notify(saved.MechanicID, models.NotificationRepairScheduled, "Repair scheduled",
+			fmt.Sprintf("Repair %s is scheduled on %s", saved.RepairCode, slot), repairID)
+	} else {
+		s.notify(saved.MechanicID, models.NotificationRepairRescheduled, "Repair rescheduled",
+			fmt.Sprintf("Repair %s has been moved to %s", saved.RepairCode, slot), repairID)
+		if previous.MechanicID != saved.MechanicID {
+			s.notify(previous.MechanicID, models.NotificationRepairUnscheduled, "Repair reassigned",
+				fmt.Sprintf("Repair %s has been reassigned to %s", saved.RepairCode, saved.MechanicName), repairID)
+		}
+	}
+
+	saved.Warnings = warnings
+
+	return saved, nil
+}
+
+func (s *repairService) UnscheduleRepair(repairID int) error {
+	schedule, err := s.workshopRepo.GetScheduleByRepair(repairID)
+	if err != nil {
+		return err
+	}
+
+	err = s.workshopRepo.DeleteSchedule(repairID)
+	if err != nil {
+		return err
+	}
+
+	s.notify(schedule.MechanicID, models.NotificationRepairUnscheduled, "Repair unscheduled",
+		fmt.Sprintf("Repair %s has been removed from %s", schedule.RepairCode, schedule.BayName), repairID)
+
+	return nil
+}
+
+func (s *repairService) ReserveSparePart(repairID int, request *models.SparePartReservationCreateRequest, reservedBy int) (*models.SparePartReservation, error) {
+	// Check if repair order exists and is still open
+	repair, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	if !isRepairOpen(repair.Status) {
+		return nil, fmt.Errorf("cannot reserve spare parts for repair order in %s status", repair.Status)
+	}
+
+	reservation, err := s.repairRepo.ReserveSparePart(repairID, request, reservedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve spare part: %v", err)
+	}
+
+	return reservation, nil
+}
+
+func (s *repairService) ReleaseReservation(repairID int, reservationID int) error {
+	// Check if repair order exists
+	_, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return fmt.Errorf("repair order not found: %v", err)
+	}
+
+	err = s.repairRepo.ReleaseReservation(repairID, reservationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("active reservation not found")
+		}
+		return fmt.Errorf("failed to release reservation: %v", err)
+	}
+
+	return nil
+}
+
+func (s *repairService) GetRepairReservations(repairID int) ([]models.SparePartReservation, error) {
+	// Check if repair order exists
+	_, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	reservations, err := s.repairRepo.GetReservations(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repair reservations: %v", err)
+	}
+
+	return reservations, nil
+}
+
+func (s *repairService) GetRepairStats(mechanicID *int, dateFrom, dateTo *time.Time, orderType models.RepairOrderType) (map[string]interface{}, error) {
+	return s.repairRepo.GetRepairStats(mechanicID, dateFrom, dateTo, orderType)
+}
+
+func (s *repairService) GetMechanicWorkload() ([]models.MechanicWorkload, error) {
+	workload, err := s.repairRepo.GetMechanicWorkload()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mechanic workload: %v", err)
+	}
+
+	return workload, nil
+}
+
+func (s *repairService) GetMechanicUtilization(dateFrom, dateTo time.Time, hoursPerDay float64, orderType models.RepairOrderType) ([]models.MechanicUtilization, error) {
+	if !dateTo.After(dateFrom) {
+		return nil, fmt.Errorf("date_to must be after date_from")
+	}
+	if hoursPerDay <= 0 || hoursPerDay > 24 {
+		hoursPerDay = 8
+	}
+
+	utilization, err := s.repairRepo.GetMechanicUtilization(dateFrom, dateTo, orderType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mechanic utilization: %v", err)
+	}
+
+	// Available hours assume every day in the period is a working day
+	days := math.Ceil(dateTo.Sub(dateFrom).Hours() / 24)
+	availableHours := days * hoursPerDay
+
+	for i := range utilization {
+		utilization[i].AvailableHours = availableHours
+		if availableHours > 0 {
+			utilization[i].UtilizationPercent = utilization[i].LoggedHours / availableHours * 100
+		}
+		if utilization[i].ActualHours > 0 {
+			utilization[i].EfficiencyPercent = utilization[i].StandardHours / utilization[i].ActualHours * 100
+		}
+	}
+
+	return utilization, nil
+}
+
+func (s *repairService) GetTurnaroundReport(dateFrom, dateTo time.Time, orderType models.RepairOrderType) (*models.RepairTurnaroundReport, error) {
+	byMechanic, byVehicleType, err := s.repairRepo.GetTurnaround(dateFrom, dateTo, orderType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repair turnaround: %v", err)
+	}
+
+	for _, groups := range [][]models.RepairTurnaround{byMechanic, byVehicleType} {
+		for i := range groups {
+			if measured := groups[i].OnTimeJobs + groups[i].LateJobs; measured > 0 {
+				groups[i].OnTimePercent = math.Round(float64(groups[i].OnTimeJobs)/float64(measured)*10000) / 100
+			}
+		}
+	}
+
+	return &models.RepairTurnaroundReport{
+		DateFrom:      dateFrom.Format("2006-01-02"),
+		DateTo:        dateTo.Add(-24 * time.Hour).Format("2006-01-02"),
+		ByMechanic:    byMechanic,
+		ByVehicleType: byVehicleType,
+	}, nil
+}
+
+// AlertOverdueRepairs notifies the mechanic and the assigner of every repair that went past
+// its target completion date; each order is alerted once per target
+func (s *repairService) AlertOverdueRepairs() (int, error) {
+	repairs, err := s.repairRepo.GetOverdueRepairs()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get overdue repairs: %v", err)
+	}
+
+	for _, repair := range repairs {
+		message := fmt.Sprintf("Repair order %s was due on %s and is still %s",
+			repair.Code, repair.TargetCompletionDate.Format("2006-01-02"), repair.Status)
+		s.notify(repair.MechanicID, models.NotificationRepairOverdue, "Repair overdue", message, repair.ID)
+		if repair.AssignedBy != repair.MechanicID {
+			s.notify(repair.AssignedBy, models.NotificationRepairOverdue, "Repair overdue", message, repair.ID)
+		}
+
+		err = s.repairRepo.MarkSLAAlerted(repair.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to mark overdue alert: %v", err)
+		}
+	}
+
+	return len(repairs), nil
+}
+
+func (s *repairService) GetVehiclesNeedingRepairOrders(filter models.VehicleRepairNeedFilter, page, limit int) ([]models.Vehicle, int, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	vehicles, total, err := s.repairRepo.GetVehiclesNeedingRepairOrders(filter, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get vehicles: %v", err)
+	}
+
+	return vehicles, total, nil
+}
+
 // Helper methods
 
+// pickLeastLoadedMechanic returns the qualified active mechanic with the fewest remaining hours
+func (s *repairService) pickLeastLoadedMechanic(vehicleTypeID int, categories []string) (int, []string, error) {
+	workload, err := s.repairRepo.GetMechanicWorkload()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get mechanic workload: %v", err)
+	}
+
+	if len(workload) == 0 {
+		return 0, nil, fmt.Errorf("no active mechanic available for assignment")
+	}
+
+	// Workload is already ordered by remaining hours, then open jobs
+	if s.skillCheckMode == models.SkillCheckOff {
+		return workload[0].MechanicID, nil, nil
+	}
+
+	for _, mechanic := range workload {
+		missing, err := s.missingSkills(mechanic.MechanicID, vehicleTypeID, categories)
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(missing) == 0 {
+			return mechanic.MechanicID, nil, nil
+		}
+	}
+
+	if s.skillCheckMode == models.SkillCheckEnforce {
+		return 0, nil, fmt.Errorf("no qualified mechanic available for assignment")
+	}
+
+	return workload[0].MechanicID, []string{"no qualified mechanic available, assigned least-loaded mechanic"}, nil
+}
+
+// notify sends an in-app notification about a repair order; failures are only logged
+func (s *repairService) notify(userID int, notificationType, title, message string, repairID int) {
+	err := s.notificationSvc.Notify(userID, notificationType, title, message, "repair_order", repairID)
+	if err != nil {
+		fmt.Printf("Failed to notify user %d about repair %d: %v\n", userID, repairID, err)
+	}
+}
+
+// laborCategories returns the distinct service catalog categories of the labor lines
+func laborCategories(lines []models.RepairLaborLine) []string {
+	seen := make(map[string]bool)
+	var categories []string
+	for _, line := range lines {
+		if line.Service == nil || line.Service.Category == "" || seen[line.Service.Category] {
+			continue
+		}
+		seen[line.Service.Category] = true
+		categories = append(categories, line.Service.Category)
+	}
+	return categories
+}
+
+// validateMechanicAssignment checks the user is an active mechanic and, depending on
+// the skill check mode, refuses or returns warnings when required skills are missing
+func (s *repairService) validateMechanicAssignment(mechanicID int, vehicleTypeID int, categories []string) ([]string, error) {
+	mechanic, err := s.userRepo.GetUserWithRole(mechanicID)
+	if err != nil {
+		return nil, fmt.Errorf("mechanic not found: %v", err)
+	}
+
+	if mechanic.Role == nil || mechanic.Role.Name != "mekanik" {
+		return nil, fmt.Errorf("assigned user is not a mechanic")
+	}
+
+	if !mechanic.IsActive {
+		return nil, fmt.Errorf("assigned mechanic is inactive")
+	}
+
+	if s.skillCheckMode == models.SkillCheckOff {
+		return nil, nil
+	}
+
+	missing, err := s.missingSkills(mechanicID, vehicleTypeID, categories)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	message := fmt.Sprintf("mechanic lacks required skills: %s", strings.Join(missing, ", "))
+	if s.skillCheckMode == models.SkillCheckEnforce {
+		return nil, fmt.Errorf("%s", message)
+	}
+
+	return []string{message}, nil
+}
+
+// missingSkills lists the required skills a mechanic does not hold.
+// A job needs general skill for the vehicle type, or a matching skill per service category.
+func (s *repairService) missingSkills(mechanicID int, vehicleTypeID int, categories []string) ([]string, error) {
+	userSkills, err := s.skillRepo.GetUserSkills(mechanicID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mechanic skills: %v", err)
+	}
+
+	required := categories
+	if len(required) == 0 {
+		required = []string{""}
+	}
+
+	now := time.Now()
+	var missing []string
+	for _, category := range required {
+		covered := false
+		for _, skill := range userSkills {
+			if skill.ExpiresAt != nil && skill.ExpiresAt.Before(now) {
+				continue
+			}
+			if skill.VehicleTypeID != nil && *skill.VehicleTypeID != vehicleTypeID {
+				continue
+			}
+			// Skills without a category cover every category of their vehicle type
+			if skill.ServiceCategory != nil && *skill.ServiceCategory != category {
+				continue
+			}
+			covered = true
+			break
+		}
+
+		if !covered {
+			if category == "" {
+				category = "general"
+			}
+			missing = append(missing, category)
+		}
+	}
+
+	return missing, nil
+}
+
+func (s *repairService) endRepairTimer(repairID int, mechanicID int, reason models.WorkSessionEndReason, request *models.RepairTimerRequest) (*models.RepairWorkSession, error) {
+	// Check if repair order exists
+	_, err := s.repairRepo.GetByID(repairID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found: %v", err)
+	}
+
+	session, err := s.repairRepo.EndWorkSession(repairID, mechanicID, reason, request.Notes)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no running timer on this repair order")
+		}
+		return nil, fmt.Errorf("failed to end timer: %v", err)
+	}
+
+	return session, nil
+}
+
 func (s *repairService) generateRepairCode() string {
 	// Generate repair code in format RPR-YYYYMMDD-XXX
 	now := time.Now()
@@ -337,31 +1599,325 @@ func (s *repairService) generateRepairCode() string {
 	return fmt.Sprintf("RPR-%s-%03d", dateStr, now.Nanosecond()%1000)
 }
 
-func (s *repairService) validateStatusTransition(currentStatus, newStatus models.RepairStatus) error {
-	// Define allowed status transitions
-	allowedTransitions := map[models.RepairStatus][]models.RepairStatus{
-		models.RepairStatusPending: {
-			models.RepairStatusInProgress,
-			models.RepairStatusCancelled,
-		},
-		models.RepairStatusInProgress: {
-			models.RepairStatusCompleted,
-			models.RepairStatusCancelled,
-		},
-		models.RepairStatusCompleted: {
-			// No transitions allowed from completed
-		},
-		models.RepairStatusCancelled: {
-			models.RepairStatusPending, // Allow reactivation
-		},
-	}
-
-	allowed := allowedTransitions[currentStatus]
-	for _, status := range allowed {
-		if status == newStatus {
+// releaseReservationsIfClosed frees reserved stock and stops running timers once a repair is closed
+func (s *repairService) releaseReservationsIfClosed(repairID int, status models.RepairStatus) error {
+	if status != models.RepairStatusCompleted && status != models.RepairStatusCancelled {
+		return nil
+	}
+
+	err := s.repairRepo.ReleaseReservations(repairID)
+	if err != nil {
+		return fmt.Errorf("failed to release spare part reservations: %v", err)
+	}
+
+	err = s.repairRepo.CloseWorkSessions(repairID)
+	if err != nil {
+		return fmt.Errorf("failed to stop running timers: %v", err)
+	}
+
+	return nil
+}
+
+// targetCompletionDate uses the requested date or counts the SLA days from today; the catalog
+// operations of the job win over the vehicle type, the workshop default comes last
+func (s *repairService) targetCompletionDate(requested *string, vehicleTypeID int, categories []string) (*time.Time, error) {
+	// Dates are kept at midnight UTC, like DATE columns scanned by the driver
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if requested != nil && *requested != "" {
+		target, err := time.Parse("2006-01-02", *requested)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target completion date, use YYYY-MM-DD")
+		}
+		if target.Before(today) {
+			return nil, fmt.Errorf("target completion date cannot be in the past")
+		}
+		return &target, nil
+	}
+
+	days, err := s.repairRepo.GetDefaultSLADays(vehicleTypeID, categories)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repair SLA: %v", err)
+	}
+	if days <= 0 {
+		days = s.slaDays
+	}
+
+	target := today.AddDate(0, 0, days)
+	return &target, nil
+}
+
+// syncVehicle updates the inventory vehicle after its repair order changes status
+func (s *repairService) syncVehicle(repair *models.RepairOrder, status models.RepairStatus) error {
+	// Customer-owned vehicles are not part of the inventory
+	if repair.VehicleID == nil {
+		return nil
+	}
+
+	switch {
+	case !isRepairOpen(status):
+		err := s.updateVehicleRepairCost(*repair.VehicleID)
+		if err != nil {
+			return err
+		}
+		return s.restoreVehicleStatus(*repair.VehicleID)
+
+	case status == models.RepairStatusInProgress || !isRepairOpen(repair.Status):
+		// Work started, or a closed order was reopened
+		err := s.vehicleRepo.UpdateStatus(*repair.VehicleID, string(models.VehicleStatusInRepair))
+		if err != nil {
+			return fmt.Errorf("failed to update vehicle status: %v", err)
+		}
+	}
+
+	// The vehicle stays where it is for the other states
+	return nil
+}
+
+// updateVehicleRepairCost recomputes the repair cost and HPP of a vehicle from all of its repair orders
+func (s *repairService) updateVehicleRepairCost(vehicleID int) error {
+	repairCost, err := s.repairRepo.GetVehicleRepairCost(vehicleID)
+	if err != nil {
+		return fmt.Errorf("failed to calculate vehicle repair cost: %v", err)
+	}
+
+	err = s.vehicleRepo.UpdateRepairCost(vehicleID, repairCost)
+	if err != nil {
+		return fmt.Errorf("failed to update vehicle repair cost: %v", err)
+	}
+
+	return nil
+}
+
+// restoreVehicleStatus makes a vehicle available again once none of its repairs is open;
+// vehicles that were reserved or sold meanwhile keep their status
+func (s *repairService) restoreVehicleStatus(vehicleID int) error {
+	vehicle, err := s.vehicleRepo.GetByID(vehicleID)
+	if err != nil {
+		return fmt.Errorf("vehicle not found: %v", err)
+	}
+
+	if vehicle.Status != models.VehicleStatusInRepair {
+		return nil
+	}
+
+	open, err := s.repairRepo.HasOpenRepairs(vehicleID)
+	if err != nil {
+		return fmt.Errorf("failed to check open repairs: %v", err)
+	}
+	if open {
+		return nil
+	}
+
+	err = s.vehicleRepo.UpdateStatus(vehicleID, string(models.VehicleStatusAvailable))
+	if err != nil {
+		return fmt.Errorf("failed to update vehicle status: %v", err)
+	}
+
+	return nil
+}
+
+func (s *repairService) validateStatusTransition(repair *models.RepairOrder, newStatus models.RepairStatus) error {
+	currentStatus := repair.Status
+
+	// Allowed status transitions are configured in repair_status_transitions
+	transitions, err := s.repairRepo.GetStatusTransitions()
+	if err != nil {
+		return fmt.Errorf("failed to get status transitions: %v", err)
+	}
+
+	for _, transition := range transitions {
+		if transition.FromStatus == currentStatus && transition.ToStatus == newStatus {
+			if newStatus == models.RepairStatusInProgress && awaitingApproval(repair) {
+				return fmt.Errorf("the customer has not approved the estimate yet")
+			}
+			if newStatus == models.RepairStatusCompleted {
+				return validateChecklistDone(repair.Checklist)
+			}
 			return nil
 		}
 	}
 
 	return fmt.Errorf("invalid status transition from %s to %s", currentStatus, newStatus)
 }
+
+// assignMechanic auto-assigns the least-loaded qualified mechanic when none is given
+// and checks the role and skills of the chosen mechanic
+func (s *repairService) assignMechanic(mechanicID int, vehicleTypeID int, categories []string) (int, []string, error) {
+	var warnings []string
+
+	if mechanicID == 0 {
+		picked, pickWarnings, err := s.pickLeastLoadedMechanic(vehicleTypeID, categories)
+		if err != nil {
+			return 0, nil, err
+		}
+		mechanicID = picked
+		warnings = append(warnings, pickWarnings...)
+	}
+
+	assignWarnings, err := s.validateMechanicAssignment(mechanicID, vehicleTypeID, categories)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return mechanicID, append(warnings, assignWarnings...), nil
+}
+
+// repairVehicleTypeID returns the vehicle type of the inventory or customer vehicle being repaired
+func (s *repairService) repairVehicleTypeID(repair *models.RepairOrder) (int, error) {
+	if repair.CustomerVehicle != nil {
+		return repair.CustomerVehicle.VehicleTypeID, nil
+	}
+	if repair.VehicleID == nil {
+		return 0, nil
+	}
+
+	vehicle, err := s.vehicleRepo.GetByID(*repair.VehicleID)
+	if err != nil {
+		return 0, fmt.Errorf("vehicle not found: %v", err)
+	}
+	if vehicle.Brand == nil {
+		return 0, nil
+	}
+
+	return vehicle.Brand.TypeID, nil
+}
+
+// trackingLabels are the customer-facing names of repair statuses
+var trackingLabels = map[models.RepairStatus]string{
+	models.RepairStatusPending:      "Received",
+	models.RepairStatusApproved:     "Estimate approved",
+	models.RepairStatusInProgress:   "In progress",
+	models.RepairStatusWaitingParts: "Waiting for parts",
+	models.RepairStatusQualityCheck: "Quality check",
+	models.RepairStatusOnHold:       "On hold",
+	models.RepairStatusCompleted:    "Done",
+	models.RepairStatusCancelled:    "Cancelled",
+}
+
+func digitsOnly(value string) string {
+	var digits strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	return digits.String()
+}
+
+const (
+	maxTrackingFailures = 5                // wrong phone digits allowed per repair code
+	trackingLockout     = 15 * time.Minute // how long a code stays locked after that
+)
+
+// attemptCounter locks a key after too many failures within the lockout period.
+// Counters are kept in memory, so they apply per server instance.
+type attemptCounter struct {
+	mu       sync.Mutex
+	limit    int
+	lockout  time.Duration
+	failures map[string]*attemptWindow
+}
+
+type attemptWindow struct {
+	start time.Time
+	count int
+}
+
+func newAttemptCounter(limit int, lockout time.Duration) *attemptCounter {
+	return &attemptCounter{
+		limit:    limit,
+		lockout:  lockout,
+		failures: make(map[string]*attemptWindow),
+	}
+}
+
+// Blocked reports whether key has used up its failures
+func (a *attemptCounter) Blocked(key string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	w, exists := a.failures[key]
+	if !exists {
+		return false
+	}
+	if time.Since(w.start) > a.lockout {
+		delete(a.failures, key)
+		return false
+	}
+	return w.count >= a.limit
+}
+
+// Fail records a failed attempt for key
+func (a *attemptCounter) Fail(key string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	// Drop expired windows so guessed codes do not pile up
+	for k, w := range a.failures {
+		if now.Sub(w.start) > a.lockout {
+			delete(a.failures, k)
+		}
+	}
+
+	w, exists := a.failures[key]
+	if !exists {
+		w = &attemptWindow{start: now}
+		a.failures[key] = w
+	}
+	w.count++
+}
+
+func sparePartName(part *models.SparePart, sparePartID int) string {
+	if part == nil {
+		return fmt.Sprintf("Spare part #%d", sparePartID)
+	}
+	return part.Name
+}
+
+// isRepairOpen reports whether work can still be recorded on a repair order
+func isRepairOpen(status models.RepairStatus) bool {
+	return status != models.RepairStatusCompleted && status != models.RepairStatusCancelled
+}
+
+// repairSLAStatus flags an open repair order against its target completion date
+func repairSLAStatus(repair *models.RepairOrder, now time.Time, atRiskDays int) models.RepairSLAStatus {
+	if repair.TargetCompletionDate == nil || !isRepairOpen(repair.Status) {
+		return ""
+	}
+
+	// Compare calendar dates only
+	target := repair.TargetCompletionDate.Format("2006-01-02")
+	switch {
+	case target < now.Format("2006-01-02"):
+		return models.RepairSLAOverdue
+	case target <= now.AddDate(0, 0, atRiskDays).Format("2006-01-02"):
+		return models.RepairSLAAtRisk
+	default:
+		return models.RepairSLAOnTrack
+	}
+}
+
+// awaitingApproval reports whether a customer service order still needs the customer's approval
+func awaitingApproval(repair *models.RepairOrder) bool {
+	return repair.OrderType == models.RepairOrderTypeCustomerService &&
+		(repair.EstimateStatus == nil || *repair.EstimateStatus != models.EstimateStatusApproved)
+}
+
+// validateChecklistDone blocks completion while required checklist items have no result
+func validateChecklistDone(items []models.RepairChecklistItem) error {
+	pending := 0
+	for _, item := range items {
+		if item.IsRequired && item.Result == nil {
+			pending++
+		}
+	}
+
+	if pending > 0 {
+		return fmt.Errorf("cannot complete repair: %d required checklist items are not done", pending)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
+)
+
+type ServiceCatalogService interface {
+	Create(req *models.ServiceCatalogCreateRequest) (*models.ServiceCatalog, error)
+	GetByID(id int) (*models.ServiceCatalog, error)
+	Update(id int, req *models.ServiceCatalogUpdateRequest) (*models.ServiceCatalog, error)
+	Delete(id int) error
+	List(filter models.ServiceCatalogFilter, page, limit int) ([]models.ServiceCatalog, int, error)
+}
+
+type serviceCatalogService struct {
+	serviceCatalogRepo repository.ServiceCatalogRepository
+	vehicleTypeRepo    repository.VehicleTypeRepository
+}
+
+func NewServiceCatalogService(serviceCatalogRepo repository.ServiceCatalogRepository, vehicleTypeRepo repository.VehicleTypeRepository) ServiceCatalogService {
+	return &serviceCatalogService{
+		serviceCatalogRepo: serviceCatalogRepo,
+		vehicleTypeRepo:    vehicleTypeRepo,
+	}
+}
+
+func (s *serviceCatalogService) Create(req *models.ServiceCatalogCreateRequest) (*models.ServiceCatalog, error) {
+	// Check if code already exists
+	existing, _ := s.serviceCatalogRepo.GetByCode(req.Code)
+	if existing != nil {
+		return nil, fmt.Errorf("service code already exists")
+	}
+
+	if req.VehicleTypeID != nil {
+		if _, err := s.vehicleTypeRepo.GetByID(*req.VehicleTypeID); err != nil {
+			return nil, fmt.Errorf("vehicle type not found")
+		}
+	}
+
+	service, err := s.serviceCatalogRepo.Create(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create service catalog entry: %w", err)
+	}
+
+	return service, nil
+}
+
+func (s *serviceCatalogService) GetByID(id int) (*models.ServiceCatalog, error) {
+	return s.serviceCatalogRepo.GetByID(id)
+}
+
+func (s *serviceCatalogService) Update(id int, req *models.ServiceCatalogUpdateRequest) (*models.ServiceCatalog, error) {
+	if req.VehicleTypeID != nil && *req.VehicleTypeID > 0 {
+		if _, err := s.vehicleTypeRepo.GetByID(*req.VehicleTypeID); err != nil {
+			return nil, fmt.Errorf("vehicle type not found")
+		}
+	}
+
+	return s.serviceCatalogRepo.Update(id, req)
+}
+
+func (s *serviceCatalogService) Delete(id int) error {
+	return s.serviceCatalogRepo.Delete(id)
+}
+
+func (s *serviceCatalogService) List(filter models.ServiceCatalogFilter, page, limit int) ([]models.ServiceCatalog, int, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	return s.serviceCatalogRepo.List(filter, page, limit)
+}
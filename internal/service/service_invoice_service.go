@@ -0,0 +1,151 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
+	"github.com/hafizd-kurniawan/pos-baru/pkg/utils"
+)
+
+type ServiceInvoiceService interface {
+	CreateInvoice(repairOrderID int, req *models.ServiceInvoiceCreateRequest, processedBy int) (*models.ServiceInvoice, error)
+	GetInvoice(id int) (*models.ServiceInvoice, error)
+	GetInvoiceByRepairOrder(repairOrderID int) (*models.ServiceInvoice, error)
+	ListInvoices(filter models.ServiceInvoiceFilter, page, limit int) ([]models.ServiceInvoice, int, error)
+	AddPayment(invoiceID int, req *models.ServicePaymentCreateRequest, receivedBy int) (*models.ServiceInvoice, error)
+	GetReport(dateFrom, dateTo string) (*models.WorkshopServiceReport, error)
+}
+
+type serviceInvoiceService struct {
+	invoiceRepo repository.ServiceInvoiceRepository
+	repairRepo  repository.RepairRepository
+}
+
+func NewServiceInvoiceService(invoiceRepo repository.ServiceInvoiceRepository, repairRepo repository.RepairRepository) ServiceInvoiceService {
+	return &serviceInvoiceService{
+		invoiceRepo: invoiceRepo,
+		repairRepo:  repairRepo,
+	}
+}
+
+func (s *serviceInvoiceService) CreateInvoice(repairOrderID int, req *models.ServiceInvoiceCreateRequest, processedBy int) (*models.ServiceInvoice, error) {
+	repair, err := s.repairRepo.GetByID(repairOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("repair order not found")
+	}
+
+	if repair.OrderType != models.RepairOrderTypeCustomerService || repair.CustomerID == nil {
+		return nil, fmt.Errorf("only customer service orders can be invoiced")
+	}
+	if repair.IsWarranty {
+		return nil, fmt.Errorf("warranty orders are not invoiced to the customer")
+	}
+	if repair.Status != models.RepairStatusCompleted {
+		return nil, fmt.Errorf("service order must be completed before invoicing")
+	}
+	if _, err := s.invoiceRepo.GetByRepairOrder(repairOrderID); err == nil {
+		return nil, fmt.Errorf("service order is already invoiced")
+	}
+
+	invoice := &models.ServiceInvoice{
+		InvoiceNumber: utils.GenerateInvoiceNumber("SRV"),
+		RepairOrderID: repair.ID,
+		CustomerID:    *repair.CustomerID,
+		Discount:      req.Discount,
+		Notes:         req.Notes,
+		ProcessedBy:   processedBy,
+		Items:         []models.ServiceInvoiceItem{},
+	}
+
+	// Bill the parts and labor recorded on the order
+	for _, part := range repair.SpareParts {
+		// Lines settled when the order was cancelled are not billed unless kept as cost
+		if part.Disposition != nil && *part.Disposition != models.PartDispositionKeepAsCost {
+			continue
+		}
+		description := fmt.Sprintf("Spare part #%d", part.SparePartID)
+		if part.SparePart != nil {
+			description = part.SparePart.Name
+		}
+		invoice.Items = append(invoice.Items, models.ServiceInvoiceItem{
+			ItemType:    "part",
+			Description: description,
+			Quantity:    float64(part.QuantityUsed),
+			UnitPrice:   part.UnitPrice,
+			TotalPrice:  part.TotalPrice,
+		})
+		invoice.PartsTotal += part.TotalPrice
+	}
+	for _, line := range repair.LaborLines {
+		invoice.Items = append(invoice.Items, models.ServiceInvoiceItem{
+			ItemType:    "labor",
+			Description: line.Description,
+			Quantity:    line.Hours,
+			UnitPrice:   line.LaborRate,
+			TotalPrice:  line.TotalPrice,
+		})
+		invoice.LaborTotal += line.TotalPrice
+	}
+
+	subtotal := invoice.PartsTotal + invoice.LaborTotal
+	if req.Discount > subtotal {
+		return nil, fmt.Errorf("discount cannot exceed the invoice subtotal")
+	}
+	invoice.TotalAmount = subtotal - req.Discount
+
+	if err := s.invoiceRepo.Create(invoice); err != nil {
+		return nil, err
+	}
+
+	return s.invoiceRepo.GetByID(invoice.ID)
+}
+
+func (s *serviceInvoiceService) GetInvoice(id int) (*models.ServiceInvoice, error) {
+	return s.invoiceRepo.GetByID(id)
+}
+
+func (s *serviceInvoiceService) GetInvoiceByRepairOrder(repairOrderID int) (*models.ServiceInvoice, error) {
+	return s.invoiceRepo.GetByRepairOrder(repairOrderID)
+}
+
+func (s *serviceInvoiceService) ListInvoices(filter models.ServiceInvoiceFilter, page, limit int) ([]models.ServiceInvoice, int, error) {
+	return s.invoiceRepo.List(filter, page, limit)
+}
+
+func (s *serviceInvoiceService) AddPayment(invoiceID int, req *models.ServicePaymentCreateRequest, receivedBy int) (*models.ServiceInvoice, error) {
+	payment := &models.ServicePayment{
+		InvoiceID:     invoiceID,
+		Amount:        req.Amount,
+		PaymentMethod: req.PaymentMethod,
+		Notes:         req.Notes,
+		ReceivedBy:    receivedBy,
+	}
+
+	if err := s.invoiceRepo.AddPayment(payment); err != nil {
+		return nil, err
+	}
+
+	return s.invoiceRepo.GetByID(invoiceID)
+}
+
+func (s *serviceInvoiceService) GetReport(dateFrom, dateTo string) (*models.WorkshopServiceReport, error) {
+	// Default to the current month
+	now := time.Now()
+	if dateFrom == "" {
+		dateFrom = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
+	}
+	if dateTo == "" {
+		dateTo = now.Format("2006-01-02")
+	}
+
+	if _, err := time.Parse("2006-01-02", dateFrom); err != nil {
+		return nil, fmt.Errorf("invalid date format, use YYYY-MM-DD")
+	}
+	if _, err := time.Parse("2006-01-02", dateTo); err != nil {
+		return nil, fmt.Errorf("invalid date format, use YYYY-MM-DD")
+	}
+
+	return s.invoiceRepo.GetReport(dateFrom, dateTo)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
+)
+
+type SkillService interface {
+	Create(req *models.SkillCreateRequest) (*models.Skill, error)
+	Delete(id int) error
+	List() ([]models.Skill, error)
+	AssignToUser(userID int, req *models.UserSkillAssignRequest) (*models.UserSkill, error)
+	RemoveFromUser(userID int, skillID int) error
+	GetUserSkills(userID int) ([]models.UserSkill, error)
+}
+
+type skillService struct {
+	skillRepo       repository.SkillRepository
+	userRepo        repository.UserRepository
+	vehicleTypeRepo repository.VehicleTypeRepository
+}
+
+func NewSkillService(skillRepo repository.SkillRepository, userRepo repository.UserRepository, vehicleTypeRepo repository.VehicleTypeRepository) SkillService {
+	return &skillService{
+		skillRepo:       skillRepo,
+		userRepo:        userRepo,
+		vehicleTypeRepo: vehicleTypeRepo,
+	}
+}
+
+func (s *skillService) Create(req *models.SkillCreateRequest) (*models.Skill, error) {
+	if req.VehicleTypeID != nil {
+		if _, err := s.vehicleTypeRepo.GetByID(*req.VehicleTypeID); err != nil {
+			return nil, fmt.Errorf("vehicle type not found")
+		}
+	}
+
+	return s.skillRepo.Create(req)
+}
+
+func (s *skillService) Delete(id int) error {
+	return s.skillRepo.Delete(id)
+}
+
+func (s *skillService) List() ([]models.Skill, error) {
+	return s.skillRepo.List()
+}
+
+func (s *skillService) AssignToUser(userID int, req *models.UserSkillAssignRequest) (*models.UserSkill, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.skillRepo.GetByID(req.SkillID); err != nil {
+		return nil, err
+	}
+
+	certifiedAt, err := parseOptionalDate(req.CertifiedAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certified_at: %w", err)
+	}
+
+	expiresAt, err := parseOptionalDate(req.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expires_at: %w", err)
+	}
+
+	if certifiedAt != nil && expiresAt != nil && expiresAt.Before(*certifiedAt) {
+		return nil, fmt.Errorf("expires_at must be after certified_at")
+	}
+
+	return s.skillRepo.AssignToUser(userID, req.SkillID, req.CertificationNumber, certifiedAt, expiresAt)
+}
+
+func (s *skillService) RemoveFromUser(userID int, skillID int) error {
+	return s.skillRepo.RemoveFromUser(userID, skillID)
+}
+
+func (s *skillService) GetUserSkills(userID int) ([]models.UserSkill, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	return s.skillRepo.GetUserSkills(userID)
+}
+
+func parseOptionalDate(value *string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse("2006-01-02", *value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &date, nil
+}
@@ -21,6 +21,7 @@ type SparePartService interface {
 	CheckStockAvailability(id int, requestedQuantity int) (bool, error)
 	BulkUpdateStock(updates []models.SparePartStockUpdate) error
 	GetCategories() ([]string, error)
+	GetMovements(sparePartID int, page, limit int) ([]models.SparePartMovement, int64, error)
 }
 
 type sparePartService struct {
@@ -141,29 +142,30 @@ func (s *sparePartService) ListWithFilters(page, limit int, search, category str
 		return nil, 0, fmt.Errorf("failed to list spare parts with filters: %w", err)
 	}
 
-	// Apply additional status filter if needed
+	// Apply additional status filter if needed.
+	// Stock levels are judged on available quantity, i.e. after repair reservations.
 	if statusFilter != "" {
 		filteredSpareParts := make([]models.SparePart, 0)
 		for _, sp := range spareParts {
 			switch statusFilter {
 			case "low_stock":
 				// Low stock: stock is greater than 0 but less than or equal to minimum stock
-				if sp.IsActive && sp.StockQuantity > 0 && sp.StockQuantity <= sp.MinimumStock {
+				if sp.IsActive && sp.AvailableQuantity > 0 && sp.AvailableQuantity <= sp.MinimumStock {
 					filteredSpareParts = append(filteredSpareParts, sp)
 				}
 			case "out_of_stock":
-				// Out of stock: stock quantity is 0
-				if sp.StockQuantity == 0 {
+				// Out of stock: nothing left to promise
+				if sp.AvailableQuantity <= 0 {
 					filteredSpareParts = append(filteredSpareParts, sp)
 				}
 			case "in_stock":
 				// In stock: stock is above minimum stock level
-				if sp.IsActive && sp.StockQuantity > sp.MinimumStock {
+				if sp.IsActive && sp.AvailableQuantity > sp.MinimumStock {
 					filteredSpareParts = append(filteredSpareParts, sp)
 				}
 			case "available":
 				// Available: active items with stock > 0
-				if sp.IsActive && sp.StockQuantity > 0 {
+				if sp.IsActive && sp.AvailableQuantity > 0 {
 					filteredSpareParts = append(filteredSpareParts, sp)
 				}
 			case "inactive":
@@ -240,6 +242,21 @@ func (s *sparePartService) GetLowStockItems(page, limit int) ([]models.SparePart
 	return spareParts, total, nil
 }
 
+func (s *sparePartService) GetMovements(sparePartID int, page, limit int) ([]models.SparePartMovement, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	if _, err := s.sparePartRepo.GetByID(sparePartID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.sparePartRepo.GetMovements(sparePartID, page, limit)
+}
+
 func (s *sparePartService) CheckStockAvailability(id int, requestedQuantity int) (bool, error) {
 	if requestedQuantity <= 0 {
 		return false, fmt.Errorf("requested quantity must be greater than 0")
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
+)
+
+type VehicleAttributeService interface {
+	CreateDefinition(typeID int, req *models.VehicleAttributeDefinitionCreateRequest) (*models.VehicleAttributeDefinition, error)
+	GetDefinition(id int) (*models.VehicleAttributeDefinition, error)
+	ListDefinitions(typeID int, activeOnly bool) ([]models.VehicleAttributeDefinition, error)
+	UpdateDefinition(id int, req *models.VehicleAttributeDefinitionUpdateRequest) (*models.VehicleAttributeDefinition, error)
+	DeleteDefinition(id int) error
+}
+
+type vehicleAttributeService struct {
+	attributeRepo   repository.VehicleAttributeRepository
+	vehicleTypeRepo repository.VehicleTypeRepository
+}
+
+func NewVehicleAttributeService(attributeRepo repository.VehicleAttributeRepository, vehicleTypeRepo repository.VehicleTypeRepository) VehicleAttributeService {
+	return &vehicleAttributeService{
+		attributeRepo:   attributeRepo,
+		vehicleTypeRepo: vehicleTypeRepo,
+	}
+}
+
+var attributeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// maxAttributeTextLength matches vehicle_attribute_values.value
+const maxAttributeTextLength = 255
+
+func (s *vehicleAttributeService) CreateDefinition(typeID int, req *models.VehicleAttributeDefinitionCreateRequest) (*models.VehicleAttributeDefinition, error) {
+	if _, err := s.vehicleTypeRepo.GetByID(typeID); err != nil {
+		return nil, fmt.Errorf("vehicle type not found")
+	}
+
+	if !attributeCodePattern.MatchString(req.Code) {
+		return nil, fmt.Errorf("invalid attribute code, use lowercase letters, digits and underscores")
+	}
+
+	options, err := attributeOptions(req.DataType, req.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing, _ := s.attributeRepo.FindDefinitionByCode(typeID, req.Code); existing != nil {
+		return nil, fmt.Errorf("vehicle attribute %s already exists for this vehicle type", req.Code)
+	}
+
+	definition := &models.VehicleAttributeDefinition{
+		TypeID:     typeID,
+		Code:       req.Code,
+		Name:       req.Name,
+		DataType:   req.DataType,
+		Options:    options,
+		IsRequired: req.IsRequired,
+		SortOrder:  req.SortOrder,
+	}
+
+	if err := s.attributeRepo.CreateDefinition(definition); err != nil {
+		return nil, err
+	}
+
+	return s.attributeRepo.GetDefinition(definition.ID)
+}
+
+func (s *vehicleAttributeService) GetDefinition(id int) (*models.VehicleAttributeDefinition, error) {
+	return s.attributeRepo.GetDefinition(id)
+}
+
+func (s *vehicleAttributeService) ListDefinitions(typeID int, activeOnly bool) ([]models.VehicleAttributeDefinition, error) {
+	if _, err := s.vehicleTypeRepo.GetByID(typeID); err != nil {
+		return nil, fmt.Errorf("vehicle type not found")
+	}
+
+	return s.attributeRepo.ListDefinitions(typeID, activeOnly)
+}
+
+// UpdateDefinition changes an attribute. Values already stored are kept when enum options are
+// removed; they are checked again the next time the vehicle's attributes are edited.
+func (s *vehicleAttributeService) UpdateDefinition(id int, req *models.VehicleAttributeDefinitionUpdateRequest) (*models.VehicleAttributeDefinition, error) {
+	definition, err := s.attributeRepo.GetDefinition(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Options != nil {
+		options, err := attributeOptions(definition.DataType, req.Options)
+		if err != nil {
+			return nil, err
+		}
+		req.Options = options
+	}
+
+	if err := s.attributeRepo.UpdateDefinition(id, req); err != nil {
+		return nil, err
+	}
+
+	return s.attributeRepo.GetDefinition(id)
+}
+
+func (s *vehicleAttributeService) DeleteDefinition(id int) error {
+	return s.attributeRepo.DeleteDefinition(id)
+}
+
+// attributeOptions checks the options of an attribute: enums need at least one, other data types
+// take none. Duplicates differing only in case are dropped.
+func attributeOptions(dataType models.VehicleAttributeDataType, options []string) ([]string, error) {
+	if dataType != models.VehicleAttributeEnum {
+		if len(options) > 0 {
+			return nil, fmt.Errorf("invalid attribute options, only enum attributes have options")
+		}
+		return []string{}, nil
+	}
+
+	cleaned := make([]string, 0, len(options))
+	seen := make(map[string]bool, len(options))
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		key := strings.ToLower(option)
+		if option == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, option)
+	}
+
+	if len(cleaned) == 0 {
+		return nil, fmt.Errorf("invalid attribute options, an enum attribute needs at least one option")
+	}
+
+	return cleaned, nil
+}
+
+// resolveAttributeValues checks custom attribute input against the active attributes of the
+// vehicle type and returns the values to store by definition ID, nil removing one. current holds
+// the values the vehicle already has, so required attributes are checked on the result.
+func resolveAttributeValues(definitions []models.VehicleAttributeDefinition, input map[string]interface{}, current []models.VehicleAttributeValue) (map[int]*string, error) {
+	byCode := make(map[string]*models.VehicleAttributeDefinition, len(definitions))
+	for i := range definitions {
+		byCode[definitions[i].Code] = &definitions[i]
+	}
+
+	codes := make([]string, 0, len(input))
+	for code := range input {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	values := make(map[int]*string, len(input))
+	for _, code := range codes {
+		definition, ok := byCode[code]
+		if !ok {
+			return nil, fmt.Errorf("invalid attribute %s for this vehicle type", code)
+		}
+
+		value, err := normalizeAttributeValue(definition, input[code])
+		if err != nil {
+			return nil, err
+		}
+		values[definition.ID] = value
+	}
+
+	stored := make(map[int]bool, len(current))
+	for _, value := range current {
+		stored[value.DefinitionID] = true
+	}
+
+	for _, definition := range definitions {
+		if !definition.IsRequired {
+			continue
+		}
+		value, changed := values[definition.ID]
+		if (changed && value == nil) || (!changed && !stored[definition.ID]) {
+			return nil, fmt.Errorf("invalid attributes, %s is required", definition.Code)
+		}
+	}
+
+	return values, nil
+}
+
+// normalizeAttributeValue checks a value against its attribute and returns it in the stored form;
+// nil or an empty string clears the attribute
+func normalizeAttributeValue(definition *models.VehicleAttributeDefinition, raw interface{}) (*string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	if text, ok := raw.(string); ok {
+		raw = strings.TrimSpace(text)
+		if raw == "" {
+			return nil, nil
+		}
+	}
+
+	var value string
+	switch definition.DataType {
+	case models.VehicleAttributeNumber:
+		var number float64
+		switch v := raw.(type) {
+		case float64:
+			number = v
+		case string:
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid attribute %s, expected a number", definition.Code)
+			}
+			number = parsed
+		default:
+			return nil, fmt.Errorf("invalid attribute %s, expected a number", definition.Code)
+		}
+		if math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, fmt.Errorf("invalid attribute %s, expected a number", definition.Code)
+		}
+		value = strconv.FormatFloat(number, 'f', -1, 64)
+
+	case models.VehicleAttributeBoolean:
+		switch v := raw.(type) {
+		case bool:
+			value = strconv.FormatBool(v)
+		case string:
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid attribute %s, expected true or false", definition.Code)
+			}
+			value = strconv.FormatBool(parsed)
+		default:
+			return nil, fmt.Errorf("invalid attribute %s, expected true or false", definition.Code)
+		}
+
+	case models.VehicleAttributeEnum:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid attribute %s, expected one of %s", definition.Code, strings.Join(definition.Options, ", "))
+		}
+		for _, option := range definition.Options {
+			if strings.EqualFold(option, text) {
+				value = option
+				break
+			}
+		}
+		if value == "" {
+			return nil, fmt.Errorf("invalid attribute %s, expected one of %s", definition.Code, strings.Join(definition.Options, ", "))
+		}
+
+	default:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid attribute %s, expected text", definition.Code)
+		}
+		if len(text) > maxAttributeTextLength {
+			return nil, fmt.Errorf("invalid attribute %s, at most %d characters", definition.Code, maxAttributeTextLength)
+		}
+		value = text
+	}
+
+	return &value, nil
+}
+
+// validateAttributeFilters checks that the bounds of number attribute filters are numbers
+func validateAttributeFilters(filters models.VehicleSearchFilters) error {
+	for param, bounds := range map[string]map[string]string{"attr_min": filters.AttributeMin, "attr_max": filters.AttributeMax} {
+		for code, bound := range bounds {
+			if _, err := strconv.ParseFloat(bound, 64); err != nil {
+				return fmt.Errorf("invalid attribute filter %s[%s], expected a number", param, code)
+			}
+		}
+	}
+	return nil
+}
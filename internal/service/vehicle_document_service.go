@@ -0,0 +1,292 @@
+package service
+
+import (
+	"fmt"
+	"mime/multipart"
+	"time"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
+)
+
+type VehicleDocumentService interface {
+	CreateDocument(vehicleID int, req *models.VehicleDocumentCreateRequest, createdBy int) (*models.VehicleDocument, error)
+	GetDocument(id int) (*models.VehicleDocument, error)
+	ListVehicleDocuments(vehicleID int) ([]models.VehicleDocument, error)
+	UpdateDocument(id int, req *models.VehicleDocumentUpdateRequest) (*models.VehicleDocument, error)
+	DeleteDocument(id int) error
+	UploadAttachment(documentID int, req *models.VehicleDocumentAttachmentUploadRequest, file *multipart.FileHeader, uploadedBy int) (*models.VehicleDocumentAttachment, error)
+	DeleteAttachment(documentID int, attachmentID int) error
+	HandOver(salesTransactionID int, req *models.DocumentHandoverRequest, handedOverBy int) (*models.DocumentHandover, error)
+	GetHandovers(salesTransactionID int) ([]models.DocumentHandover, error)
+	ListPendingHandovers(documentType string, page, limit int) ([]models.PendingDocumentHandover, int, error)
+}
+
+type vehicleDocumentService struct {
+	documentRepo    repository.VehicleDocumentRepository
+	vehicleRepo     repository.VehicleRepository
+	transactionRepo repository.TransactionRepository
+	fileUploader    *FileUploader
+}
+
+func NewVehicleDocumentService(documentRepo repository.VehicleDocumentRepository, vehicleRepo repository.VehicleRepository, transactionRepo repository.TransactionRepository, fileUploader *FileUploader) VehicleDocumentService {
+	return &vehicleDocumentService{
+		documentRepo:    documentRepo,
+		vehicleRepo:     vehicleRepo,
+		transactionRepo: transactionRepo,
+		fileUploader:    fileUploader,
+	}
+}
+
+func (s *vehicleDocumentService) CreateDocument(vehicleID int, req *models.VehicleDocumentCreateRequest, createdBy int) (*models.VehicleDocument, error) {
+	if _, err := s.vehicleRepo.GetByID(vehicleID); err != nil {
+		return nil, err
+	}
+
+	expiryDate, err := parseOptionalDate(req.ExpiryDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiry date, use YYYY-MM-DD")
+	}
+
+	document := &models.VehicleDocument{
+		VehicleID:       vehicleID,
+		DocumentType:    req.DocumentType,
+		DocumentNumber:  req.DocumentNumber,
+		ExpiryDate:      expiryDate,
+		StorageLocation: req.StorageLocation,
+		Notes:           req.Notes,
+		CreatedBy:       createdBy,
+	}
+
+	if err := s.documentRepo.Create(document); err != nil {
+		return nil, err
+	}
+
+	return s.GetDocument(document.ID)
+}
+
+func (s *vehicleDocumentService) GetDocument(id int) (*models.VehicleDocument, error) {
+	document, err := s.documentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	s.setAttachmentURLs(document.Attachments)
+
+	return document, nil
+}
+
+func (s *vehicleDocumentService) ListVehicleDocuments(vehicleID int) ([]models.VehicleDocument, error) {
+	if _, err := s.vehicleRepo.GetByID(vehicleID); err != nil {
+		return nil, err
+	}
+
+	documents, err := s.documentRepo.ListByVehicle(vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range documents {
+		s.setAttachmentURLs(documents[i].Attachments)
+	}
+
+	return documents, nil
+}
+
+func (s *vehicleDocumentService) UpdateDocument(id int, req *models.VehicleDocumentUpdateRequest) (*models.VehicleDocument, error) {
+	document, err := s.documentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The buyer has the document now, its handover record is final
+	if document.CustodyStatus == models.DocumentCustodyHandedOver && req.CustodyStatus != nil {
+		return nil, fmt.Errorf("document was already handed over")
+	}
+
+	if req.ExpiryDate != nil {
+		if _, err := parseOptionalDate(req.ExpiryDate); err != nil {
+			return nil, fmt.Errorf("invalid expiry date, use YYYY-MM-DD")
+		}
+	}
+
+	if err := s.documentRepo.Update(id, req); err != nil {
+		return nil, err
+	}
+
+	return s.GetDocument(id)
+}
+
+func (s *vehicleDocumentService) DeleteDocument(id int) error {
+	document, err := s.documentRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if document.CustodyStatus == models.DocumentCustodyHandedOver {
+		return fmt.Errorf("handed over documents cannot be deleted")
+	}
+
+	if err := s.documentRepo.Delete(id); err != nil {
+		return err
+	}
+
+	// Attachment rows are removed by the cascade, their files are not
+	for _, attachment := range document.Attachments {
+		s.removeAttachmentFiles(attachment)
+	}
+
+	return nil
+}
+
+// UploadAttachment stores a scan (JPEG/PNG/PDF) of a document
+func (s *vehicleDocumentService) UploadAttachment(documentID int, req *models.VehicleDocumentAttachmentUploadRequest, file *multipart.FileHeader, uploadedBy int) (*models.VehicleDocumentAttachment, error) {
+	document, err := s.documentRepo.GetByID(documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.fileUploader.Store(fmt.Sprintf("vehicles/%d/documents/%d", document.VehicleID, document.ID), file, documentUploadTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &models.VehicleDocumentAttachment{
+		DocumentID:    document.ID,
+		FilePath:      stored.Path,
+		ThumbnailPath: stored.ThumbnailPath,
+		Caption:       req.Caption,
+		FileName:      stored.FileName,
+		ContentType:   stored.ContentType,
+		FileSize:      stored.Size,
+		UploadedBy:    uploadedBy,
+	}
+
+	if err := s.documentRepo.AddAttachment(attachment); err != nil {
+		s.removeAttachmentFiles(*attachment)
+		return nil, fmt.Errorf("failed to save attachment: %v", err)
+	}
+
+	attachments := []models.VehicleDocumentAttachment{*attachment}
+	s.setAttachmentURLs(attachments)
+
+	return &attachments[0], nil
+}
+
+func (s *vehicleDocumentService) DeleteAttachment(documentID int, attachmentID int) error {
+	attachment, err := s.documentRepo.GetAttachment(documentID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.documentRepo.DeleteAttachment(documentID, attachmentID); err != nil {
+		return err
+	}
+
+	s.removeAttachmentFiles(*attachment)
+
+	return nil
+}
+
+// HandOver records documents of the sold vehicle given to the buyer. A sale may need several
+// handovers, e.g. the BPKB only after a leasing company has been paid.
+func (s *vehicleDocumentService) HandOver(salesTransactionID int, req *models.DocumentHandoverRequest, handedOverBy int) (*models.DocumentHandover, error) {
+	sale, err := s.transactionRepo.GetSalesTransactionByID(salesTransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("sales transaction not found")
+	}
+
+	documentIDs := make([]int, 0, len(req.DocumentIDs))
+	seen := make(map[int]bool, len(req.DocumentIDs))
+	for _, id := range req.DocumentIDs {
+		if !seen[id] {
+			seen[id] = true
+			documentIDs = append(documentIDs, id)
+		}
+	}
+
+	handedOverAt := time.Now()
+	if req.HandedOverAt != nil {
+		if req.HandedOverAt.After(handedOverAt) {
+			return nil, fmt.Errorf("handover time cannot be in the future")
+		}
+		handedOverAt = *req.HandedOverAt
+	}
+
+	handover := &models.DocumentHandover{
+		SalesTransactionID: sale.ID,
+		RecipientName:      req.RecipientName,
+		RecipientPhone:     req.RecipientPhone,
+		HandedOverAt:       handedOverAt,
+		Notes:              req.Notes,
+		HandedOverBy:       handedOverBy,
+	}
+
+	if err := s.documentRepo.CreateHandover(handover, sale.VehicleID, documentIDs); err != nil {
+		return nil, err
+	}
+
+	handovers, err := s.GetHandovers(sale.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range handovers {
+		if handovers[i].ID == handover.ID {
+			return &handovers[i], nil
+		}
+	}
+
+	return handover, nil
+}
+
+func (s *vehicleDocumentService) GetHandovers(salesTransactionID int) ([]models.DocumentHandover, error) {
+	if _, err := s.transactionRepo.GetSalesTransactionByID(salesTransactionID); err != nil {
+		return nil, fmt.Errorf("sales transaction not found")
+	}
+
+	handovers, err := s.documentRepo.GetHandovers(salesTransactionID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range handovers {
+		for j := range handovers[i].Documents {
+			s.setAttachmentURLs(handovers[i].Documents[j].Attachments)
+		}
+	}
+
+	return handovers, nil
+}
+
+func (s *vehicleDocumentService) ListPendingHandovers(documentType string, page, limit int) ([]models.PendingDocumentHandover, int, error) {
+	switch models.VehicleDocumentType(documentType) {
+	case "", models.VehicleDocumentTypeBPKB, models.VehicleDocumentTypeSTNK, models.VehicleDocumentTypePurchaseInvoice,
+		models.VehicleDocumentTypeSpareKey, models.VehicleDocumentTypeOther:
+	default:
+		return nil, 0, fmt.Errorf("invalid document type: %s", documentType)
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	return s.documentRepo.ListPendingHandovers(documentType, page, limit)
+}
+
+func (s *vehicleDocumentService) setAttachmentURLs(attachments []models.VehicleDocumentAttachment) {
+	for i := range attachments {
+		attachments[i].URL = s.fileUploader.URL(attachments[i].FilePath)
+		if attachments[i].ThumbnailPath != nil {
+			thumbnailURL := s.fileUploader.URL(*attachments[i].ThumbnailPath)
+			attachments[i].ThumbnailURL = &thumbnailURL
+		}
+	}
+}
+
+func (s *vehicleDocumentService) removeAttachmentFiles(attachment models.VehicleDocumentAttachment) {
+	paths := []string{attachment.FilePath}
+	if attachment.ThumbnailPath != nil {
+		paths = append(paths, *attachment.ThumbnailPath)
+	}
+	s.fileUploader.Remove(paths...)
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
+)
+
+type VehicleExpenseService interface {
+	AddExpense(vehicleID int, req *models.VehicleExpenseCreateRequest, createdBy int) (*models.VehicleExpense, error)
+	ListExpenses(vehicleID int) ([]models.VehicleExpense, error)
+	DeleteExpense(vehicleID int, expenseID int) error
+	ListRegistrationDue(days int) ([]models.VehicleRegistrationDue, error)
+	AlertRegistrationDue() (int, error)
+}
+
+type vehicleExpenseService struct {
+	expenseRepo     repository.VehicleExpenseRepository
+	vehicleRepo     repository.VehicleRepository
+	userRepo        repository.UserRepository
+	notificationSvc NotificationService
+	dueWindowDays   int
+}
+
+func NewVehicleExpenseService(expenseRepo repository.VehicleExpenseRepository, vehicleRepo repository.VehicleRepository, userRepo repository.UserRepository, notificationSvc NotificationService, dueWindowDays int) VehicleExpenseService {
+	return &vehicleExpenseService{
+		expenseRepo:     expenseRepo,
+		vehicleRepo:     vehicleRepo,
+		userRepo:        userRepo,
+		notificationSvc: notificationSvc,
+		dueWindowDays:   dueWindowDays,
+	}
+}
+
+// AddExpense records a cost paid for a vehicle in stock and adds it to the HPP. Paying the
+// annual tax moves the tax due date a year; a plate renewal also covers that year's tax.
+func (s *vehicleExpenseService) AddExpense(vehicleID int, req *models.VehicleExpenseCreateRequest, createdBy int) (*models.VehicleExpense, error) {
+	vehicle, err := s.vehicleRepo.GetByID(vehicleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The sale has already taken the HPP over
+	if vehicle.Status == models.VehicleStatusSold {
+		return nil, fmt.Errorf("cannot add expenses to a sold vehicle")
+	}
+
+	now := time.Now()
+	expenseDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if req.ExpenseDate != nil && *req.ExpenseDate != "" {
+		expenseDate, err = time.Parse("2006-01-02", *req.ExpenseDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expense date, use YYYY-MM-DD")
+		}
+	}
+
+	nextDueDate, err := parseOptionalDate(req.NextDueDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid next due date, use YYYY-MM-DD")
+	}
+
+	var taxDueDate, plateRenewalDue *time.Time
+	switch req.ExpenseType {
+	case models.VehicleExpenseStnkTax:
+		taxDueDate = nextDueDate
+		if taxDueDate == nil {
+			taxDueDate = nextRegistrationDate(vehicle.TaxDueDate, expenseDate, 1)
+		}
+	case models.VehicleExpensePlateRenewal:
+		plateRenewalDue = nextDueDate
+		if plateRenewalDue == nil {
+			plateRenewalDue = nextRegistrationDate(vehicle.PlateRenewalDue, expenseDate, 5)
+		}
+		taxDueDate = nextRegistrationDate(vehicle.TaxDueDate, expenseDate, 1)
+	default:
+		if nextDueDate != nil {
+			return nil, fmt.Errorf("next due date only applies to tax and plate renewal payments")
+		}
+	}
+
+	expense := &models.VehicleExpense{
+		VehicleID:       vehicleID,
+		ExpenseType:     req.ExpenseType,
+		Amount:          req.Amount,
+		ExpenseDate:     expenseDate,
+		Description:     req.Description,
+		ReferenceNumber: req.ReferenceNumber,
+		CreatedBy:       createdBy,
+	}
+
+	if err := s.expenseRepo.Create(expense, taxDueDate, plateRenewalDue); err != nil {
+		return nil, err
+	}
+
+	return s.expenseRepo.GetByID(vehicleID, expense.ID)
+}
+
+func (s *vehicleExpenseService) ListExpenses(vehicleID int) ([]models.VehicleExpense, error) {
+	if _, err := s.vehicleRepo.GetByID(vehicleID); err != nil {
+		return nil, err
+	}
+
+	return s.expenseRepo.ListByVehicle(vehicleID)
+}
+
+func (s *vehicleExpenseService) DeleteExpense(vehicleID int, expenseID int) error {
+	vehicle, err := s.vehicleRepo.GetByID(vehicleID)
+	if err != nil {
+		return err
+	}
+
+	if vehicle.Status == models.VehicleStatusSold {
+		return fmt.Errorf("cannot delete expenses of a sold vehicle")
+	}
+
+	return s.expenseRepo.Delete(vehicleID, expenseID)
+}
+
+// ListRegistrationDue lists tax and plate renewal dates of vehicles in stock that are overdue or
+// due within days; zero uses the configured window
+func (s *vehicleExpenseService) ListRegistrationDue(days int) ([]models.VehicleRegistrationDue, error) {
+	if days <= 0 {
+		days = s.dueWindowDays
+	}
+
+	return s.expenseRepo.ListRegistrationDue(time.Now().AddDate(0, 0, days))
+}
+
+// AlertRegistrationDue notifies the admins of tax and plate renewal dates coming up within the
+// configured window; each due date is alerted once
+func (s *vehicleExpenseService) AlertRegistrationDue() (int, error) {
+	dues, err := s.ListRegistrationDue(0)
+	if err != nil {
+		return 0, err
+	}
+	if len(dues) == 0 {
+		return 0, nil
+	}
+
+	admins, err := s.userRepo.GetUsersByRole("admin")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get admins: %v", err)
+	}
+
+	alerted := 0
+	for i := range dues {
+		due := &dues[i]
+		isNew, err := s.expenseRepo.MarkAlerted(due)
+		if err != nil {
+			return alerted, err
+		}
+		if !isNew {
+			continue
+		}
+
+		title, message := registrationDueMessage(due)
+		for _, admin := range admins {
+			err := s.notificationSvc.Notify(admin.ID, models.NotificationRegistrationDue, title, message, "vehicle", due.VehicleID)
+			if err != nil {
+				// A missed notification must not stop the others
+				log.Printf("Warning: failed to notify user %d: %v", admin.ID, err)
+			}
+		}
+		alerted++
+	}
+
+	return alerted, nil
+}
+
+// nextRegistrationDate moves a due date on by years, counting from the payment date when the
+// vehicle had no due date yet
+func nextRegistrationDate(current *time.Time, paidOn time.Time, years int) *time.Time {
+	base := paidOn
+	if current != nil {
+		base = *current
+	}
+	next := base.AddDate(years, 0, 0)
+	return &next
+}
+
+func registrationDueMessage(due *models.VehicleRegistrationDue) (string, string) {
+	what := "STNK tax"
+	if due.DueType == models.VehicleExpensePlateRenewal {
+		what = "Plate renewal"
+	}
+
+	plate := due.VehicleCode
+	if due.LicensePlate != nil && *due.LicensePlate != "" {
+		plate = *due.LicensePlate
+	}
+
+	if due.IsOverdue {
+		return what + " overdue", fmt.Sprintf("%s of %s %s (%s) was due on %s",
+			what, due.BrandName, due.Model, plate, due.DueDate.Format("2006-01-02"))
+	}
+	return what + " due", fmt.Sprintf("%s of %s %s (%s) is due on %s",
+		what, due.BrandName, due.Model, plate, due.DueDate.Format("2006-01-02"))
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
+)
+
+type VehicleModelService interface {
+	CreateModel(brandID int, req *models.VehicleModelCreateRequest) (*models.VehicleModel, error)
+	GetModel(id int) (*models.VehicleModel, error)
+	ListBrandModels(brandID int, activeOnly bool) ([]models.VehicleModel, error)
+	UpdateModel(id int, req *models.VehicleModelUpdateRequest) (*models.VehicleModel, error)
+	DeleteModel(id int) error
+	CreateVariant(modelID int, req *models.VehicleVariantCreateRequest) (*models.VehicleVariant, error)
+	GetVariant(id int) (*models.VehicleVariant, error)
+	ListVariants(modelID int, activeOnly bool) ([]models.VehicleVariant, error)
+	UpdateVariant(id int, req *models.VehicleVariantUpdateRequest) (*models.VehicleVariant, error)
+	DeleteVariant(id int) error
+	ListUnmapped() ([]models.UnmappedVehicleModel, error)
+	MapFreeText(req *models.VehicleModelMappingRequest) (*models.VehicleModelMappingResult, error)
+	AutoMap() (*models.VehicleModelMappingResult, error)
+}
+
+type vehicleModelService struct {
+	modelRepo repository.VehicleModelRepository
+	brandRepo repository.VehicleBrandRepository
+}
+
+func NewVehicleModelService(modelRepo repository.VehicleModelRepository, brandRepo repository.VehicleBrandRepository) VehicleModelService {
+	return &vehicleModelService{
+		modelRepo: modelRepo,
+		brandRepo: brandRepo,
+	}
+}
+
+func (s *vehicleModelService) CreateModel(brandID int, req *models.VehicleModelCreateRequest) (*models.VehicleModel, error) {
+	if _, err := s.brandRepo.GetByID(brandID); err != nil {
+		return nil, err
+	}
+
+	if existing, _ := s.modelRepo.FindModelByName(brandID, req.Name); existing != nil {
+		return nil, fmt.Errorf("vehicle model %s already exists for this brand", existing.Name)
+	}
+
+	model := &models.VehicleModel{
+		BrandID:  brandID,
+		Name:     req.Name,
+		IsActive: req.IsActive == nil || *req.IsActive,
+	}
+
+	if err := s.modelRepo.CreateModel(model); err != nil {
+		return nil, err
+	}
+
+	return s.GetModel(model.ID)
+}
+
+// GetModel returns the model with all its variants, inactive ones included
+func (s *vehicleModelService) GetModel(id int) (*models.VehicleModel, error) {
+	model, err := s.modelRepo.GetModel(id)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := s.modelRepo.ListVariants([]int{model.ID}, false)
+	if err != nil {
+		return nil, err
+	}
+	model.Variants = variants
+
+	return model, nil
+}
+
+// ListBrandModels returns the catalog of a brand with the variants of each model
+func (s *vehicleModelService) ListBrandModels(brandID int, activeOnly bool) ([]models.VehicleModel, error) {
+	if _, err := s.brandRepo.GetByID(brandID); err != nil {
+		return nil, err
+	}
+
+	vehicleModels, err := s.modelRepo.ListModels(brandID, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	modelIDs := make([]int, len(vehicleModels))
+	for i, model := range vehicleModels {
+		modelIDs[i] = model.ID
+	}
+
+	variants, err := s.modelRepo.ListVariants(modelIDs, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	byModel := make(map[int][]models.VehicleVariant)
+	for _, variant := range variants {
+		byModel[variant.ModelID] = append(byModel[variant.ModelID], variant)
+	}
+	for i := range vehicleModels {
+		vehicleModels[i].Variants = byModel[vehicleModels[i].ID]
+	}
+
+	return vehicleModels, nil
+}
+
+func (s *vehicleModelService) UpdateModel(id int, req *models.VehicleModelUpdateRequest) (*models.VehicleModel, error) {
+	model, err := s.modelRepo.GetModel(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if existing, _ := s.modelRepo.FindModelByName(model.BrandID, *req.Name); existing != nil && existing.ID != id {
+			return nil, fmt.Errorf("vehicle model %s already exists for this brand", existing.Name)
+		}
+	}
+
+	if err := s.modelRepo.UpdateModel(id, req); err != nil {
+		return nil, err
+	}
+
+	return s.GetModel(id)
+}
+
+func (s *vehicleModelService) DeleteModel(id int) error {
+	return s.modelRepo.DeleteModel(id)
+}
+
+func (s *vehicleModelService) CreateVariant(modelID int, req *models.VehicleVariantCreateRequest) (*models.VehicleVariant, error) {
+	if _, err := s.modelRepo.GetModel(modelID); err != nil {
+		return nil, err
+	}
+
+	if err := validateVariantYears(req.YearFrom, req.YearTo); err != nil {
+		return nil, err
+	}
+
+	if existing, _ := s.modelRepo.FindVariantByName(modelID, req.Name); existing != nil {
+		return nil, fmt.Errorf("vehicle variant %s already exists for this model", existing.Name)
+	}
+
+	variant := &models.VehicleVariant{
+		ModelID:          modelID,
+		Name:             req.Name,
+		EngineCapacity:   req.EngineCapacity,
+		FuelType:         req.FuelType,
+		TransmissionType: req.TransmissionType,
+		YearFrom:         req.YearFrom,
+		YearTo:           req.YearTo,
+		IsActive:         req.IsActive == nil || *req.IsActive,
+	}
+
+	if err := s.modelRepo.CreateVariant(variant); err != nil {
+		return nil, err
+	}
+
+	return s.modelRepo.GetVariant(variant.ID)
+}
+
+func (s *vehicleModelService) GetVariant(id int) (*models.VehicleVariant, error) {
+	return s.modelRepo.GetVariant(id)
+}
+
+func (s *vehicleModelService) ListVariants(modelID int, activeOnly bool) ([]models.VehicleVariant, error) {
+	if _, err := s.modelRepo.GetModel(modelID); err != nil {
+		return nil, err
+	}
+
+	return s.modelRepo.ListVariants([]int{modelID}, activeOnly)
+}
+
+func (s *vehicleModelService) UpdateVariant(id int, req *models.VehicleVariantUpdateRequest) (*models.VehicleVariant, error) {
+	variant, err := s.modelRepo.GetVariant(id)
+	if err != nil {
+		return nil, err
+	}
+
+	yearFrom, yearTo := variant.YearFrom, variant.YearTo
+	if req.YearFrom != nil {
+		yearFrom = req.YearFrom
+	}
+	if req.YearTo != nil {
+		yearTo = req.YearTo
+	}
+	if err := validateVariantYears(yearFrom, yearTo); err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if existing, _ := s.modelRepo.FindVariantByName(variant.ModelID, *req.Name); existing != nil && existing.ID != id {
+			return nil, fmt.Errorf("vehicle variant %s already exists for this model", existing.Name)
+		}
+	}
+
+	if err := s.modelRepo.UpdateVariant(id, req); err != nil {
+		return nil, err
+	}
+
+	return s.modelRepo.GetVariant(id)
+}
+
+func (s *vehicleModelService) DeleteVariant(id int) error {
+	return s.modelRepo.DeleteVariant(id)
+}
+
+// ListUnmapped lists the free-text models still in use, each with a suggested catalog entry
+// when its name matches one
+func (s *vehicleModelService) ListUnmapped() ([]models.UnmappedVehicleModel, error) {
+	return s.modelRepo.ListUnmapped()
+}
+
+// MapFreeText moves the vehicles with a free-text model onto a catalog model and variant
+func (s *vehicleModelService) MapFreeText(req *models.VehicleModelMappingRequest) (*models.VehicleModelMappingResult, error) {
+	model, err := s.modelRepo.GetModel(req.ModelID)
+	if err != nil {
+		return nil, err
+	}
+	if model.BrandID != req.BrandID {
+		return nil, fmt.Errorf("invalid model: %s is not a model of this brand", model.Name)
+	}
+
+	if req.VariantID != nil {
+		variant, err := s.modelRepo.GetVariant(*req.VariantID)
+		if err != nil {
+			return nil, err
+		}
+		if variant.ModelID != model.ID {
+			return nil, fmt.Errorf("invalid variant: %s is not a variant of %s", variant.Name, model.Name)
+		}
+	}
+
+	mapped, err := s.modelRepo.MapFreeText(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.VehicleModelMappingResult{VehiclesMapped: mapped}, nil
+}
+
+// AutoMap maps every free-text model that has a suggested catalog entry, leaving the rest for
+// manual mapping
+func (s *vehicleModelService) AutoMap() (*models.VehicleModelMappingResult, error) {
+	unmapped, err := s.modelRepo.ListUnmapped()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.VehicleModelMappingResult{}
+	for _, entry := range unmapped {
+		if entry.SuggestedModelID == nil {
+			continue
+		}
+
+		mapped, err := s.modelRepo.MapFreeText(&models.VehicleModelMappingRequest{
+			BrandID:   entry.BrandID,
+			Model:     entry.Model,
+			ModelID:   *entry.SuggestedModelID,
+			VariantID: entry.SuggestedVariantID,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.VehiclesMapped += mapped
+	}
+
+	return result, nil
+}
+
+func validateVariantYears(yearFrom, yearTo *int) error {
+	if yearFrom != nil && yearTo != nil && *yearTo < *yearFrom {
+		return fmt.Errorf("invalid year range, year_to is before year_from")
+	}
+	return nil
+}
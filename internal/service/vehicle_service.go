@@ -3,6 +3,8 @@ package service
 import (
 	"fmt"
 	"log"
+	"mime/multipart"
+	"strings"
 
 	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
 	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
@@ -12,26 +14,40 @@ import (
 type VehicleService interface {
 	Create(req *models.VehicleCreateRequest, createdBy int) (*models.Vehicle, error)
 	GetByID(id int) (*models.Vehicle, error)
-	Update(id int, req *models.VehicleUpdateRequest) (*models.Vehicle, error)
+	Update(id int, req *models.VehicleUpdateRequest, updatedBy int) (*models.Vehicle, error)
 	Delete(id int) error
 	List(page, limit int, status *models.VehicleStatus) ([]models.Vehicle, int64, error)
 	GetAvailableVehicles(page, limit int) ([]models.Vehicle, int64, error)
 	GetVehiclesInRepair(page, limit int) ([]models.Vehicle, int64, error)
-	SetSellingPrice(id int, sellingPrice float64) error
+	SetSellingPrice(id int, sellingPrice float64, changedBy int) error
 	MarkForRepair(id int) error
 	CompleteRepair(id int, repairCost float64) error
 	CalculateHPP(id int) error
 	SearchVehicles(page, limit int, filters models.VehicleSearchFilters) ([]models.Vehicle, int64, error)
 	GetAllBrands() ([]models.VehicleBrand, error)
+	UploadPhoto(vehicleID int, req *models.VehiclePhotoUploadRequest, file *multipart.FileHeader, uploadedBy int) (*models.VehiclePhoto, error)
+	GetPhotos(vehicleID int) ([]models.VehiclePhoto, error)
+	ReorderPhotos(vehicleID int, req *models.VehiclePhotoReorderRequest) ([]models.VehiclePhoto, error)
+	SetPrimaryPhoto(vehicleID int, photoID int) ([]models.VehiclePhoto, error)
+	DeletePhoto(vehicleID int, photoID int) error
+	GetTimeline(id int) ([]models.VehicleTimelineEvent, error)
 }
 
 type vehicleService struct {
-	vehicleRepo repository.VehicleRepository
+	vehicleRepo   repository.VehicleRepository
+	modelRepo     repository.VehicleModelRepository
+	attributeRepo repository.VehicleAttributeRepository
+	timelineRepo  repository.VehicleTimelineRepository
+	fileUploader  *FileUploader
 }
 
-func NewVehicleService(vehicleRepo repository.VehicleRepository) VehicleService {
+func NewVehicleService(vehicleRepo repository.VehicleRepository, modelRepo repository.VehicleModelRepository, attributeRepo repository.VehicleAttributeRepository, timelineRepo repository.VehicleTimelineRepository, fileUploader *FileUploader) VehicleService {
 	return &vehicleService{
-		vehicleRepo: vehicleRepo,
+		vehicleRepo:   vehicleRepo,
+		modelRepo:     modelRepo,
+		attributeRepo: attributeRepo,
+		timelineRepo:  timelineRepo,
+		fileUploader:  fileUploader,
 	}
 }
 
@@ -51,6 +67,23 @@ func (s *vehicleService) Create(req *models.VehicleCreateRequest, createdBy int)
 		return nil, fmt.Errorf("vehicle code already exists: %s", req.Code)
 	}
 
+	if err := validateRegistrationDates(req.TaxDueDate, req.PlateRenewalDue); err != nil {
+		return nil, err
+	}
+
+	if err := s.applyCatalogModel(req); err != nil {
+		return nil, err
+	}
+
+	definitions, err := s.attributeRepo.ListDefinitionsForBrand(req.BrandID)
+	if err != nil {
+		return nil, err
+	}
+	attributeValues, err := resolveAttributeValues(definitions, req.Attributes, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create vehicle
 	vehicle, err := s.vehicleRepo.Create(req, createdBy)
 	if err != nil {
@@ -66,6 +99,14 @@ func (s *vehicleService) Create(req *models.VehicleCreateRequest, createdBy int)
 
 	vehicle.HPPPrice = &hpp
 
+	if err := s.attributeRepo.SetValues(vehicle.ID, attributeValues); err != nil {
+		return nil, fmt.Errorf("failed to save vehicle attributes: %w", err)
+	}
+	vehicle.Attributes, err = s.attributeRepo.GetValues(vehicle.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return vehicle, nil
 }
 
@@ -75,22 +116,80 @@ func (s *vehicleService) GetByID(id int) (*models.Vehicle, error) {
 		return nil, fmt.Errorf("failed to get vehicle: %w", err)
 	}
 
+	photos, err := s.vehicleRepo.GetPhotos(id)
+	if err != nil {
+		return nil, err
+	}
+	s.setPhotoURLs(photos)
+	vehicle.Photos = photos
+
+	vehicle.Attributes, err = s.attributeRepo.GetValues(id)
+	if err != nil {
+		return nil, err
+	}
+
 	return vehicle, nil
 }
 
-func (s *vehicleService) Update(id int, req *models.VehicleUpdateRequest) (*models.Vehicle, error) {
+func (s *vehicleService) Update(id int, req *models.VehicleUpdateRequest, updatedBy int) (*models.Vehicle, error) {
 	// Check if vehicle exists
-	_, err := s.vehicleRepo.GetByID(id)
+	existing, err := s.vehicleRepo.GetByID(id)
 	if err != nil {
 		return nil, fmt.Errorf("vehicle not found")
 	}
 
-	// Update vehicle
-	vehicle, err := s.vehicleRepo.Update(id, req)
+	if err := validateRegistrationDates(req.TaxDueDate, req.PlateRenewalDue); err != nil {
+		return nil, err
+	}
+
+	if req.Model != nil || req.ModelID != nil || req.VariantID != nil {
+		name := ""
+		if req.Model != nil {
+			name = *req.Model
+		}
+		model, variant, err := s.resolveCatalogModel(existing.BrandID, req.ModelID, req.VariantID, name)
+		if err != nil {
+			return nil, err
+		}
+		if model != nil {
+			req.ModelID = &model.ID
+			req.Model = &model.Name
+			req.VariantID = nil
+			if variant != nil {
+				req.VariantID = &variant.ID
+			}
+		} else if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid model, pick one from the catalog or enter its name")
+		}
+	}
+
+	var attributeValues map[int]*string
+	if req.Attributes != nil {
+		definitions, err := s.attributeRepo.ListDefinitionsForBrand(existing.BrandID)
+		if err != nil {
+			return nil, err
+		}
+		current, err := s.attributeRepo.GetValues(id)
+		if err != nil {
+			return nil, err
+		}
+		attributeValues, err = resolveAttributeValues(definitions, req.Attributes, current)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Update vehicle with its attributes and timeline entries
+	vehicle, err := s.vehicleRepo.UpdateWithHistory(id, req, attributeValues, updatedBy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update vehicle: %w", err)
 	}
 
+	vehicle.Attributes, err = s.attributeRepo.GetValues(id)
+	if err != nil {
+		return nil, err
+	}
+
 	return vehicle, nil
 }
 
@@ -106,12 +205,22 @@ func (s *vehicleService) Delete(id int) error {
 		return fmt.Errorf("cannot delete sold vehicle")
 	}
 
+	photos, err := s.vehicleRepo.GetPhotos(id)
+	if err != nil {
+		return err
+	}
+
 	// Delete vehicle
 	err = s.vehicleRepo.Delete(id)
 	if err != nil {
 		return fmt.Errorf("failed to delete vehicle: %w", err)
 	}
 
+	// Photo rows are removed by the cascade, their files are not
+	for _, photo := range photos {
+		s.removePhotoFiles(photo)
+	}
+
 	return nil
 }
 
@@ -128,6 +237,10 @@ func (s *vehicleService) List(page, limit int, status *models.VehicleStatus) ([]
 		return nil, 0, fmt.Errorf("failed to list vehicles: %w", err)
 	}
 
+	if err := s.attachPhotos(vehicles); err != nil {
+		return nil, 0, err
+	}
+
 	return vehicles, total, nil
 }
 
@@ -144,6 +257,10 @@ func (s *vehicleService) GetAvailableVehicles(page, limit int) ([]models.Vehicle
 		return nil, 0, fmt.Errorf("failed to get available vehicles: %w", err)
 	}
 
+	if err := s.attachPhotos(vehicles); err != nil {
+		return nil, 0, err
+	}
+
 	return vehicles, total, nil
 }
 
@@ -160,10 +277,14 @@ func (s *vehicleService) GetVehiclesInRepair(page, limit int) ([]models.Vehicle,
 		return nil, 0, fmt.Errorf("failed to get vehicles in repair: %w", err)
 	}
 
+	if err := s.attachPhotos(vehicles); err != nil {
+		return nil, 0, err
+	}
+
 	return vehicles, total, nil
 }
 
-func (s *vehicleService) SetSellingPrice(id int, sellingPrice float64) error {
+func (s *vehicleService) SetSellingPrice(id int, sellingPrice float64, changedBy int) error {
 	// Check if vehicle exists and is available
 	vehicle, err := s.vehicleRepo.GetByID(id)
 	if err != nil {
@@ -180,7 +301,16 @@ func (s *vehicleService) SetSellingPrice(id int, sellingPrice float64) error {
 		return fmt.Errorf("failed to update selling price: %w", err)
 	}
 
-	return nil
+	return s.timelineRepo.RecordPriceChange(id, vehicle.SellingPrice, sellingPrice, changedBy)
+}
+
+// GetTimeline returns every recorded event of a vehicle, oldest first
+func (s *vehicleService) GetTimeline(id int) ([]models.VehicleTimelineEvent, error) {
+	if _, err := s.vehicleRepo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("vehicle not found")
+	}
+
+	return s.timelineRepo.GetTimeline(id)
 }
 
 func (s *vehicleService) MarkForRepair(id int) error {
@@ -241,11 +371,14 @@ func (s *vehicleService) CalculateHPP(id int) error {
 		return fmt.Errorf("vehicle not found")
 	}
 
-	// Calculate HPP = Purchase Price + Repair Cost
+	// Calculate HPP = Purchase Price + Repair Cost + Expenses (taxes and fees)
 	repairCost := float64(0)
 	if vehicle.RepairCost != nil {
 		repairCost = *vehicle.RepairCost
 	}
+	if vehicle.ExpenseCost != nil {
+		repairCost += *vehicle.ExpenseCost
+	}
 	hpp := utils.CalculateHPP(vehicle.PurchasePrice, repairCost)
 
 	// Update HPP price
@@ -257,6 +390,100 @@ func (s *vehicleService) CalculateHPP(id int) error {
 	return nil
 }
 
+// validateRegistrationDates checks the optional STNK tax and plate renewal due dates
+func validateRegistrationDates(taxDueDate, plateRenewalDue *string) error {
+	if _, err := parseOptionalDate(taxDueDate); err != nil {
+		return fmt.Errorf("invalid tax due date, use YYYY-MM-DD")
+	}
+	if _, err := parseOptionalDate(plateRenewalDue); err != nil {
+		return fmt.Errorf("invalid plate renewal due date, use YYYY-MM-DD")
+	}
+	return nil
+}
+
+// applyCatalogModel links a new vehicle to the model catalog and fills the engine capacity, fuel
+// and transmission left empty from the variant. A free-text model stays as it is when no catalog
+// model has its name; it can be mapped later.
+func (s *vehicleService) applyCatalogModel(req *models.VehicleCreateRequest) error {
+	model, variant, err := s.resolveCatalogModel(req.BrandID, req.ModelID, req.VariantID, req.Model)
+	if err != nil {
+		return err
+	}
+
+	if model == nil {
+		if strings.TrimSpace(req.Model) == "" {
+			return fmt.Errorf("invalid model, pick one from the catalog or enter its name")
+		}
+		return nil
+	}
+
+	req.ModelID = &model.ID
+	req.Model = model.Name
+	req.VariantID = nil
+	if variant != nil {
+		req.VariantID = &variant.ID
+		if req.EngineCapacity == nil {
+			req.EngineCapacity = variant.EngineCapacity
+		}
+		if req.FuelType == nil {
+			req.FuelType = variant.FuelType
+		}
+		if req.TransmissionType == nil {
+			req.TransmissionType = variant.TransmissionType
+		}
+	}
+
+	return nil
+}
+
+// resolveCatalogModel finds the catalog model (and variant) of a vehicle of the brand. A variant
+// implies its model; without ids the free-text name is matched against the brand's models.
+func (s *vehicleService) resolveCatalogModel(brandID int, modelID, variantID *int, name string) (*models.VehicleModel, *models.VehicleVariant, error) {
+	var variant *models.VehicleVariant
+	if variantID != nil {
+		found, err := s.modelRepo.GetVariant(*variantID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid variant: %v", err)
+		}
+		if modelID != nil && *modelID != found.ModelID {
+			return nil, nil, fmt.Errorf("invalid variant: %s is not a variant of the given model", found.Name)
+		}
+		if !found.IsActive {
+			return nil, nil, fmt.Errorf("invalid variant: %s is no longer offered", found.Name)
+		}
+		variant = found
+		modelID = &found.ModelID
+	}
+
+	if modelID != nil {
+		model, err := s.modelRepo.GetModel(*modelID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid model: %v", err)
+		}
+		if model.BrandID != brandID {
+			return nil, nil, fmt.Errorf("invalid model: %s is not a model of this brand", model.Name)
+		}
+		if !model.IsActive {
+			return nil, nil, fmt.Errorf("invalid model: %s is no longer offered", model.Name)
+		}
+		return model, variant, nil
+	}
+
+	if strings.TrimSpace(name) == "" {
+		return nil, nil, nil
+	}
+
+	model, err := s.modelRepo.FindModelByName(brandID, name)
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "not found") {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	return model, nil, nil
+}
+
 // generateUniqueVehicleCode generates a unique vehicle code in VEH001, VEH002, etc. format
 func (s *vehicleService) generateUniqueVehicleCode() (string, error) {
 	// Start with a simple sequential approach
@@ -276,15 +503,152 @@ func (s *vehicleService) generateUniqueVehicleCode() (string, error) {
 }
 
 func (s *vehicleService) SearchVehicles(page, limit int, filters models.VehicleSearchFilters) ([]models.Vehicle, int64, error) {
+	if err := validateAttributeFilters(filters); err != nil {
+		return nil, 0, err
+	}
+
 	offset := (page - 1) * limit
 	vehicles, totalCount, err := s.vehicleRepo.SearchVehicles(offset, limit, filters)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to search vehicles: %w", err)
 	}
 
+	if err := s.attachPhotos(vehicles); err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]int, len(vehicles))
+	for i := range vehicles {
+		ids[i] = vehicles[i].ID
+	}
+	attributesByVehicle, err := s.attributeRepo.GetValuesByVehicles(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range vehicles {
+		vehicles[i].Attributes = attributesByVehicle[vehicles[i].ID]
+	}
+
 	return vehicles, totalCount, nil
 }
 
 func (s *vehicleService) GetAllBrands() ([]models.VehicleBrand, error) {
 	return s.vehicleRepo.GetAllBrands()
 }
+
+func (s *vehicleService) UploadPhoto(vehicleID int, req *models.VehiclePhotoUploadRequest, file *multipart.FileHeader, uploadedBy int) (*models.VehiclePhoto, error) {
+	// Check if vehicle exists
+	_, err := s.vehicleRepo.GetByID(vehicleID)
+	if err != nil {
+		return nil, fmt.Errorf("vehicle not found")
+	}
+
+	stored, err := s.fileUploader.Store(fmt.Sprintf("vehicles/%d", vehicleID), file, imageUploadTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	photo := &models.VehiclePhoto{
+		VehicleID:     vehicleID,
+		PhotoPath:     stored.Path,
+		ThumbnailPath: stored.ThumbnailPath,
+		IsPrimary:     req.IsPrimary,
+		Caption:       req.Caption,
+		ContentType:   &stored.ContentType,
+		FileSize:      stored.Size,
+		UploadedBy:    &uploadedBy,
+	}
+
+	err = s.vehicleRepo.AddPhoto(photo)
+	if err != nil {
+		s.removePhotoFiles(*photo)
+		return nil, err
+	}
+
+	photos := []models.VehiclePhoto{*photo}
+	s.setPhotoURLs(photos)
+
+	return &photos[0], nil
+}
+
+func (s *vehicleService) GetPhotos(vehicleID int) ([]models.VehiclePhoto, error) {
+	// Check if vehicle exists
+	_, err := s.vehicleRepo.GetByID(vehicleID)
+	if err != nil {
+		return nil, fmt.Errorf("vehicle not found")
+	}
+
+	photos, err := s.vehicleRepo.GetPhotos(vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	s.setPhotoURLs(photos)
+
+	return photos, nil
+}
+
+func (s *vehicleService) ReorderPhotos(vehicleID int, req *models.VehiclePhotoReorderRequest) ([]models.VehiclePhoto, error) {
+	if err := s.vehicleRepo.ReorderPhotos(vehicleID, req.PhotoIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetPhotos(vehicleID)
+}
+
+func (s *vehicleService) SetPrimaryPhoto(vehicleID int, photoID int) ([]models.VehiclePhoto, error) {
+	if err := s.vehicleRepo.SetPrimaryPhoto(vehicleID, photoID); err != nil {
+		return nil, err
+	}
+
+	return s.GetPhotos(vehicleID)
+}
+
+func (s *vehicleService) DeletePhoto(vehicleID int, photoID int) error {
+	photo, err := s.vehicleRepo.DeletePhoto(vehicleID, photoID)
+	if err != nil {
+		return err
+	}
+
+	s.removePhotoFiles(*photo)
+
+	return nil
+}
+
+// attachPhotos adds the photo gallery to each vehicle of a list
+func (s *vehicleService) attachPhotos(vehicles []models.Vehicle) error {
+	ids := make([]int, len(vehicles))
+	for i := range vehicles {
+		ids[i] = vehicles[i].ID
+	}
+
+	photosByVehicle, err := s.vehicleRepo.GetPhotosByVehicles(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range vehicles {
+		photos := photosByVehicle[vehicles[i].ID]
+		s.setPhotoURLs(photos)
+		vehicles[i].Photos = photos
+	}
+
+	return nil
+}
+
+func (s *vehicleService) setPhotoURLs(photos []models.VehiclePhoto) {
+	for i := range photos {
+		photos[i].URL = s.fileUploader.URL(photos[i].PhotoPath)
+		if photos[i].ThumbnailPath != nil {
+			thumbnailURL := s.fileUploader.URL(*photos[i].ThumbnailPath)
+			photos[i].ThumbnailURL = &thumbnailURL
+		}
+	}
+}
+
+func (s *vehicleService) removePhotoFiles(photo models.VehiclePhoto) {
+	paths := []string{photo.PhotoPath}
+	if photo.ThumbnailPath != nil {
+		paths = append(paths, *photo.ThumbnailPath)
+	}
+	s.fileUploader.Remove(paths...)
+}
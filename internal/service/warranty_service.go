@@ -0,0 +1,207 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
+)
+
+type WarrantyService interface {
+	SetWarranty(salesTransactionID int, req *models.SalesWarrantyRequest, createdBy int) (*models.SalesWarranty, error)
+	GetWarranty(salesTransactionID int) (*models.SalesWarranty, error)
+	CreateClaim(salesTransactionID int, req *models.WarrantyClaimCreateRequest, claimedBy int) (*models.WarrantyClaim, error)
+	GetCostReport(dateFrom, dateTo string) (*models.WarrantyCostReport, error)
+}
+
+type warrantyService struct {
+	warrantyRepo        repository.WarrantyRepository
+	transactionRepo     repository.TransactionRepository
+	customerVehicleRepo repository.CustomerVehicleRepository
+	repairService       RepairService
+}
+
+func NewWarrantyService(warrantyRepo repository.WarrantyRepository, transactionRepo repository.TransactionRepository, customerVehicleRepo repository.CustomerVehicleRepository, repairService RepairService) WarrantyService {
+	return &warrantyService{
+		warrantyRepo:        warrantyRepo,
+		transactionRepo:     transactionRepo,
+		customerVehicleRepo: customerVehicleRepo,
+		repairService:       repairService,
+	}
+}
+
+func (s *warrantyService) SetWarranty(salesTransactionID int, req *models.SalesWarrantyRequest, createdBy int) (*models.SalesWarranty, error) {
+	sale, err := s.transactionRepo.GetSalesTransactionByID(salesTransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("sales transaction not found")
+	}
+
+	// Coverage runs from the sale date
+	odometerAtSale := 0
+	if sale.Vehicle != nil {
+		odometerAtSale = sale.Vehicle.Odometer
+	}
+	if req.OdometerAtSale != nil {
+		odometerAtSale = *req.OdometerAtSale
+	}
+
+	warranty := &models.SalesWarranty{
+		SalesTransactionID: sale.ID,
+		Coverage:           req.Coverage,
+		DurationMonths:     req.DurationMonths,
+		OdometerLimit:      req.OdometerLimit,
+		OdometerAtSale:     odometerAtSale,
+		StartDate:          sale.TransactionDate,
+		EndDate:            sale.TransactionDate.AddDate(0, req.DurationMonths, 0),
+		Notes:              req.Notes,
+		CreatedBy:          createdBy,
+	}
+
+	if err := s.warrantyRepo.Upsert(warranty); err != nil {
+		return nil, err
+	}
+
+	return s.GetWarranty(salesTransactionID)
+}
+
+func (s *warrantyService) GetWarranty(salesTransactionID int) (*models.SalesWarranty, error) {
+	warranty, err := s.warrantyRepo.GetBySale(salesTransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	warranty.IsActive = !warrantyExpired(warranty, time.Now())
+	return warranty, nil
+}
+
+// CreateClaim opens a warranty repair order on the buyer's vehicle. The order is a customer
+// service order, so its cost stays out of the inventory HPP and is booked against the sale.
+func (s *warrantyService) CreateClaim(salesTransactionID int, req *models.WarrantyClaimCreateRequest, claimedBy int) (*models.WarrantyClaim, error) {
+	warranty, err := s.warrantyRepo.GetBySale(salesTransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if warrantyExpired(warranty, time.Now()) {
+		return nil, fmt.Errorf("warranty expired on %s", warranty.EndDate.Format("2006-01-02"))
+	}
+	if req.Odometer < warranty.OdometerAtSale {
+		return nil, fmt.Errorf("odometer cannot be lower than at sale (%d km)", warranty.OdometerAtSale)
+	}
+	if warranty.OdometerLimit != nil && req.Odometer-warranty.OdometerAtSale > *warranty.OdometerLimit {
+		return nil, fmt.Errorf("warranty odometer limit of %d km exceeded", *warranty.OdometerLimit)
+	}
+
+	sale, err := s.transactionRepo.GetSalesTransactionByID(salesTransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("sales transaction not found")
+	}
+
+	customerVehicle, err := s.buyerVehicle(sale, req.Odometer)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := fmt.Sprintf("Warranty claim on sale %s (%s)", sale.InvoiceNumber, warranty.Coverage)
+	repair, err := s.repairService.CreateWarrantyOrder(&models.ServiceOrderCreateRequest{
+		CustomerVehicleID:    customerVehicle.ID,
+		MechanicID:           req.MechanicID,
+		Description:          &req.Complaint,
+		Notes:                &notes,
+		ServiceCategories:    req.ServiceCategories,
+		TargetCompletionDate: req.TargetCompletionDate,
+	}, claimedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := &models.WarrantyClaim{
+		WarrantyID:    warranty.ID,
+		RepairOrderID: repair.ID,
+		Complaint:     req.Complaint,
+		Odometer:      req.Odometer,
+		ClaimedBy:     claimedBy,
+		RepairCode:    repair.Code,
+		RepairStatus:  repair.Status,
+		RepairOrder:   repair,
+	}
+
+	if err := s.warrantyRepo.CreateClaim(claim); err != nil {
+		// Do not leave a warranty order behind that no claim points to
+		if deleteErr := s.repairService.DeleteRepairOrder(repair.ID); deleteErr != nil {
+			fmt.Printf("Warning: failed to delete warranty order %s: %v\n", repair.Code, deleteErr)
+		}
+		return nil, err
+	}
+
+	return claim, nil
+}
+
+func (s *warrantyService) GetCostReport(dateFrom, dateTo string) (*models.WarrantyCostReport, error) {
+	// Default to the current month
+	now := time.Now()
+	if dateFrom == "" {
+		dateFrom = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
+	}
+	if dateTo == "" {
+		dateTo = now.Format("2006-01-02")
+	}
+
+	if _, err := time.Parse("2006-01-02", dateFrom); err != nil {
+		return nil, fmt.Errorf("invalid date format, use YYYY-MM-DD")
+	}
+	if _, err := time.Parse("2006-01-02", dateTo); err != nil {
+		return nil, fmt.Errorf("invalid date format, use YYYY-MM-DD")
+	}
+
+	return s.warrantyRepo.GetCostReport(dateFrom, dateTo)
+}
+
+// buyerVehicle finds the sold vehicle among the buyer's registered vehicles, registering it
+// on the first claim, and records the odometer reading of the claim
+func (s *warrantyService) buyerVehicle(sale *models.SalesTransaction, odometer int) (*models.CustomerVehicle, error) {
+	if sale.Vehicle == nil {
+		return nil, fmt.Errorf("vehicle not found")
+	}
+	vehicle := sale.Vehicle
+
+	// Vehicles without a plate yet are registered under their stock code
+	plate := vehicle.Code
+	if vehicle.LicensePlate != nil && *vehicle.LicensePlate != "" {
+		plate = *vehicle.LicensePlate
+	}
+
+	registered, err := s.customerVehicleRepo.ListByCustomer(sale.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, cv := range registered {
+		sameChassis := vehicle.ChassisNumber != nil && cv.ChassisNumber != nil &&
+			strings.EqualFold(*vehicle.ChassisNumber, *cv.ChassisNumber)
+		if sameChassis || strings.EqualFold(cv.LicensePlate, plate) {
+			if odometer <= cv.Odometer {
+				return &cv, nil
+			}
+			return s.customerVehicleRepo.Update(cv.ID, &models.CustomerVehicleUpdateRequest{Odometer: &odometer})
+		}
+	}
+
+	year := vehicle.Year
+	return s.customerVehicleRepo.Create(sale.CustomerID, &models.CustomerVehicleCreateRequest{
+		BrandID:       vehicle.BrandID,
+		Model:         vehicle.Model,
+		Year:          &year,
+		Color:         vehicle.Color,
+		LicensePlate:  plate,
+		ChassisNumber: vehicle.ChassisNumber,
+		EngineNumber:  vehicle.EngineNumber,
+		Odometer:      odometer,
+	})
+}
+
+// warrantyExpired reports whether the warranty period ended before the given day
+func warrantyExpired(warranty *models.SalesWarranty, now time.Time) bool {
+	return now.Format("2006-01-02") > warranty.EndDate.Format("2006-01-02")
+}
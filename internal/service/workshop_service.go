@@ -0,0 +1,107 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hafizd-kurniawan/pos-baru/internal/domain/models"
+	"github.com/hafizd-kurniawan/pos-baru/internal/repository"
+)
+
+type WorkshopService interface {
+	CreateBay(req *models.WorkshopBayCreateRequest) (*models.WorkshopBay, error)
+	GetBay(id int) (*models.WorkshopBay, error)
+	UpdateBay(id int, req *models.WorkshopBayUpdateRequest) (*models.WorkshopBay, error)
+	DeleteBay(id int) error
+	ListBays(activeOnly bool) ([]models.WorkshopBay, error)
+	GetCalendar(filter models.WorkshopCalendarFilter) (*models.WorkshopCalendar, error)
+}
+
+type workshopService struct {
+	workshopRepo repository.WorkshopRepository
+}
+
+func NewWorkshopService(workshopRepo repository.WorkshopRepository) WorkshopService {
+	return &workshopService{
+		workshopRepo: workshopRepo,
+	}
+}
+
+func (s *workshopService) CreateBay(req *models.WorkshopBayCreateRequest) (*models.WorkshopBay, error) {
+	return s.workshopRepo.CreateBay(req)
+}
+
+func (s *workshopService) GetBay(id int) (*models.WorkshopBay, error) {
+	return s.workshopRepo.GetBayByID(id)
+}
+
+func (s *workshopService) UpdateBay(id int, req *models.WorkshopBayUpdateRequest) (*models.WorkshopBay, error) {
+	return s.workshopRepo.UpdateBay(id, req)
+}
+
+func (s *workshopService) DeleteBay(id int) error {
+	return s.workshopRepo.DeleteBay(id)
+}
+
+func (s *workshopService) ListBays(activeOnly bool) ([]models.WorkshopBay, error) {
+	return s.workshopRepo.ListBays(activeOnly)
+}
+
+func (s *workshopService) GetCalendar(filter models.WorkshopCalendarFilter) (*models.WorkshopCalendar, error) {
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if filter.Date != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", filter.Date, now.Location())
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format, use YYYY-MM-DD")
+		}
+		date = parsed
+	}
+
+	view := filter.View
+	if view == "" {
+		view = "day"
+	}
+
+	var start, end time.Time
+	switch view {
+	case "day":
+		start = date
+		end = start.AddDate(0, 0, 1)
+	case "week":
+		// Weeks start on Monday
+		offset := (int(date.Weekday()) + 6) % 7
+		start = date.AddDate(0, 0, -offset)
+		end = start.AddDate(0, 0, 7)
+	default:
+		return nil, fmt.Errorf("invalid view, use day or week")
+	}
+
+	bays, err := s.workshopRepo.ListBays(true)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.BayID > 0 {
+		filtered := []models.WorkshopBay{}
+		for _, bay := range bays {
+			if bay.ID == filter.BayID {
+				filtered = append(filtered, bay)
+			}
+		}
+		bays = filtered
+	}
+
+	entries, err := s.workshopRepo.ListSchedules(start, end, filter.BayID, filter.MechanicID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WorkshopCalendar{
+		View:      view,
+		StartDate: start,
+		EndDate:   end,
+		Bays:      bays,
+		Entries:   entries,
+	}, nil
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS spare_part_reservations;
+DROP TYPE IF EXISTS reservation_status_enum;
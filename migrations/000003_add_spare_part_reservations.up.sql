@@ -0,0 +1,25 @@
+-- Soft reservations of spare parts for open repair orders
+-- Migration: 003_add_spare_part_reservations
+
+CREATE TYPE reservation_status_enum AS ENUM ('active', 'consumed', 'released');
+
+-- Table: spare_part_reservations
+CREATE TABLE spare_part_reservations (
+    id SERIAL PRIMARY KEY,
+    repair_order_id INT NOT NULL,
+    spare_part_id INT NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    consumed_quantity INT NOT NULL DEFAULT 0, -- part of the reservation already used via repair_spare_parts
+    status reservation_status_enum NOT NULL DEFAULT 'active',
+    reserved_by INT NOT NULL,
+    released_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repair_order_id) REFERENCES repair_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (spare_part_id) REFERENCES spare_parts(id),
+    FOREIGN KEY (reserved_by) REFERENCES users(id)
+);
+
+-- Only one active reservation per part and repair order; new reservations are merged into it
+CREATE UNIQUE INDEX idx_reservations_active_unique ON spare_part_reservations(repair_order_id, spare_part_id) WHERE status = 'active';
+CREATE INDEX idx_reservations_spare_part_status ON spare_part_reservations(spare_part_id, status);
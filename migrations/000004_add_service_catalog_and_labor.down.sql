@@ -0,0 +1,36 @@
+DROP TRIGGER IF EXISTS trigger_update_labor_cost_on_insert ON repair_labor_lines;
+DROP TRIGGER IF EXISTS trigger_update_labor_cost_on_update ON repair_labor_lines;
+DROP TRIGGER IF EXISTS trigger_update_labor_cost_on_delete ON repair_labor_lines;
+
+DROP TABLE IF EXISTS repair_labor_lines;
+DROP TABLE IF EXISTS service_catalog;
+
+-- Restore parts-only repair cost calculation
+CREATE OR REPLACE FUNCTION update_vehicle_repair_cost()
+RETURNS TRIGGER AS $$
+DECLARE
+    vehicle_id_var INTEGER;
+    total_cost DECIMAL(12,2);
+BEGIN
+    SELECT vehicle_id INTO vehicle_id_var
+    FROM repair_orders 
+    WHERE id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    SELECT COALESCE(SUM(rsp.quantity_used * sp.selling_price), 0) INTO total_cost
+    FROM repair_spare_parts rsp
+    JOIN spare_parts sp ON rsp.spare_part_id = sp.id
+    WHERE rsp.repair_order_id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    UPDATE vehicles 
+    SET repair_cost = total_cost,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = vehicle_id_var;
+    
+    UPDATE repair_orders 
+    SET actual_cost = total_cost,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ LANGUAGE plpgsql;
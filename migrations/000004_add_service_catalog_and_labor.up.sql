@@ -0,0 +1,102 @@
+-- Service/labor catalog and labor lines on repair orders
+-- Migration: 004_add_service_catalog_and_labor
+
+-- Table: service_catalog (standard workshop operations)
+CREATE TABLE service_catalog (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(150) NOT NULL,
+    description TEXT,
+    category VARCHAR(50) NOT NULL DEFAULT 'General', -- 'Engine', 'Brake', 'Electrical', etc
+    vehicle_type_id INT NULL, -- NULL means the operation applies to every vehicle type
+    standard_hours DECIMAL(6,2) NOT NULL DEFAULT 0,
+    labor_rate DECIMAL(15,2) NOT NULL DEFAULT 0, -- per hour
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (vehicle_type_id) REFERENCES vehicle_types(id)
+);
+
+CREATE INDEX idx_service_catalog_category ON service_catalog(category);
+
+-- Table: repair_labor_lines
+CREATE TABLE repair_labor_lines (
+    id SERIAL PRIMARY KEY,
+    repair_order_id INT NOT NULL,
+    service_id INT NULL, -- NULL for free-text labor
+    description VARCHAR(255) NOT NULL,
+    hours DECIMAL(6,2) NOT NULL,
+    labor_rate DECIMAL(15,2) NOT NULL,
+    total_price DECIMAL(15,2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repair_order_id) REFERENCES repair_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (service_id) REFERENCES service_catalog(id)
+);
+
+CREATE INDEX idx_labor_lines_repair ON repair_labor_lines(repair_order_id);
+
+-- Repair cost now covers spare parts and labor
+CREATE OR REPLACE FUNCTION update_vehicle_repair_cost()
+RETURNS TRIGGER AS $$
+DECLARE
+    vehicle_id_var INTEGER;
+    parts_cost DECIMAL(15,2);
+    labor_cost DECIMAL(15,2);
+    total_cost DECIMAL(15,2);
+BEGIN
+    -- Get vehicle_id from repair_orders table
+    SELECT vehicle_id INTO vehicle_id_var
+    FROM repair_orders 
+    WHERE id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    -- Calculate total cost of spare parts for this repair order
+    SELECT COALESCE(SUM(rsp.quantity_used * sp.selling_price), 0) INTO parts_cost
+    FROM repair_spare_parts rsp
+    JOIN spare_parts sp ON rsp.spare_part_id = sp.id
+    WHERE rsp.repair_order_id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    -- Calculate total labor for this repair order
+    SELECT COALESCE(SUM(rll.total_price), 0) INTO labor_cost
+    FROM repair_labor_lines rll
+    WHERE rll.repair_order_id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    total_cost := parts_cost + labor_cost;
+    
+    -- Update vehicle repair_cost
+    UPDATE vehicles 
+    SET repair_cost = total_cost,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = vehicle_id_var;
+    
+    -- Also update the repair order actual_cost
+    UPDATE repair_orders 
+    SET actual_cost = total_cost,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_update_labor_cost_on_insert
+    AFTER INSERT ON repair_labor_lines
+    FOR EACH ROW
+    EXECUTE FUNCTION update_vehicle_repair_cost();
+
+CREATE TRIGGER trigger_update_labor_cost_on_update
+    AFTER UPDATE ON repair_labor_lines
+    FOR EACH ROW
+    EXECUTE FUNCTION update_vehicle_repair_cost();
+
+CREATE TRIGGER trigger_update_labor_cost_on_delete
+    AFTER DELETE ON repair_labor_lines
+    FOR EACH ROW
+    EXECUTE FUNCTION update_vehicle_repair_cost();
+
+-- Sample catalog entries
+INSERT INTO service_catalog (code, name, description, category, vehicle_type_id, standard_hours, labor_rate) VALUES
+    ('SVC001', 'Tune-up Motor Bebek', 'Servis ringan: busi, filter udara, setel karburator/injeksi', 'Engine', 1, 1.00, 50000),
+    ('SVC002', 'Ganti Kampas Rem', 'Ganti kampas rem depan atau belakang', 'Brake', NULL, 0.50, 50000),
+    ('SVC003', 'Ganti Oli Mesin', 'Kuras dan ganti oli mesin', 'Engine', NULL, 0.25, 40000),
+    ('SVC004', 'Tune-up Mobil', 'Servis berkala mesin mobil', 'Engine', 2, 2.00, 100000)
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,4 @@
+ALTER TABLE repair_orders DROP COLUMN IF EXISTS actual_hours;
+
+DROP TABLE IF EXISTS repair_work_sessions;
+DROP TYPE IF EXISTS work_session_end_enum;
@@ -0,0 +1,26 @@
+-- Mechanic time tracking on repair orders
+-- Migration: 005_add_repair_time_tracking
+
+CREATE TYPE work_session_end_enum AS ENUM ('paused', 'stopped', 'closed');
+
+-- Table: repair_work_sessions (one row per start..pause/stop interval)
+CREATE TABLE repair_work_sessions (
+    id SERIAL PRIMARY KEY,
+    repair_order_id INT NOT NULL,
+    mechanic_id INT NOT NULL,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ended_at TIMESTAMP NULL, -- NULL while the timer is running
+    end_reason work_session_end_enum NULL,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repair_order_id) REFERENCES repair_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (mechanic_id) REFERENCES users(id)
+);
+
+-- A mechanic can only run one timer at a time
+CREATE UNIQUE INDEX idx_work_sessions_running ON repair_work_sessions(mechanic_id) WHERE ended_at IS NULL;
+CREATE INDEX idx_work_sessions_repair ON repair_work_sessions(repair_order_id);
+CREATE INDEX idx_work_sessions_started_at ON repair_work_sessions(started_at);
+
+-- Actual hours booked on the job, summed from closed sessions
+ALTER TABLE repair_orders ADD COLUMN actual_hours DECIMAL(8,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,36 @@
+-- Mechanic skill matrix
+-- Migration: 006_add_mechanic_skills
+
+-- Table: skills (what a mechanic can work on)
+CREATE TABLE skills (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    vehicle_type_id INT NULL, -- NULL means any vehicle type
+    service_category VARCHAR(50) NULL, -- matches service_catalog.category, NULL means any category
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (vehicle_type_id) REFERENCES vehicle_types(id)
+);
+
+-- Table: user_skills (skills and certifications held by a user)
+CREATE TABLE user_skills (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    skill_id INT NOT NULL,
+    certification_number VARCHAR(100),
+    certified_at DATE,
+    expires_at DATE, -- NULL means the skill does not expire
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (skill_id) REFERENCES skills(id) ON DELETE CASCADE,
+    UNIQUE (user_id, skill_id)
+);
+
+CREATE INDEX idx_user_skills_user ON user_skills(user_id);
+
+-- Sample skills
+INSERT INTO skills (name, description, vehicle_type_id, service_category) VALUES
+    ('Mekanik Motor', 'Perbaikan umum sepeda motor', 1, NULL),
+    ('Mekanik Mobil', 'Perbaikan umum mobil', 2, NULL),
+    ('Kelistrikan', 'Kelistrikan semua jenis kendaraan', NULL, 'Electrical')
+ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,60 @@
+-- Workshop bays/lifts, repair scheduling and in-app notifications
+-- Migration: 007_add_workshop_scheduling
+
+-- Table: workshop_bays
+CREATE TABLE workshop_bays (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    bay_type VARCHAR(30) NOT NULL DEFAULT 'lift', -- 'lift', 'bay', 'wash', etc
+    notes TEXT,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Table: repair_schedules (time slot of a repair order on a bay and mechanic)
+CREATE TABLE repair_schedules (
+    id SERIAL PRIMARY KEY,
+    repair_order_id INT UNIQUE NOT NULL,
+    bay_id INT NOT NULL,
+    mechanic_id INT NOT NULL,
+    scheduled_start TIMESTAMP NOT NULL,
+    scheduled_end TIMESTAMP NOT NULL,
+    scheduled_by INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repair_order_id) REFERENCES repair_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (bay_id) REFERENCES workshop_bays(id),
+    FOREIGN KEY (mechanic_id) REFERENCES users(id),
+    FOREIGN KEY (scheduled_by) REFERENCES users(id),
+    CHECK (scheduled_end > scheduled_start)
+);
+
+CREATE INDEX idx_repair_schedules_bay_time ON repair_schedules(bay_id, scheduled_start, scheduled_end);
+CREATE INDEX idx_repair_schedules_mechanic_time ON repair_schedules(mechanic_id, scheduled_start, scheduled_end);
+
+-- Table: notifications (in-app messages for users)
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    type VARCHAR(50) NOT NULL, -- 'repair_scheduled', 'repair_rescheduled', etc
+    title VARCHAR(150) NOT NULL,
+    message TEXT NOT NULL,
+    reference_type VARCHAR(50), -- 'repair_order', etc
+    reference_id INT,
+    is_read BOOLEAN DEFAULT FALSE,
+    read_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notifications_user_read ON notifications(user_id, is_read);
+
+-- Sample bays
+INSERT INTO workshop_bays (code, name, bay_type) VALUES
+    ('LIFT-1', 'Lift 1', 'lift'),
+    ('LIFT-2', 'Lift 2', 'lift'),
+    ('LIFT-3', 'Lift 3', 'lift'),
+    ('LIFT-4', 'Lift 4', 'lift')
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS repair_checklist_items;
+DROP TABLE IF EXISTS checklist_template_items;
+DROP TABLE IF EXISTS checklist_templates;
+DROP TYPE IF EXISTS checklist_result_enum;
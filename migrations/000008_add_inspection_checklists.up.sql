@@ -0,0 +1,72 @@
+-- Inspection checklist templates and repair checklists
+-- Migration: 008_add_inspection_checklists
+
+CREATE TYPE checklist_result_enum AS ENUM ('pass', 'fail', 'needs_attention');
+
+-- Table: checklist_templates
+CREATE TABLE checklist_templates (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(150) NOT NULL,
+    vehicle_type_id INT NULL, -- NULL means the template applies to every vehicle type
+    description TEXT,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (vehicle_type_id) REFERENCES vehicle_types(id)
+);
+
+-- Table: checklist_template_items
+CREATE TABLE checklist_template_items (
+    id SERIAL PRIMARY KEY,
+    template_id INT NOT NULL,
+    label VARCHAR(150) NOT NULL,
+    category VARCHAR(50), -- 'Brakes', 'Lights', 'Tires', 'Engine', etc
+    is_required BOOLEAN DEFAULT TRUE,
+    sort_order INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (template_id) REFERENCES checklist_templates(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_template_items_template ON checklist_template_items(template_id);
+
+-- Table: repair_checklist_items (template items copied onto a repair order)
+CREATE TABLE repair_checklist_items (
+    id SERIAL PRIMARY KEY,
+    repair_order_id INT NOT NULL,
+    template_id INT NULL,
+    label VARCHAR(150) NOT NULL,
+    category VARCHAR(50),
+    is_required BOOLEAN DEFAULT TRUE,
+    sort_order INT NOT NULL DEFAULT 0,
+    result checklist_result_enum NULL, -- NULL until the item is checked
+    note TEXT,
+    photo_path VARCHAR(255),
+    checked_by INT NULL,
+    checked_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repair_order_id) REFERENCES repair_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (template_id) REFERENCES checklist_templates(id) ON DELETE SET NULL,
+    FOREIGN KEY (checked_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_repair_checklist_repair ON repair_checklist_items(repair_order_id);
+
+-- Sample templates
+INSERT INTO checklist_templates (name, vehicle_type_id, description) VALUES
+    ('Inspeksi Motor', 1, 'Pemeriksaan standar sepeda motor'),
+    ('Inspeksi Mobil', 2, 'Pemeriksaan standar mobil');
+
+INSERT INTO checklist_template_items (template_id, label, category, is_required, sort_order)
+SELECT t.id, i.label, i.category, i.is_required, i.sort_order
+FROM checklist_templates t
+JOIN (VALUES
+    ('Inspeksi Motor', 'Rem depan dan belakang', 'Brakes', TRUE, 1),
+    ('Inspeksi Motor', 'Lampu utama, sein dan rem', 'Lights', TRUE, 2),
+    ('Inspeksi Motor', 'Kondisi dan tekanan ban', 'Tires', TRUE, 3),
+    ('Inspeksi Motor', 'Suara mesin', 'Engine', TRUE, 4),
+    ('Inspeksi Motor', 'Rantai dan gir', 'Drivetrain', FALSE, 5),
+    ('Inspeksi Mobil', 'Rem dan minyak rem', 'Brakes', TRUE, 1),
+    ('Inspeksi Mobil', 'Semua lampu', 'Lights', TRUE, 2),
+    ('Inspeksi Mobil', 'Kondisi dan tekanan ban', 'Tires', TRUE, 3),
+    ('Inspeksi Mobil', 'Suara mesin', 'Engine', TRUE, 4),
+    ('Inspeksi Mobil', 'AC', 'Electrical', FALSE, 5)
+) AS i(template_name, label, category, is_required, sort_order) ON i.template_name = t.name;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS repair_attachments;
+DROP TYPE IF EXISTS repair_attachment_stage_enum;
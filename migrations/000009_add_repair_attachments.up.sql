@@ -0,0 +1,23 @@
+-- Photos and documents attached to repair orders
+-- Migration: 009_add_repair_attachments
+
+CREATE TYPE repair_attachment_stage_enum AS ENUM ('before', 'during', 'after', 'document');
+
+-- Table: repair_attachments (same path/caption storage model as vehicle_photos)
+CREATE TABLE repair_attachments (
+    id SERIAL PRIMARY KEY,
+    repair_order_id INT NOT NULL,
+    stage repair_attachment_stage_enum NOT NULL,
+    file_path VARCHAR(255) NOT NULL,
+    thumbnail_path VARCHAR(255), -- only for images
+    caption VARCHAR(255),
+    file_name VARCHAR(255) NOT NULL, -- original name of the uploaded file
+    content_type VARCHAR(100) NOT NULL,
+    file_size BIGINT NOT NULL DEFAULT 0,
+    uploaded_by INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repair_order_id) REFERENCES repair_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (uploaded_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_repair_attachments_repair ON repair_attachments(repair_order_id);
@@ -0,0 +1,21 @@
+DROP TABLE IF EXISTS service_payments;
+DROP TABLE IF EXISTS service_invoice_items;
+DROP TABLE IF EXISTS service_invoices;
+
+DELETE FROM repair_orders WHERE order_type = 'customer_service';
+
+ALTER TABLE repair_orders
+    DROP CONSTRAINT IF EXISTS chk_repair_orders_vehicle,
+    DROP CONSTRAINT IF EXISTS fk_repair_orders_customer_vehicle,
+    DROP CONSTRAINT IF EXISTS fk_repair_orders_customer,
+    DROP COLUMN IF EXISTS estimate_decided_by,
+    DROP COLUMN IF EXISTS estimate_decided_at,
+    DROP COLUMN IF EXISTS estimate_status,
+    DROP COLUMN IF EXISTS customer_vehicle_id,
+    DROP COLUMN IF EXISTS customer_id,
+    DROP COLUMN IF EXISTS order_type,
+    ALTER COLUMN vehicle_id SET NOT NULL;
+
+DROP TABLE IF EXISTS customer_vehicles;
+DROP TYPE IF EXISTS estimate_status_enum;
+DROP TYPE IF EXISTS repair_order_type_enum;
@@ -0,0 +1,97 @@
+-- Workshop service for customer-owned vehicles
+-- Migration: 010_add_customer_service_orders
+
+CREATE TYPE repair_order_type_enum AS ENUM ('reconditioning', 'customer_service');
+CREATE TYPE estimate_status_enum AS ENUM ('pending', 'approved', 'rejected');
+
+-- Table: customer_vehicles (vehicles owned by workshop customers, not part of the inventory)
+CREATE TABLE customer_vehicles (
+    id SERIAL PRIMARY KEY,
+    customer_id INT NOT NULL,
+    brand_id INT NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    year INT,
+    color VARCHAR(50),
+    license_plate VARCHAR(20) NOT NULL,
+    chassis_number VARCHAR(100),
+    engine_number VARCHAR(100),
+    odometer INT DEFAULT 0,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES customers(id),
+    FOREIGN KEY (brand_id) REFERENCES vehicle_brands(id)
+);
+
+CREATE INDEX idx_customer_vehicles_customer ON customer_vehicles(customer_id);
+CREATE INDEX idx_customer_vehicles_plate ON customer_vehicles(license_plate);
+
+-- Repair orders either recondition an inventory vehicle or service a customer vehicle
+ALTER TABLE repair_orders
+    ADD COLUMN order_type repair_order_type_enum NOT NULL DEFAULT 'reconditioning',
+    ADD COLUMN customer_id INT NULL,
+    ADD COLUMN customer_vehicle_id INT NULL,
+    ADD COLUMN estimate_status estimate_status_enum NULL, -- only for customer service
+    ADD COLUMN estimate_decided_at TIMESTAMP NULL,
+    ADD COLUMN estimate_decided_by VARCHAR(150) NULL, -- name of the person who approved or rejected
+    ALTER COLUMN vehicle_id DROP NOT NULL,
+    ADD CONSTRAINT fk_repair_orders_customer FOREIGN KEY (customer_id) REFERENCES customers(id),
+    ADD CONSTRAINT fk_repair_orders_customer_vehicle FOREIGN KEY (customer_vehicle_id) REFERENCES customer_vehicles(id),
+    ADD CONSTRAINT chk_repair_orders_vehicle CHECK (
+        (order_type = 'reconditioning' AND vehicle_id IS NOT NULL AND customer_vehicle_id IS NULL)
+        OR (order_type = 'customer_service' AND vehicle_id IS NULL AND customer_vehicle_id IS NOT NULL AND customer_id IS NOT NULL)
+    );
+
+CREATE INDEX idx_repair_order_type ON repair_orders(order_type);
+CREATE INDEX idx_repair_customer_vehicle ON repair_orders(customer_vehicle_id);
+
+-- Table: service_invoices
+CREATE TABLE service_invoices (
+    id SERIAL PRIMARY KEY,
+    invoice_number VARCHAR(50) UNIQUE NOT NULL,
+    repair_order_id INT UNIQUE NOT NULL,
+    customer_id INT NOT NULL,
+    invoice_date DATE NOT NULL,
+    parts_total DECIMAL(15,2) NOT NULL DEFAULT 0,
+    labor_total DECIMAL(15,2) NOT NULL DEFAULT 0,
+    discount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    total_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    paid_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    payment_status payment_status_enum DEFAULT 'pending',
+    notes TEXT,
+    processed_by INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repair_order_id) REFERENCES repair_orders(id),
+    FOREIGN KEY (customer_id) REFERENCES customers(id),
+    FOREIGN KEY (processed_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_service_invoices_date ON service_invoices(invoice_date);
+
+-- Table: service_invoice_items (parts and labor copied from the repair order)
+CREATE TABLE service_invoice_items (
+    id SERIAL PRIMARY KEY,
+    invoice_id INT NOT NULL,
+    item_type VARCHAR(20) NOT NULL, -- 'part', 'labor'
+    description VARCHAR(255) NOT NULL,
+    quantity DECIMAL(10,2) NOT NULL,
+    unit_price DECIMAL(15,2) NOT NULL,
+    total_price DECIMAL(15,2) NOT NULL,
+    FOREIGN KEY (invoice_id) REFERENCES service_invoices(id) ON DELETE CASCADE
+);
+
+-- Table: service_payments
+CREATE TABLE service_payments (
+    id SERIAL PRIMARY KEY,
+    invoice_id INT NOT NULL,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    payment_method VARCHAR(50) NOT NULL, -- 'cash', 'transfer', 'card'
+    notes TEXT,
+    received_by INT NOT NULL,
+    paid_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (invoice_id) REFERENCES service_invoices(id) ON DELETE CASCADE,
+    FOREIGN KEY (received_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_service_payments_invoice ON service_payments(invoice_id);
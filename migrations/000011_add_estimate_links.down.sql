@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS repair_estimate_links;
+
+-- PostgreSQL cannot drop enum values; move approved orders back to pending instead
+UPDATE repair_orders SET status = 'pending' WHERE status = 'approved';
@@ -0,0 +1,19 @@
+-- Shareable estimate links for customer approval
+-- Migration: 011_add_estimate_links
+
+-- Customer service orders wait in 'approved' between the estimate decision and the start of work
+ALTER TYPE repair_status_enum ADD VALUE IF NOT EXISTS 'approved' AFTER 'pending';
+
+-- Table: repair_estimate_links (tokenized, expiring public links to a read-only estimate)
+CREATE TABLE repair_estimate_links (
+    id SERIAL PRIMARY KEY,
+    repair_order_id INT NOT NULL,
+    token VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_by INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repair_order_id) REFERENCES repair_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_repair_estimate_links_repair ON repair_estimate_links(repair_order_id);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS repair_status_transitions;
+DROP TABLE IF EXISTS repair_status_history;
+
+-- PostgreSQL cannot drop enum values; fold the new states back into the old ones
+UPDATE repair_orders SET status = 'in_progress' WHERE status IN ('waiting_parts', 'quality_check');
+UPDATE repair_orders SET status = 'pending' WHERE status = 'on_hold';
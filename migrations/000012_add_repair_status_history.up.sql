@@ -0,0 +1,49 @@
+-- Repair status history and configurable workflow
+-- Migration: 012_add_repair_status_history
+
+ALTER TYPE repair_status_enum ADD VALUE IF NOT EXISTS 'waiting_parts' AFTER 'in_progress';
+ALTER TYPE repair_status_enum ADD VALUE IF NOT EXISTS 'quality_check' AFTER 'waiting_parts';
+ALTER TYPE repair_status_enum ADD VALUE IF NOT EXISTS 'on_hold' AFTER 'quality_check';
+
+-- Table: repair_status_history (every status change with its actor)
+CREATE TABLE repair_status_history (
+    id SERIAL PRIMARY KEY,
+    repair_order_id INT NOT NULL,
+    from_status repair_status_enum,
+    to_status repair_status_enum NOT NULL,
+    changed_by INT, -- NULL when the customer decided through an estimate link
+    actor_name VARCHAR(150),
+    note TEXT,
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repair_order_id) REFERENCES repair_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (changed_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_repair_status_history_repair ON repair_status_history(repair_order_id, changed_at);
+
+-- Existing orders start their history at creation; the current status is recorded when it differs
+INSERT INTO repair_status_history (repair_order_id, from_status, to_status, changed_by, changed_at)
+SELECT id, NULL, 'pending', assigned_by, created_at FROM repair_orders;
+
+INSERT INTO repair_status_history (repair_order_id, from_status, to_status, changed_by, note, changed_at)
+SELECT id, 'pending', status, NULL, 'Recorded before status history was kept', updated_at
+FROM repair_orders
+WHERE status <> 'pending';
+
+-- Table: repair_status_transitions (allowed status changes, editable by admins)
+-- Text columns so the seed can use the enum values added above in the same migration
+CREATE TABLE repair_status_transitions (
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    PRIMARY KEY (from_status, to_status)
+);
+
+INSERT INTO repair_status_transitions (from_status, to_status) VALUES
+('pending', 'in_progress'), ('pending', 'on_hold'), ('pending', 'cancelled'),
+('approved', 'in_progress'), ('approved', 'on_hold'), ('approved', 'cancelled'),
+('in_progress', 'waiting_parts'), ('in_progress', 'quality_check'), ('in_progress', 'on_hold'),
+('in_progress', 'completed'), ('in_progress', 'cancelled'),
+('waiting_parts', 'in_progress'), ('waiting_parts', 'on_hold'), ('waiting_parts', 'cancelled'),
+('quality_check', 'in_progress'), ('quality_check', 'completed'), ('quality_check', 'cancelled'),
+('on_hold', 'pending'), ('on_hold', 'in_progress'), ('on_hold', 'cancelled'),
+('cancelled', 'pending');
@@ -0,0 +1,50 @@
+DROP TABLE IF EXISTS spare_part_movements;
+
+CREATE OR REPLACE FUNCTION update_vehicle_repair_cost()
+RETURNS TRIGGER AS $$
+DECLARE
+    vehicle_id_var INTEGER;
+    parts_cost DECIMAL(15,2);
+    labor_cost DECIMAL(15,2);
+    total_cost DECIMAL(15,2);
+BEGIN
+    -- Get vehicle_id from repair_orders table
+    SELECT vehicle_id INTO vehicle_id_var
+    FROM repair_orders 
+    WHERE id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    -- Calculate total cost of spare parts for this repair order
+    SELECT COALESCE(SUM(rsp.quantity_used * sp.selling_price), 0) INTO parts_cost
+    FROM repair_spare_parts rsp
+    JOIN spare_parts sp ON rsp.spare_part_id = sp.id
+    WHERE rsp.repair_order_id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    -- Calculate total labor for this repair order
+    SELECT COALESCE(SUM(rll.total_price), 0) INTO labor_cost
+    FROM repair_labor_lines rll
+    WHERE rll.repair_order_id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    total_cost := parts_cost + labor_cost;
+    
+    -- Update vehicle repair_cost
+    UPDATE vehicles 
+    SET repair_cost = total_cost,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = vehicle_id_var;
+    
+    -- Also update the repair order actual_cost
+    UPDATE repair_orders 
+    SET actual_cost = total_cost,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE repair_spare_parts
+    DROP COLUMN IF EXISTS disposed_at,
+    DROP COLUMN IF EXISTS disposition;
+
+DROP TYPE IF EXISTS stock_movement_type_enum;
+DROP TYPE IF EXISTS part_disposition_enum;
@@ -0,0 +1,78 @@
+-- Spare part dispositions on cancelled repairs and stock movements
+-- Migration: 013_add_repair_part_dispositions
+
+CREATE TYPE part_disposition_enum AS ENUM ('return_to_stock', 'scrap', 'keep_as_cost');
+CREATE TYPE stock_movement_type_enum AS ENUM ('repair_issue', 'repair_return', 'repair_scrap');
+
+-- What happened to a part line when its repair was cancelled (NULL while the line is in use)
+ALTER TABLE repair_spare_parts
+    ADD COLUMN disposition part_disposition_enum,
+    ADD COLUMN disposed_at TIMESTAMP;
+
+-- Parts returned to stock or scrapped no longer count towards the repair cost
+CREATE OR REPLACE FUNCTION update_vehicle_repair_cost()
+RETURNS TRIGGER AS $$
+DECLARE
+    vehicle_id_var INTEGER;
+    parts_cost DECIMAL(15,2);
+    labor_cost DECIMAL(15,2);
+    total_cost DECIMAL(15,2);
+BEGIN
+    -- Get vehicle_id from repair_orders table
+    SELECT vehicle_id INTO vehicle_id_var
+    FROM repair_orders 
+    WHERE id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    -- Calculate total cost of spare parts for this repair order
+    SELECT COALESCE(SUM(rsp.quantity_used * sp.selling_price), 0) INTO parts_cost
+    FROM repair_spare_parts rsp
+    JOIN spare_parts sp ON rsp.spare_part_id = sp.id
+    WHERE rsp.repair_order_id = COALESCE(NEW.repair_order_id, OLD.repair_order_id)
+      AND (rsp.disposition IS NULL OR rsp.disposition = 'keep_as_cost');
+    
+    -- Calculate total labor for this repair order
+    SELECT COALESCE(SUM(rll.total_price), 0) INTO labor_cost
+    FROM repair_labor_lines rll
+    WHERE rll.repair_order_id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    total_cost := parts_cost + labor_cost;
+    
+    -- Update vehicle repair_cost
+    UPDATE vehicles 
+    SET repair_cost = total_cost,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = vehicle_id_var;
+    
+    -- Also update the repair order actual_cost
+    UPDATE repair_orders 
+    SET actual_cost = total_cost,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ LANGUAGE plpgsql;
+
+-- Table: spare_part_movements (stock issued to and returned from repairs)
+-- quantity is the change to stock_quantity; scrapped parts were already issued, so they move 0
+CREATE TABLE spare_part_movements (
+    id SERIAL PRIMARY KEY,
+    spare_part_id INT NOT NULL,
+    movement_type stock_movement_type_enum NOT NULL,
+    quantity INT NOT NULL,
+    units INT NOT NULL CHECK (units > 0),
+    repair_order_id INT, -- kept when the repair order is deleted
+    notes TEXT,
+    created_by INT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (spare_part_id) REFERENCES spare_parts(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_spare_part_movements_part ON spare_part_movements(spare_part_id, created_at);
+CREATE INDEX idx_spare_part_movements_repair ON spare_part_movements(repair_order_id);
+
+-- Parts already used on repairs were issued from stock
+INSERT INTO spare_part_movements (spare_part_id, movement_type, quantity, units, repair_order_id, created_at)
+SELECT spare_part_id, 'repair_issue', -quantity_used, quantity_used, repair_order_id, created_at
+FROM repair_spare_parts;
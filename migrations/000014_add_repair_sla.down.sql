@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_repair_orders_target_date;
+
+ALTER TABLE repair_orders
+    DROP COLUMN IF EXISTS sla_alerted_at,
+    DROP COLUMN IF EXISTS target_completion_date;
+
+ALTER TABLE service_catalog DROP COLUMN IF EXISTS sla_days;
+ALTER TABLE vehicle_types DROP COLUMN IF EXISTS repair_sla_days;
@@ -0,0 +1,16 @@
+-- Repair SLA targets and overdue alerts
+-- Migration: 014_add_repair_sla
+
+-- Target turnaround in days; the longest applicable catalog target wins over the vehicle type
+ALTER TABLE vehicle_types ADD COLUMN repair_sla_days INT CHECK (repair_sla_days > 0);
+ALTER TABLE service_catalog ADD COLUMN sla_days INT CHECK (sla_days > 0);
+
+ALTER TABLE repair_orders
+    ADD COLUMN target_completion_date DATE,
+    ADD COLUMN sla_alerted_at TIMESTAMP; -- set once the overdue alert went out
+
+-- Existing orders keep the former three-day rule
+UPDATE repair_orders SET target_completion_date = DATE(created_at) + 3;
+
+CREATE INDEX idx_repair_orders_target_date ON repair_orders(target_completion_date)
+    WHERE status NOT IN ('completed', 'cancelled');
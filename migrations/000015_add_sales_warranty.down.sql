@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS warranty_claims;
+
+ALTER TABLE repair_orders
+    DROP COLUMN IF EXISTS is_warranty;
+
+DROP TABLE IF EXISTS sales_warranties;
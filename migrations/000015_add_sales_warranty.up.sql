@@ -0,0 +1,40 @@
+-- After-sales warranty on sold vehicles and warranty claims
+-- Migration: 015_add_sales_warranty
+
+-- Table: sales_warranties (showroom warranty terms given with a sale)
+CREATE TABLE sales_warranties (
+    id SERIAL PRIMARY KEY,
+    sales_transaction_id INT UNIQUE NOT NULL,
+    coverage VARCHAR(255) NOT NULL, -- e.g. 'engine', 'engine and transmission'
+    duration_months INT NOT NULL CHECK (duration_months > 0),
+    odometer_limit INT CHECK (odometer_limit > 0), -- km covered after the sale, NULL for no limit
+    odometer_at_sale INT NOT NULL DEFAULT 0,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    notes TEXT,
+    created_by INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (sales_transaction_id) REFERENCES sales_transactions(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+-- Warranty work runs as a customer service order that is never invoiced to the customer
+ALTER TABLE repair_orders
+    ADD COLUMN is_warranty BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Table: warranty_claims (each claim opens one warranty repair order)
+CREATE TABLE warranty_claims (
+    id SERIAL PRIMARY KEY,
+    warranty_id INT NOT NULL,
+    repair_order_id INT UNIQUE NOT NULL,
+    complaint TEXT NOT NULL,
+    odometer INT NOT NULL,
+    claimed_by INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (warranty_id) REFERENCES sales_warranties(id) ON DELETE CASCADE,
+    FOREIGN KEY (repair_order_id) REFERENCES repair_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (claimed_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_warranty_claims_warranty ON warranty_claims(warranty_id);
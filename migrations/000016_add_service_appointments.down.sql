@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS service_appointments;
+
+DROP TYPE IF EXISTS appointment_status_enum;
@@ -0,0 +1,37 @@
+-- Service appointments booked ahead for customer vehicles
+-- Migration: 016_add_service_appointments
+
+CREATE TYPE appointment_status_enum AS ENUM ('booked', 'arrived', 'no_show', 'cancelled');
+
+-- Table: service_appointments
+CREATE TABLE service_appointments (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) UNIQUE NOT NULL,
+    customer_id INT NOT NULL,
+    customer_vehicle_id INT NOT NULL,
+    service_id INT, -- requested catalog operation, if any
+    requested_service TEXT NOT NULL,
+    scheduled_start TIMESTAMP NOT NULL,
+    scheduled_end TIMESTAMP NOT NULL,
+    status appointment_status_enum NOT NULL DEFAULT 'booked',
+    source VARCHAR(20) NOT NULL DEFAULT 'front_desk', -- 'front_desk', 'public'
+    repair_order_id INT UNIQUE, -- set when the customer arrives
+    notes TEXT,
+    cancel_reason TEXT,
+    arrived_at TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    no_show_at TIMESTAMP,
+    booked_by INT, -- NULL for public bookings
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES customers(id),
+    FOREIGN KEY (customer_vehicle_id) REFERENCES customer_vehicles(id),
+    FOREIGN KEY (service_id) REFERENCES service_catalog(id) ON DELETE SET NULL,
+    FOREIGN KEY (repair_order_id) REFERENCES repair_orders(id) ON DELETE SET NULL,
+    FOREIGN KEY (booked_by) REFERENCES users(id),
+    CHECK (scheduled_end > scheduled_start)
+);
+
+CREATE INDEX idx_service_appointments_time ON service_appointments(scheduled_start, scheduled_end);
+CREATE INDEX idx_service_appointments_status ON service_appointments(status);
+CREATE INDEX idx_service_appointments_customer ON service_appointments(customer_id);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS service_reminders;
+DROP TABLE IF EXISTS service_reminder_rules;
+
+DROP TYPE IF EXISTS reminder_outcome_enum;
+DROP TYPE IF EXISTS reminder_status_enum;
+DROP TYPE IF EXISTS reminder_trigger_enum;
@@ -0,0 +1,66 @@
+-- Periodic service reminders for customer vehicles
+-- Migration: 017_add_service_reminders
+
+CREATE TYPE reminder_trigger_enum AS ENUM ('time', 'odometer', 'warranty_expiry');
+CREATE TYPE reminder_status_enum AS ENUM ('pending', 'sent', 'failed');
+CREATE TYPE reminder_outcome_enum AS ENUM ('booked', 'declined', 'unreachable');
+
+-- Table: service_reminder_rules
+CREATE TABLE service_reminder_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    trigger_type reminder_trigger_enum NOT NULL,
+    interval_months INT, -- time: months after the last service
+    interval_km INT, -- odometer: km after the last service
+    avg_km_per_month INT NOT NULL DEFAULT 1000, -- odometer: assumed usage to estimate the due date
+    days_before INT NOT NULL DEFAULT 7, -- lead time before the due date
+    service_id INT, -- suggested catalog operation
+    message TEXT NOT NULL, -- placeholders: {customer}, {vehicle}, {plate}, {due_date}, {due_odometer}
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (service_id) REFERENCES service_catalog(id) ON DELETE SET NULL,
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    CHECK (trigger_type <> 'time' OR interval_months > 0),
+    CHECK (trigger_type <> 'odometer' OR (interval_km > 0 AND avg_km_per_month > 0)),
+    CHECK (days_before >= 0)
+);
+
+-- Table: service_reminders (one per rule and due date, sent through the configured channel)
+CREATE TABLE service_reminders (
+    id SERIAL PRIMARY KEY,
+    rule_id INT NOT NULL,
+    customer_id INT NOT NULL,
+    customer_vehicle_id INT, -- time and odometer reminders
+    sales_warranty_id INT, -- warranty expiry reminders
+    due_date DATE NOT NULL,
+    due_odometer INT,
+    estimated_odometer INT,
+    message TEXT NOT NULL,
+    status reminder_status_enum NOT NULL DEFAULT 'pending',
+    channel VARCHAR(30),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    sent_at TIMESTAMP,
+    outcome reminder_outcome_enum,
+    outcome_notes TEXT,
+    appointment_id INT, -- booking that came out of the reminder
+    contacted_by INT,
+    contacted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (rule_id) REFERENCES service_reminder_rules(id) ON DELETE CASCADE,
+    FOREIGN KEY (customer_id) REFERENCES customers(id),
+    FOREIGN KEY (customer_vehicle_id) REFERENCES customer_vehicles(id) ON DELETE CASCADE,
+    FOREIGN KEY (sales_warranty_id) REFERENCES sales_warranties(id) ON DELETE CASCADE,
+    FOREIGN KEY (appointment_id) REFERENCES service_appointments(id) ON DELETE SET NULL,
+    FOREIGN KEY (contacted_by) REFERENCES users(id),
+    CHECK (customer_vehicle_id IS NOT NULL OR sales_warranty_id IS NOT NULL),
+    UNIQUE (rule_id, customer_vehicle_id, due_date),
+    UNIQUE (rule_id, sales_warranty_id)
+);
+
+CREATE INDEX idx_service_reminders_status ON service_reminders(status);
+CREATE INDEX idx_service_reminders_customer ON service_reminders(customer_id);
+CREATE INDEX idx_service_reminders_due_date ON service_reminders(due_date);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_vehicle_photos_vehicle;
+DROP INDEX IF EXISTS uq_vehicle_photos_primary;
+
+ALTER TABLE vehicle_photos
+    ALTER COLUMN is_primary DROP NOT NULL,
+    DROP CONSTRAINT IF EXISTS fk_vehicle_photos_uploaded_by,
+    DROP COLUMN IF EXISTS uploaded_by,
+    DROP COLUMN IF EXISTS file_size,
+    DROP COLUMN IF EXISTS content_type,
+    DROP COLUMN IF EXISTS sort_order,
+    DROP COLUMN IF EXISTS thumbnail_path;
@@ -0,0 +1,35 @@
+-- Vehicle photo uploads with thumbnails, ordering and a single primary photo
+-- Migration: 018_add_vehicle_photo_details
+
+ALTER TABLE vehicle_photos
+    ADD COLUMN thumbnail_path VARCHAR(255),
+    ADD COLUMN sort_order INT NOT NULL DEFAULT 0,
+    ADD COLUMN content_type VARCHAR(100),
+    ADD COLUMN file_size BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN uploaded_by INT, -- NULL for photos added before uploads were tracked
+    ADD CONSTRAINT fk_vehicle_photos_uploaded_by FOREIGN KEY (uploaded_by) REFERENCES users(id);
+
+UPDATE vehicle_photos SET is_primary = FALSE WHERE is_primary IS NULL;
+ALTER TABLE vehicle_photos ALTER COLUMN is_primary SET NOT NULL;
+
+-- Number existing photos in upload order
+UPDATE vehicle_photos vp
+SET sort_order = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY vehicle_id ORDER BY created_at, id) AS position
+    FROM vehicle_photos
+) ordered
+WHERE vp.id = ordered.id;
+
+-- Keep exactly one primary photo per vehicle: the first primary, else the first photo
+UPDATE vehicle_photos vp
+SET is_primary = (vp.id = chosen.id)
+FROM (
+    SELECT DISTINCT ON (vehicle_id) vehicle_id, id
+    FROM vehicle_photos
+    ORDER BY vehicle_id, is_primary DESC, sort_order, id
+) chosen
+WHERE vp.vehicle_id = chosen.vehicle_id;
+
+CREATE UNIQUE INDEX uq_vehicle_photos_primary ON vehicle_photos(vehicle_id) WHERE is_primary;
+CREATE INDEX idx_vehicle_photos_vehicle ON vehicle_photos(vehicle_id, sort_order);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS vehicle_document_attachments;
+DROP TABLE IF EXISTS vehicle_documents;
+DROP TABLE IF EXISTS document_handovers;
+DROP TYPE IF EXISTS document_custody_enum;
+DROP TYPE IF EXISTS vehicle_document_type_enum;
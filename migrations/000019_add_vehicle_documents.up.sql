@@ -0,0 +1,61 @@
+-- Ownership papers, receipts and keys held for each vehicle and their handover to buyers
+-- Migration: 019_add_vehicle_documents
+
+CREATE TYPE vehicle_document_type_enum AS ENUM ('bpkb', 'stnk', 'purchase_invoice', 'spare_key', 'other');
+CREATE TYPE document_custody_enum AS ENUM ('in_custody', 'out_for_processing', 'handed_over', 'missing');
+
+-- Table: document_handovers (documents given to the buyer of a sale, possibly in several handovers)
+CREATE TABLE document_handovers (
+    id SERIAL PRIMARY KEY,
+    sales_transaction_id INT NOT NULL,
+    recipient_name VARCHAR(100) NOT NULL,
+    recipient_phone VARCHAR(20),
+    handed_over_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    notes TEXT,
+    handed_over_by INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (sales_transaction_id) REFERENCES sales_transactions(id) ON DELETE CASCADE,
+    FOREIGN KEY (handed_over_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_document_handovers_sale ON document_handovers(sales_transaction_id);
+
+-- Table: vehicle_documents (one row per physical document or key)
+CREATE TABLE vehicle_documents (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INT NOT NULL,
+    document_type vehicle_document_type_enum NOT NULL,
+    document_number VARCHAR(100),
+    expiry_date DATE,
+    storage_location VARCHAR(100), -- e.g. 'safe A, folder 3'
+    custody_status document_custody_enum NOT NULL DEFAULT 'in_custody',
+    handover_id INT,
+    notes TEXT,
+    created_by INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (vehicle_id) REFERENCES vehicles(id) ON DELETE CASCADE,
+    FOREIGN KEY (handover_id) REFERENCES document_handovers(id) ON DELETE SET NULL,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_vehicle_documents_vehicle ON vehicle_documents(vehicle_id);
+CREATE INDEX idx_vehicle_documents_handover ON vehicle_documents(handover_id);
+
+-- Table: vehicle_document_attachments (scans, same storage model as repair_attachments)
+CREATE TABLE vehicle_document_attachments (
+    id SERIAL PRIMARY KEY,
+    document_id INT NOT NULL,
+    file_path VARCHAR(255) NOT NULL,
+    thumbnail_path VARCHAR(255), -- only for images
+    caption VARCHAR(255),
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    file_size BIGINT NOT NULL DEFAULT 0,
+    uploaded_by INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (document_id) REFERENCES vehicle_documents(id) ON DELETE CASCADE,
+    FOREIGN KEY (uploaded_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_vehicle_document_attachments_document ON vehicle_document_attachments(document_id);
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS vehicle_registration_alerts;
+DROP TABLE IF EXISTS vehicle_expenses;
+DROP TYPE IF EXISTS vehicle_expense_type_enum;
+
+-- Expenses leave HPP again
+UPDATE vehicles SET hpp_price = hpp_price - expense_cost WHERE expense_cost <> 0;
+
+DROP INDEX IF EXISTS idx_vehicles_plate_renewal_due_date;
+DROP INDEX IF EXISTS idx_vehicles_tax_due_date;
+
+ALTER TABLE vehicles
+    DROP COLUMN IF EXISTS plate_renewal_due_date,
+    DROP COLUMN IF EXISTS tax_due_date,
+    DROP COLUMN IF EXISTS expense_cost;
@@ -0,0 +1,40 @@
+-- STNK tax and plate renewal due dates, and vehicle expenses booked into HPP
+-- Migration: 020_add_vehicle_registration_dues
+
+ALTER TABLE vehicles
+    ADD COLUMN expense_cost DECIMAL(15,2) NOT NULL DEFAULT 0, -- taxes and fees paid while in stock
+    ADD COLUMN tax_due_date DATE, -- STNK annual tax (PKB)
+    ADD COLUMN plate_renewal_due_date DATE; -- five-yearly STNK and plate renewal
+
+CREATE INDEX idx_vehicles_tax_due_date ON vehicles(tax_due_date);
+CREATE INDEX idx_vehicles_plate_renewal_due_date ON vehicles(plate_renewal_due_date);
+
+CREATE TYPE vehicle_expense_type_enum AS ENUM ('stnk_tax', 'plate_renewal', 'other');
+
+-- Table: vehicle_expenses (costs other than repairs, summed into vehicles.expense_cost)
+CREATE TABLE vehicle_expenses (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INT NOT NULL,
+    expense_type vehicle_expense_type_enum NOT NULL,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    expense_date DATE NOT NULL,
+    description TEXT,
+    reference_number VARCHAR(100), -- e.g. SAMSAT receipt number
+    created_by INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (vehicle_id) REFERENCES vehicles(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_vehicle_expenses_vehicle ON vehicle_expenses(vehicle_id);
+
+-- Table: vehicle_registration_alerts (one alert per vehicle and due date)
+CREATE TABLE vehicle_registration_alerts (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INT NOT NULL,
+    due_type vehicle_expense_type_enum NOT NULL,
+    due_date DATE NOT NULL,
+    alerted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (vehicle_id) REFERENCES vehicles(id) ON DELETE CASCADE,
+    UNIQUE (vehicle_id, due_type, due_date)
+);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_vehicles_variant_id;
+DROP INDEX IF EXISTS idx_vehicles_model_id;
+
+ALTER TABLE vehicles
+    DROP COLUMN IF EXISTS variant_id,
+    DROP COLUMN IF EXISTS model_id;
+
+DROP TABLE IF EXISTS vehicle_variants;
+DROP TABLE IF EXISTS vehicle_models;
+
+DROP FUNCTION IF EXISTS normalize_model_name(TEXT);
@@ -0,0 +1,47 @@
+-- Model and variant catalog under vehicle brands
+-- Migration: 021_add_vehicle_model_catalog
+
+-- Compares model names regardless of case, spaces and punctuation ('BeAT', 'Beat', 'beat-fi')
+CREATE FUNCTION normalize_model_name(name TEXT) RETURNS TEXT AS $$
+    SELECT LOWER(REGEXP_REPLACE(name, '[^[:alnum:]]', '', 'g'))
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Table: vehicle_models
+CREATE TABLE vehicle_models (
+    id SERIAL PRIMARY KEY,
+    brand_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (brand_id) REFERENCES vehicle_brands(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_vehicle_models_brand_name ON vehicle_models(brand_id, normalize_model_name(name));
+
+-- Table: vehicle_variants (default specs prefill new vehicles)
+CREATE TABLE vehicle_variants (
+    id SERIAL PRIMARY KEY,
+    model_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    engine_capacity VARCHAR(20),
+    fuel_type VARCHAR(20),
+    transmission_type VARCHAR(20),
+    year_from INT,
+    year_to INT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (model_id) REFERENCES vehicle_models(id) ON DELETE CASCADE,
+    CHECK (year_from IS NULL OR year_to IS NULL OR year_to >= year_from)
+);
+
+CREATE UNIQUE INDEX idx_vehicle_variants_model_name ON vehicle_variants(model_id, normalize_model_name(name));
+
+-- vehicles.model stays as the display name; model_id is NULL until the free text is mapped
+ALTER TABLE vehicles
+    ADD COLUMN model_id INT REFERENCES vehicle_models(id),
+    ADD COLUMN variant_id INT REFERENCES vehicle_variants(id);
+
+CREATE INDEX idx_vehicles_model_id ON vehicles(model_id);
+CREATE INDEX idx_vehicles_variant_id ON vehicles(variant_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS vehicle_attribute_values;
+DROP TABLE IF EXISTS vehicle_attribute_definitions;
+DROP TYPE IF EXISTS vehicle_attribute_data_type_enum;
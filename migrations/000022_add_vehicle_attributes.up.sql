@@ -0,0 +1,34 @@
+-- Custom vehicle attributes defined per vehicle type
+-- Migration: 022_add_vehicle_attributes
+
+CREATE TYPE vehicle_attribute_data_type_enum AS ENUM ('text', 'number', 'boolean', 'enum');
+
+-- Table: vehicle_attribute_definitions (e.g. seats and drive type for cars, ABS for motorcycles)
+CREATE TABLE vehicle_attribute_definitions (
+    id SERIAL PRIMARY KEY,
+    type_id INT NOT NULL,
+    code VARCHAR(50) NOT NULL, -- key used in vehicle requests and search filters
+    name VARCHAR(100) NOT NULL,
+    data_type vehicle_attribute_data_type_enum NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}', -- allowed values of enum attributes
+    is_required BOOLEAN NOT NULL DEFAULT FALSE,
+    sort_order INT NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (type_id) REFERENCES vehicle_types(id) ON DELETE CASCADE,
+    UNIQUE (type_id, code)
+);
+
+-- Table: vehicle_attribute_values (numbers and booleans are stored in their text form)
+CREATE TABLE vehicle_attribute_values (
+    vehicle_id INT NOT NULL,
+    definition_id INT NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (vehicle_id, definition_id),
+    FOREIGN KEY (vehicle_id) REFERENCES vehicles(id) ON DELETE CASCADE,
+    FOREIGN KEY (definition_id) REFERENCES vehicle_attribute_definitions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_vehicle_attribute_values_definition ON vehicle_attribute_values(definition_id, value);
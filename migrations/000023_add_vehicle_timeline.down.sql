@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS vehicle_status_history;
+DROP TABLE IF EXISTS vehicle_price_changes;
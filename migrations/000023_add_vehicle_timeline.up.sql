@@ -0,0 +1,40 @@
+-- Price and status history for the vehicle timeline
+-- Migration: 023_add_vehicle_timeline
+
+CREATE TABLE vehicle_price_changes (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INT NOT NULL,
+    old_price DECIMAL(15,2), -- NULL when no price was set before
+    new_price DECIMAL(15,2) NOT NULL,
+    changed_by INT, -- NULL for prices set before changes were recorded
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (vehicle_id) REFERENCES vehicles(id) ON DELETE CASCADE,
+    FOREIGN KEY (changed_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_vehicle_price_changes_vehicle ON vehicle_price_changes(vehicle_id, changed_at);
+
+-- Status changes made by hand, e.g. reserving a vehicle; repairs and sales keep their own records
+CREATE TABLE vehicle_status_history (
+    id SERIAL PRIMARY KEY,
+    vehicle_id INT NOT NULL,
+    from_status vehicle_status_enum,
+    to_status vehicle_status_enum NOT NULL,
+    changed_by INT,
+    note TEXT,
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (vehicle_id) REFERENCES vehicles(id) ON DELETE CASCADE,
+    FOREIGN KEY (changed_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_vehicle_status_history_vehicle ON vehicle_status_history(vehicle_id, changed_at);
+
+INSERT INTO vehicle_price_changes (vehicle_id, old_price, new_price, changed_by, changed_at)
+SELECT id, NULL, selling_price, NULL, updated_at
+FROM vehicles
+WHERE selling_price > 0;
+
+INSERT INTO vehicle_status_history (vehicle_id, from_status, to_status, changed_by, note, changed_at)
+SELECT id, NULL, status, NULL, 'Recorded before status history was kept', updated_at
+FROM vehicles
+WHERE status = 'reserved';
@@ -0,0 +1,62 @@
+-- Repair order cost from recorded line prices; vehicle repair cost and HPP are left to the application
+-- Migration: 024_fix_repair_cost_trigger
+
+-- The trigger only keeps actual_cost of the edited order. The vehicle repair cost covers all of its
+-- completed orders and moves the HPP, so the application recomputes it when an order completes or
+-- is cancelled.
+CREATE OR REPLACE FUNCTION update_vehicle_repair_cost()
+RETURNS TRIGGER AS $$
+DECLARE
+    parts_cost DECIMAL(15,2);
+    labor_cost DECIMAL(15,2);
+BEGIN
+    -- Parts at the price recorded on the line; returned or scrapped parts do not count
+    SELECT COALESCE(SUM(rsp.total_price), 0) INTO parts_cost
+    FROM repair_spare_parts rsp
+    WHERE rsp.repair_order_id = COALESCE(NEW.repair_order_id, OLD.repair_order_id)
+      AND (rsp.disposition IS NULL OR rsp.disposition = 'keep_as_cost');
+    
+    SELECT COALESCE(SUM(rll.total_price), 0) INTO labor_cost
+    FROM repair_labor_lines rll
+    WHERE rll.repair_order_id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    UPDATE repair_orders 
+    SET actual_cost = parts_cost + labor_cost,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE id = COALESCE(NEW.repair_order_id, OLD.repair_order_id);
+    
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ LANGUAGE plpgsql;
+
+-- Reprice existing orders the same way
+UPDATE repair_orders ro
+SET actual_cost = COALESCE((SELECT SUM(rsp.total_price) FROM repair_spare_parts rsp
+                            WHERE rsp.repair_order_id = ro.id
+                              AND (rsp.disposition IS NULL OR rsp.disposition = 'keep_as_cost')), 0)
+                + COALESCE((SELECT SUM(rll.total_price) FROM repair_labor_lines rll
+                            WHERE rll.repair_order_id = ro.id), 0);
+
+-- Vehicles in stock get the repair cost of all their completed orders back, and the HPP with it
+UPDATE vehicles v
+SET repair_cost = costs.total,
+    hpp_price = v.purchase_price + costs.total + v.expense_cost,
+    updated_at = CURRENT_TIMESTAMP
+FROM (
+    SELECT v2.id, COALESCE((
+        SELECT SUM(cost) FROM (
+            SELECT rsp.total_price AS cost
+            FROM repair_spare_parts rsp
+            JOIN repair_orders ro ON rsp.repair_order_id = ro.id
+            WHERE ro.vehicle_id = v2.id
+              AND ((ro.status = 'completed' AND rsp.disposition IS NULL) OR rsp.disposition = 'keep_as_cost')
+            UNION ALL
+            SELECT rll.total_price
+            FROM repair_labor_lines rll
+            JOIN repair_orders ro ON rll.repair_order_id = ro.id
+            WHERE ro.vehicle_id = v2.id AND ro.status = 'completed'
+        ) lines), 0) AS total
+    FROM vehicles v2
+    WHERE v2.status <> 'sold'
+) costs
+WHERE v.id = costs.id;
@@ -0,0 +1,22 @@
+package notifier
+
+import (
+	"log"
+)
+
+// Log writes messages to the server log instead of delivering them; useful in development
+// and until a real gateway is configured
+type Log struct{}
+
+func NewLog() *Log {
+	return &Log{}
+}
+
+func (l *Log) Name() string {
+	return "log"
+}
+
+func (l *Log) Send(msg Message) error {
+	log.Printf("Notification %s to %s: %s - %s", msg.Reference, msg.Recipient, msg.Subject, msg.Body)
+	return nil
+}
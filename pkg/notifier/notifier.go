@@ -0,0 +1,17 @@
+package notifier
+
+// Message is a notification addressed to a customer
+type Message struct {
+	Reference string  `json:"reference"` // e.g. "service_reminder:12"
+	Recipient string  `json:"recipient"`
+	Phone     *string `json:"phone,omitempty"`
+	Email     *string `json:"email,omitempty"`
+	Subject   string  `json:"subject"`
+	Body      string  `json:"body"`
+}
+
+// Channel delivers messages to customers, e.g. by WhatsApp or e-mail
+type Channel interface {
+	Name() string
+	Send(msg Message) error
+}
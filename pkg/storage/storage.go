@@ -0,0 +1,14 @@
+package storage
+
+import (
+	"io"
+	"time"
+)
+
+// Store keeps uploaded files under keys such as "vehicles/12/photo.jpg". Files are not public;
+// clients download them through signed links that expire.
+type Store interface {
+	Save(key string, r io.Reader, contentType string) error
+	Delete(key string) error
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
@@ -110,7 +110,7 @@ func ValidateStruct(s interface{}) error {
 				errors = append(errors, fmt.Sprintf("%s is invalid", err.Field()))
 			}
 		}
-		return fmt.Errorf(strings.Join(errors, ", "))
+		return fmt.Errorf("%s", strings.Join(errors, ", "))
 	}
 	return nil
 }
\ No newline at end of file